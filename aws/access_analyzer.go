@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer/accessanalyzeriface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -16,7 +18,7 @@ import (
 )
 
 func getAllAccessAnalyzers(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := accessanalyzer.New(session)
+	svc := newAccessAnalyzerClient(session)
 
 	allAnalyzers := []*string{}
 	err := svc.ListAnalyzersPages(
@@ -42,14 +44,10 @@ func shouldIncludeAccessAnalyzer(analyzer *accessanalyzer.AnalyzerSummary, exclu
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(analyzer.Name),
-		configObj.AccessAnalyzer.IncludeRule.NamesRegExp,
-		configObj.AccessAnalyzer.ExcludeRule.NamesRegExp,
-	)
+	return configObj.AccessAnalyzer.ShouldInclude(config.ResourceValue{Name: aws.StringValue(analyzer.Name)})
 }
 
-func nukeAllAccessAnalyzers(session *session.Session, names []*string) error {
+func nukeAllAccessAnalyzers(ctx context.Context, session *session.Session, names []*string) error {
 	if len(names) == 0 {
 		logging.Logger.Debugf("No IAM Access Analyzers to nuke in region %s", *session.Config.Region)
 		return nil
@@ -67,13 +65,13 @@ func nukeAllAccessAnalyzers(session *session.Session, names []*string) error {
 	// There is no bulk delete access analyzer API, so we delete the batch of Access Analyzers concurrently using go routines.
 	logging.Logger.Debugf("Deleting all Access Analyzers in region %s", *session.Config.Region)
 
-	svc := accessanalyzer.New(session)
+	svc := newAccessAnalyzerClient(session)
 	wg := new(sync.WaitGroup)
 	wg.Add(len(names))
 	errChans := make([]chan error, len(names))
 	for i, analyzerName := range names {
 		errChans[i] = make(chan error, 1)
-		go deleteAccessAnalyzerAsync(wg, errChans[i], svc, analyzerName)
+		go deleteAccessAnalyzerAsync(ctx, wg, errChans[i], svc, analyzerName)
 	}
 	wg.Wait()
 
@@ -96,11 +94,11 @@ func nukeAllAccessAnalyzers(session *session.Session, names []*string) error {
 
 // deleteAccessAnalyzerAsync deletes the provided IAM Access Analyzer asynchronously in a goroutine, using wait groups
 // for concurrency control and a return channel for errors.
-func deleteAccessAnalyzerAsync(wg *sync.WaitGroup, errChan chan error, svc *accessanalyzer.AccessAnalyzer, analyzerName *string) {
+func deleteAccessAnalyzerAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc accessanalyzeriface.AccessAnalyzerAPI, analyzerName *string) {
 	defer wg.Done()
 
 	input := &accessanalyzer.DeleteAnalyzerInput{AnalyzerName: analyzerName}
-	_, err := svc.DeleteAnalyzer(input)
+	_, err := svc.DeleteAnalyzerWithContext(ctx, input)
 	errChan <- err
 }
 
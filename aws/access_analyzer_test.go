@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -82,7 +83,7 @@ func TestNukeAccessAnalyzerOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllAccessAnalyzers(session, identifiers),
+		nukeAllAccessAnalyzers(context.Background(), session, identifiers),
 	)
 
 	// Make sure the Access Analyzer is deleted.
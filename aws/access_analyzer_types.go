@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,22 @@ func (analyzer AccessAnalyzer) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (analyzer AccessAnalyzer) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllAccessAnalyzers(session, awsgo.StringSlice(identifiers)); err != nil {
+func (analyzer AccessAnalyzer) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllAccessAnalyzers(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        AccessAnalyzer{}.ResourceName(),
+		Description: "Access Analyzers",
+		ConfigKey:   "AccessAnalyzer",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllAccessAnalyzers(session, params.ExcludeAfter, params.Config)
+			return AccessAnalyzer{AnalyzerNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"regexp"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+var accountIdRegExp = regexp.MustCompile(`^\d{12}$`)
+
+// CheckAccount returns an error if cloud-nuke must not run against the account of the current credentials, according to
+// the given rules. The session is created in the given region. It must be called before nuking anything.
+func CheckAccount(region string, rules config.AccountRules) error {
+	if rules.IsEmpty() {
+		return nil
+	}
+
+	session := newSession(region)
+	accountId, err := getCallerAccountId(session)
+	if err != nil {
+		return err
+	}
+
+	// Account aliases are only looked up if they are needed, as it requires an additional IAM permission
+	var aliases []string
+	if rulesUseAliases(rules) {
+		aliases, err = getAccountAliases(session)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := checkAccount(rules, accountId, aliases); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	logging.Logger.Infof("Account %s is allowed to be nuked", accountId)
+	return nil
+}
+
+// GetCallerAccountID returns the ID of the account of the current credentials, using the API endpoint of the given
+// region
+func GetCallerAccountID(region string) (string, error) {
+	return getCallerAccountId(newSession(region))
+}
+
+// CheckRecordedAccountID returns the ID of the account of the current credentials, or an error if it is not the given
+// account, which a plan, a state file or a run report recorded. Resources such as S3 buckets or IAM roles are identified by name, so
+// acting on a plan or state file with credentials of another account could nuke unrelated resources. An empty recorded
+// ID, from files written before the account was recorded, matches any account.
+func CheckRecordedAccountID(region string, recordedAccountId string) (string, error) {
+	accountId, err := GetCallerAccountID(region)
+	if err != nil {
+		return "", err
+	}
+	if recordedAccountId == "" {
+		logging.Logger.Warnf("The account the file was written for is not recorded, make sure that account %s is the right one", accountId)
+		return accountId, nil
+	}
+	if accountId != recordedAccountId {
+		return "", errors.WithStackTrace(RecordedAccountMismatchError{RecordedAccountId: recordedAccountId, AccountId: accountId})
+	}
+	return accountId, nil
+}
+
+// checkAccount returns an error if the account with the given ID and aliases is blocked, or is not allowed
+func checkAccount(rules config.AccountRules, accountId string, aliases []string) error {
+	for _, entry := range rules.Blocked {
+		if accountMatches(entry, accountId, aliases) {
+			return AccountBlockedError{AccountId: accountId, Entry: entry}
+		}
+	}
+
+	if len(rules.Allowed) == 0 {
+		return nil
+	}
+	for _, entry := range rules.Allowed {
+		if accountMatches(entry, accountId, aliases) {
+			return nil
+		}
+	}
+	return AccountNotAllowedError{AccountId: accountId, Aliases: aliases}
+}
+
+// accountMatches returns true if the given account ID or alias is the one of the given account
+func accountMatches(entry string, accountId string, aliases []string) bool {
+	if entry == accountId {
+		return true
+	}
+	for _, alias := range aliases {
+		if entry == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// rulesUseAliases returns true if any of the allowed or blocked accounts is given by alias rather than by ID
+func rulesUseAliases(rules config.AccountRules) bool {
+	for _, entry := range append(rules.Allowed, rules.Blocked...) {
+		if !accountIdRegExp.MatchString(entry) {
+			return true
+		}
+	}
+	return false
+}
+
+func getCallerAccountId(session *session.Session) (string, error) {
+	output, err := newSTSClient(session).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return awsgo.StringValue(output.Account), nil
+}
+
+func getAccountAliases(session *session.Session) ([]string, error) {
+	var aliases []string
+	err := newIAMClient(session).ListAccountAliasesPages(&iam.ListAccountAliasesInput{}, func(page *iam.ListAccountAliasesOutput, lastPage bool) bool {
+		aliases = append(aliases, awsgo.StringValueSlice(page.AccountAliases)...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return aliases, nil
+}
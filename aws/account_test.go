@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestCheckAccount(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		rules    config.AccountRules
+		aliases  []string
+		expected error
+	}{
+		{"NoRules", config.AccountRules{}, nil, nil},
+		{"AllowedById", config.AccountRules{Allowed: []string{"111111111111", "123456789012"}}, nil, nil},
+		{"AllowedByAlias", config.AccountRules{Allowed: []string{"sandbox"}}, []string{"sandbox"}, nil},
+		{
+			"NotAllowed",
+			config.AccountRules{Allowed: []string{"111111111111"}},
+			[]string{"prod"},
+			AccountNotAllowedError{AccountId: "123456789012", Aliases: []string{"prod"}},
+		},
+		{
+			"BlockedById",
+			config.AccountRules{Blocked: []string{"123456789012"}},
+			nil,
+			AccountBlockedError{AccountId: "123456789012", Entry: "123456789012"},
+		},
+		{
+			// Blocking an account wins over allowing it
+			"BlockedByAliasAndAllowed",
+			config.AccountRules{Allowed: []string{"123456789012"}, Blocked: []string{"prod"}},
+			[]string{"prod"},
+			AccountBlockedError{AccountId: "123456789012", Entry: "prod"},
+		},
+		{"NotBlocked", config.AccountRules{Blocked: []string{"prod"}}, []string{"sandbox"}, nil},
+	}
+
+	for _, testCase := range testCases {
+		// Capture the range variable as per https://blog.golang.org/subtests
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, checkAccount(testCase.rules, "123456789012", testCase.aliases))
+		})
+	}
+}
+
+func TestRulesUseAliases(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, rulesUseAliases(config.AccountRules{Allowed: []string{"123456789012"}, Blocked: []string{"210987654321"}}))
+	assert.True(t, rulesUseAliases(config.AccountRules{Allowed: []string{"123456789012"}, Blocked: []string{"prod"}}))
+}
+
+func TestCheckRecordedAccountIDOffline(t *testing.T) {
+	useFakeAccountCredentials(t, "123456789012", accountOfRoleArn)
+
+	accountId, err := CheckRecordedAccountID("us-east-1", "123456789012")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", accountId)
+
+	// Files written before the account was recorded can be used with any account
+	accountId, err = CheckRecordedAccountID("us-east-1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", accountId)
+
+	_, err = CheckRecordedAccountID("us-east-1", "210987654321")
+	assert.Equal(t, RecordedAccountMismatchError{RecordedAccountId: "210987654321", AccountId: "123456789012"}, errors.Unwrap(err))
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,12 +20,12 @@ import (
 )
 
 // getAllACMPCA returns a list of all arns of ACMPCA, which can be deleted.
-func getAllACMPCA(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := acmpca.New(session)
+func getAllACMPCA(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newACMPCAClient(session)
 	var arns []*string
 	if paginationErr := svc.ListCertificateAuthoritiesPages(&acmpca.ListCertificateAuthoritiesInput{}, func(p *acmpca.ListCertificateAuthoritiesOutput, lastPage bool) bool {
 		for _, ca := range p.CertificateAuthorities {
-			if shouldIncludeACMPCA(ca, excludeAfter) {
+			if shouldIncludeACMPCA(ca, excludeAfter, configObj) {
 				arns = append(arns, ca.Arn)
 			}
 		}
@@ -34,7 +36,7 @@ func getAllACMPCA(session *session.Session, region string, excludeAfter time.Tim
 	return arns, nil
 }
 
-func shouldIncludeACMPCA(ca *acmpca.CertificateAuthority, excludeAfter time.Time) bool {
+func shouldIncludeACMPCA(ca *acmpca.CertificateAuthority, excludeAfter time.Time, configObj config.Config) bool {
 	if ca == nil {
 		return false
 	}
@@ -57,20 +59,16 @@ func shouldIncludeACMPCA(ca *acmpca.CertificateAuthority, excludeAfter time.Time
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(ca.Arn),
-		nil,
-		nil,
-	)
+	return configObj.ACMPCA.ShouldInclude(config.ResourceValue{Name: aws.StringValue(ca.Arn)})
 }
 
 // nukeAllACMPCA will delete all ACMPCA, which are given by a list of arns.
-func nukeAllACMPCA(session *session.Session, arns []*string) error {
+func nukeAllACMPCA(ctx context.Context, session *session.Session, arns []*string) error {
 	if len(arns) == 0 {
 		logging.Logger.Debugf("No ACMPCA to nuke in region %s", *session.Config.Region)
 		return nil
 	}
-	svc := acmpca.New(session)
+	svc := newACMPCAClient(session)
 
 	logging.Logger.Debugf("Deleting all ACMPCA in region %s", *session.Config.Region)
 	// There is no bulk delete acmpca API, so we delete the batch of ARNs concurrently using go routines.
@@ -79,7 +77,7 @@ func nukeAllACMPCA(session *session.Session, arns []*string) error {
 	errChans := make([]chan error, len(arns))
 	for i, arn := range arns {
 		errChans[i] = make(chan error, 1)
-		go deleteACMPCAASync(wg, errChans[i], svc, arn, aws.StringValue(session.Config.Region))
+		go deleteACMPCAASync(ctx, wg, errChans[i], svc, arn, aws.StringValue(session.Config.Region))
 	}
 	wg.Wait()
 
@@ -101,11 +99,11 @@ func nukeAllACMPCA(session *session.Session, arns []*string) error {
 
 // deleteACMPCAASync deletes the provided ACMPCA arn. Intended to be run in a goroutine, using wait groups
 // and a return channel for errors.
-func deleteACMPCAASync(wg *sync.WaitGroup, errChan chan error, svc *acmpca.ACMPCA, arn *string, region string) {
+func deleteACMPCAASync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc acmpcaiface.ACMPCAAPI, arn *string, region string) {
 	defer wg.Done()
 
 	logging.Logger.Debugf("Fetching details of CA to be deleted for ACMPCA %s in region %s", *arn, region)
-	details, detailsErr := svc.DescribeCertificateAuthority(&acmpca.DescribeCertificateAuthorityInput{CertificateAuthorityArn: arn})
+	details, detailsErr := svc.DescribeCertificateAuthorityWithContext(ctx, &acmpca.DescribeCertificateAuthorityInput{CertificateAuthorityArn: arn})
 	if detailsErr != nil {
 		errChan <- detailsErr
 		return
@@ -128,7 +126,7 @@ func deleteACMPCAASync(wg *sync.WaitGroup, errChan chan error, svc *acmpca.ACMPC
 
 	if shouldUpdateStatus {
 		logging.Logger.Debugf("Setting status to 'DISABLED' for ACMPCA %s in region %s", *arn, region)
-		if _, updateStatusErr := svc.UpdateCertificateAuthority(&acmpca.UpdateCertificateAuthorityInput{
+		if _, updateStatusErr := svc.UpdateCertificateAuthorityWithContext(ctx, &acmpca.UpdateCertificateAuthorityInput{
 			CertificateAuthorityArn: arn,
 			Status:                  aws.String(acmpca.CertificateAuthorityStatusDisabled),
 		}); updateStatusErr != nil {
@@ -138,7 +136,7 @@ func deleteACMPCAASync(wg *sync.WaitGroup, errChan chan error, svc *acmpca.ACMPC
 		logging.Logger.Debugf("Did set status to 'DISABLED' for ACMPCA: %s in region %s", *arn, region)
 	}
 
-	_, deleteErr := svc.DeleteCertificateAuthority(&acmpca.DeleteCertificateAuthorityInput{
+	_, deleteErr := svc.DeleteCertificateAuthorityWithContext(ctx, &acmpca.DeleteCertificateAuthorityInput{
 		CertificateAuthorityArn: arn,
 		// the range is 7 to 30 days.
 		// since cloud-nuke should not be used in production,
@@ -152,7 +150,7 @@ func deleteACMPCAASync(wg *sync.WaitGroup, errChan chan error, svc *acmpca.ACMPC
 		ResourceType: "ACM Private CA (ACMPCA)",
 		Error:        deleteErr,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	if deleteErr != nil {
 		errChan <- deleteErr
@@ -1,12 +1,14 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"os"
 	"testing"
 	"time"
 
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/go-commons/retry"
 
@@ -107,15 +109,15 @@ func TestListACMPCA(t *testing.T) {
 	uniqueTestID := "cloud-nuke-test-" + util.UniqueID()
 	arn := createTestACMPCA(t, session, uniqueTestID)
 	// clean up after this test
-	defer nukeAllACMPCA(session, []*string{arn})
+	defer nukeAllACMPCA(context.Background(), session, []*string{arn})
 
-	newARNs, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour*-1))
+	newARNs, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of ACMPCA arns")
 	}
 	assert.NotContains(t, awsgo.StringValueSlice(newARNs), awsgo.StringValue(arn))
 
-	allARNs, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour))
+	allARNs, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of ACMPCA arns")
 	}
@@ -144,11 +146,11 @@ func TestNukeACMPCA(t *testing.T) {
 	uniqueTestID := "cloud-nuke-test-" + util.UniqueID()
 	arn := createTestACMPCA(t, session, uniqueTestID)
 
-	if err := nukeAllACMPCA(session, []*string{arn}); err != nil {
+	if err := nukeAllACMPCA(context.Background(), session, []*string{arn}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	arns, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour))
+	arns, err := getAllACMPCA(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of ACMPCA arns")
 	}
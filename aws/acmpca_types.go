@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,22 @@ func (ca ACMPCA) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (ca ACMPCA) Nuke(session *session.Session, arns []string) error {
-	if err := nukeAllACMPCA(session, awsgo.StringSlice(arns)); err != nil {
+func (ca ACMPCA) Nuke(ctx context.Context, session *session.Session, arns []string) error {
+	if err := nukeAllACMPCA(ctx, session, awsgo.StringSlice(arns)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ACMPCA{}.ResourceName(),
+		Description: "ACMPCAs",
+		ConfigKey:   "acmpca",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllACMPCA(session, params.Region, params.ExcludeAfter, params.Config)
+			return ACMPCA{ARNs: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
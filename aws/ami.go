@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"time"
 
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"github.com/tnn-gruntwork-io/cloud-nuke/util"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -17,8 +19,8 @@ import (
 )
 
 // Returns a formatted string of AMI Image ids
-func getAllAMIs(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := ec2.New(session)
+func getAllAMIs(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
 
 	params := &ec2.DescribeImagesInput{
 		Owners: []*string{awsgo.String("self")},
@@ -31,6 +33,10 @@ func getAllAMIs(session *session.Session, region string, excludeAfter time.Time)
 
 	var imageIds []*string
 	for _, image := range output.Images {
+		if excludedByTag(AMIs{}.ResourceName(), region, awsgo.StringValue(image.ImageId), ec2TagsToMap(image.Tags)) {
+			continue
+		}
+
 		layout := "2006-01-02T15:04:05.000Z"
 		createdTime, err := time.Parse(layout, *image.CreationDate)
 		if err != nil {
@@ -38,7 +44,12 @@ func getAllAMIs(session *session.Session, region string, excludeAfter time.Time)
 		}
 
 		// Test for time exclusion and check if resource is managed by AWS Backup (see note in README)
-		if excludeAfter.After(createdTime) && !util.HasAWSBackupTag(image.Tags) {
+		if excludeAfter.After(createdTime) && !util.HasAWSBackupTag(image.Tags) && configObj.AMI.ShouldInclude(config.ResourceValue{
+			Name: awsgo.StringValue(image.Name),
+			Tags: ec2TagsToMap(image.Tags),
+			ID:   awsgo.StringValue(image.ImageId),
+			Time: createdTime,
+		}) {
 			imageIds = append(imageIds, image.ImageId)
 		}
 	}
@@ -47,8 +58,8 @@ func getAllAMIs(session *session.Session, region string, excludeAfter time.Time)
 }
 
 // Deletes all AMIs
-func nukeAllAMIs(session *session.Session, imageIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllAMIs(ctx context.Context, session *session.Session, imageIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(imageIds) == 0 {
 		logging.Logger.Debugf("No AMIs to nuke in region %s", *session.Config.Region)
@@ -63,7 +74,7 @@ func nukeAllAMIs(session *session.Session, imageIds []*string) error {
 			ImageId: imageID,
 		}
 
-		_, err := svc.DeregisterImage(params)
+		_, err := svc.DeregisterImageWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -71,7 +82,7 @@ func nukeAllAMIs(session *session.Session, imageIds []*string) error {
 			ResourceType: "Amazon Machine Image (AMI)",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -59,7 +61,7 @@ func createTestAMI(t *testing.T, session *session.Session, name string) (*ec2.Im
 
 	if err != nil {
 		// clean this up since we won't use it again
-		defer nukeAllAMIs(session, []*string{output.ImageId})
+		defer nukeAllAMIs(context.Background(), session, []*string{output.ImageId})
 		return nil, errors.WithStackTrace(err)
 	}
 
@@ -105,17 +107,17 @@ func TestListAMIs(t *testing.T) {
 	}
 
 	// clean up after this test
-	defer nukeAllAMIs(session, []*string{image.ImageId})
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllAMIs(context.Background(), session, []*string{image.ImageId})
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
-	amis, err := getAllAMIs(session, region, time.Now().Add(1*time.Hour*-1))
+	amis, err := getAllAMIs(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of AMIs")
 	}
 
 	assert.NotContains(t, awsgo.StringValueSlice(amis), *image.ImageId)
 
-	amis, err = getAllAMIs(session, region, time.Now().Add(1*time.Hour))
+	amis, err = getAllAMIs(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of AMIs")
 	}
@@ -155,7 +157,7 @@ func TestNukeAMIs(t *testing.T) {
 	}
 
 	// clean up ec2 instance created by the above call
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
 	_, err = svc.DescribeImages(&ec2.DescribeImagesInput{
 		ImageIds: []*string{image.ImageId},
@@ -165,11 +167,11 @@ func TestNukeAMIs(t *testing.T) {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	if err := nukeAllAMIs(session, []*string{image.ImageId}); err != nil {
+	if err := nukeAllAMIs(context.Background(), session, []*string{image.ImageId}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	amis, err := getAllAMIs(session, region, time.Now().Add(1*time.Hour))
+	amis, err := getAllAMIs(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of AMIs")
 	}
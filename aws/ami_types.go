@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,8 +29,8 @@ func (image AMIs) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (image AMIs) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllAMIs(session, awsgo.StringSlice(identifiers)); err != nil {
+func (image AMIs) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllAMIs(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -40,3 +42,17 @@ type ImageAvailableError struct{}
 func (e ImageAvailableError) Error() string {
 	return "Image didn't become available within wait attempts"
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            AMIs{}.ResourceName(),
+		Description:     "AMIs",
+		ConfigKey:       "AMI",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllAMIs(session, params.Region, params.ExcludeAfter, params.Config)
+			return AMIs{ImageIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
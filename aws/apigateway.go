@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -17,17 +19,19 @@ import (
 )
 
 func getAllAPIGateways(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := apigateway.New(session)
+	svc := newAPIGatewayClient(session)
 
-	result, err := svc.GetRestApis(&apigateway.GetRestApisInput{})
-	if err != nil {
-		return []*string{}, errors.WithStackTrace(err)
-	}
 	Ids := []*string{}
-	for _, apigateway := range result.Items {
-		if shouldIncludeAPIGateway(apigateway, excludeAfter, configObj) {
-			Ids = append(Ids, apigateway.Id)
+	err := svc.GetRestApisPages(&apigateway.GetRestApisInput{}, func(page *apigateway.GetRestApisOutput, lastPage bool) bool {
+		for _, restApi := range page.Items {
+			if shouldIncludeAPIGateway(restApi, excludeAfter, configObj) {
+				Ids = append(Ids, restApi.Id)
+			}
 		}
+		return !lastPage
+	})
+	if err != nil {
+		return []*string{}, errors.WithStackTrace(err)
 	}
 
 	return Ids, nil
@@ -44,17 +48,16 @@ func shouldIncludeAPIGateway(apigw *apigateway.RestApi, excludeAfter time.Time,
 		}
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(apigw.Name),
-		configObj.APIGateway.IncludeRule.NamesRegExp,
-		configObj.APIGateway.ExcludeRule.NamesRegExp,
-	)
+	return configObj.APIGateway.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(apigw.Name),
+		ID:   aws.StringValue(apigw.Id),
+	})
 }
 
-func nukeAllAPIGateways(session *session.Session, identifiers []*string) error {
+func nukeAllAPIGateways(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := apigateway.New(session)
+	svc := newAPIGatewayClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No API Gateways (v1) to nuke in region %s", region)
@@ -72,7 +75,7 @@ func nukeAllAPIGateways(session *session.Session, identifiers []*string) error {
 	errChans := make([]chan error, len(identifiers))
 	for i, apigwID := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteApiGatewayAsync(wg, errChans[i], svc, apigwID, region)
+		go deleteApiGatewayAsync(ctx, wg, errChans[i], svc, apigwID, region)
 	}
 	wg.Wait()
 
@@ -95,11 +98,11 @@ func nukeAllAPIGateways(session *session.Session, identifiers []*string) error {
 	return nil
 }
 
-func deleteApiGatewayAsync(wg *sync.WaitGroup, errChan chan error, svc *apigateway.APIGateway, apigwID *string, region string) {
+func deleteApiGatewayAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc apigatewayiface.APIGatewayAPI, apigwID *string, region string) {
 	defer wg.Done()
 
 	input := &apigateway.DeleteRestApiInput{RestApiId: apigwID}
-	_, err := svc.DeleteRestApi(input)
+	_, err := svc.DeleteRestApiWithContext(ctx, input)
 	errChan <- err
 
 	// Record status of this resource
@@ -108,7 +111,7 @@ func deleteApiGatewayAsync(wg *sync.WaitGroup, errChan chan error, svc *apigatew
 		ResourceType: "APIGateway (v1)",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	if err == nil {
 		logging.Logger.Debugf("[OK] API Gateway (v1) %s deleted in %s", aws.StringValue(apigwID), region)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -60,7 +61,7 @@ func TestListAPIGateways(t *testing.T) {
 	testGw, createTestGwErr := createTestAPIGateway(t, session, apigwName)
 	require.NoError(t, createTestGwErr)
 	// clean up after this test
-	defer nukeAllAPIGateways(session, []*string{testGw.ID})
+	defer nukeAllAPIGateways(context.Background(), session, []*string{testGw.ID})
 
 	apigwIds, err := getAllAPIGateways(session, time.Now(), config.Config{})
 	if err != nil {
@@ -84,7 +85,7 @@ func TestTimeFilterExclusionNewlyCreatedAPIGateway(t *testing.T) {
 
 	testGw, createTestGwErr := createTestAPIGateway(t, session, apigwName)
 	require.NoError(t, createTestGwErr)
-	defer nukeAllAPIGateways(session, []*string{testGw.ID})
+	defer nukeAllAPIGateways(context.Background(), session, []*string{testGw.ID})
 
 	// Assert API Gateway is picked up without filters
 	apigwIds, err := getAllAPIGateways(session, time.Now(), config.Config{})
@@ -113,7 +114,7 @@ func TestNukeAPIGatewayOne(t *testing.T) {
 	testGw, createTestErr := createTestAPIGateway(t, session, apigwName)
 	require.NoError(t, createTestErr)
 
-	nukeErr := nukeAllAPIGateways(session, []*string{testGw.ID})
+	nukeErr := nukeAllAPIGateways(context.Background(), session, []*string{testGw.ID})
 	require.NoError(t, nukeErr)
 
 	// Make sure the API Gateway was deleted
@@ -141,7 +142,7 @@ func TestNukeAPIGatewayMoreThanOne(t *testing.T) {
 	testGw2, createTestErr2 := createTestAPIGateway(t, session, apigwName2)
 	require.NoError(t, createTestErr2)
 
-	nukeErr := nukeAllAPIGateways(session, []*string{testGw.ID, testGw2.ID})
+	nukeErr := nukeAllAPIGateways(context.Background(), session, []*string{testGw.ID, testGw2.ID})
 	require.NoError(t, nukeErr)
 
 	// Make sure the API Gateway was deleted
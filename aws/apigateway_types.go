@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -22,8 +24,8 @@ func (apigateway ApiGateway) MaxBatchSize() int {
 	return 10
 }
 
-func (apigateway ApiGateway) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllAPIGateways(session, awsgo.StringSlice(identifiers)); err != nil {
+func (apigateway ApiGateway) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllAPIGateways(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
@@ -34,3 +36,15 @@ type TooManyApiGatewayErr struct{}
 func (err TooManyApiGatewayErr) Error() string {
 	return "Too many Api Gateways requested at once."
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ApiGateway{}.ResourceName(),
+		Description: "API Gateways (v1)",
+		ConfigKey:   "APIGateway",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllAPIGateways(session, params.ExcludeAfter, params.Config)
+			return ApiGateway{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
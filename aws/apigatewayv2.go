@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2/apigatewayv2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -17,17 +19,23 @@ import (
 )
 
 func getAllAPIGatewaysV2(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := apigatewayv2.New(session)
+	svc := newAPIGatewayV2Client(session)
 
-	output, err := svc.GetApis(&apigatewayv2.GetApisInput{})
-	if err != nil {
-		return []*string{}, errors.WithStackTrace(err)
-	}
 	Ids := []*string{}
-	for _, restapi := range output.Items {
-		if shouldIncludeAPIGatewayV2(restapi, excludeAfter, configObj) {
-			Ids = append(Ids, restapi.ApiId)
+	err := paginate(func(token *string) (*string, error) {
+		output, err := svc.GetApis(&apigatewayv2.GetApisInput{NextToken: token})
+		if err != nil {
+			return nil, err
 		}
+		for _, restapi := range output.Items {
+			if shouldIncludeAPIGatewayV2(restapi, excludeAfter, configObj) {
+				Ids = append(Ids, restapi.ApiId)
+			}
+		}
+		return output.NextToken, nil
+	})
+	if err != nil {
+		return []*string{}, err
 	}
 
 	return Ids, nil
@@ -44,17 +52,16 @@ func shouldIncludeAPIGatewayV2(api *apigatewayv2.Api, excludeAfter time.Time, co
 		}
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(api.Name),
-		configObj.APIGateway.IncludeRule.NamesRegExp,
-		configObj.APIGateway.ExcludeRule.NamesRegExp,
-	)
+	return configObj.APIGatewayV2.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(api.Name),
+		ID:   aws.StringValue(api.ApiId),
+	})
 }
 
-func nukeAllAPIGatewaysV2(session *session.Session, identifiers []*string) error {
+func nukeAllAPIGatewaysV2(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := apigatewayv2.New(session)
+	svc := newAPIGatewayV2Client(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No API Gateways (v2) to nuke in region %s", region)
@@ -72,7 +79,7 @@ func nukeAllAPIGatewaysV2(session *session.Session, identifiers []*string) error
 	errChans := make([]chan error, len(identifiers))
 	for i, apigwID := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteApiGatewayAsyncV2(wg, errChans[i], svc, apigwID, region)
+		go deleteApiGatewayAsyncV2(ctx, wg, errChans[i], svc, apigwID, region)
 	}
 	wg.Wait()
 
@@ -95,11 +102,11 @@ func nukeAllAPIGatewaysV2(session *session.Session, identifiers []*string) error
 	return nil
 }
 
-func deleteApiGatewayAsyncV2(wg *sync.WaitGroup, errChan chan error, svc *apigatewayv2.ApiGatewayV2, apiId *string, region string) {
+func deleteApiGatewayAsyncV2(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc apigatewayv2iface.ApiGatewayV2API, apiId *string, region string) {
 	defer wg.Done()
 
 	input := &apigatewayv2.DeleteApiInput{ApiId: apiId}
-	_, err := svc.DeleteApi(input)
+	_, err := svc.DeleteApiWithContext(ctx, input)
 	errChan <- err
 
 	// Record status of this resource
@@ -108,7 +115,7 @@ func deleteApiGatewayAsyncV2(wg *sync.WaitGroup, errChan chan error, svc *apigat
 		ResourceType: "APIGateway (v2)",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	if err == nil {
 		logging.Logger.Debugf("[OK] API Gateway (v2) %s deleted in %s", aws.StringValue(apiId), region)
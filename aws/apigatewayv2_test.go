@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"regexp"
 	"testing"
 	"time"
 
@@ -60,7 +62,7 @@ func TestListAPIGatewaysV2(t *testing.T) {
 	testGw, createTestGwErr := createTestAPIGatewayV2(t, session, apigwName)
 	require.NoError(t, createTestGwErr)
 	// clean up after this test
-	defer nukeAllAPIGatewaysV2(session, []*string{testGw.ID})
+	defer nukeAllAPIGatewaysV2(context.Background(), session, []*string{testGw.ID})
 
 	apigwIds, err := getAllAPIGatewaysV2(session, time.Now(), config.Config{})
 	if err != nil {
@@ -83,7 +85,7 @@ func TestTimeFilterExclusionNewlyCreatedAPIGatewayV2(t *testing.T) {
 
 	testGw, createTestGwErr := createTestAPIGatewayV2(t, session, apigwName)
 	require.NoError(t, createTestGwErr)
-	defer nukeAllAPIGatewaysV2(session, []*string{testGw.ID})
+	defer nukeAllAPIGatewaysV2(context.Background(), session, []*string{testGw.ID})
 
 	// Assert API Gateway is picked up without filters
 	apigwIds, err := getAllAPIGatewaysV2(session, time.Now(), config.Config{})
@@ -97,6 +99,25 @@ func TestTimeFilterExclusionNewlyCreatedAPIGatewayV2(t *testing.T) {
 	assert.NotContains(t, aws.StringValueSlice(apiGwIdsOlder), aws.StringValue(testGw.ID))
 }
 
+func TestShouldIncludeAPIGatewayV2(t *testing.T) {
+	t.Parallel()
+
+	excludeRule := config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile("^keep-")}}}
+	configObj := config.Config{
+		APIGatewayV2: config.ResourceType{ExcludeRule: excludeRule},
+		// The rules of API Gateways (v1) do not apply to API Gateways (v2)
+		APIGateway: config.ResourceType{ExcludeRule: config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile(".*")}}}},
+	}
+
+	excludeAfter := time.Now()
+	createdAt := excludeAfter.Add(-time.Hour)
+	api := func(name string) *apigatewayv2.Api {
+		return &apigatewayv2.Api{ApiId: aws.String(name + "-id"), Name: aws.String(name), CreatedDate: &createdAt}
+	}
+	assert.True(t, shouldIncludeAPIGatewayV2(api("nuke-me"), excludeAfter, configObj))
+	assert.False(t, shouldIncludeAPIGatewayV2(api("keep-me"), excludeAfter, configObj))
+}
+
 func TestNukeAPIGatewayV2One(t *testing.T) {
 	t.Parallel()
 
@@ -111,7 +132,7 @@ func TestNukeAPIGatewayV2One(t *testing.T) {
 	testGw, createTestErr := createTestAPIGatewayV2(t, session, apigwName)
 	require.NoError(t, createTestErr)
 
-	nukeErr := nukeAllAPIGatewaysV2(session, []*string{testGw.ID})
+	nukeErr := nukeAllAPIGatewaysV2(context.Background(), session, []*string{testGw.ID})
 	require.NoError(t, nukeErr)
 
 	// Make sure the API Gateway was deleted
@@ -138,7 +159,7 @@ func TestNukeAPIGatewayV2MoreThanOne(t *testing.T) {
 	testGw2, createTestErr2 := createTestAPIGatewayV2(t, session, apigwName2)
 	require.NoError(t, createTestErr2)
 
-	nukeErr := nukeAllAPIGatewaysV2(session, []*string{testGw.ID, testGw2.ID})
+	nukeErr := nukeAllAPIGatewaysV2(context.Background(), session, []*string{testGw.ID, testGw2.ID})
 	require.NoError(t, nukeErr)
 
 	// Make sure the API Gateway was deleted
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -22,8 +24,8 @@ func (apigateway ApiGatewayV2) MaxBatchSize() int {
 	return 10
 }
 
-func (apigateway ApiGatewayV2) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllAPIGatewaysV2(session, awsgo.StringSlice(identifiers)); err != nil {
+func (apigateway ApiGatewayV2) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllAPIGatewaysV2(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -35,3 +37,15 @@ type TooManyApiGatewayV2Err struct{}
 func (err TooManyApiGatewayV2Err) Error() string {
 	return "Too many Api Gateways requested at once."
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ApiGatewayV2{}.ResourceName(),
+		Description: "API Gateways (v2)",
+		ConfigKey:   "APIGatewayV2",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllAPIGatewaysV2(session, params.ExcludeAfter, params.Config)
+			return ApiGatewayV2{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
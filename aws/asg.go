@@ -1,13 +1,16 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strconv"
 	"time"
 
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -16,17 +19,23 @@ import (
 
 // Returns a formatted string of ASG Names
 func getAllAutoScalingGroups(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := autoscaling.New(session)
-	result, err := svc.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{})
-	if err != nil {
-		return nil, errors.WithStackTrace(err)
-	}
-
+	svc := newAutoScalingClient(session)
 	var groupNames []*string
-	for _, group := range result.AutoScalingGroups {
-		if shouldIncludeAutoScalingGroup(group, excludeAfter, configObj) {
-			groupNames = append(groupNames, group.AutoScalingGroupName)
+	err := svc.DescribeAutoScalingGroupsPages(&autoscaling.DescribeAutoScalingGroupsInput{}, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+		for _, group := range page.AutoScalingGroups {
+			tags := autoScalingGroupTagsToMap(group.Tags)
+			if excludedByTag(ASGroups{}.ResourceName(), region, awsgo.StringValue(group.AutoScalingGroupName), tags) ||
+				excludedByQuarantine(ASGroups{}.ResourceName(), region, awsgo.StringValue(group.AutoScalingGroupName), tags, excludeAfter) {
+				continue
+			}
+			if shouldIncludeAutoScalingGroup(group, excludeAfter, configObj) {
+				groupNames = append(groupNames, group.AutoScalingGroupName)
+			}
 		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	return groupNames, nil
@@ -41,16 +50,16 @@ func shouldIncludeAutoScalingGroup(group *autoscaling.Group, excludeAfter time.T
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(group.AutoScalingGroupName),
-		configObj.AutoScalingGroup.IncludeRule.NamesRegExp,
-		configObj.AutoScalingGroup.ExcludeRule.NamesRegExp,
-	)
+	return configObj.AutoScalingGroup.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(group.AutoScalingGroupName),
+		Tags: autoScalingGroupTagsToMap(group.Tags),
+		Time: awsgo.TimeValue(group.CreatedTime),
+	})
 }
 
 // Deletes all Auto Scaling Groups
-func nukeAllAutoScalingGroups(session *session.Session, groupNames []*string) error {
-	svc := autoscaling.New(session)
+func nukeAllAutoScalingGroups(ctx context.Context, session *session.Session, groupNames []*string) error {
+	svc := newAutoScalingClient(session)
 
 	if len(groupNames) == 0 {
 		logging.Logger.Debugf("No Auto Scaling Groups to nuke in region %s", *session.Config.Region)
@@ -74,7 +83,7 @@ func nukeAllAutoScalingGroups(session *session.Session, groupNames []*string) er
 			ResourceType: "Auto-Scaling Group",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -90,7 +99,7 @@ func nukeAllAutoScalingGroups(session *session.Session, groupNames []*string) er
 	}
 
 	if len(deletedGroupNames) > 0 {
-		err := svc.WaitUntilGroupNotExists(&autoscaling.DescribeAutoScalingGroupsInput{
+		err := svc.WaitUntilGroupNotExistsWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
 			AutoScalingGroupNames: deletedGroupNames,
 		})
 		if err != nil {
@@ -107,3 +116,107 @@ func nukeAllAutoScalingGroups(session *session.Session, groupNames []*string) er
 	logging.Logger.Debugf("[OK] %d Auto Scaling Group(s) deleted in %s", len(deletedGroupNames), *session.Config.Region)
 	return nil
 }
+
+// autoScalingGroupTagsToMap converts the tags of an Auto Scaling Group into a map of tag keys to values, as used by
+// config rules
+func autoScalingGroupTagsToMap(tags []*autoscaling.TagDescription) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[awsgo.StringValue(tag.Key)] = awsgo.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+// quarantineAutoScalingGroups tags the given Auto Scaling Groups with the quarantine tag and scales them down to zero
+// instances, so that their owners can scale them up again if they still need them
+func quarantineAutoScalingGroups(ctx context.Context, session *session.Session, groupNames []*string) error {
+	svc := newAutoScalingClient(session)
+
+	tagsByGroup := map[string]map[string]string{}
+	capacityByGroup := map[string]map[string]string{}
+	err := svc.DescribeAutoScalingGroupsPagesWithContext(ctx, &autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: groupNames}, func(page *autoscaling.DescribeAutoScalingGroupsOutput, lastPage bool) bool {
+		for _, group := range page.AutoScalingGroups {
+			tagsByGroup[awsgo.StringValue(group.AutoScalingGroupName)] = autoScalingGroupTagsToMap(group.Tags)
+			capacityByGroup[awsgo.StringValue(group.AutoScalingGroupName)] = map[string]string{
+				metadataMinSize:      strconv.FormatInt(awsgo.Int64Value(group.MinSize), 10),
+				metadataMaxSize:      strconv.FormatInt(awsgo.Int64Value(group.MaxSize), 10),
+				metadataDesiredCount: strconv.FormatInt(awsgo.Int64Value(group.DesiredCapacity), 10),
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	for _, groupName := range groupNames {
+		err := quarantineAutoScalingGroup(ctx, svc, groupName, tagsByGroup[awsgo.StringValue(groupName)])
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined Auto Scaling Group: %s", awsgo.StringValue(groupName))
+		}
+		recordQuarantine(ctx, ASGroups{}.ResourceName(), awsgo.StringValue(groupName), capacityByGroup[awsgo.StringValue(groupName)], err)
+	}
+	return nil
+}
+
+func quarantineAutoScalingGroup(ctx context.Context, svc autoscalingiface.AutoScalingAPI, groupName *string, tags map[string]string) error {
+	_, err := svc.CreateOrUpdateTagsWithContext(ctx, &autoscaling.CreateOrUpdateTagsInput{
+		Tags: []*autoscaling.Tag{{
+			ResourceId:        groupName,
+			ResourceType:      awsgo.String("auto-scaling-group"),
+			Key:               awsgo.String(QuarantineTagKey),
+			Value:             awsgo.String(quarantineTagValue(tags)),
+			PropagateAtLaunch: awsgo.Bool(false),
+		}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UpdateAutoScalingGroupWithContext(ctx, &autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: groupName,
+		MinSize:              awsgo.Int64(0),
+		MaxSize:              awsgo.Int64(0),
+		DesiredCapacity:      awsgo.Int64(0),
+	})
+	return errors.WithStackTrace(err)
+}
+
+// undoAutoScalingGroupQuarantine gives a quarantined Auto Scaling Group its capacity back, and removes its quarantine
+// tag
+func undoAutoScalingGroupQuarantine(session *session.Session, resource RunReportResource) error {
+	svc := newAutoScalingClient(session)
+	groupName := awsgo.String(resource.Identifier)
+
+	minSize, err := metadataInt64(resource, metadataMinSize)
+	if err != nil {
+		return err
+	}
+	maxSize, err := metadataInt64(resource, metadataMaxSize)
+	if err != nil {
+		return err
+	}
+	desiredCapacity, err := metadataInt64(resource, metadataDesiredCount)
+	if err != nil {
+		return err
+	}
+
+	_, err = svc.UpdateAutoScalingGroup(&autoscaling.UpdateAutoScalingGroupInput{
+		AutoScalingGroupName: groupName,
+		MinSize:              awsgo.Int64(minSize),
+		MaxSize:              awsgo.Int64(maxSize),
+		DesiredCapacity:      awsgo.Int64(desiredCapacity),
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.DeleteTags(&autoscaling.DeleteTagsInput{
+		Tags: []*autoscaling.Tag{{
+			ResourceId:   groupName,
+			ResourceType: awsgo.String("auto-scaling-group"),
+			Key:          awsgo.String(QuarantineTagKey),
+		}},
+	})
+	return errors.WithStackTrace(err)
+}
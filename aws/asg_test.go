@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -60,8 +61,8 @@ func TestListAutoScalingGroups(t *testing.T) {
 	uniqueTestID := "cloud-nuke-test-" + util.UniqueID()
 	createTestAutoScalingGroup(t, session, uniqueTestID)
 	// clean up after this test
-	defer nukeAllAutoScalingGroups(session, []*string{&uniqueTestID})
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllAutoScalingGroups(context.Background(), session, []*string{&uniqueTestID})
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
 	groupNames, err := getAllAutoScalingGroups(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -99,7 +100,7 @@ func TestNukeAutoScalingGroups(t *testing.T) {
 	createTestAutoScalingGroup(t, session, uniqueTestID)
 
 	// clean up ec2 instance created by the above call
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
 	_, err = svc.DescribeAutoScalingGroups(&autoscaling.DescribeAutoScalingGroupsInput{
 		AutoScalingGroupNames: []*string{&uniqueTestID},
@@ -109,7 +110,7 @@ func TestNukeAutoScalingGroups(t *testing.T) {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	if err := nukeAllAutoScalingGroups(session, []*string{&uniqueTestID}); err != nil {
+	if err := nukeAllAutoScalingGroups(context.Background(), session, []*string{&uniqueTestID}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
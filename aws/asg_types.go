@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,35 @@ func (group ASGroups) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (group ASGroups) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllAutoScalingGroups(session, awsgo.StringSlice(identifiers)); err != nil {
+func (group ASGroups) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllAutoScalingGroups(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+// Quarantine - scale the auto scaling groups down to zero instances, so that their owners can scale them up again
+func (group ASGroups) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineAutoScalingGroups(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:               ASGroups{}.ResourceName(),
+		Description:        "Auto-Scaling Groups",
+		ConfigKey:          "AutoScalingGroup",
+		SupportsTags:       true,
+		SupportsRuleAge:    true,
+		SupportsQuarantine: true,
+		UndoQuarantine:     undoAutoScalingGroupQuarantine,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllAutoScalingGroups(session, params.Region, params.ExcludeAfter, params.Config)
+			return ASGroups{GroupNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,271 @@
+package aws
+
+import (
+	"context"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer/accessanalyzeriface"
+	"github.com/aws/aws-sdk-go/service/acmpca"
+	"github.com/aws/aws-sdk-go/service/acmpca/acmpcaiface"
+	"github.com/aws/aws-sdk-go/service/apigateway"
+	"github.com/aws/aws-sdk-go/service/apigateway/apigatewayiface"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go/service/apigatewayv2/apigatewayv2iface"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/aws/aws-sdk-go/service/autoscaling/autoscalingiface"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/cloudtrail/cloudtrailiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/configservice/configserviceiface"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
+	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
+	"github.com/aws/aws-sdk-go/service/guardduty"
+	"github.com/aws/aws-sdk-go/service/guardduty/guarddutyiface"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
+	"github.com/aws/aws-sdk-go/service/macie2"
+	"github.com/aws/aws-sdk-go/service/macie2/macie2iface"
+	"github.com/aws/aws-sdk-go/service/opensearchservice"
+	"github.com/aws/aws-sdk-go/service/opensearchservice/opensearchserviceiface"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/sagemaker"
+	"github.com/aws/aws-sdk-go/service/sagemaker/sagemakeriface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/tnn-gruntwork-io/cloud-nuke/throttling"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// The functions below create the AWS service clients that resource types use to discover and nuke resources. Listers
+// and nukers never create clients themselves, so that tests can replace these functions with in-process fakes and
+// exercise discovery filters and deletion flows without an AWS account.
+var (
+	newAccessAnalyzerClient = func(session *session.Session) accessanalyzeriface.AccessAnalyzerAPI {
+		return accessanalyzer.New(session)
+	}
+	newACMPCAClient = func(session *session.Session) acmpcaiface.ACMPCAAPI {
+		return acmpca.New(session)
+	}
+	newAPIGatewayClient = func(session *session.Session) apigatewayiface.APIGatewayAPI {
+		return apigateway.New(session)
+	}
+	newAPIGatewayV2Client = func(session *session.Session) apigatewayv2iface.ApiGatewayV2API {
+		return apigatewayv2.New(session)
+	}
+	newAutoScalingClient = func(session *session.Session) autoscalingiface.AutoScalingAPI {
+		return autoscaling.New(session)
+	}
+	newCloudTrailClient = func(session *session.Session) cloudtrailiface.CloudTrailAPI {
+		return cloudtrail.New(session)
+	}
+	newCloudWatchClient = func(session *session.Session) cloudwatchiface.CloudWatchAPI {
+		return cloudwatch.New(session)
+	}
+	newCloudWatchLogsClient = func(session *session.Session) cloudwatchlogsiface.CloudWatchLogsAPI {
+		return cloudwatchlogs.New(session)
+	}
+	newConfigServiceClient = func(session *session.Session) configserviceiface.ConfigServiceAPI {
+		return configservice.New(session)
+	}
+	newDynamoDBClient = func(session *session.Session) dynamodbiface.DynamoDBAPI {
+		return dynamodb.New(session)
+	}
+	newEC2Client = func(session *session.Session) ec2iface.EC2API {
+		return ec2.New(session)
+	}
+	newECRClient = func(session *session.Session) ecriface.ECRAPI {
+		return ecr.New(session)
+	}
+	newECSClient = func(session *session.Session) ecsiface.ECSAPI {
+		return ecs.New(session)
+	}
+	newEKSClient = func(session *session.Session) eksiface.EKSAPI {
+		return eks.New(session)
+	}
+	newElastiCacheClient = func(session *session.Session) elasticacheiface.ElastiCacheAPI {
+		return elasticache.New(session)
+	}
+	newELBClient = func(session *session.Session) elbiface.ELBAPI {
+		return elb.New(session)
+	}
+	newELBV2Client = func(session *session.Session) elbv2iface.ELBV2API {
+		return elbv2.New(session)
+	}
+	newGuardDutyClient = func(session *session.Session) guarddutyiface.GuardDutyAPI {
+		return guardduty.New(session)
+	}
+	newIAMClient = func(session *session.Session) iamiface.IAMAPI {
+		return iam.New(session)
+	}
+	newKMSClient = func(session *session.Session) kmsiface.KMSAPI {
+		return kms.New(session)
+	}
+	newLambdaClient = func(session *session.Session) lambdaiface.LambdaAPI {
+		return lambda.New(session)
+	}
+	newMacie2Client = func(session *session.Session) macie2iface.Macie2API {
+		return macie2.New(session)
+	}
+	newOpenSearchClient = func(session *session.Session) opensearchserviceiface.OpenSearchServiceAPI {
+		return opensearchservice.New(session)
+	}
+	newOrganizationsClient = func(session *session.Session) organizationsiface.OrganizationsAPI {
+		return organizations.New(session)
+	}
+	newRDSClient = func(session *session.Session) rdsiface.RDSAPI {
+		return rds.New(session)
+	}
+	newS3Client = func(session *session.Session) s3iface.S3API {
+		return s3.New(session)
+	}
+	newSageMakerClient = func(session *session.Session) sagemakeriface.SageMakerAPI {
+		return sagemaker.New(session)
+	}
+	newSecretsManagerClient = func(session *session.Session) secretsmanageriface.SecretsManagerAPI {
+		return secretsmanager.New(session)
+	}
+	newSQSClient = func(session *session.Session) sqsiface.SQSAPI {
+		return sqs.New(session)
+	}
+	newSTSClient = func(session *session.Session) stsiface.STSAPI {
+		return sts.New(session)
+	}
+	// newAssumeRoleCredentials returns the credentials of the role with the given ARN, assumed with the credentials of
+	// the given session. They are refreshed automatically before they expire.
+	newAssumeRoleCredentials = func(session *session.Session, roleArn string) *credentials.Credentials {
+		return stscreds.NewCredentials(session, roleArn, func(provider *stscreds.AssumeRoleProvider) {
+			provider.RoleSessionName = "cloud-nuke"
+		})
+	}
+
+	// The resource types below use version 2 of the AWS SDK, which has no client interfaces. Their clients are
+	// narrowed down to the operations cloud-nuke calls.
+	newEFSClient = func(session *session.Session) (efsAPI, error) {
+		cfg, err := loadV2Config(session)
+		if err != nil {
+			return nil, err
+		}
+		return efs.NewFromConfig(cfg), nil
+	}
+	newKinesisClient = func(session *session.Session) (kinesisAPI, error) {
+		cfg, err := loadV2Config(session)
+		if err != nil {
+			return nil, err
+		}
+		return kinesis.NewFromConfig(cfg), nil
+	}
+	newSNSClient = func(session *session.Session) (snsAPI, error) {
+		cfg, err := loadV2Config(session)
+		if err != nil {
+			return nil, err
+		}
+		return sns.NewFromConfig(cfg), nil
+	}
+)
+
+// loadV2Config loads the configuration of a version 2 client for the region of the given session. Version 2 clients
+// cannot use the retryer of throttling.Configure, so they use the adaptive retry mode of the SDK instead, which also
+// retries throttled requests with backoff and jitter, and slows down once AWS starts throttling them. They use the
+// credentials of the session, so that they act on the same account as the version 1 clients, e.g. when cloud-nuke
+// assumes a role in another account.
+func loadV2Config(session *session.Session) (awsv2.Config, error) {
+	return awsconfig.LoadDefaultConfig(
+		context.TODO(),
+		awsconfig.WithRegion(awsgo.StringValue(session.Config.Region)),
+		awsconfig.WithCredentialsProvider(v1CredentialsProvider{credentials: session.Config.Credentials}),
+		awsconfig.WithRetryer(func() awsv2.Retryer {
+			return retry.NewAdaptiveMode(func(options *retry.AdaptiveModeOptions) {
+				options.StandardOptions = append(options.StandardOptions, func(options *retry.StandardOptions) {
+					options.MaxAttempts = throttling.MaxRetries + 1
+				})
+			})
+		}),
+	)
+}
+
+// v1CredentialsProvider makes the credentials of a version 1 session available to version 2 clients
+type v1CredentialsProvider struct {
+	credentials *credentials.Credentials
+}
+
+func (provider v1CredentialsProvider) Retrieve(ctx context.Context) (awsv2.Credentials, error) {
+	value, err := provider.credentials.GetWithContext(ctx)
+	if err != nil {
+		return awsv2.Credentials{}, errors.WithStackTrace(err)
+	}
+	v2Credentials := awsv2.Credentials{
+		AccessKeyID:     value.AccessKeyID,
+		SecretAccessKey: value.SecretAccessKey,
+		SessionToken:    value.SessionToken,
+		Source:          value.ProviderName,
+	}
+	if expires, err := provider.credentials.ExpiresAt(); err == nil {
+		v2Credentials.CanExpire = true
+		v2Credentials.Expires = expires
+	}
+	return v2Credentials, nil
+}
+
+// efsAPI is the part of the EFS client used to nuke Elastic File Systems
+type efsAPI interface {
+	DescribeFileSystems(ctx context.Context, params *efs.DescribeFileSystemsInput, optFns ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error)
+	DescribeAccessPoints(ctx context.Context, params *efs.DescribeAccessPointsInput, optFns ...func(*efs.Options)) (*efs.DescribeAccessPointsOutput, error)
+	DeleteAccessPoint(ctx context.Context, params *efs.DeleteAccessPointInput, optFns ...func(*efs.Options)) (*efs.DeleteAccessPointOutput, error)
+	DescribeMountTargets(ctx context.Context, params *efs.DescribeMountTargetsInput, optFns ...func(*efs.Options)) (*efs.DescribeMountTargetsOutput, error)
+	DeleteMountTarget(ctx context.Context, params *efs.DeleteMountTargetInput, optFns ...func(*efs.Options)) (*efs.DeleteMountTargetOutput, error)
+	DeleteFileSystem(ctx context.Context, params *efs.DeleteFileSystemInput, optFns ...func(*efs.Options)) (*efs.DeleteFileSystemOutput, error)
+}
+
+// kinesisAPI is the part of the Kinesis client used to nuke Kinesis streams
+type kinesisAPI interface {
+	kinesis.ListStreamsAPIClient
+	DeleteStream(ctx context.Context, params *kinesis.DeleteStreamInput, optFns ...func(*kinesis.Options)) (*kinesis.DeleteStreamOutput, error)
+}
+
+// snsAPI is the part of the SNS client used to nuke SNS topics
+type snsAPI interface {
+	sns.ListTopicsAPIClient
+	ListTagsForResource(ctx context.Context, params *sns.ListTagsForResourceInput, optFns ...func(*sns.Options)) (*sns.ListTagsForResourceOutput, error)
+	DeleteTopic(ctx context.Context, params *sns.DeleteTopicInput, optFns ...func(*sns.Options)) (*sns.DeleteTopicOutput, error)
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -15,7 +16,7 @@ import (
 )
 
 func getAllCloudtrailTrails(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := cloudtrail.New(session)
+	svc := newCloudTrailClient(session)
 
 	param := &cloudtrail.ListTrailsInput{}
 
@@ -43,15 +44,14 @@ func shouldIncludeCloudtrailTrail(trail *cloudtrail.TrailInfo, configObj config.
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(trail.Name),
-		configObj.CloudtrailTrail.IncludeRule.NamesRegExp,
-		configObj.CloudtrailTrail.ExcludeRule.NamesRegExp,
-	)
+	return configObj.CloudtrailTrail.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(trail.Name),
+		ID:   aws.StringValue(trail.TrailARN),
+	})
 }
 
-func nukeAllCloudTrailTrails(session *session.Session, arns []*string) error {
-	svc := cloudtrail.New(session)
+func nukeAllCloudTrailTrails(ctx context.Context, session *session.Session, arns []*string) error {
+	svc := newCloudTrailClient(session)
 
 	if len(arns) == 0 {
 		logging.Logger.Debugf("No Cloudtrail Trails to nuke in region %s", *session.Config.Region)
@@ -66,7 +66,7 @@ func nukeAllCloudTrailTrails(session *session.Session, arns []*string) error {
 			Name: arn,
 		}
 
-		_, err := svc.DeleteTrail(params)
+		_, err := svc.DeleteTrailWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -74,7 +74,7 @@ func nukeAllCloudTrailTrails(session *session.Session, arns []*string) error {
 			ResourceType: "Cloudtrail Trail",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -171,7 +172,7 @@ func TestNukeCloudTrailOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudTrailTrails(session, identifiers),
+		nukeAllCloudTrailTrails(context.Background(), session, identifiers),
 	)
 
 	assertCloudTrailTrailsDeleted(t, region, identifiers)
@@ -197,7 +198,7 @@ func TestNukeCloudTrailTrailMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudTrailTrails(session, trailArns),
+		nukeAllCloudTrailTrails(context.Background(), session, trailArns),
 	)
 
 	assertCloudTrailTrailsDeleted(t, region, trailArns)
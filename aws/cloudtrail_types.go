@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -26,10 +28,22 @@ func (ct CloudtrailTrail) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (ct CloudtrailTrail) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllCloudTrailTrails(session, awsgo.StringSlice(identifiers)); err != nil {
+func (ct CloudtrailTrail) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllCloudTrailTrails(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        CloudtrailTrail{}.ResourceName(),
+		Description: "Cloudtrail Trails",
+		ConfigKey:   "CloudtrailTrail",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllCloudtrailTrails(session, params.ExcludeAfter, params.Config)
+			return CloudtrailTrail{Arns: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
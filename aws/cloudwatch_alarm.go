@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -15,7 +16,7 @@ import (
 )
 
 func getAllCloudWatchAlarms(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := cloudwatch.New(session)
+	svc := newCloudWatchClient(session)
 
 	allAlarms := []*string{}
 	input := &cloudwatch.DescribeAlarmsInput{
@@ -49,11 +50,7 @@ func shouldIncludeCloudWatchCompositeAlarm(alarm *cloudwatch.CompositeAlarm, exc
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(alarm.AlarmName),
-		configObj.CloudWatchAlarm.IncludeRule.NamesRegExp,
-		configObj.CloudWatchAlarm.ExcludeRule.NamesRegExp,
-	)
+	return configObj.CloudWatchAlarm.ShouldInclude(config.ResourceValue{Name: aws.StringValue(alarm.AlarmName)})
 }
 
 func shouldIncludeCloudWatchMetricAlarm(alarm *cloudwatch.MetricAlarm, excludeAfter time.Time, configObj config.Config) bool {
@@ -65,17 +62,13 @@ func shouldIncludeCloudWatchMetricAlarm(alarm *cloudwatch.MetricAlarm, excludeAf
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(alarm.AlarmName),
-		configObj.CloudWatchAlarm.IncludeRule.NamesRegExp,
-		configObj.CloudWatchAlarm.ExcludeRule.NamesRegExp,
-	)
+	return configObj.CloudWatchAlarm.ShouldInclude(config.ResourceValue{Name: aws.StringValue(alarm.AlarmName)})
 }
 
-func nukeAllCloudWatchAlarms(session *session.Session, identifiers []*string) error {
+func nukeAllCloudWatchAlarms(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := cloudwatch.New(session)
+	svc := newCloudWatchClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No CloudWatch Alarms to nuke in region %s", region)
@@ -94,7 +87,7 @@ func nukeAllCloudWatchAlarms(session *session.Session, identifiers []*string) er
 	logging.Logger.Debugf("Deleting CloudWatch Alarms in region %s", region)
 
 	// If the alarm's type is composite alarm, remove the dependency by removing the rule.
-	alarms, err := svc.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
+	alarms, err := svc.DescribeAlarmsWithContext(ctx, &cloudwatch.DescribeAlarmsInput{
 		AlarmTypes: aws.StringSlice([]string{cloudwatch.AlarmTypeMetricAlarm, cloudwatch.AlarmTypeCompositeAlarm}),
 		AlarmNames: identifiers,
 	})
@@ -108,7 +101,7 @@ func nukeAllCloudWatchAlarms(session *session.Session, identifiers []*string) er
 	}
 
 	for _, compositeAlarm := range alarms.CompositeAlarms {
-		_, err := svc.PutCompositeAlarm(&cloudwatch.PutCompositeAlarmInput{
+		_, err := svc.PutCompositeAlarmWithContext(ctx, &cloudwatch.PutCompositeAlarmInput{
 			AlarmName: compositeAlarm.AlarmName,
 			AlarmRule: aws.String("FALSE"),
 		})
@@ -123,7 +116,7 @@ func nukeAllCloudWatchAlarms(session *session.Session, identifiers []*string) er
 	}
 
 	input := cloudwatch.DeleteAlarmsInput{AlarmNames: identifiers}
-	_, err = svc.DeleteAlarms(&input)
+	_, err = svc.DeleteAlarmsWithContext(ctx, &input)
 
 	// Record status of this resource
 	e := report.BatchEntry{
@@ -131,7 +124,7 @@ func nukeAllCloudWatchAlarms(session *session.Session, identifiers []*string) er
 		ResourceType: "CloudWatch Alarm",
 		Error:        err,
 	}
-	report.RecordBatch(e)
+	recordBatch(ctx, e)
 
 	if err != nil {
 		logging.Logger.Debugf("[Failed] %s", err)
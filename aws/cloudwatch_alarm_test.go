@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -80,7 +81,7 @@ func TestNukeCloudWatchAlarmOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchAlarms(session, identifiers),
+		nukeAllCloudWatchAlarms(context.Background(), session, identifiers),
 	)
 
 	// Make sure the CloudWatch Alar m is deleted.
@@ -108,7 +109,7 @@ func TestNukeCloudWatchAlarmsMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchAlarms(session, cwalNames),
+		nukeAllCloudWatchAlarms(context.Background(), session, cwalNames),
 	)
 
 	// Make sure the CloudWatch Alarm is deleted.
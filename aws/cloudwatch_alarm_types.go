@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -26,10 +28,22 @@ func (cwal CloudWatchAlarms) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (cwal CloudWatchAlarms) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllCloudWatchAlarms(session, awsgo.StringSlice(identifiers)); err != nil {
+func (cwal CloudWatchAlarms) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllCloudWatchAlarms(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        CloudWatchAlarms{}.ResourceName(),
+		Description: "CloudWatch Alarms",
+		ConfigKey:   "CloudWatchAlarm",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllCloudWatchAlarms(session, params.ExcludeAfter, params.Config)
+			return CloudWatchAlarms{AlarmNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
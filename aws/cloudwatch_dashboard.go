@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -16,7 +17,7 @@ import (
 )
 
 func getAllCloudWatchDashboards(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := cloudwatch.New(session)
+	svc := newCloudWatchClient(session)
 
 	allDashboards := []*string{}
 	input := &cloudwatch.ListDashboardsInput{}
@@ -43,17 +44,13 @@ func shouldIncludeCloudWatchDashboard(dashboard *cloudwatch.DashboardEntry, excl
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(dashboard.DashboardName),
-		configObj.CloudWatchDashboard.IncludeRule.NamesRegExp,
-		configObj.CloudWatchDashboard.ExcludeRule.NamesRegExp,
-	)
+	return configObj.CloudWatchDashboard.ShouldInclude(config.ResourceValue{Name: aws.StringValue(dashboard.DashboardName)})
 }
 
-func nukeAllCloudWatchDashboards(session *session.Session, identifiers []*string) error {
+func nukeAllCloudWatchDashboards(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := cloudwatch.New(session)
+	svc := newCloudWatchClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No CloudWatch Dashboards to nuke in region %s", region)
@@ -71,7 +68,7 @@ func nukeAllCloudWatchDashboards(session *session.Session, identifiers []*string
 
 	logging.Logger.Debugf("Deleting CloudWatch Dashboards in region %s", region)
 	input := cloudwatch.DeleteDashboardsInput{DashboardNames: identifiers}
-	_, err := svc.DeleteDashboards(&input)
+	_, err := svc.DeleteDashboardsWithContext(ctx, &input)
 
 	// Record status of this resource
 	e := report.BatchEntry{
@@ -79,7 +76,7 @@ func nukeAllCloudWatchDashboards(session *session.Session, identifiers []*string
 		ResourceType: "CloudWatch Dashboard",
 		Error:        err,
 	}
-	report.RecordBatch(e)
+	recordBatch(ctx, e)
 
 	if err != nil {
 		logging.Logger.Debugf("[Failed] %s", err)
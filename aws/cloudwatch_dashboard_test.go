@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -79,7 +80,7 @@ func TestNukeCloudWatchDashboardOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchDashboards(session, identifiers),
+		nukeAllCloudWatchDashboards(context.Background(), session, identifiers),
 	)
 
 	// Make sure the CloudWatch Dashboard is deleted.
@@ -107,7 +108,7 @@ func TestNukeCloudWatchDashboardsMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchDashboards(session, cwdbNames),
+		nukeAllCloudWatchDashboards(context.Background(), session, cwdbNames),
 	)
 
 	// Make sure the CloudWatch Dashboard is deleted.
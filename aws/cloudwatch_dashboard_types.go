@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -26,10 +28,22 @@ func (cwdb CloudWatchDashboards) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (cwdb CloudWatchDashboards) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllCloudWatchDashboards(session, awsgo.StringSlice(identifiers)); err != nil {
+func (cwdb CloudWatchDashboards) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllCloudWatchDashboards(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        CloudWatchDashboards{}.ResourceName(),
+		Description: "CloudWatch Dashboards",
+		ConfigKey:   "CloudWatchDashboard",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllCloudWatchDashboards(session, params.ExcludeAfter, params.Config)
+			return CloudWatchDashboards{DashboardNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,7 +20,7 @@ import (
 )
 
 func getAllCloudWatchLogGroups(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := cloudwatchlogs.New(session)
+	svc := newCloudWatchLogsClient(session)
 
 	allLogGroups := []*string{}
 	err := svc.DescribeLogGroupsPages(
@@ -51,16 +53,12 @@ func shouldIncludeCloudWatchLogGroup(logGroup *cloudwatchlogs.LogGroup, excludeA
 		}
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(logGroup.LogGroupName),
-		configObj.CloudWatchLogGroup.IncludeRule.NamesRegExp,
-		configObj.CloudWatchLogGroup.ExcludeRule.NamesRegExp,
-	)
+	return configObj.CloudWatchLogGroup.ShouldInclude(config.ResourceValue{Name: aws.StringValue(logGroup.LogGroupName)})
 }
 
-func nukeAllCloudWatchLogGroups(session *session.Session, identifiers []*string) error {
+func nukeAllCloudWatchLogGroups(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
-	svc := cloudwatchlogs.New(session)
+	svc := newCloudWatchLogsClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No CloudWatch Log Groups to nuke in region %s", *session.Config.Region)
@@ -84,7 +82,7 @@ func nukeAllCloudWatchLogGroups(session *session.Session, identifiers []*string)
 	errChans := make([]chan error, len(identifiers))
 	for i, logGroupName := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteCloudWatchLogGroupAsync(wg, errChans[i], svc, logGroupName, region)
+		go deleteCloudWatchLogGroupAsync(ctx, wg, errChans[i], svc, logGroupName, region)
 	}
 	wg.Wait()
 
@@ -114,15 +112,16 @@ func nukeAllCloudWatchLogGroups(session *session.Session, identifiers []*string)
 // deleteCloudWatchLogGroupAsync deletes the provided Log Group asynchronously in a goroutine, using wait groups for
 // concurrency control and a return channel for errors.
 func deleteCloudWatchLogGroupAsync(
+	ctx context.Context,
 	wg *sync.WaitGroup,
 	errChan chan error,
-	svc *cloudwatchlogs.CloudWatchLogs,
+	svc cloudwatchlogsiface.CloudWatchLogsAPI,
 	logGroupName *string,
 	region string,
 ) {
 	defer wg.Done()
 	input := &cloudwatchlogs.DeleteLogGroupInput{LogGroupName: logGroupName}
-	_, err := svc.DeleteLogGroup(input)
+	_, err := svc.DeleteLogGroupWithContext(ctx, input)
 
 	// Record status of this resource
 	e := report.Entry{
@@ -130,7 +129,7 @@ func deleteCloudWatchLogGroupAsync(
 		ResourceType: "CloudWatch Log Group",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 
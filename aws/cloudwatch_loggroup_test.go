@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -80,7 +81,7 @@ func TestNukeCloudWatchLogGroupOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchLogGroups(session, identifiers),
+		nukeAllCloudWatchLogGroups(context.Background(), session, identifiers),
 	)
 
 	// Make sure the CloudWatch Dashboard is deleted.
@@ -108,7 +109,7 @@ func TestNukeCloudWatchLogGroupMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllCloudWatchLogGroups(session, lgNames),
+		nukeAllCloudWatchLogGroups(context.Background(), session, lgNames),
 	)
 
 	// Make sure the CloudWatch Dashboard is deleted.
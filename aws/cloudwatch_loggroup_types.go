@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,23 @@ func (r CloudWatchLogGroups) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (r CloudWatchLogGroups) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllCloudWatchLogGroups(session, awsgo.StringSlice(identifiers)); err != nil {
+func (r CloudWatchLogGroups) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllCloudWatchLogGroups(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        CloudWatchLogGroups{}.ResourceName(),
+		Description: "CloudWatch Log Groups",
+		ConfigKey:   "CloudWatchLogGroup",
+		DependsOn:   []string{"lambda"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllCloudWatchLogGroups(session, params.ExcludeAfter, params.Config)
+			return CloudWatchLogGroups{Names: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -15,7 +16,7 @@ import (
 )
 
 func getAllConfigRecorders(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]string, error) {
-	svc := configservice.New(session)
+	svc := newConfigServiceClient(session)
 
 	configRecorderNames := []string{}
 
@@ -40,15 +41,11 @@ func shouldIncludeConfigRecorder(configRecorder *configservice.ConfigurationReco
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(configRecorder.Name),
-		configObj.ConfigServiceRecorder.IncludeRule.NamesRegExp,
-		configObj.ConfigServiceRecorder.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ConfigServiceRecorder.ShouldInclude(config.ResourceValue{Name: aws.StringValue(configRecorder.Name)})
 }
 
-func nukeAllConfigRecorders(session *session.Session, configRecorderNames []string) error {
-	svc := configservice.New(session)
+func nukeAllConfigRecorders(ctx context.Context, session *session.Session, configRecorderNames []string) error {
+	svc := newConfigServiceClient(session)
 
 	if len(configRecorderNames) == 0 {
 		logging.Logger.Debugf("No Config recorders to nuke in region %s", *session.Config.Region)
@@ -62,7 +59,7 @@ func nukeAllConfigRecorders(session *session.Session, configRecorderNames []stri
 			ConfigurationRecorderName: aws.String(configRecorderName),
 		}
 
-		_, err := svc.DeleteConfigurationRecorder(params)
+		_, err := svc.DeleteConfigurationRecorderWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -70,7 +67,7 @@ func nukeAllConfigRecorders(session *session.Session, configRecorderNames []stri
 			ResourceType: "Config Recorder",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -53,7 +54,7 @@ func TestNukeConfigRecorderOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllConfigRecorders(session, []string{configRecorderName}),
+		nukeAllConfigRecorders(context.Background(), session, []string{configRecorderName}),
 	)
 
 	assertConfigRecordersDeleted(t, region)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
@@ -21,9 +23,22 @@ func (u ConfigServiceRecorders) MaxBatchSize() int {
 	return 50
 }
 
-func (u ConfigServiceRecorders) Nuke(session *session.Session, configServiceRecorderNames []string) error {
-	if err := nukeAllConfigRecorders(session, configServiceRecorderNames); err != nil {
+func (u ConfigServiceRecorders) Nuke(ctx context.Context, session *session.Session, configServiceRecorderNames []string) error {
+	if err := nukeAllConfigRecorders(ctx, session, configServiceRecorderNames); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ConfigServiceRecorders{}.ResourceName(),
+		Description: "Config Service Recorders",
+		ConfigKey:   "ConfigServiceRecorder",
+		DependsOn:   []string{"config-rules"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllConfigRecorders(session, params.ExcludeAfter, params.Config)
+			return ConfigServiceRecorders{RecorderNames: ids}, err
+		},
+	})
+}
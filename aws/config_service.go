@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -15,7 +16,7 @@ import (
 )
 
 func getAllConfigRules(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]string, error) {
-	svc := configservice.New(session)
+	svc := newConfigServiceClient(session)
 
 	configRuleNames := []string{}
 
@@ -44,15 +45,11 @@ func shouldIncludeConfigRule(configRule *configservice.ConfigRule, excludeAfter
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(configRule.ConfigRuleName),
-		configObj.ConfigServiceRule.IncludeRule.NamesRegExp,
-		configObj.ConfigServiceRule.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ConfigServiceRule.ShouldInclude(config.ResourceValue{Name: aws.StringValue(configRule.ConfigRuleName)})
 }
 
-func nukeAllConfigServiceRules(session *session.Session, configRuleNames []string) error {
-	svc := configservice.New(session)
+func nukeAllConfigServiceRules(ctx context.Context, session *session.Session, configRuleNames []string) error {
+	svc := newConfigServiceClient(session)
 
 	if len(configRuleNames) == 0 {
 		logging.Logger.Debugf("No Config service rules to nuke in region %s", *session.Config.Region)
@@ -64,7 +61,7 @@ func nukeAllConfigServiceRules(session *session.Session, configRuleNames []strin
 		params := &configservice.DeleteConfigRuleInput{
 			ConfigRuleName: aws.String(configRuleName),
 		}
-		_, err := svc.DeleteConfigRule(params)
+		_, err := svc.DeleteConfigRuleWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -72,7 +69,7 @@ func nukeAllConfigServiceRules(session *session.Session, configRuleNames []strin
 			ResourceType: "Config service rule",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
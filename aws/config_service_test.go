@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -53,7 +54,7 @@ func TestNukeConfigServiceRuleOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllConfigServiceRules(session, configServiceRuleNames),
+		nukeAllConfigServiceRules(context.Background(), session, configServiceRuleNames),
 	)
 
 	assertConfigServiceRulesDeleted(t, region, configServiceRuleNames)
@@ -78,7 +79,7 @@ func TestNukeConfigServiceRuleMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllConfigServiceRules(session, configServiceRuleNames),
+		nukeAllConfigServiceRules(context.Background(), session, configServiceRuleNames),
 	)
 
 	assertConfigServiceRulesDeleted(t, region, configServiceRuleNames)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
@@ -21,9 +23,21 @@ func (c ConfigServiceRule) MaxBatchSize() int {
 	return 200
 }
 
-func (c ConfigServiceRule) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllConfigServiceRules(session, identifiers); err != nil {
+func (c ConfigServiceRule) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllConfigServiceRules(ctx, session, identifiers); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ConfigServiceRule{}.ResourceName(),
+		Description: "Config Service Rules",
+		ConfigKey:   "ConfigServiceRule",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllConfigRules(session, params.ExcludeAfter, params.Config)
+			return ConfigServiceRule{RuleNames: ids}, err
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+	"github.com/tnn-gruntwork-io/go-commons/retry"
+)
+
+// sleepWithContext waits for the given duration, or until the context is done, in which case it returns the error of
+// the context
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordScopeKey is the context key of the recordScope that outcomes are recorded in
+type recordScopeKey struct{}
+
+// recordScope is the region and registered resource type whose resources are being nuked, so that their outcomes are
+// recorded for them regardless of the resource type that is displayed
+type recordScope struct {
+	region       string
+	resourceType string
+}
+
+// withRecordScope returns a context in which the outcomes recorded by record and recordBatch belong to the given region
+// and resource type
+func withRecordScope(ctx context.Context, region string, resourceType string) context.Context {
+	return context.WithValue(ctx, recordScopeKey{}, recordScope{region: region, resourceType: resourceType})
+}
+
+// record records the outcome of an attempt to nuke a resource in the record scope of the context. Outside of a record
+// scope, the outcome is recorded for the region and resource type of the entry.
+func record(ctx context.Context, e report.Entry) {
+	scope, ok := ctx.Value(recordScopeKey{}).(recordScope)
+	if !ok {
+		report.Record(e)
+		return
+	}
+	if e.Region == "" {
+		e.Region = scope.region
+	}
+	report.RecordAs(scope.resourceType, e)
+}
+
+// recordBatch is like record, for each identifier of the batch
+func recordBatch(ctx context.Context, e report.BatchEntry) {
+	for _, entry := range e.Entries() {
+		record(ctx, entry)
+	}
+}
+
+// recordedOutcome returns the outcome recorded for the given identifier in the record scope of the context, if any
+func recordedOutcome(ctx context.Context, identifier string) (report.Entry, bool) {
+	scope, _ := ctx.Value(recordScopeKey{}).(recordScope)
+	return report.GetRecord(scope.region, scope.resourceType, identifier)
+}
+
+// recordCancelled records the given identifiers of the given resources as cancelled, as the context was done before
+// they could be nuked
+func recordCancelled(ctx context.Context, resources AwsResources, identifiers []string) {
+	recordBatch(ctx, report.BatchEntry{
+		Identifiers:  identifiers,
+		ResourceType: resources.ResourceName(),
+		Error:        ctx.Err(),
+	})
+}
+
+// retryWithContext is like retry.DoWithRetry, except that it stops retrying once the context is done, in which case it
+// returns the error of the context
+func retryWithContext(ctx context.Context, actionDescription string, maxRetries int, sleepBetweenRetries time.Duration, action func() error) error {
+	for i := 0; i <= maxRetries; i++ {
+		logging.Logger.Debugf(actionDescription)
+
+		err := action()
+		if err == nil {
+			return nil
+		}
+		if _, isFatalErr := errors.Unwrap(err).(retry.FatalError); isFatalErr {
+			return err
+		}
+
+		logging.Logger.Debugf("%s returned an error: %s. Attempt %d of %d. Sleeping for %s and will retry.", actionDescription, err, i+1, maxRetries, sleepBetweenRetries)
+		if err := sleepWithContext(ctx, sleepBetweenRetries); err != nil {
+			return err
+		}
+	}
+	return retry.MaxRetriesExceeded{Description: actionDescription, MaxRetries: maxRetries}
+}
@@ -0,0 +1,30 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryWithContextStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := retryWithContext(ctx, "Waiting for a fake resource to be deleted", 30, time.Hour, func() error {
+		attempts++
+		cancel()
+		return errors.New("not deleted yet")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestSleepWithMessageStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, sleepWithMessage(ctx, time.Hour, "waiting for a fake resource"), context.Canceled)
+}
@@ -1,15 +1,16 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
-	"log"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,52 +19,62 @@ import (
 
 func getAllDynamoTables(session *session.Session, excludeAfter time.Time, configObj config.Config, db DynamoDB) ([]*string, error) {
 	var tableNames []*string
-	svc := dynamodb.New(session)
+	svc := newDynamoDBClient(session)
 
-	var lastTableName *string
-	// Run count is used for pagination if the list tables exceeds max value
-	// Tells loop to rerun
-	PaginationRunCount := 1
-	for PaginationRunCount > 0 {
-		result, err := svc.ListTables(&dynamodb.ListTablesInput{ExclusiveStartTableName: lastTableName, Limit: aws.Int64(int64(DynamoDB.MaxBatchSize(db)))})
-
-		lastTableName = result.LastEvaluatedTableName
-		if err != nil {
-			if aerr, ok := err.(awserr.Error); ok {
-				switch aerr.Error() {
-				case dynamodb.ErrCodeInternalServerError:
-					return nil, errors.WithStackTrace(aerr)
-				default:
-					return nil, errors.WithStackTrace(aerr)
-				}
+	var tableErr error
+	err := svc.ListTablesPages(&dynamodb.ListTablesInput{Limit: aws.Int64(int64(DynamoDB.MaxBatchSize(db)))}, func(page *dynamodb.ListTablesOutput, lastPage bool) bool {
+		for _, table := range page.TableNames {
+			responseDescription, err := svc.DescribeTable(&dynamodb.DescribeTableInput{TableName: table})
+			if err != nil {
+				tableErr = err
+				return false
 			}
-		}
 
-		tableLen := len(result.TableNames)
-		// Check table length if it matches the max value add 1 to rerun
-		if tableLen == DynamoDB.MaxBatchSize(db) {
-			// Tell the user that this will be run twice due to max tables detected
-			logging.Logger.Debugf("The tables detected exceed the 100. Running more than once")
-			// Adds one to the count as it will = 2 runs at least until this loops again to check if it's another max.
-			PaginationRunCount += 1
-		}
-		for _, table := range result.TableNames {
-			responseDescription, err := svc.DescribeTable(&dynamodb.DescribeTableInput{TableName: table})
+			// Tags are always looked up, so that tables carrying the exclusion tag are never nuked
+			tags, err := getDynamoTableTags(svc, responseDescription.Table.TableArn)
 			if err != nil {
-				log.Fatalf("There was an error describing table: %v\n", err)
+				tableErr = err
+				return false
+			}
+			if excludedByTag(db.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(table), tags) {
+				continue
 			}
 
-			if shouldIncludeTable(responseDescription.Table, excludeAfter, configObj) {
+			if shouldIncludeTable(responseDescription.Table, tags, excludeAfter, configObj) {
 				tableNames = append(tableNames, table)
 			}
 		}
-		// Remove 1 from the counter if it's one run the loop will end as PaginationRunCount will = 0
-		PaginationRunCount -= 1
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if tableErr != nil {
+		return nil, errors.WithStackTrace(tableErr)
 	}
 	return tableNames, nil
 }
 
-func shouldIncludeTable(table *dynamodb.TableDescription, excludeAfter time.Time, configObj config.Config) bool {
+// getDynamoTableTags returns the tags of the given DynamoDB table as a map of tag keys to values
+func getDynamoTableTags(svc dynamodbiface.DynamoDBAPI, tableArn *string) (map[string]string, error) {
+	tags := make(map[string]string)
+	input := &dynamodb.ListTagsOfResourceInput{ResourceArn: tableArn}
+	for {
+		output, err := svc.ListTagsOfResource(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if output.NextToken == nil {
+			return tags, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func shouldIncludeTable(table *dynamodb.TableDescription, tags map[string]string, excludeAfter time.Time, configObj config.Config) bool {
 	if table == nil {
 		return false
 	}
@@ -72,15 +83,15 @@ func shouldIncludeTable(table *dynamodb.TableDescription, excludeAfter time.Time
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(table.TableName),
-		configObj.DynamoDB.IncludeRule.NamesRegExp,
-		configObj.DynamoDB.ExcludeRule.NamesRegExp,
-	)
+	return configObj.DynamoDB.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(table.TableName),
+		Tags: tags,
+		Time: aws.TimeValue(table.CreationDateTime),
+	})
 }
 
-func nukeAllDynamoDBTables(session *session.Session, tables []*string) error {
-	svc := dynamodb.New(session)
+func nukeAllDynamoDBTables(ctx context.Context, session *session.Session, tables []*string) error {
+	svc := newDynamoDBClient(session)
 	if len(tables) == 0 {
 		logging.Logger.Debugf("No DynamoDB tables to nuke in region %s", *session.Config.Region)
 		return nil
@@ -92,7 +103,7 @@ func nukeAllDynamoDBTables(session *session.Session, tables []*string) error {
 		input := &dynamodb.DeleteTableInput{
 			TableName: aws.String(*table),
 		}
-		_, err := svc.DeleteTable(input)
+		_, err := svc.DeleteTableWithContext(ctx, input)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -100,7 +111,7 @@ func nukeAllDynamoDBTables(session *session.Session, tables []*string) error {
 			ResourceType: "DynamoDB Table",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			if aerr, ok := err.(awserr.Error); ok {
@@ -120,3 +131,54 @@ func nukeAllDynamoDBTables(session *session.Session, tables []*string) error {
 	}
 	return nil
 }
+
+// preserveDynamoDBTables takes an on-demand backup of each of the given DynamoDB tables and returns the names of the
+// tables that are safe to delete. Tables that could not be backed up are recorded as failed. DynamoDB backups cannot
+// be tagged, so they are only told apart by their name, which holds the run ID.
+func preserveDynamoDBTables(ctx context.Context, session *session.Session, tables []*string, runID string) []*string {
+	svc := newDynamoDBClient(session)
+
+	var preserved []*string
+	for _, table := range tables {
+		err := preserveDynamoDBTable(ctx, svc, table, runID)
+		if err != nil {
+			logging.Logger.Errorf("[Failed] %s: %s", aws.StringValue(table), err)
+			record(ctx, report.Entry{
+				Identifier:   aws.StringValue(table),
+				ResourceType: "DynamoDB Table",
+				Error:        DataPreservationError{Identifier: aws.StringValue(table), Underlying: err},
+			})
+			continue
+		}
+		preserved = append(preserved, table)
+	}
+	return preserved
+}
+
+func preserveDynamoDBTable(ctx context.Context, svc dynamodbiface.DynamoDBAPI, table *string, runID string) error {
+	output, err := svc.CreateBackup(&dynamodb.CreateBackupInput{
+		TableName:  table,
+		BackupName: aws.String(preservedDataName(runID, aws.StringValue(table))),
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// wait up to 15 minutes
+	backupArn := output.BackupDetails.BackupArn
+	for i := 0; i < 90; i++ {
+		description, err := svc.DescribeBackup(&dynamodb.DescribeBackupInput{BackupArn: backupArn})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if aws.StringValue(description.BackupDescription.BackupDetails.BackupStatus) == dynamodb.BackupStatusAvailable {
+			return nil
+		}
+
+		logging.Logger.Debugf("Waiting for backup of DynamoDB table %s", aws.StringValue(table))
+		if err := sleepWithContext(ctx, 10*time.Second); err != nil {
+			return err
+		}
+	}
+	return DynamoDBBackupNotAvailableError{BackupArn: aws.StringValue(backupArn)}
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"log"
 	"regexp"
@@ -143,7 +144,7 @@ func TestShouldIncludeTable(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.Name, func(t *testing.T) {
-			result := shouldIncludeTable(c.Table, c.ExcludeAfter, c.Config)
+			result := shouldIncludeTable(c.Table, nil, c.ExcludeAfter, c.Config)
 			assert.Equal(t, c.Expected, result)
 		})
 	}
@@ -180,7 +181,7 @@ func TestNukeAllDynamoDBTables(t *testing.T) {
 	require.NoError(t, err)
 
 	tableName := "cloud-nuke-test-" + util.UniqueID()
-	defer nukeAllDynamoDBTables(awsSession, []*string{&tableName})
+	defer nukeAllDynamoDBTables(context.Background(), awsSession, []*string{&tableName})
 	createTestDynamoTables(t, tableName, region)
 	COUNTER := 0
 	for COUNTER <= 1 {
@@ -192,7 +193,7 @@ func TestNukeAllDynamoDBTables(t *testing.T) {
 			log.Printf("Table not ready yet: %v", tableName)
 		}
 	}
-	nukeErr := nukeAllDynamoDBTables(awsSession, []*string{&tableName})
+	nukeErr := nukeAllDynamoDBTables(context.Background(), awsSession, []*string{&tableName})
 	require.NoError(t, nukeErr)
 
 	time.Sleep(5 * time.Second)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/gruntwork-cli/errors"
@@ -24,9 +26,41 @@ func (tables DynamoDB) MaxBatchSize() int {
 }
 
 // Nuke - nuke all Dynamo DB Tables
-func (tables DynamoDB) Nuke(awsSession *session.Session, identifiers []string) error {
-	if err := nukeAllDynamoDBTables(awsSession, awsgo.StringSlice(identifiers)); err != nil {
+func (tables DynamoDB) Nuke(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := nukeAllDynamoDBTables(ctx, awsSession, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+type DynamoDBBackupNotAvailableError struct {
+	BackupArn string
+}
+
+func (e DynamoDBBackupNotAvailableError) Error() string {
+	return "DynamoDB backup " + e.BackupArn + " did not become available in time"
+}
+
+// NukePreservingData - take an on-demand backup of the Dynamo DB Tables, then nuke the ones that were backed up
+func (tables DynamoDB) NukePreservingData(ctx context.Context, awsSession *session.Session, identifiers []string, runID string) error {
+	preserved := preserveDynamoDBTables(ctx, awsSession, awsgo.StringSlice(identifiers), runID)
+	if err := nukeAllDynamoDBTables(ctx, awsSession, preserved); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                     DynamoDB{}.ResourceName(),
+		Description:              "DynamoDB Tables",
+		ConfigKey:                "DynamoDB",
+		SupportsTags:             true,
+		SupportsRuleAge:          true,
+		SupportsDataPreservation: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllDynamoTables(session, params.ExcludeAfter, params.Config, DynamoDB{})
+			return DynamoDB{DynamoTableNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
@@ -10,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,7 +21,7 @@ import (
 
 // Returns a formatted string of EBS volume ids
 func getAllEbsVolumes(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
 	// Available statuses: (creating | available | in-use | deleting | deleted | error).
 	// Since the output of this function is used to delete the returned volumes
@@ -26,20 +29,24 @@ func getAllEbsVolumes(session *session.Session, region string, excludeAfter time
 	// Since those are the only statuses that are eligible for deletion
 	statusFilter := ec2.Filter{Name: aws.String("status"), Values: aws.StringSlice([]string{"available", "creating", "error"})}
 
-	result, err := svc.DescribeVolumes(&ec2.DescribeVolumesInput{
+	var volumeIds []*string
+	err := svc.DescribeVolumesPages(&ec2.DescribeVolumesInput{
 		Filters: []*ec2.Filter{&statusFilter},
+	}, func(page *ec2.DescribeVolumesOutput, lastPage bool) bool {
+		for _, volume := range page.Volumes {
+			if excludedByTag(EBSVolumes{}.ResourceName(), region, aws.StringValue(volume.VolumeId), ec2TagsToMap(volume.Tags)) {
+				continue
+			}
+			if shouldIncludeEBSVolume(volume, excludeAfter, configObj) {
+				volumeIds = append(volumeIds, volume.VolumeId)
+			}
+		}
+		return !lastPage
 	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
-	var volumeIds []*string
-	for _, volume := range result.Volumes {
-		if shouldIncludeEBSVolume(volume, excludeAfter, configObj) {
-			volumeIds = append(volumeIds, volume.VolumeId)
-		}
-	}
-
 	return volumeIds, nil
 }
 
@@ -58,16 +65,17 @@ func shouldIncludeEBSVolume(volume *ec2.Volume, excludeAfter time.Time, configOb
 			name = aws.StringValue(tag.Value)
 		}
 	}
-	return config.ShouldInclude(
-		name,
-		configObj.EBSVolume.IncludeRule.NamesRegExp,
-		configObj.EBSVolume.ExcludeRule.NamesRegExp,
-	)
+	return configObj.EBSVolume.ShouldInclude(config.ResourceValue{
+		Name: name,
+		Tags: ec2TagsToMap(volume.Tags),
+		ID:   aws.StringValue(volume.VolumeId),
+		Time: aws.TimeValue(volume.CreateTime),
+	})
 }
 
 // Deletes all EBS Volumes
-func nukeAllEbsVolumes(session *session.Session, volumeIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllEbsVolumes(ctx context.Context, session *session.Session, volumeIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(volumeIds) == 0 {
 		logging.Logger.Debugf("No EBS volumes to nuke in region %s", *session.Config.Region)
@@ -90,7 +98,7 @@ func nukeAllEbsVolumes(session *session.Session, volumeIds []*string) error {
 			ResourceType: "EBS Volume",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == "VolumeInUse" {
@@ -124,7 +132,7 @@ func nukeAllEbsVolumes(session *session.Session, volumeIds []*string) error {
 	}
 
 	if len(deletedVolumeIDs) > 0 {
-		err := svc.WaitUntilVolumeDeleted(&ec2.DescribeVolumesInput{
+		err := svc.WaitUntilVolumeDeletedWithContext(ctx, &ec2.DescribeVolumesInput{
 			VolumeIds: deletedVolumeIDs,
 		})
 		if err != nil {
@@ -141,3 +149,45 @@ func nukeAllEbsVolumes(session *session.Session, volumeIds []*string) error {
 	logging.Logger.Debugf("[OK] %d EBS volumes(s) terminated in %s", len(deletedVolumeIDs), *session.Config.Region)
 	return nil
 }
+
+// preserveEbsVolumes takes a snapshot of each of the given EBS volumes, tagged with the run ID, and returns the IDs of
+// the volumes that are safe to delete. Volumes that could not be snapshotted are recorded as failed.
+func preserveEbsVolumes(ctx context.Context, session *session.Session, volumeIds []*string, runID string) []*string {
+	svc := newEC2Client(session)
+
+	var preserved []*string
+	for _, volumeID := range volumeIds {
+		err := preserveEbsVolume(ctx, svc, volumeID, runID)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s: %s", aws.StringValue(volumeID), err)
+			record(ctx, report.Entry{
+				Identifier:   aws.StringValue(volumeID),
+				ResourceType: "EBS Volume",
+				Error:        DataPreservationError{Identifier: aws.StringValue(volumeID), Underlying: err},
+			})
+			continue
+		}
+		preserved = append(preserved, volumeID)
+	}
+	return preserved
+}
+
+func preserveEbsVolume(ctx context.Context, svc ec2iface.EC2API, volumeID *string, runID string) error {
+	var tags []*ec2.Tag
+	for key, value := range preservedDataTags(runID, aws.StringValue(volumeID)) {
+		tags = append(tags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	tags = append(tags, &ec2.Tag{Key: aws.String("Name"), Value: aws.String(preservedDataName(runID, aws.StringValue(volumeID)))})
+
+	snapshot, err := svc.CreateSnapshot(&ec2.CreateSnapshotInput{
+		VolumeId:          volumeID,
+		Description:       aws.String(fmt.Sprintf("Data of %s, preserved by cloud-nuke run %s", aws.StringValue(volumeID), runID)),
+		TagSpecifications: []*ec2.TagSpecification{{ResourceType: aws.String(ec2.ResourceTypeSnapshot), Tags: tags}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	logging.Logger.Debugf("Waiting for snapshot %s of EBS volume %s", aws.StringValue(snapshot.SnapshotId), aws.StringValue(volumeID))
+	err = svc.WaitUntilSnapshotCompletedWithContext(ctx, &ec2.DescribeSnapshotsInput{SnapshotIds: []*string{snapshot.SnapshotId}})
+	return errors.WithStackTrace(err)
+}
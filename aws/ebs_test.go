@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"regexp"
 	"testing"
 	"time"
@@ -124,7 +125,7 @@ func TestListEBSVolumes(t *testing.T) {
 	az := awsgo.StringValue(session.Config.Region) + "a"
 	volume := createTestEBSVolume(t, session, uniqueTestID, az)
 	// clean up after this test
-	defer nukeAllEbsVolumes(session, []*string{volume.VolumeId})
+	defer nukeAllEbsVolumes(context.Background(), session, []*string{volume.VolumeId})
 
 	volumeIds, err := getAllEbsVolumes(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -160,7 +161,7 @@ func TestListEBSVolumesWithConfigFile(t *testing.T) {
 	includedVolume := createTestEBSVolume(t, session, includedEBSVolumeName, az)
 	excludedVolume := createTestEBSVolume(t, session, excludedEBSVolumeName, az)
 	// clean up after this test
-	defer nukeAllEbsVolumes(session, []*string{includedVolume.VolumeId, excludedVolume.VolumeId})
+	defer nukeAllEbsVolumes(context.Background(), session, []*string{includedVolume.VolumeId, excludedVolume.VolumeId})
 
 	volumeIds, err := getAllEbsVolumes(session, region, time.Now().Add(1*time.Hour), config.Config{
 		EBSVolume: config.ResourceType{
@@ -202,7 +203,7 @@ func TestNukeEBSVolumes(t *testing.T) {
 	assert.Len(t, volumeIds, 1)
 	assert.Equal(t, awsgo.StringValue(volume.VolumeId), awsgo.StringValue(volumeIds[0]))
 
-	if err := nukeAllEbsVolumes(session, volumeIds); err != nil {
+	if err := nukeAllEbsVolumes(context.Background(), session, volumeIds); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
@@ -238,8 +239,8 @@ func TestNukeEBSVolumesInUse(t *testing.T) {
 	az := getAZFromSubnet(t, session, instance.SubnetId)
 	volume := createTestEBSVolume(t, session, uniqueTestID, az)
 
-	defer nukeAllEbsVolumes(session, []*string{volume.VolumeId})
-	defer nukeAllEc2Instances(session, []*string{instance.InstanceId})
+	defer nukeAllEbsVolumes(context.Background(), session, []*string{volume.VolumeId})
+	defer nukeAllEc2Instances(context.Background(), session, []*string{instance.InstanceId})
 
 	// attach volume to protected instance
 	_, err = svc.AttachVolume(&ec2.AttachVolumeInput{
@@ -265,7 +266,7 @@ func TestNukeEBSVolumesInUse(t *testing.T) {
 	assert.Len(t, volumeIds, 1)
 	assert.Equal(t, awsgo.StringValue(volume.VolumeId), awsgo.StringValue(volumeIds[0]))
 
-	if err := nukeAllEbsVolumes(session, volumeIds); err != nil {
+	if err := nukeAllEbsVolumes(context.Background(), session, volumeIds); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
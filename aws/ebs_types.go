@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,36 @@ func (volume EBSVolumes) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (volume EBSVolumes) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllEbsVolumes(session, awsgo.StringSlice(identifiers)); err != nil {
+func (volume EBSVolumes) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllEbsVolumes(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+// NukePreservingData - take a snapshot of the ebs volumes, then nuke the ones that were snapshotted
+func (volume EBSVolumes) NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error {
+	preserved := preserveEbsVolumes(ctx, session, awsgo.StringSlice(identifiers), runID)
+	if err := nukeAllEbsVolumes(ctx, session, preserved); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                     EBSVolumes{}.ResourceName(),
+		Description:              "EBS Volumes",
+		ConfigKey:                "EBSVolume",
+		SupportsTags:             true,
+		SupportsRuleAge:          true,
+		SupportsDataPreservation: true,
+		DependsOn:                []string{"ec2"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEbsVolumes(session, params.Region, params.ExcludeAfter, params.Config)
+			return EBSVolumes{VolumeIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
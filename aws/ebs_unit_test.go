@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+)
+
+// fakeEBS is an in-process EC2 backend holding EBS volumes and snapshots in memory. Only the operations used to
+// preserve the data of volumes, delete them and list their snapshots are implemented, calling any other operation
+// panics.
+type fakeEBS struct {
+	ec2iface.EC2API
+
+	volumes   map[string]bool
+	snapshots []*ec2.Snapshot
+	// failingVolumes cannot be snapshotted
+	failingVolumes map[string]bool
+}
+
+func (fake *fakeEBS) CreateSnapshot(input *ec2.CreateSnapshotInput) (*ec2.Snapshot, error) {
+	if fake.failingVolumes[*input.VolumeId] {
+		return nil, errors.New("IncorrectState: volume is not available")
+	}
+	snapshot := &ec2.Snapshot{
+		SnapshotId: awsgo.String("snap-" + *input.VolumeId),
+		VolumeId:   input.VolumeId,
+		StartTime:  awsgo.Time(time.Now()),
+		Tags:       input.TagSpecifications[0].Tags,
+	}
+	fake.snapshots = append(fake.snapshots, snapshot)
+	return snapshot, nil
+}
+
+func (fake *fakeEBS) WaitUntilSnapshotCompletedWithContext(ctx awsgo.Context, input *ec2.DescribeSnapshotsInput, opts ...request.WaiterOption) error {
+	return nil
+}
+
+// DescribeSnapshotsPages returns one snapshot per page, to make sure every page is looked at
+func (fake *fakeEBS) DescribeSnapshotsPages(input *ec2.DescribeSnapshotsInput, fn func(*ec2.DescribeSnapshotsOutput, bool) bool) error {
+	for i, snapshot := range fake.snapshots {
+		if !fn(&ec2.DescribeSnapshotsOutput{Snapshots: []*ec2.Snapshot{snapshot}}, i == len(fake.snapshots)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (fake *fakeEBS) DeleteVolume(input *ec2.DeleteVolumeInput) (*ec2.DeleteVolumeOutput, error) {
+	delete(fake.volumes, *input.VolumeId)
+	return &ec2.DeleteVolumeOutput{}, nil
+}
+
+func (fake *fakeEBS) WaitUntilVolumeDeletedWithContext(ctx awsgo.Context, input *ec2.DescribeVolumesInput, opts ...request.WaiterOption) error {
+	return nil
+}
+
+func TestNukeEbsVolumesPreservingDataOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	fake := &fakeEBS{
+		volumes:        map[string]bool{"vol-ok": true, "vol-busy": true},
+		failingVolumes: map[string]bool{"vol-busy": true},
+	}
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+
+	err := EBSVolumes{}.NukePreservingData(context.Background(), newFakeSession(t, "us-east-1"), []string{"vol-ok", "vol-busy"}, "run-1")
+	require.NoError(t, err)
+
+	// Only the volume whose data was backed up is deleted
+	assert.Equal(t, map[string]bool{"vol-busy": true}, fake.volumes)
+	require.Len(t, fake.snapshots, 1)
+	tags := ec2TagsToMap(fake.snapshots[0].Tags)
+	assert.Equal(t, "run-1", tags[RunIDTagKey])
+	assert.Equal(t, "vol-ok", tags[PreservedFromTagKey])
+	assert.Equal(t, "cloud-nuke-run-1-vol-ok", tags["Name"])
+
+	records := report.GetRecords()
+	assert.NoError(t, records[report.RecordKey{ResourceType: "EBS Volume", Identifier: "vol-ok"}].Error)
+	var preservationErr DataPreservationError
+	require.ErrorAs(t, records[report.RecordKey{ResourceType: "EBS Volume", Identifier: "vol-busy"}].Error, &preservationErr)
+	assert.Equal(t, "vol-busy", preservationErr.Identifier)
+}
+
+func TestListPreservedEbsSnapshotsOffline(t *testing.T) {
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	now := time.Now()
+	runIDTag := &ec2.Tag{Key: awsgo.String(RunIDTagKey), Value: awsgo.String("run-1")}
+	fake := &fakeEBS{snapshots: []*ec2.Snapshot{
+		{SnapshotId: awsgo.String("snap-old"), StartTime: awsgo.Time(now.Add(-40 * 24 * time.Hour)), Tags: []*ec2.Tag{runIDTag}},
+		{SnapshotId: awsgo.String("snap-new"), StartTime: awsgo.Time(now.Add(-2 * time.Hour)), Tags: []*ec2.Tag{runIDTag}},
+	}}
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+
+	// Backups are kept for DefaultPreservedDataRetention, even if --older-than is shorter
+	ids, err := getPreservedEbsSnapshots(newFakeSession(t, "us-east-1"), "us-east-1", preservedDataExcludeAfter(now.Add(-time.Hour), config.Config{}), config.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snap-old"}, awsgo.StringValueSlice(ids))
+
+	// Unless the config file sets its own age for PreservedData
+	configObj := config.Config{PreservedData: config.ResourceType{OlderThan: &config.Duration{Duration: time.Hour}}}
+	ids, err = getPreservedEbsSnapshots(newFakeSession(t, "us-east-1"), "us-east-1", preservedDataExcludeAfter(now.Add(-time.Hour), configObj), configObj)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"snap-old", "snap-new"}, awsgo.StringValueSlice(ids))
+}
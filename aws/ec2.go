@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -21,11 +22,16 @@ import (
 )
 
 // returns only instance Ids of unprotected ec2 instances
-func filterOutProtectedInstances(svc *ec2.EC2, output *ec2.DescribeInstancesOutput, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+func filterOutProtectedInstances(svc ec2iface.EC2API, region string, output *ec2.DescribeInstancesOutput, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
 	var filteredIds []*string
 	for _, reservation := range output.Reservations {
 		for _, instance := range reservation.Instances {
 			instanceID := *instance.InstanceId
+			tags := ec2TagsToMap(instance.Tags)
+			if excludedByTag(EC2Instances{}.ResourceName(), region, instanceID, tags) ||
+				excludedByQuarantine(EC2Instances{}.ResourceName(), region, instanceID, tags, excludeAfter) {
+				continue
+			}
 
 			attr, err := svc.DescribeInstanceAttribute(&ec2.DescribeInstanceAttributeInput{
 				Attribute:  awsgo.String("disableApiTermination"),
@@ -45,7 +51,7 @@ func filterOutProtectedInstances(svc *ec2.EC2, output *ec2.DescribeInstancesOutp
 
 // Returns a formatted string of EC2 instance ids
 func getAllEc2Instances(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
 	params := &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
@@ -59,14 +65,22 @@ func getAllEc2Instances(session *session.Session, region string, excludeAfter ti
 		},
 	}
 
-	output, err := svc.DescribeInstances(params)
+	var instanceIds []*string
+	var filterErr error
+	err := svc.DescribeInstancesPages(params, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		pageInstanceIds, err := filterOutProtectedInstances(svc, region, page, excludeAfter, configObj)
+		if err != nil {
+			filterErr = err
+			return false
+		}
+		instanceIds = append(instanceIds, pageInstanceIds...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
-
-	instanceIds, err := filterOutProtectedInstances(svc, output, excludeAfter, configObj)
-	if err != nil {
-		return nil, errors.WithStackTrace(err)
+	if filterErr != nil {
+		return nil, errors.WithStackTrace(filterErr)
 	}
 
 	return instanceIds, nil
@@ -86,19 +100,20 @@ func shouldIncludeInstanceId(instance *ec2.Instance, excludeAfter time.Time, pro
 	}
 
 	// If Name is unset, GetEC2ResourceNameTagValue returns error and zero value string
-	// Ignore this error and pass empty string to config.ShouldInclude
+	// Ignore this error and use an empty name
 	instanceName, _ := GetEC2ResourceNameTagValue(instance.Tags)
 
-	return config.ShouldInclude(
-		instanceName,
-		configObj.EC2.IncludeRule.NamesRegExp,
-		configObj.EC2.ExcludeRule.NamesRegExp,
-	)
+	return configObj.EC2.ShouldInclude(config.ResourceValue{
+		Name: instanceName,
+		Tags: ec2TagsToMap(instance.Tags),
+		ID:   awsgo.StringValue(instance.InstanceId),
+		Time: awsgo.TimeValue(instance.LaunchTime),
+	})
 }
 
 // Deletes all non protected EC2 instances
-func nukeAllEc2Instances(session *session.Session, instanceIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllEc2Instances(ctx context.Context, session *session.Session, instanceIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(instanceIds) == 0 {
 		logging.Logger.Debugf("No EC2 instances to nuke in region %s", *session.Config.Region)
@@ -122,7 +137,7 @@ func nukeAllEc2Instances(session *session.Session, instanceIds []*string) error
 		return errors.WithStackTrace(err)
 	}
 
-	err = svc.WaitUntilInstanceTerminated(&ec2.DescribeInstancesInput{
+	err = svc.WaitUntilInstanceTerminatedWithContext(ctx, &ec2.DescribeInstancesInput{
 		Filters: []*ec2.Filter{
 			{
 				Name:   awsgo.String("instance-id"),
@@ -149,8 +164,76 @@ func nukeAllEc2Instances(session *session.Session, instanceIds []*string) error
 	return nil
 }
 
+// quarantineEc2Instances tags the given EC2 instances with the quarantine tag and stops them, so that their owners can
+// start them again if they still need them
+func quarantineEc2Instances(ctx context.Context, session *session.Session, instanceIds []*string) error {
+	svc := newEC2Client(session)
+
+	tagsByInstance := map[string]map[string]string{}
+	statesByInstance := map[string]string{}
+	err := svc.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{InstanceIds: instanceIds}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				tagsByInstance[awsgo.StringValue(instance.InstanceId)] = ec2TagsToMap(instance.Tags)
+				if instance.State != nil {
+					statesByInstance[awsgo.StringValue(instance.InstanceId)] = awsgo.StringValue(instance.State.Name)
+				}
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	for _, instanceID := range instanceIds {
+		err := quarantineEc2Instance(ctx, svc, instanceID, tagsByInstance[awsgo.StringValue(instanceID)])
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined EC2 Instance: %s", awsgo.StringValue(instanceID))
+		}
+		metadata := map[string]string{metadataPreviousState: statesByInstance[awsgo.StringValue(instanceID)]}
+		recordQuarantine(ctx, EC2Instances{}.ResourceName(), awsgo.StringValue(instanceID), metadata, err)
+	}
+	return nil
+}
+
+func quarantineEc2Instance(ctx context.Context, svc ec2iface.EC2API, instanceID *string, tags map[string]string) error {
+	// The instance is tagged first, so that it is never stopped without telling its owners why
+	_, err := svc.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
+		Resources: []*string{instanceID},
+		Tags:      []*ec2.Tag{{Key: awsgo.String(QuarantineTagKey), Value: awsgo.String(quarantineTagValue(tags))}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{InstanceIds: []*string{instanceID}})
+	return errors.WithStackTrace(err)
+}
+
+// undoEc2InstanceQuarantine starts a quarantined EC2 instance again, unless it was already stopped when it was
+// quarantined, and removes its quarantine tag
+func undoEc2InstanceQuarantine(session *session.Session, resource RunReportResource) error {
+	svc := newEC2Client(session)
+	instanceID := awsgo.String(resource.Identifier)
+
+	previousState := resource.Metadata[metadataPreviousState]
+	if previousState != ec2.InstanceStateNameStopped && previousState != ec2.InstanceStateNameStopping {
+		_, err := svc.StartInstances(&ec2.StartInstancesInput{InstanceIds: []*string{instanceID}})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	_, err := svc.DeleteTags(&ec2.DeleteTagsInput{
+		Resources: []*string{instanceID},
+		Tags:      []*ec2.Tag{{Key: awsgo.String(QuarantineTagKey)}},
+	})
+	return errors.WithStackTrace(err)
+}
+
 func GetEc2ServiceClient(region string) ec2iface.EC2API {
-	return ec2.New(newSession(region))
+	return newEC2Client(newSession(region))
 }
 
 type Vpc struct {
@@ -427,7 +510,7 @@ func (v Vpc) nukeSecurityGroups(spinner *pterm.SpinnerPrinter) error {
 	return nil
 }
 
-func (v Vpc) nukeEndpoints(spinner *pterm.SpinnerPrinter) error {
+func (v Vpc) nukeEndpoints(ctx context.Context, spinner *pterm.SpinnerPrinter) error {
 	endpoints, _ := v.svc.DescribeVpcEndpoints(
 		&ec2.DescribeVpcEndpointsInput{
 			Filters: []*ec2.Filter{
@@ -462,7 +545,7 @@ func (v Vpc) nukeEndpoints(spinner *pterm.SpinnerPrinter) error {
 		return errors.WithStackTrace(err)
 	}
 
-	if err := waitForVPCEndpointsToBeDeleted(v); err != nil {
+	if err := waitForVPCEndpointsToBeDeleted(ctx, v); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -547,7 +630,7 @@ func (v Vpc) dissociateDhcpOptions(spinner *pterm.SpinnerPrinter) error {
 	return err
 }
 
-func waitForVPCEndpointsToBeDeleted(v Vpc) error {
+func waitForVPCEndpointsToBeDeleted(ctx context.Context, v Vpc) error {
 	for i := 0; i < 30; i++ {
 		endpoints, err := v.svc.DescribeVpcEndpoints(
 			&ec2.DescribeVpcEndpointsInput{
@@ -571,8 +654,10 @@ func waitForVPCEndpointsToBeDeleted(v Vpc) error {
 			return nil
 		}
 
-		time.Sleep(20 * time.Second)
 		logging.Logger.Debug("Waiting for VPC endpoints to be deleted...")
+		if err := sleepWithContext(ctx, 20*time.Second); err != nil {
+			return err
+		}
 	}
 
 	return VPCEndpointDeleteTimeoutError{}
@@ -598,7 +683,7 @@ func (v Vpc) nukeVpc(spinner *pterm.SpinnerPrinter) error {
 	return nil
 }
 
-func (v Vpc) nuke(spinner *pterm.SpinnerPrinter) error {
+func (v Vpc) nuke(ctx context.Context, spinner *pterm.SpinnerPrinter) error {
 	logging.Logger.Debugf("Nuking VPC %s in region %s", v.VpcId, v.Region)
 	spinner.UpdateText(fmt.Sprintf("Nuking VPC %s in region %s", v.VpcId, v.Region))
 
@@ -614,7 +699,7 @@ func (v Vpc) nuke(spinner *pterm.SpinnerPrinter) error {
 		return err
 	}
 
-	err = v.nukeEndpoints(spinner)
+	err = v.nukeEndpoints(ctx, spinner)
 	if err != nil {
 		logging.Logger.Debugf("Error cleaning up Endpoints for VPC %s: %s", v.VpcId, err.Error())
 		return err
@@ -678,11 +763,12 @@ func NukeVpcs(vpcs []Vpc) error {
 	}
 
 	for _, vpc := range vpcs {
-		err := vpc.nuke(spinnerSuccess)
+		err := vpc.nuke(context.Background(), spinnerSuccess)
 		// Record status of this resource
 		e := report.Entry{
 			Identifier:   vpc.VpcId,
 			ResourceType: "VPC",
+			Region:       vpc.Region,
 			Error:        err,
 		}
 		report.Record(e)
@@ -705,15 +791,17 @@ type DefaultSecurityGroup struct {
 
 func DescribeDefaultSecurityGroups(svc ec2iface.EC2API) ([]string, error) {
 	var groupIds []string
-	securityGroups, err := svc.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{})
+	err := svc.DescribeSecurityGroupsPages(&ec2.DescribeSecurityGroupsInput{}, func(page *ec2.DescribeSecurityGroupsOutput, lastPage bool) bool {
+		for _, securityGroup := range page.SecurityGroups {
+			if *securityGroup.GroupName == "default" {
+				groupIds = append(groupIds, awsgo.StringValue(securityGroup.GroupId))
+			}
+		}
+		return !lastPage
+	})
 	if err != nil {
 		return []string{}, errors.WithStackTrace(err)
 	}
-	for _, securityGroup := range securityGroups.SecurityGroups {
-		if *securityGroup.GroupName == "default" {
-			groupIds = append(groupIds, awsgo.StringValue(securityGroup.GroupId))
-		}
-	}
 	return groupIds, nil
 }
 
@@ -825,6 +913,7 @@ func NukeDefaultSecurityGroupRules(sgs []DefaultSecurityGroup) error {
 		e := report.Entry{
 			Identifier:   sg.GroupId,
 			ResourceType: "Default Security Group",
+			Region:       sg.Region,
 			Error:        err,
 		}
 		report.Record(e)
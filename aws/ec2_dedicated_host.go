@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -17,7 +18,7 @@ import (
 )
 
 func getAllEc2DedicatedHosts(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 	var hostIds []*string
 
 	describeHostsInput := &ec2.DescribeHostsInput{
@@ -37,6 +38,9 @@ func getAllEc2DedicatedHosts(session *session.Session, excludeAfter time.Time, c
 		describeHostsInput,
 		func(page *ec2.DescribeHostsOutput, lastPage bool) bool {
 			for _, host := range page.Hosts {
+				if excludedByTag(EC2DedicatedHosts{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(host.HostId), ec2TagsToMap(host.Tags)) {
+					continue
+				}
 				if shouldIncludeHostId(host, excludeAfter, configObj) {
 					hostIds = append(hostIds, host.HostId)
 				}
@@ -68,18 +72,18 @@ func shouldIncludeHostId(host *ec2.Host, excludeAfter time.Time, configObj confi
 	}
 
 	// If Name is unset, GetEC2ResourceNameTagValue returns error and zero value string
-	// Ignore this error and pass empty string to config.ShouldInclude
+	// Ignore this error and use an empty name
 	hostNameTagValue, _ := GetEC2ResourceNameTagValue(host.Tags)
 
-	return config.ShouldInclude(
-		hostNameTagValue,
-		configObj.EC2DedicatedHosts.IncludeRule.NamesRegExp,
-		configObj.EC2DedicatedHosts.ExcludeRule.NamesRegExp,
-	)
+	return configObj.EC2DedicatedHosts.ShouldInclude(config.ResourceValue{
+		Name: hostNameTagValue,
+		Tags: ec2TagsToMap(host.Tags),
+		ID:   aws.StringValue(host.HostId),
+	})
 }
 
-func nukeAllEc2DedicatedHosts(session *session.Session, hostIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllEc2DedicatedHosts(ctx context.Context, session *session.Session, hostIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(hostIds) == 0 {
 		logging.Logger.Debugf("No EC2 dedicated hosts to nuke in region %s", *session.Config.Region)
@@ -90,7 +94,7 @@ func nukeAllEc2DedicatedHosts(session *session.Session, hostIds []*string) error
 
 	input := &ec2.ReleaseHostsInput{HostIds: hostIds}
 
-	releaseResult, err := svc.ReleaseHosts(input)
+	releaseResult, err := svc.ReleaseHostsWithContext(ctx, input)
 
 	if err != nil {
 		logging.Logger.Debugf("[Failed] %s", err)
@@ -109,7 +113,7 @@ func nukeAllEc2DedicatedHosts(session *session.Session, hostIds []*string) error
 			Identifier:   aws.StringValue(hostSuccess),
 			ResourceType: "EC2 Dedicated Host",
 		}
-		report.Record(e)
+		record(ctx, e)
 	}
 
 	for _, hostFailed := range releaseResult.Unsuccessful {
@@ -124,7 +128,7 @@ func nukeAllEc2DedicatedHosts(session *session.Session, hostIds []*string) error
 			ResourceType: "EC2 Dedicated Host",
 			Error:        fmt.Errorf(*hostFailed.Error.Message),
 		}
-		report.Record(e)
+		record(ctx, e)
 	}
 
 	return nil
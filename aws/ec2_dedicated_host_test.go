@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
@@ -38,7 +39,7 @@ func TestListDedicatedHosts(t *testing.T) {
 	createdHostIds, err := allocateDedicatedHosts(svc, 1)
 	require.NoError(t, err)
 
-	defer nukeAllEc2DedicatedHosts(session, createdHostIds)
+	defer nukeAllEc2DedicatedHosts(context.Background(), session, createdHostIds)
 
 	// test if created allocation matches get response
 	hostIds, err := getAllEc2DedicatedHosts(session, time.Now(), config.Config{})
@@ -70,7 +71,7 @@ func TestNukeDedicatedHosts(t *testing.T) {
 	createdHostIds, err := allocateDedicatedHosts(svc, 1)
 	require.NoError(t, err)
 
-	err = nukeAllEc2DedicatedHosts(session, createdHostIds)
+	err = nukeAllEc2DedicatedHosts(context.Background(), session, createdHostIds)
 	require.NoError(t, err)
 
 	hostIds, err := getAllEc2DedicatedHosts(session, time.Now(), config.Config{})
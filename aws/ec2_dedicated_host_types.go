@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,24 @@ func (h EC2DedicatedHosts) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (h EC2DedicatedHosts) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllEc2DedicatedHosts(session, awsgo.StringSlice(identifiers)); err != nil {
+func (h EC2DedicatedHosts) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllEc2DedicatedHosts(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:         EC2DedicatedHosts{}.ResourceName(),
+		Description:  "EC2 Dedicated Hosts",
+		ConfigKey:    "EC2DedicatedHosts",
+		SupportsTags: true,
+		DependsOn:    []string{"ec2"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEc2DedicatedHosts(session, params.ExcludeAfter, params.Config)
+			return EC2DedicatedHosts{HostIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
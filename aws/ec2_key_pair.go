@@ -1,12 +1,15 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/gruntwork-cli/errors"
@@ -15,7 +18,7 @@ import (
 
 // getAllEc2KeyPairs extracts the list of existing ec2 key pairs.
 func getAllEc2KeyPairs(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
 	result, err := svc.DescribeKeyPairs(&ec2.DescribeKeyPairsInput{})
 	if err != nil {
@@ -24,6 +27,9 @@ func getAllEc2KeyPairs(session *session.Session, excludeAfter time.Time, configO
 
 	var ids []*string
 	for _, keyPair := range result.KeyPairs {
+		if excludedByTag(EC2KeyPairs{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(keyPair.KeyPairId), ec2TagsToMap(keyPair.Tags)) {
+			continue
+		}
 		if shouldIncludeEc2KeyPair(keyPair, excludeAfter, configObj) {
 			ids = append(ids, keyPair.KeyPairId)
 		}
@@ -41,20 +47,20 @@ func shouldIncludeEc2KeyPair(keyPairInfo *ec2.KeyPairInfo, excludeAfter time.Tim
 		return false
 	}
 
-	return config.ShouldInclude(
-		*keyPairInfo.KeyName,
-		configObj.EC2KeyPairs.IncludeRule.NamesRegExp,
-		configObj.EC2KeyPairs.ExcludeRule.NamesRegExp,
-	)
+	return configObj.EC2KeyPairs.ShouldInclude(config.ResourceValue{
+		Name: *keyPairInfo.KeyName,
+		Tags: ec2TagsToMap(keyPairInfo.Tags),
+		ID:   aws.StringValue(keyPairInfo.KeyPairId),
+	})
 }
 
 // deleteKeyPair is a helper method that deletes the given ec2 key pair.
-func deleteKeyPair(svc *ec2.EC2, keyPairId *string) error {
+func deleteKeyPair(ctx context.Context, svc ec2iface.EC2API, keyPairId *string) error {
 	params := &ec2.DeleteKeyPairInput{
 		KeyPairId: keyPairId,
 	}
 
-	_, err := svc.DeleteKeyPair(params)
+	_, err := svc.DeleteKeyPairWithContext(ctx, params)
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
@@ -63,8 +69,8 @@ func deleteKeyPair(svc *ec2.EC2, keyPairId *string) error {
 }
 
 // nukeAllEc2KeyPairs attempts to delete given ec2 key pair IDs.
-func nukeAllEc2KeyPairs(session *session.Session, keypairIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllEc2KeyPairs(ctx context.Context, session *session.Session, keypairIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(keypairIds) == 0 {
 		logging.Logger.Infof("No EC2 key pairs to nuke in region %s", *session.Config.Region)
@@ -76,7 +82,7 @@ func nukeAllEc2KeyPairs(session *session.Session, keypairIds []*string) error {
 	deletedKeyPairs := 0
 	var multiErr *multierror.Error
 	for _, keypair := range keypairIds {
-		if err := deleteKeyPair(svc, keypair); err != nil {
+		if err := deleteKeyPair(ctx, svc, keypair); err != nil {
 			telemetry.TrackEvent(commonTelemetry.EventContext{
 				EventName: "Error Nuking EC2 Key Pair",
 			}, map[string]interface{}{
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -52,7 +53,7 @@ func TestEc2KeyPairListAndNuke(t *testing.T) {
 	assert.Contains(t, awsgo.StringValueSlice(keyPairIds), *createdKeyPair.KeyPairId)
 
 	// Note: nuking the ec2 key pair created for testing purpose
-	err = nukeAllEc2KeyPairs(testSession, []*string{createdKeyPair.KeyPairId})
+	err = nukeAllEc2KeyPairs(context.Background(), testSession, []*string{createdKeyPair.KeyPairId})
 	require.NoError(t, err)
 
 	// Check whether the key still exist or not.
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -25,10 +27,23 @@ func (k EC2KeyPairs) MaxBatchSize() int {
 	return 200
 }
 
-func (k EC2KeyPairs) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllEc2KeyPairs(session, awsgo.StringSlice(identifiers)); err != nil {
+func (k EC2KeyPairs) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllEc2KeyPairs(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:         EC2KeyPairs{}.ResourceName(),
+		Description:  "EC2 Key Pairs",
+		ConfigKey:    "EC2KeyPairs",
+		SupportsTags: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEc2KeyPairs(session, params.ExcludeAfter, params.Config)
+			return EC2KeyPairs{KeyPairIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
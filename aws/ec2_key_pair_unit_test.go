@@ -0,0 +1,88 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+)
+
+// fakeEC2KeyPairs is an in-process EC2 backend holding key pairs in memory. Only the key pair operations used by
+// cloud-nuke are implemented, calling any other operation panics.
+type fakeEC2KeyPairs struct {
+	ec2iface.EC2API
+
+	keyPairs []*ec2.KeyPairInfo
+}
+
+func (fake *fakeEC2KeyPairs) addKeyPair(id string, name string, createdAt time.Time, tags map[string]string) {
+	keyPair := &ec2.KeyPairInfo{KeyPairId: awsgo.String(id), KeyName: awsgo.String(name), CreateTime: awsgo.Time(createdAt)}
+	for key, value := range tags {
+		keyPair.Tags = append(keyPair.Tags, &ec2.Tag{Key: awsgo.String(key), Value: awsgo.String(value)})
+	}
+	fake.keyPairs = append(fake.keyPairs, keyPair)
+}
+
+func (fake *fakeEC2KeyPairs) DescribeKeyPairs(input *ec2.DescribeKeyPairsInput) (*ec2.DescribeKeyPairsOutput, error) {
+	return &ec2.DescribeKeyPairsOutput{KeyPairs: fake.keyPairs}, nil
+}
+
+func (fake *fakeEC2KeyPairs) DeleteKeyPairWithContext(_ awsgo.Context, input *ec2.DeleteKeyPairInput, _ ...request.Option) (*ec2.DeleteKeyPairOutput, error) {
+	for i, keyPair := range fake.keyPairs {
+		if awsgo.StringValue(keyPair.KeyPairId) == awsgo.StringValue(input.KeyPairId) {
+			fake.keyPairs = append(fake.keyPairs[:i], fake.keyPairs[i+1:]...)
+			return &ec2.DeleteKeyPairOutput{}, nil
+		}
+	}
+	return nil, awserr.New("InvalidKeyPair.NotFound", "The key pair does not exist", nil)
+}
+
+func TestListEc2KeyPairsOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	now := time.Now()
+	fake := &fakeEC2KeyPairs{}
+	fake.addKeyPair("key-old", "old", now.Add(-2*time.Hour), nil)
+	fake.addKeyPair("key-new", "new", now, nil)
+	fake.addKeyPair("key-excluded", "excluded", now.Add(-2*time.Hour), map[string]string{AwsResourceExclusionTagKey: "true"})
+	fake.addKeyPair("key-included", "ci-key", now.Add(-2*time.Hour), nil)
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+
+	configObj := config.Config{EC2KeyPairs: config.ResourceType{
+		IncludeRule: config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile("^ci-")}}},
+	}}
+	ids, err := getAllEc2KeyPairs(newFakeSession(t, "us-east-1"), now.Add(-1*time.Hour), configObj)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-included"}, awsgo.StringValueSlice(ids))
+
+	ids, err = getAllEc2KeyPairs(newFakeSession(t, "us-east-1"), now.Add(-1*time.Hour), config.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-old", "key-included"}, awsgo.StringValueSlice(ids))
+}
+
+func TestNukeEc2KeyPairsOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+
+	fake := &fakeEC2KeyPairs{}
+	fake.addKeyPair("key-1", "one", time.Now(), nil)
+	fake.addKeyPair("key-2", "two", time.Now(), nil)
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+
+	err := nukeAllEc2KeyPairs(context.Background(), newFakeSession(t, "us-east-1"), awsgo.StringSlice([]string{"key-1", "key-missing"}))
+	assert.Error(t, err)
+	require.Len(t, fake.keyPairs, 1)
+	assert.Equal(t, "key-2", awsgo.StringValue(fake.keyPairs[0].KeyPairId))
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
@@ -204,7 +205,7 @@ func TestListInstances(t *testing.T) {
 	instance := createTestEC2Instance(t, session, uniqueTestID, false)
 	protectedInstance := createTestEC2Instance(t, session, uniqueTestID, true)
 	// clean up after this test
-	defer nukeAllEc2Instances(session, []*string{instance.InstanceId, protectedInstance.InstanceId})
+	defer nukeAllEc2Instances(context.Background(), session, []*string{instance.InstanceId, protectedInstance.InstanceId})
 
 	instanceIds, err := getAllEc2Instances(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -249,7 +250,7 @@ func TestNukeInstances(t *testing.T) {
 
 	instanceIds := findEC2InstancesByNameTag(t, session, uniqueTestID)
 
-	if err := nukeAllEc2Instances(session, instanceIds); err != nil {
+	if err := nukeAllEc2Instances(context.Background(), session, instanceIds); err != nil {
 		assert.Fail(t, gruntworkerrors.WithStackTrace(err).Error())
 	}
 	instances, err := getAllEc2Instances(session, region, time.Now().Add(1*time.Hour), config.Config{})
@@ -363,6 +364,37 @@ func TestShouldIncludeInstanceId(t *testing.T) {
 		},
 	}
 
+	mockTagValueExpression, err := regexp.Compile("^Bar$")
+	if err != nil {
+		logging.Logger.Fatalf("There was an error compiling regex expression %v", err)
+	}
+
+	mockTagExcludeConfig := config.Config{
+		EC2: config.ResourceType{
+			ExcludeRule: config.FilterRule{
+				Tags: []config.TagMatcher{
+					{
+						Key:         "Foo",
+						ValueRegExp: &config.Expression{RE: *mockTagValueExpression},
+					},
+				},
+			},
+		},
+	}
+
+	mockTagIncludeConfig := config.Config{
+		EC2: config.ResourceType{
+			IncludeRule: config.FilterRule{
+				Tags: []config.TagMatcher{
+					{
+						Key:    "owner",
+						Absent: true,
+					},
+				},
+			},
+		},
+	}
+
 	cases := []struct {
 		Name         string
 		Instance     *ec2.Instance
@@ -371,6 +403,22 @@ func TestShouldIncludeInstanceId(t *testing.T) {
 		Protected    bool
 		Expected     bool
 	}{
+		{
+			Name:         "ConfigTagExclude",
+			Instance:     mockInstance,
+			Config:       mockTagExcludeConfig,
+			ExcludeAfter: time.Now().Add(1 * time.Hour),
+			Protected:    false,
+			Expected:     false,
+		},
+		{
+			Name:         "ConfigTagInclude",
+			Instance:     mockInstance,
+			Config:       mockTagIncludeConfig,
+			ExcludeAfter: time.Now().Add(1 * time.Hour),
+			Protected:    false,
+			Expected:     true,
+		},
 		{
 			Name:         "ConfigExclude",
 			Instance:     mockInstance,
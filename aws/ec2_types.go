@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,8 +29,17 @@ func (instance EC2Instances) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (instance EC2Instances) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllEc2Instances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (instance EC2Instances) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllEc2Instances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Quarantine - stop the ec2 instances, so that their owners can start them again
+func (instance EC2Instances) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineEc2Instances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -56,10 +67,55 @@ func (v EC2VPCs) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (v EC2VPCs) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllVPCs(session, identifiers, v.VPCs); err != nil {
+func (v EC2VPCs) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllVPCs(ctx, session, identifiers, v.VPCs); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:               EC2Instances{}.ResourceName(),
+		Description:        "EC2 Instances",
+		ConfigKey:          "EC2",
+		SupportsTags:       true,
+		SupportsRuleAge:    true,
+		SupportsQuarantine: true,
+		UndoQuarantine:     undoEc2InstanceQuarantine,
+		DependsOn:          []string{"asg"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEc2Instances(session, params.Region, params.ExcludeAfter, params.Config)
+			return EC2Instances{InstanceIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+	RegisterResourceType(ResourceRegistration{
+		Name:         EC2VPCs{}.ResourceName(),
+		Description:  "VPCs",
+		ConfigKey:    "VPC",
+		SupportsTags: true,
+		DependsOn: []string{
+			"asg",
+			"ec2",
+			"eip",
+			"elb",
+			"elbv2",
+			"nat-gateway",
+			"transit-gateway-attachment",
+			"ecsserv",
+			"ekscluster",
+			"rds",
+			"rds-cluster",
+			"lambda",
+			"elasticache",
+			"opensearchdomain",
+			"efs",
+			"sagemaker-notebook-instance",
+		},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, vpcs, err := getAllVpcs(session, params.Region, params.ExcludeAfter, params.Config)
+			return EC2VPCs{VPCIds: awsgo.StringValueSlice(ids), VPCs: vpcs}, err
+		},
+	})
+}
@@ -9,11 +9,15 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 
 	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/golang/mock/gomock"
 	mock_ec2iface "github.com/tnn-gruntwork-io/cloud-nuke/aws/mocks"
 	"github.com/stretchr/testify/assert"
@@ -236,17 +240,19 @@ func TestNukeDefaultSecurityGroups(t *testing.T) {
 	}
 	describeSecurityGroupsInput := getDescribeSecurityGroupsInputEmpty()
 	describeSecurityGroupsOutputOne := getDescribeDefaultSecurityGroupsOutput(groups[0:2])
-	describeSecurityGroupsFuncOne := func(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
-		return describeSecurityGroupsOutputOne, nil
+	describeSecurityGroupsFuncOne := func(input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) error {
+		fn(describeSecurityGroupsOutputOne, true)
+		return nil
 	}
 	describeSecurityGroupsOutputTwo := getDescribeDefaultSecurityGroupsOutput(groups[2:])
-	describeSecurityGroupsFuncTwo := func(input *ec2.DescribeSecurityGroupsInput) (*ec2.DescribeSecurityGroupsOutput, error) {
-		return describeSecurityGroupsOutputTwo, nil
+	describeSecurityGroupsFuncTwo := func(input *ec2.DescribeSecurityGroupsInput, fn func(*ec2.DescribeSecurityGroupsOutput, bool) bool) error {
+		fn(describeSecurityGroupsOutputTwo, true)
+		return nil
 	}
 
 	gomock.InOrder(
-		mockEC2.EXPECT().DescribeSecurityGroups(describeSecurityGroupsInput).DoAndReturn(describeSecurityGroupsFuncOne),
-		mockEC2.EXPECT().DescribeSecurityGroups(describeSecurityGroupsInput).DoAndReturn(describeSecurityGroupsFuncTwo),
+		mockEC2.EXPECT().DescribeSecurityGroupsPages(describeSecurityGroupsInput, gomock.Any()).DoAndReturn(describeSecurityGroupsFuncOne),
+		mockEC2.EXPECT().DescribeSecurityGroupsPages(describeSecurityGroupsInput, gomock.Any()).DoAndReturn(describeSecurityGroupsFuncTwo),
 		mockEC2.EXPECT().RevokeSecurityGroupIngress(groups[0].getDefaultSecurityGroupIngressRule()),
 		mockEC2.EXPECT().RevokeSecurityGroupEgress(groups[0].getDefaultSecurityGroupEgressRule()),
 		mockEC2.EXPECT().RevokeSecurityGroupEgress(groups[0].getDefaultSecurityGroupIPv6EgressRule()),
@@ -321,3 +327,131 @@ func TestNukeDefaultSecurityGroups(t *testing.T) {
 // 		assert.Len(t, result.Vpcs, 0)
 // 	}
 // }
+
+// fakeEC2Instances is an in-process EC2 backend holding instances in memory. Only the operations used to quarantine
+// instances and to undo it are implemented, calling any other operation panics.
+type fakeEC2Instances struct {
+	ec2iface.EC2API
+
+	instances map[string]*ec2.Instance
+}
+
+func (fake *fakeEC2Instances) DescribeInstancesPagesWithContext(_ awsgo.Context, input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool, _ ...request.Option) error {
+	// Return one instance per page, to make sure every page is looked at
+	for i, id := range input.InstanceIds {
+		page := &ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: []*ec2.Instance{fake.instances[*id]}}}}
+		if !fn(page, i == len(input.InstanceIds)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (fake *fakeEC2Instances) CreateTagsWithContext(_ awsgo.Context, input *ec2.CreateTagsInput, _ ...request.Option) (*ec2.CreateTagsOutput, error) {
+	for _, id := range input.Resources {
+		instance := fake.instances[*id]
+		for _, tag := range input.Tags {
+			instance.Tags = append(removeEc2Tag(instance.Tags, *tag.Key), tag)
+		}
+	}
+	return &ec2.CreateTagsOutput{}, nil
+}
+
+func (fake *fakeEC2Instances) StopInstancesWithContext(_ awsgo.Context, input *ec2.StopInstancesInput, _ ...request.Option) (*ec2.StopInstancesOutput, error) {
+	for _, id := range input.InstanceIds {
+		fake.instances[*id].State = &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameStopped)}
+	}
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+func (fake *fakeEC2Instances) StartInstances(input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	for _, id := range input.InstanceIds {
+		fake.instances[*id].State = &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameRunning)}
+	}
+	return &ec2.StartInstancesOutput{}, nil
+}
+
+func (fake *fakeEC2Instances) DeleteTags(input *ec2.DeleteTagsInput) (*ec2.DeleteTagsOutput, error) {
+	for _, id := range input.Resources {
+		instance := fake.instances[*id]
+		for _, tag := range input.Tags {
+			instance.Tags = removeEc2Tag(instance.Tags, *tag.Key)
+		}
+	}
+	return &ec2.DeleteTagsOutput{}, nil
+}
+
+func removeEc2Tag(tags []*ec2.Tag, key string) []*ec2.Tag {
+	var kept []*ec2.Tag
+	for _, tag := range tags {
+		if awsgo.StringValue(tag.Key) != key {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
+
+func TestQuarantineEc2InstancesOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	firstQuarantinedAt := "2023-01-02T03:04:05Z"
+	fake := &fakeEC2Instances{instances: map[string]*ec2.Instance{
+		"i-running": {
+			InstanceId: awsgo.String("i-running"),
+			State:      &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameRunning)},
+		},
+		"i-quarantined": {
+			InstanceId: awsgo.String("i-quarantined"),
+			State:      &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameStopped)},
+			Tags:       []*ec2.Tag{{Key: awsgo.String(QuarantineTagKey), Value: awsgo.String(firstQuarantinedAt)}},
+		},
+	}}
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+
+	err := EC2Instances{}.Quarantine(context.Background(), newFakeSession(t, "us-east-1"), []string{"i-running", "i-quarantined"})
+	require.NoError(t, err)
+
+	records := report.GetRecords()
+	recordKey := func(id string) report.RecordKey {
+		return report.RecordKey{ResourceType: EC2Instances{}.ResourceName(), Identifier: id}
+	}
+	for id, instance := range fake.instances {
+		assert.Equal(t, ec2.InstanceStateNameStopped, awsgo.StringValue(instance.State.Name), id)
+		assert.Contains(t, ec2TagsToMap(instance.Tags), QuarantineTagKey, id)
+		entry := records[recordKey(id)]
+		assert.NoError(t, entry.Error)
+		assert.True(t, entry.Quarantined)
+	}
+	// Quarantining an instance again does not restart its grace period
+	assert.Equal(t, firstQuarantinedAt, ec2TagsToMap(fake.instances["i-quarantined"].Tags)[QuarantineTagKey])
+	// The state of the instances is recorded, so that undoing the quarantine only starts the ones that were running
+	assert.Equal(t, ec2.InstanceStateNameRunning, records[recordKey("i-running")].Metadata[metadataPreviousState])
+	assert.Equal(t, ec2.InstanceStateNameStopped, records[recordKey("i-quarantined")].Metadata[metadataPreviousState])
+}
+
+func TestUndoEc2InstanceQuarantineOffline(t *testing.T) {
+	fake := &fakeEC2Instances{instances: map[string]*ec2.Instance{
+		"i-was-running": {
+			InstanceId: awsgo.String("i-was-running"),
+			State:      &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameStopped)},
+			Tags:       []*ec2.Tag{{Key: awsgo.String(QuarantineTagKey), Value: awsgo.String("2023-01-02T03:04:05Z")}},
+		},
+		"i-was-stopped": {
+			InstanceId: awsgo.String("i-was-stopped"),
+			State:      &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameStopped)},
+			Tags:       []*ec2.Tag{{Key: awsgo.String(QuarantineTagKey), Value: awsgo.String("2023-01-02T03:04:05Z")}},
+		},
+	}}
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(fake))
+	session := newFakeSession(t, "us-east-1")
+
+	for id, previousState := range map[string]string{"i-was-running": ec2.InstanceStateNameRunning, "i-was-stopped": ec2.InstanceStateNameStopped} {
+		resource := RunReportResource{Identifier: id, Metadata: map[string]string{metadataPreviousState: previousState}}
+		require.NoError(t, undoEc2InstanceQuarantine(session, resource))
+		assert.NotContains(t, ec2TagsToMap(fake.instances[id].Tags), QuarantineTagKey, id)
+	}
+	assert.Equal(t, ec2.InstanceStateNameRunning, awsgo.StringValue(fake.instances["i-was-running"].State.Name))
+	assert.Equal(t, ec2.InstanceStateNameStopped, awsgo.StringValue(fake.instances["i-was-stopped"].State.Name))
+}
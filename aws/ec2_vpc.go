@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -9,6 +10,7 @@ import (
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -17,7 +19,7 @@ import (
 	"github.com/pterm/pterm"
 )
 
-func setFirstSeenVpcTag(svc *ec2.EC2, vpc ec2.Vpc, key string, value time.Time) error {
+func setFirstSeenVpcTag(svc ec2iface.EC2API, vpc ec2.Vpc, key string, value time.Time) error {
 	// We set a first seen tag because an Elastic IP doesn't contain an attribute that gives us it's creation time
 	_, err := svc.CreateTags(&ec2.CreateTagsInput{
 		Resources: []*string{vpc.VpcId},
@@ -52,9 +54,10 @@ func getFirstSeenVpcTag(vpc ec2.Vpc, key string) (*time.Time, error) {
 }
 
 func getAllVpcs(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, []Vpc, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
-	result, err := svc.DescribeVpcs(&ec2.DescribeVpcsInput{
+	var allVpcs []*ec2.Vpc
+	err := svc.DescribeVpcsPages(&ec2.DescribeVpcsInput{
 		Filters: []*ec2.Filter{
 			// Note: this filter omits the default since there is special
 			// handling for default resources already
@@ -63,6 +66,9 @@ func getAllVpcs(session *session.Session, region string, excludeAfter time.Time,
 				Values: awsgo.StringSlice([]string{"false"}),
 			},
 		},
+	}, func(page *ec2.DescribeVpcsOutput, lastPage bool) bool {
+		allVpcs = append(allVpcs, page.Vpcs...)
+		return !lastPage
 	})
 	if err != nil {
 		return nil, nil, errors.WithStackTrace(err)
@@ -70,7 +76,11 @@ func getAllVpcs(session *session.Session, region string, excludeAfter time.Time,
 
 	var ids []*string
 	var vpcs []Vpc
-	for _, vpc := range result.Vpcs {
+	for _, vpc := range allVpcs {
+		if excludedByTag(EC2VPCs{}.ResourceName(), region, awsgo.StringValue(vpc.VpcId), ec2TagsToMap(vpc.Tags)) {
+			continue
+		}
+
 		firstSeenTime, err := getFirstSeenVpcTag(*vpc, firstSeenTagKey)
 		if err != nil {
 			logging.Logger.Error("Unable to retrieve tags")
@@ -109,17 +119,17 @@ func shouldIncludeVpc(vpc *ec2.Vpc, excludeAfter time.Time, firstSeenTime time.T
 	}
 
 	// If Name is unset, GetEC2ResourceNameTagValue returns error and zero value string
-	// Ignore this error and pass empty string to config.ShouldInclude
+	// Ignore this error and use an empty name
 	vpcName, _ := GetEC2ResourceNameTagValue(vpc.Tags)
 
-	return config.ShouldInclude(
-		vpcName,
-		configObj.VPC.IncludeRule.NamesRegExp,
-		configObj.VPC.ExcludeRule.NamesRegExp,
-	)
+	return configObj.VPC.ShouldInclude(config.ResourceValue{
+		Name: vpcName,
+		Tags: ec2TagsToMap(vpc.Tags),
+		ID:   awsgo.StringValue(vpc.VpcId),
+	})
 }
 
-func nukeAllVPCs(session *session.Session, vpcIds []string, vpcs []Vpc) error {
+func nukeAllVPCs(ctx context.Context, session *session.Session, vpcIds []string, vpcs []Vpc) error {
 	if len(vpcIds) == 0 {
 		logging.Logger.Debug("No VPCs to nuke")
 		return nil
@@ -142,14 +152,17 @@ func nukeAllVPCs(session *session.Session, vpcIds []string, vpcs []Vpc) error {
 	multiErr := new(multierror.Error)
 
 	for _, vpc := range vpcs {
-		err := vpc.nuke(spinnerSuccess)
+		if ctx.Err() != nil {
+			break
+		}
+		err := vpc.nuke(ctx, spinnerSuccess)
 		// Record status of this resource
 		e := report.Entry{
 			Identifier:   vpc.VpcId,
 			ResourceType: "VPC",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -83,7 +84,7 @@ func TestCanTagVpc(t *testing.T) {
 	svc := ec2.New(awsSession)
 
 	// clean up after this test
-	defer nukeAllVPCs(awsSession, []string{vpcId}, []Vpc{{
+	defer nukeAllVPCs(context.Background(), awsSession, []string{vpcId}, []Vpc{{
 		Region: region,
 		VpcId:  vpcId,
 		svc:    svc,
@@ -137,7 +138,7 @@ func TestListVpcs(t *testing.T) {
 	vpcId := createTestVpc(t, session)
 
 	// clean up after this test
-	defer nukeAllVPCs(session, []string{vpcId}, []Vpc{{
+	defer nukeAllVPCs(context.Background(), session, []string{vpcId}, []Vpc{{
 		Region: region,
 		VpcId:  vpcId,
 		svc:    ec2.New(session),
@@ -170,7 +171,7 @@ func TestNukeVpcs(t *testing.T) {
 	vpcId := createTestVpc(t, session)
 
 	// clean up after this test
-	err = nukeAllVPCs(session, []string{vpcId}, []Vpc{{
+	err = nukeAllVPCs(context.Background(), session, []string{vpcId}, []Vpc{{
 		Region: region,
 		VpcId:  vpcId,
 		svc:    ec2.New(session),
@@ -205,7 +206,7 @@ func TestNukeVpcsWithEgressGateway(t *testing.T) {
 	vpcId := createTestVpcWithEgressGateway(t, awsSession)
 
 	// clean up after this test
-	err = nukeAllVPCs(awsSession, []string{vpcId}, []Vpc{{
+	err = nukeAllVPCs(context.Background(), awsSession, []string{vpcId}, []Vpc{{
 		Region: region,
 		VpcId:  vpcId,
 		svc:    ec2.New(awsSession),
@@ -240,7 +241,7 @@ func TestNukeVpcsWithNetworkInterface(t *testing.T) {
 	vpcId := createTestVpcWithNetworkInterface(t, awsSession)
 
 	// clean up after this test
-	err = nukeAllVPCs(awsSession, []string{vpcId}, []Vpc{{
+	err = nukeAllVPCs(context.Background(), awsSession, []string{vpcId}, []Vpc{{
 		Region: region,
 		VpcId:  vpcId,
 		svc:    ec2.New(awsSession),
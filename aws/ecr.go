@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ecr"
+	"github.com/aws/aws-sdk-go/service/ecr/ecriface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -15,16 +17,12 @@ import (
 )
 
 func getAllECRRepositories(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]string, error) {
-	svc := ecr.New(session)
+	svc := newECRClient(session)
 
-	repositoryNames := []string{}
+	repositories := []*ecr.Repository{}
 
 	paginator := func(output *ecr.DescribeRepositoriesOutput, lastPage bool) bool {
-		for _, repository := range output.Repositories {
-			if shouldIncludeECRRepository(repository, excludeAfter, configObj) {
-				repositoryNames = append(repositoryNames, aws.StringValue(repository.RepositoryName))
-			}
-		}
+		repositories = append(repositories, output.Repositories...)
 		return !lastPage
 	}
 
@@ -35,10 +33,39 @@ func getAllECRRepositories(session *session.Session, excludeAfter time.Time, con
 		return nil, errors.WithStackTrace(err)
 	}
 
+	repositoryNames := []string{}
+	for _, repository := range repositories {
+		// Tags are always looked up, so that repositories carrying the exclusion tag are never nuked
+		tags, err := getECRRepositoryTags(svc, repository.RepositoryArn)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		if excludedByTag(ECR{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(repository.RepositoryName), tags) {
+			continue
+		}
+		if shouldIncludeECRRepository(repository, tags, excludeAfter, configObj) {
+			repositoryNames = append(repositoryNames, aws.StringValue(repository.RepositoryName))
+		}
+	}
+
 	return repositoryNames, nil
 }
 
-func shouldIncludeECRRepository(repository *ecr.Repository, excludeAfter time.Time, configObj config.Config) bool {
+// getECRRepositoryTags returns the tags of the given ECR repository as a map of tag keys to values
+func getECRRepositoryTags(svc ecriface.ECRAPI, repositoryArn *string) (map[string]string, error) {
+	output, err := svc.ListTagsForResource(&ecr.ListTagsForResourceInput{ResourceArn: repositoryArn})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+func shouldIncludeECRRepository(repository *ecr.Repository, tags map[string]string, excludeAfter time.Time, configObj config.Config) bool {
 	if repository == nil {
 		return false
 	}
@@ -49,15 +76,15 @@ func shouldIncludeECRRepository(repository *ecr.Repository, excludeAfter time.Ti
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(repository.RepositoryName),
-		configObj.ECRRepository.IncludeRule.NamesRegExp,
-		configObj.ECRRepository.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ECRRepository.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(repository.RepositoryName),
+		Tags: tags,
+		Time: createdAtVal,
+	})
 }
 
-func nukeAllECRRepositories(session *session.Session, repositoryNames []string) error {
-	svc := ecr.New(session)
+func nukeAllECRRepositories(ctx context.Context, session *session.Session, repositoryNames []string) error {
+	svc := newECRClient(session)
 
 	if len(repositoryNames) == 0 {
 		logging.Logger.Debugf("No ECR repositories to nuke in region %s", *session.Config.Region)
@@ -72,7 +99,7 @@ func nukeAllECRRepositories(session *session.Session, repositoryNames []string)
 			RepositoryName: aws.String(repositoryName),
 		}
 
-		_, err := svc.DeleteRepository(params)
+		_, err := svc.DeleteRepositoryWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -80,7 +107,7 @@ func nukeAllECRRepositories(session *session.Session, repositoryNames []string)
 			ResourceType: "ECR Repository",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			telemetry.TrackEvent(commonTelemetry.EventContext{
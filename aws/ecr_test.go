@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -89,7 +90,7 @@ func TestNukeECRRepositoryOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllECRRepositories(session, aws.StringValueSlice(identifiers)),
+		nukeAllECRRepositories(context.Background(), session, aws.StringValueSlice(identifiers)),
 	)
 
 	assertECRRepositoriesDeleted(t, region, identifiers)
@@ -114,7 +115,7 @@ func TestNukeECRRepositoryMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllECRRepositories(session, aws.StringValueSlice(repositoryNames)),
+		nukeAllECRRepositories(context.Background(), session, aws.StringValueSlice(repositoryNames)),
 	)
 
 	assertECRRepositoriesDeleted(t, region, repositoryNames)
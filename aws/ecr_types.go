@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
@@ -21,10 +23,24 @@ func (registry ECR) MaxBatchSize() int {
 	return 50
 }
 
-func (registry ECR) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllECRRepositories(session, identifiers); err != nil {
+func (registry ECR) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllECRRepositories(ctx, session, identifiers); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            ECR{}.ResourceName(),
+		Description:     "ECR Repositories",
+		ConfigKey:       "ECRRepository",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllECRRepositories(session, params.ExcludeAfter, params.Config)
+			return ECR{RepositoryNames: ids}, err
+		},
+	})
+}
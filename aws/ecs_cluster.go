@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -25,7 +26,7 @@ const describeClustersRequestBatchSize = 100
 
 // Filter all active ecs clusters
 func getAllActiveEcsClusterArns(awsSession *session.Session, configObj config.Config) ([]*string, error) {
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
 
 	allClusters, err := getAllEcsClusters(awsSession)
 	if err != nil {
@@ -70,11 +71,10 @@ func shouldIncludeECSCluster(cluster *ecs.Cluster, configObj config.Config) bool
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(cluster.ClusterName),
-		configObj.ECSCluster.IncludeRule.NamesRegExp,
-		configObj.ECSCluster.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ECSCluster.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(cluster.ClusterName),
+		ID:   awsgo.StringValue(cluster.ClusterArn),
+	})
 }
 
 func getAllEcsClustersOlderThan(awsSession *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
@@ -106,8 +106,8 @@ func getAllEcsClustersOlderThan(awsSession *session.Session, excludeAfter time.T
 	return filteredEcsClusters, nil
 }
 
-func nukeEcsClusters(awsSession *session.Session, ecsClusterArns []*string) error {
-	svc := ecs.New(awsSession)
+func nukeEcsClusters(ctx context.Context, awsSession *session.Session, ecsClusterArns []*string) error {
+	svc := newECSClient(awsSession)
 
 	numNuking := len(ecsClusterArns)
 
@@ -123,7 +123,7 @@ func nukeEcsClusters(awsSession *session.Session, ecsClusterArns []*string) erro
 		params := &ecs.DeleteClusterInput{
 			Cluster: clusterArn,
 		}
-		_, err := svc.DeleteCluster(params)
+		_, err := svc.DeleteClusterWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -131,7 +131,7 @@ func nukeEcsClusters(awsSession *session.Session, ecsClusterArns []*string) erro
 			ResourceType: "ECS Cluster",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("Error, failed to delete cluster with ARN %s", aws.StringValue(clusterArn))
@@ -155,7 +155,7 @@ func nukeEcsClusters(awsSession *session.Session, ecsClusterArns []*string) erro
 
 // Tag an ECS cluster identified by the given cluster ARN when it's first seen by cloud-nuke
 func tagEcsClusterWhenFirstSeen(awsSession *session.Session, clusterArn *string, timestamp time.Time) error {
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
 
 	firstSeenTime := formatTimestampTag(timestamp)
 
@@ -181,7 +181,7 @@ func tagEcsClusterWhenFirstSeen(awsSession *session.Session, clusterArn *string,
 func getFirstSeenEcsClusterTag(awsSession *session.Session, clusterArn *string) (time.Time, error) {
 	var firstSeenTime time.Time
 
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
 	input := &ecs.ListTagsForResourceInput{
 		ResourceArn: clusterArn,
 	}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -128,7 +129,7 @@ func TestCanNukeAllEcsClustersOlderThan24Hours(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	nukeErr := nukeEcsClusters(awsSession, filteredClusterArns)
+	nukeErr := nukeEcsClusters(context.Background(), awsSession, filteredClusterArns)
 	require.NoError(t, nukeErr)
 
 	allLeftClusterArns, err := getAllEcsClusters(awsSession)
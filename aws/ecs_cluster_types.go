@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -33,9 +35,22 @@ func (clusters ECSClusters) MaxBatchSize() int {
 }
 
 // Nuke - nuke all ECS Cluster resources
-func (clusters ECSClusters) Nuke(awsSession *session.Session, identifiers []string) error {
-	if err := nukeEcsClusters(awsSession, awsgo.StringSlice(identifiers)); err != nil {
+func (clusters ECSClusters) Nuke(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := nukeEcsClusters(ctx, awsSession, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        ECSClusters{}.ResourceName(),
+		Description: "ECS Clusters",
+		ConfigKey:   "ECSCluster",
+		DependsOn:   []string{"ecsserv", "ec2", "asg"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEcsClustersOlderThan(session, params.ExcludeAfter, params.Config)
+			return ECSClusters{ClusterArns: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
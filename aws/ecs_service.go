@@ -1,14 +1,17 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go/service/ecs/ecsiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,22 +21,15 @@ import (
 // getAllEcsClusters - Returns a string of ECS Cluster ARNs, which uniquely identifies the cluster.
 // We need to get all clusters before we can get all services.
 func getAllEcsClusters(awsSession *session.Session) ([]*string, error) {
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
 	clusterArns := []*string{}
-	result, err := svc.ListClusters(&ecs.ListClustersInput{})
+	err := svc.ListClustersPages(&ecs.ListClustersInput{}, func(page *ecs.ListClustersOutput, lastPage bool) bool {
+		clusterArns = append(clusterArns, page.ClusterArns...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
-	clusterArns = append(clusterArns, result.ClusterArns...)
-
-	// Handle pagination: continuously pull the next page if nextToken is set
-	for awsgo.StringValue(result.NextToken) != "" {
-		result, err = svc.ListClusters(&ecs.ListClustersInput{NextToken: result.NextToken})
-		if err != nil {
-			return nil, errors.WithStackTrace(err)
-		}
-		clusterArns = append(clusterArns, result.ClusterArns...)
-	}
 
 	return clusterArns, nil
 }
@@ -41,7 +37,7 @@ func getAllEcsClusters(awsSession *session.Session) ([]*string, error) {
 // filterOutRecentServices - Given a list of services and an excludeAfter
 // timestamp, filter out any services that were created after `excludeAfter.
 // Additionally, filter based on Config file patterns.
-func filterOutRecentServices(svc *ecs.ECS, clusterArn *string, ecsServiceArns []string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+func filterOutRecentServices(svc ecsiface.ECSAPI, region string, clusterArn *string, ecsServiceArns []string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
 	// Fetch descriptions in batches of 10, which is the max that AWS
 	// accepts for describe service.
 	var filteredEcsServiceArns []*string
@@ -50,12 +46,16 @@ func filterOutRecentServices(svc *ecs.ECS, clusterArn *string, ecsServiceArns []
 		params := &ecs.DescribeServicesInput{
 			Cluster:  clusterArn,
 			Services: awsgo.StringSlice(batch),
+			Include:  awsgo.StringSlice([]string{ecs.ServiceFieldTags}),
 		}
 		describeResult, err := svc.DescribeServices(params)
 		if err != nil {
 			return nil, errors.WithStackTrace(err)
 		}
 		for _, service := range describeResult.Services {
+			if excludedByQuarantine(ECSServices{}.ResourceName(), region, awsgo.StringValue(service.ServiceArn), ecsTagsToMap(service.Tags), excludeAfter) {
+				continue
+			}
 			if shouldIncludeECSService(service, excludeAfter, configObj) {
 				filteredEcsServiceArns = append(filteredEcsServiceArns, service.ServiceArn)
 			}
@@ -73,11 +73,10 @@ func shouldIncludeECSService(service *ecs.Service, excludeAfter time.Time, confi
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(service.ServiceName),
-		configObj.ECSService.IncludeRule.NamesRegExp,
-		configObj.ECSService.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ECSService.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(service.ServiceName),
+		ID:   awsgo.StringValue(service.ServiceArn),
+	})
 }
 
 // getAllEcsServices - Returns a formatted string of ECS Service ARNs, which
@@ -87,17 +86,21 @@ func shouldIncludeECSService(service *ecs.Service, excludeAfter time.Time, confi
 // Note that this looks up services by ECS cluster ARNs.
 func getAllEcsServices(awsSession *session.Session, ecsClusterArns []*string, excludeAfter time.Time, configObj config.Config) ([]*string, map[string]string, error) {
 	ecsServiceClusterMap := map[string]string{}
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
 
 	// For each cluster, fetch all services, filtering out recently created
 	// ones.
 	var ecsServiceArns []*string
 	for _, clusterArn := range ecsClusterArns {
-		result, err := svc.ListServices(&ecs.ListServicesInput{Cluster: clusterArn})
+		var serviceArns []*string
+		err := svc.ListServicesPages(&ecs.ListServicesInput{Cluster: clusterArn}, func(page *ecs.ListServicesOutput, lastPage bool) bool {
+			serviceArns = append(serviceArns, page.ServiceArns...)
+			return !lastPage
+		})
 		if err != nil {
 			return nil, nil, errors.WithStackTrace(err)
 		}
-		filteredServiceArns, err := filterOutRecentServices(svc, clusterArn, awsgo.StringValueSlice(result.ServiceArns), excludeAfter, configObj)
+		filteredServiceArns, err := filterOutRecentServices(svc, awsgo.StringValue(awsSession.Config.Region), clusterArn, awsgo.StringValueSlice(serviceArns), excludeAfter, configObj)
 		if err != nil {
 			return nil, nil, errors.WithStackTrace(err)
 		}
@@ -114,7 +117,7 @@ func getAllEcsServices(awsSession *session.Session, ecsClusterArns []*string, ex
 // drainEcsServices - Drain all tasks from all services requested. This will
 // return a list of service ARNs that have been successfully requested to be
 // drained.
-func drainEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
+func drainEcsServices(svc ecsiface.ECSAPI, region string, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
 	var requestedDrains []*string
 	for _, ecsServiceArn := range ecsServiceArns {
 		params := &ecs.UpdateServiceInput{
@@ -128,7 +131,7 @@ func drainEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsS
 			telemetry.TrackEvent(commonTelemetry.EventContext{
 				EventName: "Error Nuking ECS Service",
 			}, map[string]interface{}{
-				"region": *svc.Config.Region,
+				"region": region,
 				"reason": "Unable to drain",
 			})
 		} else {
@@ -142,20 +145,20 @@ func drainEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsS
 // given list of services, by waiting for stability which is defined as
 // desiredCount == runningCount. This will return a list of service ARNs that
 // have successfully been drained.
-func waitUntilServicesDrained(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
+func waitUntilServicesDrained(ctx context.Context, svc ecsiface.ECSAPI, region string, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
 	var successfullyDrained []*string
 	for _, ecsServiceArn := range ecsServiceArns {
 		params := &ecs.DescribeServicesInput{
 			Cluster:  awsgo.String(ecsServiceClusterMap[*ecsServiceArn]),
 			Services: []*string{ecsServiceArn},
 		}
-		err := svc.WaitUntilServicesStable(params)
+		err := svc.WaitUntilServicesStableWithContext(ctx, params)
 		if err != nil {
 			logging.Logger.Debugf("[Failed] Failed waiting for service to be stable %s: %s", *ecsServiceArn, err)
 			telemetry.TrackEvent(commonTelemetry.EventContext{
 				EventName: "Error Nuking ECS Service",
 			}, map[string]interface{}{
-				"region": *svc.Config.Region,
+				"region": region,
 				"reason": "Failed Waiting for Drain",
 			})
 		} else {
@@ -168,7 +171,7 @@ func waitUntilServicesDrained(svc *ecs.ECS, ecsServiceClusterMap map[string]stri
 
 // deleteEcsServices - Deletes all services requested. Returns a list of
 // service ARNs that have been accepted by AWS for deletion.
-func deleteEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
+func deleteEcsServices(svc ecsiface.ECSAPI, region string, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
 	var requestedDeletes []*string
 	for _, ecsServiceArn := range ecsServiceArns {
 		params := &ecs.DeleteServiceInput{
@@ -181,7 +184,7 @@ func deleteEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecs
 			telemetry.TrackEvent(commonTelemetry.EventContext{
 				EventName: "Error Nuking ECS Service",
 			}, map[string]interface{}{
-				"region": *svc.Config.Region,
+				"region": region,
 				"reason": "Unable to Delete",
 			})
 		} else {
@@ -194,14 +197,14 @@ func deleteEcsServices(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecs
 // waitUntilServicesDeleted - Waits until the service has been actually deleted
 // from AWS. Returns a list of service ARNs that have been successfully
 // deleted.
-func waitUntilServicesDeleted(svc *ecs.ECS, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
+func waitUntilServicesDeleted(ctx context.Context, svc ecsiface.ECSAPI, region string, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) []*string {
 	var successfullyDeleted []*string
 	for _, ecsServiceArn := range ecsServiceArns {
 		params := &ecs.DescribeServicesInput{
 			Cluster:  awsgo.String(ecsServiceClusterMap[*ecsServiceArn]),
 			Services: []*string{ecsServiceArn},
 		}
-		err := svc.WaitUntilServicesInactive(params)
+		err := svc.WaitUntilServicesInactiveWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -209,14 +212,14 @@ func waitUntilServicesDeleted(svc *ecs.ECS, ecsServiceClusterMap map[string]stri
 			ResourceType: "ECS Service",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] Failed waiting for service to be deleted %s: %s", *ecsServiceArn, err)
 			telemetry.TrackEvent(commonTelemetry.EventContext{
 				EventName: "Error Nuking ECS Service",
 			}, map[string]interface{}{
-				"region": *svc.Config.Region,
+				"region": region,
 				"reason": "Failed Waiting for Delete",
 			})
 		} else {
@@ -235,9 +238,10 @@ func waitUntilServicesDeleted(svc *ecs.ECS, ecsServiceClusterMap map[string]stri
 // 2.) Delete service object once no tasks are running.
 // Note that this will swallow failed deletes and continue along, logging the
 // service ARN so that we can find it later.
-func nukeAllEcsServices(awsSession *session.Session, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) error {
+func nukeAllEcsServices(ctx context.Context, awsSession *session.Session, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) error {
 	numNuking := len(ecsServiceArns)
-	svc := ecs.New(awsSession)
+	svc := newECSClient(awsSession)
+	region := awsgo.StringValue(awsSession.Config.Region)
 
 	if numNuking == 0 {
 		logging.Logger.Debugf("No ECS services to nuke in region %s", *awsSession.Config.Region)
@@ -252,12 +256,93 @@ func nukeAllEcsServices(awsSession *session.Session, ecsServiceClusterMap map[st
 	// wait for them in a separate loop because it will take a
 	// while to drain the services.
 	// Then, we delete the services that have been successfully drained.
-	requestedDrains := drainEcsServices(svc, ecsServiceClusterMap, ecsServiceArns)
-	successfullyDrained := waitUntilServicesDrained(svc, ecsServiceClusterMap, requestedDrains)
-	requestedDeletes := deleteEcsServices(svc, ecsServiceClusterMap, successfullyDrained)
-	successfullyDeleted := waitUntilServicesDeleted(svc, ecsServiceClusterMap, requestedDeletes)
+	requestedDrains := drainEcsServices(svc, region, ecsServiceClusterMap, ecsServiceArns)
+	successfullyDrained := waitUntilServicesDrained(ctx, svc, region, ecsServiceClusterMap, requestedDrains)
+	requestedDeletes := deleteEcsServices(svc, region, ecsServiceClusterMap, successfullyDrained)
+	successfullyDeleted := waitUntilServicesDeleted(ctx, svc, region, ecsServiceClusterMap, requestedDeletes)
 
 	numNuked := len(successfullyDeleted)
 	logging.Logger.Debugf("[OK] %d of %d ECS service(s) deleted in %s", numNuked, numNuking, *awsSession.Config.Region)
 	return nil
 }
+
+// quarantineEcsServices tags the given ECS services with the quarantine tag and scales them down to zero tasks, so that
+// their owners can scale them up again if they still need them
+func quarantineEcsServices(ctx context.Context, awsSession *session.Session, ecsServiceClusterMap map[string]string, ecsServiceArns []*string) error {
+	svc := newECSClient(awsSession)
+
+	for _, ecsServiceArn := range ecsServiceArns {
+		output, err := svc.ListTagsForResourceWithContext(ctx, &ecs.ListTagsForResourceInput{ResourceArn: ecsServiceArn})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		clusterArn := awsgo.String(ecsServiceClusterMap[*ecsServiceArn])
+		described, err := svc.DescribeServicesWithContext(ctx, &ecs.DescribeServicesInput{Cluster: clusterArn, Services: []*string{ecsServiceArn}})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		metadata := map[string]string{metadataCluster: awsgo.StringValue(clusterArn)}
+		if len(described.Services) > 0 {
+			metadata[metadataDesiredCount] = strconv.FormatInt(awsgo.Int64Value(described.Services[0].DesiredCount), 10)
+		}
+
+		err = quarantineEcsService(ctx, svc, clusterArn, ecsServiceArn, ecsTagsToMap(output.Tags))
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined ECS service: %s", awsgo.StringValue(ecsServiceArn))
+		}
+		recordQuarantine(ctx, ECSServices{}.ResourceName(), awsgo.StringValue(ecsServiceArn), metadata, err)
+	}
+	return nil
+}
+
+func quarantineEcsService(ctx context.Context, svc ecsiface.ECSAPI, clusterArn *string, ecsServiceArn *string, tags map[string]string) error {
+	_, err := svc.TagResourceWithContext(ctx, &ecs.TagResourceInput{
+		ResourceArn: ecsServiceArn,
+		Tags:        []*ecs.Tag{{Key: awsgo.String(QuarantineTagKey), Value: awsgo.String(quarantineTagValue(tags))}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UpdateServiceWithContext(ctx, &ecs.UpdateServiceInput{
+		Cluster:      clusterArn,
+		Service:      ecsServiceArn,
+		DesiredCount: awsgo.Int64(0),
+	})
+	return errors.WithStackTrace(err)
+}
+
+// undoEcsServiceQuarantine gives a quarantined ECS service its desired count back, and removes its quarantine tag
+func undoEcsServiceQuarantine(awsSession *session.Session, resource RunReportResource) error {
+	svc := newECSClient(awsSession)
+	ecsServiceArn := awsgo.String(resource.Identifier)
+
+	desiredCount, err := metadataInt64(resource, metadataDesiredCount)
+	if err != nil {
+		return err
+	}
+	_, err = svc.UpdateService(&ecs.UpdateServiceInput{
+		Cluster:      awsgo.String(resource.Metadata[metadataCluster]),
+		Service:      ecsServiceArn,
+		DesiredCount: awsgo.Int64(desiredCount),
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UntagResource(&ecs.UntagResourceInput{
+		ResourceArn: ecsServiceArn,
+		TagKeys:     []*string{awsgo.String(QuarantineTagKey)},
+	})
+	return errors.WithStackTrace(err)
+}
+
+// ecsTagsToMap converts the tags of an ECS resource into a map of tag keys to values
+func ecsTagsToMap(tags []*ecs.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[awsgo.StringValue(tag.Key)] = awsgo.StringValue(tag.Value)
+	}
+	return tagMap
+}
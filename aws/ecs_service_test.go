@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -44,7 +45,7 @@ func TestListECSFargateServices(t *testing.T) {
 
 	service := createEcsService(t, awsSession, serviceName, cluster, "FARGATE", taskDefinition)
 	ecsServiceClusterMap[*service.ServiceArn] = *cluster.ClusterArn
-	defer nukeAllEcsServices(awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
+	defer nukeAllEcsServices(context.Background(), awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
 
 	ecsServiceArns, newEcsServiceClusterMap, err := getAllEcsServices(awsSession, []*string{cluster.ClusterArn}, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -92,7 +93,7 @@ func TestNukeECSFargateServices(t *testing.T) {
 
 	ecsServiceClusterMap := map[string]string{}
 	ecsServiceClusterMap[*service.ServiceArn] = *cluster.ClusterArn
-	err = nukeAllEcsServices(awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
+	err = nukeAllEcsServices(context.Background(), awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
 	if err != nil {
 		assert.Fail(t, err.Error())
 	}
@@ -142,7 +143,7 @@ func TestListECSEC2Services(t *testing.T) {
 	// forgetting to schedule deletion
 	cluster, instance := createEcsEC2Cluster(t, awsSession, clusterName, instanceProfile)
 	defer deleteEcsCluster(awsSession, cluster)
-	defer nukeAllEc2Instances(awsSession, []*string{instance.InstanceId})
+	defer nukeAllEc2Instances(context.Background(), awsSession, []*string{instance.InstanceId})
 
 	// Finally, define the task and service
 	taskDefinition := createEcsTaskDefinition(t, awsSession, taskFamilyName, "EC2")
@@ -150,7 +151,7 @@ func TestListECSEC2Services(t *testing.T) {
 
 	service := createEcsService(t, awsSession, serviceName, cluster, "EC2", taskDefinition)
 	ecsServiceClusterMap[*service.ServiceArn] = *cluster.ClusterArn
-	defer nukeAllEcsServices(awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
+	defer nukeAllEcsServices(context.Background(), awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
 	// END prepare resources
 
 	ecsServiceArns, newEcsServiceClusterMap, err := getAllEcsServices(awsSession, []*string{cluster.ClusterArn}, time.Now().Add(1*time.Hour*-1), config.Config{})
@@ -208,7 +209,7 @@ func TestNukeECSEC2Services(t *testing.T) {
 	// forgetting to schedule deletion
 	cluster, instance := createEcsEC2Cluster(t, awsSession, clusterName, instanceProfile)
 	defer deleteEcsCluster(awsSession, cluster)
-	defer nukeAllEc2Instances(awsSession, []*string{instance.InstanceId})
+	defer nukeAllEc2Instances(context.Background(), awsSession, []*string{instance.InstanceId})
 
 	// Finally, define the task and service
 	taskDefinition := createEcsTaskDefinition(t, awsSession, taskFamilyName, "EC2")
@@ -218,7 +219,7 @@ func TestNukeECSEC2Services(t *testing.T) {
 	ecsServiceClusterMap[*service.ServiceArn] = *cluster.ClusterArn
 	// END prepare resources
 
-	err = nukeAllEcsServices(awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
+	err = nukeAllEcsServices(context.Background(), awsSession, ecsServiceClusterMap, []*string{service.ServiceArn})
 
 	ecsServiceArns, _, err := getAllEcsServices(awsSession, []*string{cluster.ClusterArn}, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,9 +29,35 @@ func (services ECSServices) MaxBatchSize() int {
 }
 
 // Nuke - nuke all ECS service resources
-func (services ECSServices) Nuke(awsSession *session.Session, identifiers []string) error {
-	if err := nukeAllEcsServices(awsSession, services.ServiceClusterMap, awsgo.StringSlice(identifiers)); err != nil {
+func (services ECSServices) Nuke(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := nukeAllEcsServices(ctx, awsSession, services.ServiceClusterMap, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// Quarantine - scale the ECS services down to zero tasks, so that their owners can scale them up again
+func (services ECSServices) Quarantine(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := quarantineEcsServices(ctx, awsSession, services.ServiceClusterMap, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:               ECSServices{}.ResourceName(),
+		Description:        "ECS Services",
+		ConfigKey:          "ECSService",
+		SupportsQuarantine: true,
+		UndoQuarantine:     undoEcsServiceQuarantine,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			clusterArns, err := getAllEcsClusters(session)
+			if err != nil || len(clusterArns) == 0 {
+				return nil, err
+			}
+			serviceArns, serviceClusterMap, err := getAllEcsServices(session, clusterArns, params.ExcludeAfter, params.Config)
+			return ECSServices{Services: awsgo.StringValueSlice(serviceArns), ServiceClusterMap: serviceClusterMap}, err
+		},
+	})
+}
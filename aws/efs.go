@@ -7,7 +7,6 @@ import (
 	"sync"
 	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/efs"
 	"github.com/aws/aws-sdk-go-v2/service/efs/types"
 	"github.com/aws/aws-sdk-go/aws"
@@ -20,19 +19,29 @@ import (
 )
 
 func getAllElasticFileSystems(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newEFSClient(session)
 	if err != nil {
 		return []*string{}, errors.WithStackTrace(err)
 	}
-	svc := efs.NewFromConfig(cfg)
 
-	result, err := svc.DescribeFileSystems(context.TODO(), &efs.DescribeFileSystemsInput{})
+	var fileSystems []types.FileSystemDescription
+	err = paginate(func(marker *string) (*string, error) {
+		result, err := svc.DescribeFileSystems(context.TODO(), &efs.DescribeFileSystemsInput{Marker: marker})
+		if err != nil {
+			return nil, err
+		}
+		fileSystems = append(fileSystems, result.FileSystems...)
+		return result.NextMarker, nil
+	})
 	if err != nil {
 		return []*string{}, errors.WithStackTrace(err)
 	}
 
 	allEfs := []*string{}
-	for _, fileSystem := range result.FileSystems {
+	for _, fileSystem := range fileSystems {
+		if excludedByTag(ElasticFileSystem{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(fileSystem.FileSystemId), elasticFileSystemTagsToMap(fileSystem.Tags)) {
+			continue
+		}
 		if shouldIncludeElasticFileSystem(&fileSystem, excludeAfter, configObj) {
 			allEfs = append(allEfs, fileSystem.FileSystemId)
 		}
@@ -51,21 +60,21 @@ func shouldIncludeElasticFileSystem(efsDescription *types.FileSystemDescription,
 		}
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(efsDescription.Name),
-		configObj.ElasticFileSystem.IncludeRule.NamesRegExp,
-		configObj.ElasticFileSystem.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ElasticFileSystem.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(efsDescription.Name),
+		Tags: elasticFileSystemTagsToMap(efsDescription.Tags),
+		ID:   aws.StringValue(efsDescription.FileSystemId),
+		Time: aws.TimeValue(efsDescription.CreationTime),
+	})
 }
 
-func nukeAllElasticFileSystems(session *session.Session, identifiers []*string) error {
+func nukeAllElasticFileSystems(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newEFSClient(session)
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
-	svc := efs.NewFromConfig(cfg)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No Elastic FileSystems (efs) to nuke in region %s", region)
@@ -83,7 +92,7 @@ func nukeAllElasticFileSystems(session *session.Session, identifiers []*string)
 	errChans := make([]chan error, len(identifiers))
 	for i, efsID := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteElasticFileSystemAsync(wg, errChans[i], svc, efsID, region)
+		go deleteElasticFileSystemAsync(ctx, wg, errChans[i], svc, efsID, region)
 	}
 	wg.Wait()
 
@@ -106,7 +115,7 @@ func nukeAllElasticFileSystems(session *session.Session, identifiers []*string)
 	return nil
 }
 
-func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *efs.Client, efsID *string, region string) {
+func deleteElasticFileSystemAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc efsAPI, efsID *string, region string) {
 	var allErrs *multierror.Error
 
 	defer wg.Done()
@@ -121,7 +130,7 @@ func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *e
 		FileSystemId: efsID,
 	}
 
-	out, err := svc.DescribeAccessPoints(context.TODO(), accessPointParam)
+	out, err := svc.DescribeAccessPoints(ctx, accessPointParam)
 	if err != nil {
 		allErrs = multierror.Append(allErrs, err)
 	}
@@ -138,7 +147,7 @@ func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *e
 
 		logging.Logger.Debugf("Deleting access point (id=%s) for Elastic FileSystem (%s) in region: %s", aws.StringValue(apID), aws.StringValue(efsID), region)
 
-		_, err := svc.DeleteAccessPoint(context.TODO(), deleteParam)
+		_, err := svc.DeleteAccessPoint(ctx, deleteParam)
 		if err != nil {
 			allErrs = multierror.Append(allErrs, err)
 		} else {
@@ -166,7 +175,7 @@ func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *e
 			mountTargetParam.Marker = marker
 		}
 
-		mountTargetsOutput, describeMountsErr := svc.DescribeMountTargets(context.TODO(), mountTargetParam)
+		mountTargetsOutput, describeMountsErr := svc.DescribeMountTargets(ctx, mountTargetParam)
 		if describeMountsErr != nil {
 			allErrs = multierror.Append(allErrs, err)
 		}
@@ -191,7 +200,7 @@ func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *e
 
 		logging.Logger.Debugf("Deleting mount target (id=%s) for Elastic FileSystem (%s) in region: %s", aws.StringValue(mtID), aws.StringValue(efsID), region)
 
-		_, err := svc.DeleteMountTarget(context.TODO(), deleteMtParam)
+		_, err := svc.DeleteMountTarget(ctx, deleteMtParam)
 		if err != nil {
 			allErrs = multierror.Append(allErrs, err)
 		} else {
@@ -200,29 +209,40 @@ func deleteElasticFileSystemAsync(wg *sync.WaitGroup, errChan chan error, svc *e
 	}
 
 	logging.Logger.Debug("Sleeping 20 seconds to allow AWS to realize the Elastic FileSystem is no longer in use...")
-	time.Sleep(20 * time.Second)
+	deleteErr := sleepWithContext(ctx, 20*time.Second)
+	if deleteErr == nil {
+		// Now we can attempt to delete the Elastic FileSystem itself
+		deleteEfsParam := &efs.DeleteFileSystemInput{
+			FileSystemId: efsID,
+		}
 
-	// Now we can attempt to delete the Elastic FileSystem itself
-	deleteEfsParam := &efs.DeleteFileSystemInput{
-		FileSystemId: efsID,
+		_, deleteErr = svc.DeleteFileSystem(ctx, deleteEfsParam)
 	}
-
-	_, deleteErr := svc.DeleteFileSystem(context.TODO(), deleteEfsParam)
 	// Record status of this resource
 	e := report.Entry{
 		Identifier:   aws.StringValue(efsID),
 		ResourceType: "Elastic FileSystem (EFS)",
-		Error:        err,
+		Error:        deleteErr,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	if deleteErr != nil {
 		allErrs = multierror.Append(allErrs, deleteErr)
 	}
 
-	if err == nil {
+	if deleteErr == nil {
 		logging.Logger.Debugf("[OK] Elastic FileSystem (efs) %s deleted in %s", aws.StringValue(efsID), region)
 	} else {
 		logging.Logger.Debugf("[Failed] Error deleting Elastic FileSystem (efs) %s in %s", aws.StringValue(efsID), region)
 	}
 }
+
+// elasticFileSystemTagsToMap converts the tags of an Elastic File System into a map of tag keys to values, as used by
+// config rules
+func elasticFileSystemTagsToMap(tags []types.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
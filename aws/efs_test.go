@@ -67,7 +67,7 @@ func TestListElasticFileSystems(t *testing.T) {
 	testEfs, createTestEfsErr := createTestElasticFileSystem(t, session, efsName)
 	require.NoError(t, createTestEfsErr)
 	// clean up after this test
-	defer nukeAllElasticFileSystems(session, []*string{testEfs.ID})
+	defer nukeAllElasticFileSystems(context.Background(), session, []*string{testEfs.ID})
 
 	efsIds, err := getAllElasticFileSystems(session, time.Now(), config.Config{})
 	if err != nil {
@@ -90,7 +90,7 @@ func TestTimeFilterExclusionNewlyCreatedEFS(t *testing.T) {
 
 	testEFS, createEFSErr := createTestElasticFileSystem(t, session, testEFSName)
 	require.NoError(t, createEFSErr)
-	defer nukeAllElasticFileSystems(session, []*string{testEFS.ID})
+	defer nukeAllElasticFileSystems(context.Background(), session, []*string{testEFS.ID})
 
 	// Assert Elastic FileSystem is picked up without filters
 	efsIds, err := getAllElasticFileSystems(session, time.Now(), config.Config{})
@@ -118,7 +118,7 @@ func TestNukeElasticFileSystemOne(t *testing.T) {
 	testEfs, createTestErr := createTestElasticFileSystem(t, session, apigwName)
 	require.NoError(t, createTestErr)
 
-	nukeErr := nukeAllElasticFileSystems(session, []*string{testEfs.ID})
+	nukeErr := nukeAllElasticFileSystems(context.Background(), session, []*string{testEfs.ID})
 	require.NoError(t, nukeErr)
 
 	// This sleep is necessary to allow AWS to realize the Elastic FileSystem is no longer "in-use"
@@ -148,7 +148,7 @@ func TestNukeElasticFileSystemsMoreThanOne(t *testing.T) {
 	testEFS2, createTestErr2 := createTestElasticFileSystem(t, session, efsName2)
 	require.NoError(t, createTestErr2)
 
-	nukeErr := nukeAllElasticFileSystems(session, []*string{testEFS.ID, testEFS2.ID})
+	nukeErr := nukeAllElasticFileSystems(context.Background(), session, []*string{testEFS.ID, testEFS2.ID})
 	require.NoError(t, nukeErr)
 
 	// Sleep for 10 seconds so that AWS has time to realize the EFS is no longer "in-use"
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -22,8 +24,8 @@ func (efs ElasticFileSystem) MaxBatchSize() int {
 	return 10
 }
 
-func (efs ElasticFileSystem) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllElasticFileSystems(session, awsgo.StringSlice(identifiers)); err != nil {
+func (efs ElasticFileSystem) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllElasticFileSystems(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
@@ -36,3 +38,17 @@ type TooManyElasticFileSystemsErr struct{}
 func (err TooManyElasticFileSystemsErr) Error() string {
 	return "Too many Elastic FileSystems requested at once."
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            ElasticFileSystem{}.ResourceName(),
+		Description:     "Elastic FileSystems",
+		ConfigKey:       "ElasticFileSystem",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllElasticFileSystems(session, params.ExcludeAfter, params.Config)
+			return ElasticFileSystem{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	"github.com/aws/aws-sdk-go-v2/service/efs/types"
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+// fakeEFS is an in-process EFS backend holding file systems in memory. Only discovery is implemented, calling any
+// other operation panics.
+type fakeEFS struct {
+	efsAPI
+
+	fileSystems []types.FileSystemDescription
+}
+
+// DescribeFileSystems returns one file system per page, to make sure every page is looked at
+func (fake *fakeEFS) DescribeFileSystems(ctx context.Context, params *efs.DescribeFileSystemsInput, optFns ...func(*efs.Options)) (*efs.DescribeFileSystemsOutput, error) {
+	i := 0
+	if params.Marker != nil {
+		i, _ = strconv.Atoi(awsgo.StringValue(params.Marker))
+	}
+	output := &efs.DescribeFileSystemsOutput{FileSystems: fake.fileSystems[i : i+1]}
+	if i+1 < len(fake.fileSystems) {
+		output.NextMarker = awsgo.String(strconv.Itoa(i + 1))
+	}
+	return output, nil
+}
+
+func TestListElasticFileSystemsOffline(t *testing.T) {
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	now := time.Now()
+	fake := &fakeEFS{fileSystems: []types.FileSystemDescription{
+		{FileSystemId: awsgo.String("fs-old"), CreationTime: awsgo.Time(now.Add(-2 * time.Hour))},
+		{FileSystemId: awsgo.String("fs-new"), CreationTime: awsgo.Time(now)},
+		{
+			FileSystemId: awsgo.String("fs-excluded"),
+			CreationTime: awsgo.Time(now.Add(-2 * time.Hour)),
+			Tags:         []types.Tag{{Key: awsgo.String(AwsResourceExclusionTagKey), Value: awsgo.String("true")}},
+		},
+	}}
+	useFakeV2Client(t, &newEFSClient, efsAPI(fake))
+
+	ids, err := getAllElasticFileSystems(newFakeSession(t, "us-east-1"), now.Add(-1*time.Hour), config.Config{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"fs-old"}, awsgo.StringValueSlice(ids))
+	exclusions := report.GetExclusions()
+	require.Len(t, exclusions, 1)
+	assert.Equal(t, "fs-excluded", exclusions[0].Identifier)
+}
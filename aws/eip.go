@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -10,13 +11,14 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
-func setFirstSeenTag(svc *ec2.EC2, address ec2.Address, key string, value time.Time, layout string) error {
+func setFirstSeenTag(svc ec2iface.EC2API, address ec2.Address, key string, value time.Time, layout string) error {
 	// We set a first seen tag because an Elastic IP doesn't contain an attribute that gives us it's creation time
 	_, err := svc.CreateTags(&ec2.CreateTagsInput{
 		Resources: []*string{address.AllocationId},
@@ -34,7 +36,7 @@ func setFirstSeenTag(svc *ec2.EC2, address ec2.Address, key string, value time.T
 	return nil
 }
 
-func getFirstSeenTag(svc *ec2.EC2, address ec2.Address, key string, layout string) (*time.Time, error) {
+func getFirstSeenTag(svc ec2iface.EC2API, address ec2.Address, key string, layout string) (*time.Time, error) {
 	tags := address.Tags
 	for _, tag := range tags {
 		if *tag.Key == key {
@@ -52,7 +54,7 @@ func getFirstSeenTag(svc *ec2.EC2, address ec2.Address, key string, layout strin
 
 // Returns a formatted string of EIP allocation ids
 func getAllEIPAddresses(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 	const layout = "2006-01-02 15:04:05"
 
 	result, err := svc.DescribeAddresses(&ec2.DescribeAddressesInput{})
@@ -62,6 +64,10 @@ func getAllEIPAddresses(session *session.Session, region string, excludeAfter ti
 
 	var allocationIds []*string
 	for _, address := range result.Addresses {
+		if excludedByTag(EIPAddresses{}.ResourceName(), region, aws.StringValue(address.AllocationId), ec2TagsToMap(address.Tags)) {
+			continue
+		}
+
 		firstSeenTime, err := getFirstSeenTag(svc, *address, firstSeenTagKey, layout)
 		if err != nil {
 			return nil, errors.WithStackTrace(err)
@@ -92,19 +98,19 @@ func shouldIncludeAllocationId(address *ec2.Address, excludeAfter time.Time, fir
 	}
 
 	// If Name is unset, GetEC2ResourceNameTagValue returns error and zero value string
-	// Ignore this error and pass empty string to config.ShouldInclude
+	// Ignore this error and use an empty name
 	allocationName, _ := GetEC2ResourceNameTagValue(address.Tags)
 
-	return config.ShouldInclude(
-		allocationName,
-		configObj.ElasticIP.IncludeRule.NamesRegExp,
-		configObj.ElasticIP.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ElasticIP.ShouldInclude(config.ResourceValue{
+		Name: allocationName,
+		Tags: ec2TagsToMap(address.Tags),
+		ID:   aws.StringValue(address.AllocationId),
+	})
 }
 
 // Deletes all EIP allocation ids
-func nukeAllEIPAddresses(session *session.Session, allocationIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllEIPAddresses(ctx context.Context, session *session.Session, allocationIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(allocationIds) == 0 {
 		logging.Logger.Debugf("No Elastic IPs to nuke in region %s", *session.Config.Region)
@@ -119,7 +125,7 @@ func nukeAllEIPAddresses(session *session.Session, allocationIds []*string) erro
 			AllocationId: allocationID,
 		}
 
-		_, err := svc.ReleaseAddress(params)
+		_, err := svc.ReleaseAddressWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -127,7 +133,7 @@ func nukeAllEIPAddresses(session *session.Session, allocationIds []*string) erro
 			ResourceType: "Elastic IP Address (EIP)",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == "AuthFailure" {
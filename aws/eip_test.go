@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"sync"
@@ -65,7 +66,7 @@ func TestSetFirstSeenTag(t *testing.T) {
 	now := time.Now().UTC()
 
 	// clean up after this test
-	defer nukeAllEIPAddresses(session, []*string{address.AllocationId})
+	defer nukeAllEIPAddresses(context.Background(), session, []*string{address.AllocationId})
 
 	if err := setFirstSeenTag(svc, address, key, now, layout); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
@@ -110,7 +111,7 @@ func TestGetFirstSeenTag(t *testing.T) {
 	now := time.Now().UTC()
 
 	// clean up after this test
-	defer nukeAllEIPAddresses(session, []*string{address.AllocationId})
+	defer nukeAllEIPAddresses(context.Background(), session, []*string{address.AllocationId})
 
 	_, err = svc.CreateTags(&ec2.CreateTagsInput{
 		Resources: []*string{address.AllocationId},
@@ -161,7 +162,7 @@ func TestListEIPAddress(t *testing.T) {
 
 	address := createTestEIPAddress(t, session)
 	// clean up after this test
-	defer nukeAllEIPAddresses(session, []*string{address.AllocationId})
+	defer nukeAllEIPAddresses(context.Background(), session, []*string{address.AllocationId})
 
 	allocationIds, err := getAllEIPAddresses(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	require.NoError(t, err)
@@ -194,7 +195,7 @@ func TestNukeEIPAddress(t *testing.T) {
 	}
 
 	address := createTestEIPAddress(t, session)
-	if err := nukeAllEIPAddresses(session, []*string{address.AllocationId}); err != nil {
+	if err := nukeAllEIPAddresses(context.Background(), session, []*string{address.AllocationId}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
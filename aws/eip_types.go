@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,24 @@ func (address EIPAddresses) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (address EIPAddresses) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllEIPAddresses(session, awsgo.StringSlice(identifiers)); err != nil {
+func (address EIPAddresses) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllEIPAddresses(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:         EIPAddresses{}.ResourceName(),
+		Description:  "EIP Addresses",
+		ConfigKey:    "ElasticIP",
+		SupportsTags: true,
+		DependsOn:    []string{"ec2", "nat-gateway"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEIPAddresses(session, params.Region, params.ExcludeAfter, params.Config)
+			return EIPAddresses{AllocationIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
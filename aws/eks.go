@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go/service/eks/eksiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,12 +20,16 @@ import (
 
 // getAllEksClusters returns a list of strings of EKS Cluster Names that uniquely identify each cluster.
 func getAllEksClusters(awsSession *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := eks.New(awsSession)
-	result, err := svc.ListClusters(&eks.ListClustersInput{})
+	svc := newEKSClient(awsSession)
+	var clusters []*string
+	err := svc.ListClustersPages(&eks.ListClustersInput{}, func(page *eks.ListClustersOutput, lastPage bool) bool {
+		clusters = append(clusters, page.Clusters...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
-	filteredClusters, err := filterOutEksClusters(svc, result.Clusters, excludeAfter, configObj)
+	filteredClusters, err := filterOutEksClusters(svc, aws.StringValue(awsSession.Config.Region), clusters, excludeAfter, configObj)
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
@@ -32,16 +38,12 @@ func getAllEksClusters(awsSession *session.Session, excludeAfter time.Time, conf
 
 // filterOutEksClusters will take in the list of clusters and filter out any clusters that were created after
 // `excludeAfter`, and those that are excluded by the config file.
-func filterOutEksClusters(svc *eks.EKS, clusterNames []*string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+func filterOutEksClusters(svc eksiface.EKSAPI, region string, clusterNames []*string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
 	var filteredEksClusterNames []*string
 	for _, clusterName := range clusterNames {
-		// Since we already have the name here, avoid an extra API call by applying the name based config filter first.
-		shouldInclude := config.ShouldInclude(
-			aws.StringValue(clusterName),
-			configObj.EKSCluster.IncludeRule.NamesRegExp,
-			configObj.EKSCluster.ExcludeRule.NamesRegExp,
-		)
-		if !shouldInclude {
+		// Since we already have the name here, avoid an extra API call by applying the config filter first, unless it
+		// needs the tags or the creation time of the cluster.
+		if !configObj.EKSCluster.HasTagRules() && !configObj.EKSCluster.HasRuleAge() && !configObj.EKSCluster.ShouldInclude(config.ResourceValue{Name: aws.StringValue(clusterName)}) {
 			continue
 		}
 
@@ -52,7 +54,15 @@ func filterOutEksClusters(svc *eks.EKS, clusterNames []*string, excludeAfter tim
 			return nil, errors.WithStackTrace(err)
 		}
 		cluster := describeResult.Cluster
-		if excludeAfter.After(*cluster.CreatedAt) {
+		if excludedByTag(EKSClusters{}.ResourceName(), region, aws.StringValue(cluster.Name), aws.StringValueMap(cluster.Tags)) {
+			continue
+		}
+		shouldInclude := configObj.EKSCluster.ShouldInclude(config.ResourceValue{
+			Name: aws.StringValue(cluster.Name),
+			Tags: aws.StringValueMap(cluster.Tags),
+			Time: aws.TimeValue(cluster.CreatedAt),
+		})
+		if shouldInclude && excludeAfter.After(*cluster.CreatedAt) {
 			filteredEksClusterNames = append(filteredEksClusterNames, cluster.Name)
 		}
 	}
@@ -62,7 +72,7 @@ func filterOutEksClusters(svc *eks.EKS, clusterNames []*string, excludeAfter tim
 // deleteEKSClusterAsync deletes the provided EKS Cluster asynchronously in a goroutine, using wait groups for
 // concurrency control and a return channel for errors. Note that this routine attempts to delete all managed compute
 // resources associated with the EKS cluster (Managed Node Groups and Fargate Profiles).
-func deleteEKSClusterAsync(wg *sync.WaitGroup, errChan chan error, svc *eks.EKS, eksClusterName string) {
+func deleteEKSClusterAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc eksiface.EKSAPI, eksClusterName string) {
 	defer wg.Done()
 
 	// Aggregate errors for each subresource being deleted
@@ -76,13 +86,13 @@ func deleteEKSClusterAsync(wg *sync.WaitGroup, errChan chan error, svc *eks.EKS,
 		allSubResourceErrs = multierror.Append(allSubResourceErrs, err)
 	}
 
-	if err := deleteEKSClusterFargateProfiles(svc, eksClusterName); err != nil {
+	if err := deleteEKSClusterFargateProfiles(ctx, svc, eksClusterName); err != nil {
 		allSubResourceErrs = multierror.Append(allSubResourceErrs, err)
 	}
 
 	// Make sure the node groups are actually deleted before returning.
 	for _, nodeGroup := range deletedNodeGroups {
-		err := svc.WaitUntilNodegroupDeleted(&eks.DescribeNodegroupInput{
+		err := svc.WaitUntilNodegroupDeletedWithContext(ctx, &eks.DescribeNodegroupInput{
 			ClusterName:   aws.String(eksClusterName),
 			NodegroupName: nodeGroup,
 		})
@@ -110,7 +120,7 @@ func deleteEKSClusterAsync(wg *sync.WaitGroup, errChan chan error, svc *eks.EKS,
 // scheduleDeleteEKSClusterManagedNodeGroup looks up all the associated Managed Node Group resources on the EKS cluster
 // and requests each one to be deleted. Note that this function will not wait for the Node Groups to be deleted. This
 // will return the list of Node Groups that were successfully scheduled for deletion.
-func scheduleDeleteEKSClusterManagedNodeGroup(svc *eks.EKS, eksClusterName string) ([]*string, error) {
+func scheduleDeleteEKSClusterManagedNodeGroup(svc eksiface.EKSAPI, eksClusterName string) ([]*string, error) {
 	allNodeGroups := []*string{}
 	err := svc.ListNodegroupsPages(
 		&eks.ListNodegroupsInput{ClusterName: aws.String(eksClusterName)},
@@ -147,7 +157,7 @@ func scheduleDeleteEKSClusterManagedNodeGroup(svc *eks.EKS, eksClusterName strin
 // deleteEKSClusterFargateProfiles looks up all the associated Fargate Profile resources on the EKS cluster and requests
 // each one to be deleted. Since only one Fargate Profile can be deleted at a time, this function will wait until the
 // Fargate Profile is actually deleted for each one before moving on to the next one.
-func deleteEKSClusterFargateProfiles(svc *eks.EKS, eksClusterName string) error {
+func deleteEKSClusterFargateProfiles(ctx context.Context, svc eksiface.EKSAPI, eksClusterName string) error {
 	allFargateProfiles := []*string{}
 	err := svc.ListFargateProfilesPages(
 		&eks.ListFargateProfilesInput{ClusterName: aws.String(eksClusterName)},
@@ -178,7 +188,7 @@ func deleteEKSClusterFargateProfiles(svc *eks.EKS, eksClusterName string) error
 			continue
 		}
 
-		waitErr := svc.WaitUntilFargateProfileDeleted(&eks.DescribeFargateProfileInput{
+		waitErr := svc.WaitUntilFargateProfileDeletedWithContext(ctx, &eks.DescribeFargateProfileInput{
 			ClusterName:        aws.String(eksClusterName),
 			FargateProfileName: fargateProfile,
 		})
@@ -194,10 +204,10 @@ func deleteEKSClusterFargateProfiles(svc *eks.EKS, eksClusterName string) error
 
 // waitUntilEksClustersDeleted waits until the EKS cluster has been actually deleted from AWS. Returns a list of EKS
 // cluster names that have been successfully deleted.
-func waitUntilEksClustersDeleted(svc *eks.EKS, eksClusterNames []*string) []*string {
+func waitUntilEksClustersDeleted(ctx context.Context, svc eksiface.EKSAPI, eksClusterNames []*string) []*string {
 	var successfullyDeleted []*string
 	for _, eksClusterName := range eksClusterNames {
-		err := svc.WaitUntilClusterDeleted(&eks.DescribeClusterInput{Name: eksClusterName})
+		err := svc.WaitUntilClusterDeletedWithContext(ctx, &eks.DescribeClusterInput{Name: eksClusterName})
 
 		// Record status of this resource
 		e := report.Entry{
@@ -205,7 +215,7 @@ func waitUntilEksClustersDeleted(svc *eks.EKS, eksClusterNames []*string) []*str
 			ResourceType: "EKS Cluster",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] Failed waiting for EKS cluster to be deleted %s: %s", *eksClusterName, err)
@@ -218,9 +228,9 @@ func waitUntilEksClustersDeleted(svc *eks.EKS, eksClusterNames []*string) []*str
 }
 
 // nukeAllEksClusters deletes all provided EKS clusters, waiting for them to be deleted before returning.
-func nukeAllEksClusters(awsSession *session.Session, eksClusterNames []*string) error {
+func nukeAllEksClusters(ctx context.Context, awsSession *session.Session, eksClusterNames []*string) error {
 	numNuking := len(eksClusterNames)
-	svc := eks.New(awsSession)
+	svc := newEKSClient(awsSession)
 
 	if numNuking == 0 {
 		logging.Logger.Debugf("No EKS clusters to nuke in region %s", *awsSession.Config.Region)
@@ -244,7 +254,7 @@ func nukeAllEksClusters(awsSession *session.Session, eksClusterNames []*string)
 	errChans := make([]chan error, numNuking)
 	for i, eksClusterName := range eksClusterNames {
 		errChans[i] = make(chan error, 1)
-		go deleteEKSClusterAsync(wg, errChans[i], svc, aws.StringValue(eksClusterName))
+		go deleteEKSClusterAsync(ctx, wg, errChans[i], svc, aws.StringValue(eksClusterName))
 	}
 	wg.Wait()
 
@@ -267,7 +277,7 @@ func nukeAllEksClusters(awsSession *session.Session, eksClusterNames []*string)
 	}
 
 	// Now wait until the EKS Clusters are deleted
-	successfullyDeleted := waitUntilEksClustersDeleted(svc, eksClusterNames)
+	successfullyDeleted := waitUntilEksClustersDeleted(ctx, svc, eksClusterNames)
 	numNuked := len(successfullyDeleted)
 	logging.Logger.Debugf("[OK] %d of %d EKS cluster(s) deleted in %s", numNuked, numNuking, *awsSession.Config.Region)
 	return nil
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"sync"
 	"testing"
@@ -40,7 +41,7 @@ func TestListEksClusters(t *testing.T) {
 	defer deleteRole(awsSession, role)
 
 	cluster := createEKSCluster(t, awsSession, uniqueID, *role.Arn)
-	defer nukeAllEksClusters(awsSession, []*string{cluster.Name})
+	defer nukeAllEksClusters(context.Background(), awsSession, []*string{cluster.Name})
 
 	eksClusterNames, err := getAllEksClusters(awsSession, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -75,7 +76,7 @@ func TestNukeEksClusters(t *testing.T) {
 	defer deleteRole(awsSession, role)
 
 	cluster := createEKSCluster(t, awsSession, uniqueID, *role.Arn)
-	err = nukeAllEksClusters(awsSession, []*string{cluster.Name})
+	err = nukeAllEksClusters(context.Background(), awsSession, []*string{cluster.Name})
 	require.NoError(t, err)
 
 	eksClusterNames, err := getAllEksClusters(awsSession, time.Now().Add(1*time.Hour), config.Config{})
@@ -128,7 +129,7 @@ func TestNukeEksClustersWithCompute(t *testing.T) {
 	}()
 	wg.Wait()
 
-	err = nukeAllEksClusters(awsSession, []*string{cluster.Name})
+	err = nukeAllEksClusters(context.Background(), awsSession, []*string{cluster.Name})
 	require.NoError(t, err)
 
 	eksClusterNames, err := getAllEksClusters(awsSession, time.Now().Add(1*time.Hour), config.Config{})
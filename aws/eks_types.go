@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,9 +31,23 @@ func (clusters EKSClusters) MaxBatchSize() int {
 }
 
 // Nuke - nuke all EKS Cluster resources
-func (clusters EKSClusters) Nuke(awsSession *session.Session, identifiers []string) error {
-	if err := nukeAllEksClusters(awsSession, awsgo.StringSlice(identifiers)); err != nil {
+func (clusters EKSClusters) Nuke(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := nukeAllEksClusters(ctx, awsSession, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            EKSClusters{}.ResourceName(),
+		Description:     "EKS Clusters",
+		ConfigKey:       "EKSCluster",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllEksClusters(session, params.ExcludeAfter, params.Config)
+			return EKSClusters{Clusters: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/elasticache/elasticacheiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -18,10 +20,18 @@ import (
 
 // Returns a formatted string of Elasticache cluster Ids
 func getAllElasticacheClusters(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := elasticache.New(session)
+	svc := newElastiCacheClient(session)
 
 	// First, get any cache clusters that are replication groups, which will be the case for all multi-node Redis clusters
-	replicationGroupsResult, replicationGroupsErr := svc.DescribeReplicationGroups(&elasticache.DescribeReplicationGroupsInput{})
+	var clusterIds []*string
+	replicationGroupsErr := svc.DescribeReplicationGroupsPages(&elasticache.DescribeReplicationGroupsInput{}, func(page *elasticache.DescribeReplicationGroupsOutput, lastPage bool) bool {
+		for _, replicationGroup := range page.ReplicationGroups {
+			if shouldIncludeElasticacheReplicationGroup(replicationGroup, excludeAfter, configObj) {
+				clusterIds = append(clusterIds, replicationGroup.ReplicationGroupId)
+			}
+		}
+		return !lastPage
+	})
 	if replicationGroupsErr != nil {
 		return nil, errors.WithStackTrace(replicationGroupsErr)
 	}
@@ -29,26 +39,20 @@ func getAllElasticacheClusters(session *session.Session, region string, excludeA
 	// Next, get any cache clusters that are not members of a replication group: meaning:
 	// 1. any cache clusters with a Engine of "memcached"
 	// 2. any single node Redis clusters
-	cacheClustersResult, cacheClustersErr := svc.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{
+	cacheClustersErr := svc.DescribeCacheClustersPages(&elasticache.DescribeCacheClustersInput{
 		ShowCacheClustersNotInReplicationGroups: aws.Bool(true),
+	}, func(page *elasticache.DescribeCacheClustersOutput, lastPage bool) bool {
+		for _, cluster := range page.CacheClusters {
+			if shouldIncludeElasticacheCluster(cluster, excludeAfter, configObj) {
+				clusterIds = append(clusterIds, cluster.CacheClusterId)
+			}
+		}
+		return !lastPage
 	})
 	if cacheClustersErr != nil {
 		return nil, errors.WithStackTrace(cacheClustersErr)
 	}
 
-	var clusterIds []*string
-	for _, replicationGroup := range replicationGroupsResult.ReplicationGroups {
-		if shouldIncludeElasticacheReplicationGroup(replicationGroup, excludeAfter, configObj) {
-			clusterIds = append(clusterIds, replicationGroup.ReplicationGroupId)
-		}
-	}
-
-	for _, cluster := range cacheClustersResult.CacheClusters {
-		if shouldIncludeElasticacheCluster(cluster, excludeAfter, configObj) {
-			clusterIds = append(clusterIds, cluster.CacheClusterId)
-		}
-	}
-
 	return clusterIds, nil
 }
 
@@ -61,11 +65,7 @@ func shouldIncludeElasticacheCluster(cluster *elasticache.CacheCluster, excludeA
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(cluster.CacheClusterId),
-		configObj.Elasticache.IncludeRule.NamesRegExp,
-		configObj.Elasticache.ExcludeRule.NamesRegExp,
-	)
+	return configObj.Elasticache.ShouldInclude(config.ResourceValue{Name: aws.StringValue(cluster.CacheClusterId)})
 }
 
 func shouldIncludeElasticacheReplicationGroup(replicationGroup *elasticache.ReplicationGroup, excludeAfter time.Time, configObj config.Config) bool {
@@ -77,11 +77,7 @@ func shouldIncludeElasticacheReplicationGroup(replicationGroup *elasticache.Repl
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(replicationGroup.ReplicationGroupId),
-		configObj.Elasticache.IncludeRule.NamesRegExp,
-		configObj.Elasticache.ExcludeRule.NamesRegExp,
-	)
+	return configObj.Elasticache.ShouldInclude(config.ResourceValue{Name: aws.StringValue(replicationGroup.ReplicationGroupId)})
 }
 
 type CacheClusterType string
@@ -91,7 +87,7 @@ const (
 	Single      CacheClusterType = "single"
 )
 
-func determineCacheClusterType(svc *elasticache.ElastiCache, clusterId *string) (*string, CacheClusterType, error) {
+func determineCacheClusterType(svc elasticacheiface.ElastiCacheAPI, clusterId *string) (*string, CacheClusterType, error) {
 	replicationGroupDescribeParams := &elasticache.DescribeReplicationGroupsInput{
 		ReplicationGroupId: clusterId,
 	}
@@ -136,7 +132,7 @@ func determineCacheClusterType(svc *elasticache.ElastiCache, clusterId *string)
 	return nil, Single, CouldNotLookupCacheClusterErr{ClusterId: clusterId}
 }
 
-func nukeNonReplicationGroupElasticacheCluster(svc *elasticache.ElastiCache, clusterId *string) error {
+func nukeNonReplicationGroupElasticacheCluster(ctx context.Context, svc elasticacheiface.ElastiCacheAPI, clusterId *string) error {
 	logging.Logger.Debugf("Deleting Elasticache cluster Id: %s which is not a member of a replication group", aws.StringValue(clusterId))
 	params := elasticache.DeleteCacheClusterInput{
 		CacheClusterId: clusterId,
@@ -146,12 +142,12 @@ func nukeNonReplicationGroupElasticacheCluster(svc *elasticache.ElastiCache, clu
 		return err
 	}
 
-	return svc.WaitUntilCacheClusterDeleted(&elasticache.DescribeCacheClustersInput{
+	return svc.WaitUntilCacheClusterDeletedWithContext(ctx, &elasticache.DescribeCacheClustersInput{
 		CacheClusterId: clusterId,
 	})
 }
 
-func nukeReplicationGroupMemberElasticacheCluster(svc *elasticache.ElastiCache, clusterId *string) error {
+func nukeReplicationGroupMemberElasticacheCluster(ctx context.Context, svc elasticacheiface.ElastiCacheAPI, clusterId *string) error {
 	logging.Logger.Debugf("Elasticache cluster Id: %s is a member of a replication group. Therefore, deleting its replication group", aws.StringValue(clusterId))
 
 	params := &elasticache.DeleteReplicationGroupInput{
@@ -162,7 +158,7 @@ func nukeReplicationGroupMemberElasticacheCluster(svc *elasticache.ElastiCache,
 		return err
 	}
 
-	waitErr := svc.WaitUntilReplicationGroupDeleted(&elasticache.DescribeReplicationGroupsInput{
+	waitErr := svc.WaitUntilReplicationGroupDeletedWithContext(ctx, &elasticache.DescribeReplicationGroupsInput{
 		ReplicationGroupId: clusterId,
 	})
 
@@ -175,8 +171,8 @@ func nukeReplicationGroupMemberElasticacheCluster(svc *elasticache.ElastiCache,
 	return nil
 }
 
-func nukeAllElasticacheClusters(session *session.Session, clusterIds []*string) error {
-	svc := elasticache.New(session)
+func nukeAllElasticacheClusters(ctx context.Context, session *session.Session, clusterIds []*string) error {
+	svc := newElastiCacheClient(session)
 
 	if len(clusterIds) == 0 {
 		logging.Logger.Debugf("No Elasticache clusters to nuke in region %s", *session.Config.Region)
@@ -198,9 +194,9 @@ func nukeAllElasticacheClusters(session *session.Session, clusterIds []*string)
 
 		var err error
 		if clusterType == Single {
-			err = nukeNonReplicationGroupElasticacheCluster(svc, clusterId)
+			err = nukeNonReplicationGroupElasticacheCluster(ctx, svc, clusterId)
 		} else if clusterType == Replication {
-			err = nukeReplicationGroupMemberElasticacheCluster(svc, clusterId)
+			err = nukeReplicationGroupMemberElasticacheCluster(ctx, svc, clusterId)
 		}
 
 		// Record status of this resource
@@ -209,7 +205,7 @@ func nukeAllElasticacheClusters(session *session.Session, clusterIds []*string)
 			ResourceType: "Elasticache",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -237,3 +233,106 @@ type CouldNotLookupCacheClusterErr struct {
 func (err CouldNotLookupCacheClusterErr) Error() string {
 	return fmt.Sprintf("Failed to lookup clusterId: %s", aws.StringValue(err.ClusterId))
 }
+
+type ElasticacheSnapshotNotAvailableErr struct {
+	SnapshotName *string
+}
+
+func (err ElasticacheSnapshotNotAvailableErr) Error() string {
+	return fmt.Sprintf("Elasticache snapshot %s did not become available in time", aws.StringValue(err.SnapshotName))
+}
+
+// preserveElasticacheClusters takes a snapshot of each of the given Elasticache clusters, tagged with the run ID, and
+// returns the IDs of the clusters that are safe to delete. Memcached clusters keep no data that could be preserved.
+// Clusters that could not be snapshotted are recorded as failed.
+func preserveElasticacheClusters(ctx context.Context, session *session.Session, clusterIds []*string, runID string) []*string {
+	svc := newElastiCacheClient(session)
+
+	var preserved []*string
+	for _, clusterId := range clusterIds {
+		err := preserveElasticacheCluster(ctx, svc, clusterId, runID)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s: %s", aws.StringValue(clusterId), err)
+			record(ctx, report.Entry{
+				Identifier:   aws.StringValue(clusterId),
+				ResourceType: "Elasticache",
+				Error:        DataPreservationError{Identifier: aws.StringValue(clusterId), Underlying: err},
+			})
+			continue
+		}
+		preserved = append(preserved, clusterId)
+	}
+	return preserved
+}
+
+func preserveElasticacheCluster(ctx context.Context, svc elasticacheiface.ElastiCacheAPI, clusterId *string, runID string) error {
+	clusterId, clusterType, err := determineCacheClusterType(svc, clusterId)
+	if err != nil {
+		return err
+	}
+
+	snapshotName := aws.String(preservedDataName(runID, aws.StringValue(clusterId)))
+	input := &elasticache.CreateSnapshotInput{SnapshotName: snapshotName}
+	for key, value := range preservedDataTags(runID, aws.StringValue(clusterId)) {
+		input.Tags = append(input.Tags, &elasticache.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	if clusterType == Replication {
+		input.ReplicationGroupId = clusterId
+	} else {
+		output, err := svc.DescribeCacheClusters(&elasticache.DescribeCacheClustersInput{CacheClusterId: clusterId})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if len(output.CacheClusters) == 0 || aws.StringValue(output.CacheClusters[0].Engine) == "memcached" {
+			return nil
+		}
+		input.CacheClusterId = clusterId
+	}
+	_, err = svc.CreateSnapshot(input)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == elasticache.ErrCodeSnapshotAlreadyExistsFault {
+		err = existingElasticacheSnapshotError(svc, snapshotName, runID, aws.StringValue(clusterId), err)
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// wait up to 30 minutes
+	for i := 0; i < 180; i++ {
+		output, err := svc.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{SnapshotName: snapshotName})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if len(output.Snapshots) > 0 && aws.StringValue(output.Snapshots[0].SnapshotStatus) == "available" {
+			return nil
+		}
+
+		logging.Logger.Debugf("Waiting for snapshot %s of Elasticache cluster %s", aws.StringValue(snapshotName), aws.StringValue(clusterId))
+		if err := sleepWithContext(ctx, 10*time.Second); err != nil {
+			return err
+		}
+	}
+	return ElasticacheSnapshotNotAvailableErr{SnapshotName: snapshotName}
+}
+
+// existingElasticacheSnapshotError returns nil if the existing snapshot with the given name was taken of the
+// Elasticache cluster with the given ID during the given run, which was then interrupted and resumed, or the given
+// error otherwise
+func existingElasticacheSnapshotError(svc elasticacheiface.ElastiCacheAPI, snapshotName *string, runID string, clusterId string, err error) error {
+	output, describeErr := svc.DescribeSnapshots(&elasticache.DescribeSnapshotsInput{SnapshotName: snapshotName})
+	if describeErr != nil || len(output.Snapshots) == 0 {
+		return err
+	}
+	tagsOutput, tagsErr := svc.ListTagsForResource(&elasticache.ListTagsForResourceInput{ResourceName: output.Snapshots[0].ARN})
+	if tagsErr != nil {
+		return err
+	}
+	tags := map[string]string{}
+	for _, tag := range tagsOutput.TagList {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	if !isPreservedDuringRun(tags, runID, clusterId) {
+		return err
+	}
+	logging.Logger.Debugf("Snapshot %s of Elasticache cluster %s was already taken by run %s", aws.StringValue(snapshotName), clusterId, runID)
+	return nil
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"strings"
@@ -79,7 +80,7 @@ func TestListElasticacheClusters(t *testing.T) {
 	createTestElasticacheCluster(t, session, clusterId)
 
 	// clean up after this test
-	defer nukeAllElasticacheClusters(session, []*string{&clusterId})
+	defer nukeAllElasticacheClusters(context.Background(), session, []*string{&clusterId})
 
 	clusterIds, err := getAllElasticacheClusters(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
@@ -109,7 +110,7 @@ func TestListElasticacheClustersWithConfigFile(t *testing.T) {
 	createTestElasticacheCluster(t, session, excludedClusterId)
 
 	// clean up after this test
-	defer nukeAllElasticacheClusters(session, []*string{&includedClusterId, &excludedClusterId})
+	defer nukeAllElasticacheClusters(context.Background(), session, []*string{&includedClusterId, &excludedClusterId})
 
 	clusterIds, err := getAllElasticacheClusters(session, region, time.Now().Add(1*time.Hour), config.Config{
 		Elasticache: config.ResourceType{
@@ -148,7 +149,7 @@ func TestNukeElasticacheClusters(t *testing.T) {
 	// Ensure that nukeAllElasticacheClusters can handle both scenarios for elasticache:
 	// 1. The elasticache cluster is not the member of a replication group, so it can be deleted directly
 	// 2. The elasticache cluster is a member of a replication group, so that replication group must be deleted
-	err = nukeAllElasticacheClusters(session, []*string{&clusterId, &replicationGroupId})
+	err = nukeAllElasticacheClusters(context.Background(), session, []*string{&clusterId, &replicationGroupId})
 	require.NoError(t, err)
 
 	clusterIds, err := getAllElasticacheClusters(session, region, time.Now().Add(1*time.Hour), config.Config{})
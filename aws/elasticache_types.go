@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,33 @@ func (cache Elasticaches) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (cache Elasticaches) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllElasticacheClusters(session, awsgo.StringSlice(identifiers)); err != nil {
+func (cache Elasticaches) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllElasticacheClusters(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+// NukePreservingData - take a snapshot of the elasticache clusters, then nuke the ones that were snapshotted
+func (cache Elasticaches) NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error {
+	preserved := preserveElasticacheClusters(ctx, session, awsgo.StringSlice(identifiers), runID)
+	if err := nukeAllElasticacheClusters(ctx, session, preserved); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                     Elasticaches{}.ResourceName(),
+		Description:              "Elasticache Clusters",
+		ConfigKey:                "Elasticache",
+		SupportsDataPreservation: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllElasticacheClusters(session, params.Region, params.ExcludeAfter, params.Config)
+			return Elasticaches{ClusterIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
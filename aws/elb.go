@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -9,12 +11,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elb/elbiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
-func waitUntilElbDeleted(svc *elb.ELB, input *elb.DescribeLoadBalancersInput) error {
+func waitUntilElbDeleted(ctx context.Context, svc elbiface.ELBAPI, input *elb.DescribeLoadBalancersInput) error {
 	for i := 0; i < 30; i++ {
 		_, err := svc.DescribeLoadBalancers(input)
 		if err != nil {
@@ -25,7 +28,9 @@ func waitUntilElbDeleted(svc *elb.ELB, input *elb.DescribeLoadBalancersInput) er
 			return err
 		}
 
-		time.Sleep(1 * time.Second)
+		if err := sleepWithContext(ctx, 1*time.Second); err != nil {
+			return err
+		}
 		logging.Logger.Debug("Waiting for ELB to be deleted")
 	}
 
@@ -33,26 +38,27 @@ func waitUntilElbDeleted(svc *elb.ELB, input *elb.DescribeLoadBalancersInput) er
 }
 
 // Returns a formatted string of ELB names
-func getAllElbInstances(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := elb.New(session)
-	result, err := svc.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
-	if err != nil {
-		return nil, errors.WithStackTrace(err)
-	}
-
+func getAllElbInstances(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newELBClient(session)
 	var names []*string
-	for _, balancer := range result.LoadBalancerDescriptions {
-		if excludeAfter.After(*balancer.CreatedTime) {
-			names = append(names, balancer.LoadBalancerName)
+	err := svc.DescribeLoadBalancersPages(&elb.DescribeLoadBalancersInput{}, func(page *elb.DescribeLoadBalancersOutput, lastPage bool) bool {
+		for _, balancer := range page.LoadBalancerDescriptions {
+			if excludeAfter.After(*balancer.CreatedTime) && configObj.ELB.ShouldInclude(config.ResourceValue{Name: aws.StringValue(balancer.LoadBalancerName), Time: aws.TimeValue(balancer.CreatedTime)}) {
+				names = append(names, balancer.LoadBalancerName)
+			}
 		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	return names, nil
 }
 
 // Deletes all Elastic Load Balancers
-func nukeAllElbInstances(session *session.Session, names []*string) error {
-	svc := elb.New(session)
+func nukeAllElbInstances(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newELBClient(session)
 
 	if len(names) == 0 {
 		logging.Logger.Debugf("No Elastic Load Balancers to nuke in region %s", *session.Config.Region)
@@ -75,7 +81,7 @@ func nukeAllElbInstances(session *session.Session, names []*string) error {
 			ResourceType: "Load Balancer (v1)",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -91,7 +97,7 @@ func nukeAllElbInstances(session *session.Session, names []*string) error {
 	}
 
 	if len(deletedNames) > 0 {
-		err := waitUntilElbDeleted(svc, &elb.DescribeLoadBalancersInput{
+		err := waitUntilElbDeleted(ctx, svc, &elb.DescribeLoadBalancersInput{
 			LoadBalancerNames: deletedNames,
 		})
 		if err != nil {
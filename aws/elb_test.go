@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -56,16 +58,16 @@ func TestListELBs(t *testing.T) {
 	elbName := "cloud-nuke-test-" + util.UniqueID()
 	createTestELB(t, session, elbName)
 	// clean up after this test
-	defer nukeAllElbInstances(session, []*string{&elbName})
+	defer nukeAllElbInstances(context.Background(), session, []*string{&elbName})
 
-	elbNames, err := getAllElbInstances(session, region, time.Now().Add(1*time.Hour*-1))
+	elbNames, err := getAllElbInstances(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
 		assert.Failf(t, "Unable to fetch list of ELBs", errors.WithStackTrace(err).Error())
 	}
 
 	assert.NotContains(t, awsgo.StringValueSlice(elbNames), elbName)
 
-	elbNames, err = getAllElbInstances(session, region, time.Now().Add(1*time.Hour))
+	elbNames, err = getAllElbInstances(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Failf(t, "Unable to fetch list of ELBs", errors.WithStackTrace(err).Error())
 	}
@@ -104,11 +106,11 @@ func TestNukeELBs(t *testing.T) {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	if err := nukeAllElbInstances(session, []*string{&elbName}); err != nil {
+	if err := nukeAllElbInstances(context.Background(), session, []*string{&elbName}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	elbNames, err := getAllElbInstances(session, region, time.Now().Add(1*time.Hour))
+	elbNames, err := getAllElbInstances(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of ELBs: %v", err)
 	}
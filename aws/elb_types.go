@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,8 +29,8 @@ func (balancer LoadBalancers) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (balancer LoadBalancers) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllElbInstances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (balancer LoadBalancers) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllElbInstances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -40,3 +42,16 @@ type ElbDeleteError struct{}
 func (e ElbDeleteError) Error() string {
 	return "ELB was not deleted"
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            LoadBalancers{}.ResourceName(),
+		Description:     "Load Balancers",
+		ConfigKey:       "ELB",
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllElbInstances(session, params.Region, params.ExcludeAfter, params.Config)
+			return LoadBalancers{Names: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
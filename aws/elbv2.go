@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -9,6 +10,7 @@ import (
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/elbv2/elbv2iface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -17,22 +19,58 @@ import (
 
 // Returns a formatted string of ELBv2 Arns
 func getAllElbv2Instances(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := elbv2.New(session)
-	result, err := svc.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{})
+	svc := newELBV2Client(session)
+	var candidateArns []*string
+	err := svc.DescribeLoadBalancersPages(&elbv2.DescribeLoadBalancersInput{}, func(page *elbv2.DescribeLoadBalancersOutput, lastPage bool) bool {
+		for _, balancer := range page.LoadBalancers {
+			if shouldIncludeELBv2(balancer, excludeAfter, configObj) {
+				candidateArns = append(candidateArns, balancer.LoadBalancerArn)
+			}
+		}
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
+	// DescribeLoadBalancers does not return tags, so they are only looked up for the load balancers that would be nuked
+	tagsByArn, err := getElbv2Tags(svc, candidateArns)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
 	var arns []*string
-	for _, balancer := range result.LoadBalancers {
-		if shouldIncludeELBv2(balancer, excludeAfter, configObj) {
-			arns = append(arns, balancer.LoadBalancerArn)
+	for _, arn := range candidateArns {
+		if excludedByTag(LoadBalancersV2{}.ResourceName(), region, awsgo.StringValue(arn), tagsByArn[awsgo.StringValue(arn)]) {
+			continue
 		}
+		arns = append(arns, arn)
 	}
 
 	return arns, nil
 }
 
+// getElbv2Tags returns the tags of the given load balancers, keyed by ARN
+func getElbv2Tags(svc elbv2iface.ELBV2API, arns []*string) (map[string]map[string]string, error) {
+	// DescribeTags accepts at most 20 resources per call
+	const describeTagsBatchSize = 20
+
+	tagsByArn := make(map[string]map[string]string)
+	for _, batch := range split(awsgo.StringValueSlice(arns), describeTagsBatchSize) {
+		output, err := svc.DescribeTags(&elbv2.DescribeTagsInput{ResourceArns: awsgo.StringSlice(batch)})
+		if err != nil {
+			return nil, err
+		}
+		for _, description := range output.TagDescriptions {
+			tags := make(map[string]string)
+			for _, tag := range description.Tags {
+				tags[awsgo.StringValue(tag.Key)] = awsgo.StringValue(tag.Value)
+			}
+			tagsByArn[awsgo.StringValue(description.ResourceArn)] = tags
+		}
+	}
+	return tagsByArn, nil
+}
+
 func shouldIncludeELBv2(balancer *elbv2.LoadBalancer, excludeAfter time.Time, configObj config.Config) bool {
 	if balancer == nil {
 		return false
@@ -42,16 +80,15 @@ func shouldIncludeELBv2(balancer *elbv2.LoadBalancer, excludeAfter time.Time, co
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(balancer.LoadBalancerName),
-		configObj.ELBv2.IncludeRule.NamesRegExp,
-		configObj.ELBv2.ExcludeRule.NamesRegExp,
-	)
+	return configObj.ELBv2.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(balancer.LoadBalancerName),
+		ID:   awsgo.StringValue(balancer.LoadBalancerArn),
+	})
 }
 
 // Deletes all Elastic Load Balancers
-func nukeAllElbv2Instances(session *session.Session, arns []*string) error {
-	svc := elbv2.New(session)
+func nukeAllElbv2Instances(ctx context.Context, session *session.Session, arns []*string) error {
+	svc := newELBV2Client(session)
 
 	if len(arns) == 0 {
 		logging.Logger.Debugf("No V2 Elastic Load Balancers to nuke in region %s", *session.Config.Region)
@@ -74,7 +111,7 @@ func nukeAllElbv2Instances(session *session.Session, arns []*string) error {
 			ResourceType: "Load Balancer (v2)",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -90,7 +127,7 @@ func nukeAllElbv2Instances(session *session.Session, arns []*string) error {
 	}
 
 	if len(deletedArns) > 0 {
-		err := svc.WaitUntilLoadBalancersDeleted(&elbv2.DescribeLoadBalancersInput{
+		err := svc.WaitUntilLoadBalancersDeletedWithContext(ctx, &elbv2.DescribeLoadBalancersInput{
 			LoadBalancerArns: deletedArns,
 		})
 		if err != nil {
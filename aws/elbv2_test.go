@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -104,7 +105,7 @@ func TestListELBv2(t *testing.T) {
 	elbName := "cloud-nuke-test-" + util.UniqueID()
 	balancer := createTestELBv2(t, session, elbName)
 	// clean up after this test
-	defer nukeAllElbv2Instances(session, []*string{balancer.LoadBalancerArn})
+	defer nukeAllElbv2Instances(context.Background(), session, []*string{balancer.LoadBalancerArn})
 
 	arns, err := getAllElbv2Instances(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	require.NoError(t, err)
@@ -143,7 +144,7 @@ func TestNukeELBv2(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	err = nukeAllElbv2Instances(session, []*string{balancer.LoadBalancerArn})
+	err = nukeAllElbv2Instances(context.Background(), session, []*string{balancer.LoadBalancerArn})
 	require.NoError(t, err)
 
 	err = svc.WaitUntilLoadBalancersDeleted(&elbv2.DescribeLoadBalancersInput{
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,22 @@ func (balancer LoadBalancersV2) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (balancer LoadBalancersV2) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllElbv2Instances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (balancer LoadBalancersV2) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllElbv2Instances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        LoadBalancersV2{}.ResourceName(),
+		Description: "Load Balancers v2",
+		ConfigKey:   "ELBv2",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllElbv2Instances(session, params.Region, params.ExcludeAfter, params.Config)
+			return LoadBalancersV2{Arns: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
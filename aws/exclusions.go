@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+const (
+	// ExcludedByTagReason is the reason recorded for resources that are left alone because they carry the exclusion tag
+	ExcludedByTagReason = "excluded by tag"
+	// ExcludedByConfigReason prefixes the reason recorded for resources excluded by the rules of the config file, which
+	// is followed by the rule that excluded them
+	ExcludedByConfigReason = "excluded by config"
+	// QuarantinedRecentlyReason is the reason recorded for quarantined resources that are left alone, as they have not
+	// been quarantined for longer than --older-than yet
+	QuarantinedRecentlyReason = "quarantined recently"
+)
+
+// recordExclusion records a discovered resource that is left alone, so that it shows up as such when inspecting
+// resources
+func recordExclusion(resourceType string, region string, identifier string, reason string) {
+	report.RecordExclusion(report.Exclusion{
+		Identifier:   identifier,
+		ResourceType: resourceType,
+		Region:       region,
+		Reason:       reason,
+	})
+}
+
+// excludedByConfigReason returns the reason recorded for a resource excluded by the given config file rule
+func excludedByConfigReason(rule string) string {
+	return fmt.Sprintf("%s: %s", ExcludedByConfigReason, rule)
+}
+
+// withExclusionRecording returns the params to pass to the lister of the given registration, in which the resources
+// excluded by the config file rules of its type are recorded along with the rule that excluded them
+func withExclusionRecording(params ListParams, registration ResourceRegistration) ListParams {
+	if registration.ConfigKey == "" {
+		return params
+	}
+	params.Config = params.Config.WithExclusionHandler(registration.ConfigKey, func(value config.ResourceValue, reason string) {
+		recordExclusion(registration.Name, params.Region, value.Identifier(), excludedByConfigReason(reason))
+	})
+	return params
+}
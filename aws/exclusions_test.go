@@ -0,0 +1,53 @@
+package aws
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+func TestWithExclusionRecording(t *testing.T) {
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	exclude, err := regexp.Compile(`^prod-`)
+	require.NoError(t, err)
+	configObj := config.Config{}
+	configObj.EC2.ExcludeRule.NamesRegExp = []config.Expression{{RE: *exclude}}
+
+	registration := ResourceRegistration{Name: EC2Instances{}.ResourceName(), ConfigKey: "EC2"}
+	params := withExclusionRecording(ListParams{Region: "us-east-1", Config: configObj}, registration)
+
+	newInstance := func(id string, name string) *ec2.Instance {
+		return &ec2.Instance{
+			InstanceId: awsgo.String(id),
+			LaunchTime: awsgo.Time(time.Now().Add(-2 * time.Hour)),
+			Tags:       []*ec2.Tag{{Key: awsgo.String("Name"), Value: awsgo.String(name)}},
+		}
+	}
+	assert.True(t, shouldIncludeInstanceId(newInstance("i-1", "dev-1"), time.Now(), false, params.Config))
+	assert.False(t, shouldIncludeInstanceId(newInstance("i-2", "prod-1"), time.Now(), false, params.Config))
+	// Resources that are too recent are not reported as excluded by the config file
+	assert.False(t, shouldIncludeInstanceId(newInstance("i-3", "prod-2"), time.Now().Add(-3*time.Hour), false, params.Config))
+
+	exclusions := report.GetExclusions()
+	require.Len(t, exclusions, 1)
+	assert.Equal(t, "i-2", exclusions[0].Identifier)
+	assert.Equal(t, "ec2", exclusions[0].ResourceType)
+	assert.Equal(t, "us-east-1", exclusions[0].Region)
+	assert.Equal(t, `excluded by config: matched exclude rule names_regex "^prod-"`, exclusions[0].Reason)
+}
+
+func TestWithExclusionRecordingWithoutConfigKey(t *testing.T) {
+	t.Parallel()
+
+	params := ListParams{Region: "us-east-1"}
+	assert.Equal(t, params, withExclusionRecording(params, ResourceRegistration{Name: "ami"}))
+}
@@ -0,0 +1,33 @@
+package aws
+
+import (
+	"testing"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/require"
+)
+
+// useFakeClient makes the given client factory return the fake client until the test finishes, so that the lister and
+// nuker of a resource type can be tested without an AWS account. Tests using it must not run in parallel, as the client
+// factories are package-level variables.
+func useFakeClient[T any](t *testing.T, factory *func(*session.Session) T, fake T) {
+	original := *factory
+	*factory = func(*session.Session) T { return fake }
+	t.Cleanup(func() { *factory = original })
+}
+
+// useFakeV2Client is like useFakeClient, for the resource types that use version 2 of the AWS SDK
+func useFakeV2Client[T any](t *testing.T, factory *func(*session.Session) (T, error), fake T) {
+	original := *factory
+	*factory = func(*session.Session) (T, error) { return fake, nil }
+	t.Cleanup(func() { *factory = original })
+}
+
+// newFakeSession returns a session for the given region that is only used to create fake clients. Creating it does not
+// make any AWS API call.
+func newFakeSession(t *testing.T, region string) *session.Session {
+	fakeSession, err := session.NewSession(&awsgo.Config{Region: awsgo.String(region)})
+	require.NoError(t, err)
+	return fakeSession
+}
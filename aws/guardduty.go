@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -21,27 +22,20 @@ type DetectorOutputWithID struct {
 }
 
 func getAllGuardDutyDetectors(session *session.Session, excludeAfter time.Time, configObj config.Config, batchSize int) ([]string, error) {
-	svc := guardduty.New(session)
+	svc := newGuardDutyClient(session)
 
 	var result []*string
 	var annotatedDetectors []*DetectorOutputWithID
 	var detectorIdsToInclude []string
 
-	var next *string = nil
-	for {
-		list, err := svc.ListDetectors(&guardduty.ListDetectorsInput{
-			MaxResults: awsgo.Int64(int64(batchSize)),
-			NextToken:  next,
-		})
-		if err != nil {
-			return nil, errors.WithStackTrace(err)
-		}
-
-		result = append(result, list.DetectorIds...)
-		if list.NextToken == nil || len(list.DetectorIds) == 0 {
-			break
-		}
-		next = list.NextToken
+	err := svc.ListDetectorsPages(&guardduty.ListDetectorsInput{
+		MaxResults: awsgo.Int64(int64(batchSize)),
+	}, func(page *guardduty.ListDetectorsOutput, lastPage bool) bool {
+		result = append(result, page.DetectorIds...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	// Due to the ListDetectors method only returning the Ids of found detectors, we need to further enrich our data about
@@ -90,11 +84,14 @@ func shouldIncludeDetector(detector *DetectorOutputWithID, excludeAfter time.Tim
 		return false
 	}
 
-	return true
+	return configObj.GuardDuty.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(detector.ID),
+		Tags: aws.StringValueMap(detector.Output.Tags),
+	})
 }
 
-func nukeAllGuardDutyDetectors(session *session.Session, detectorIds []string) error {
-	svc := guardduty.New(session)
+func nukeAllGuardDutyDetectors(ctx context.Context, session *session.Session, detectorIds []string) error {
+	svc := newGuardDutyClient(session)
 
 	if len(detectorIds) == 0 {
 		logging.Logger.Debugf("No GuardDuty detectors to nuke in region %s", *session.Config.Region)
@@ -111,7 +108,7 @@ func nukeAllGuardDutyDetectors(session *session.Session, detectorIds []string) e
 			DetectorId: aws.String(detectorId),
 		}
 
-		_, err := svc.DeleteDetector(params)
+		_, err := svc.DeleteDetectorWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -119,7 +116,7 @@ func nukeAllGuardDutyDetectors(session *session.Session, detectorIds []string) e
 			ResourceType: "GuardDuty Detector",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s: %s", detectorId, err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -65,7 +66,7 @@ func TestListGuardDutyDetectors(t *testing.T) {
 	testDetectorId := createTestGuardDutyDetector(t, session)
 
 	// clean up after this test
-	defer nukeAllGuardDutyDetectors(session, []string{testDetectorId})
+	defer nukeAllGuardDutyDetectors(context.Background(), session, []string{testDetectorId})
 
 	detectorIds, lookupErr := getAllGuardDutyDetectors(session, time.Now(), config.Config{}, GuardDuty{}.MaxBatchSize())
 
@@ -90,7 +91,7 @@ func TestTimeFilterExclusionNewlyCreatedGuardDutyDetector(t *testing.T) {
 
 	testDetectorId := createTestGuardDutyDetector(t, session)
 	// Clean up after this test
-	defer nukeAllGuardDutyDetectors(session, []string{testDetectorId})
+	defer nukeAllGuardDutyDetectors(context.Background(), session, []string{testDetectorId})
 
 	// Assert detectors are picked up without filters
 	detectorIds, err := getAllGuardDutyDetectors(session, time.Now(), config.Config{}, GuardDuty{}.MaxBatchSize())
@@ -126,7 +127,7 @@ func TestNukeGuardDutyDetectorOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllGuardDutyDetectors(session, identifiers),
+		nukeAllGuardDutyDetectors(context.Background(), session, identifiers),
 	)
 
 	// Make sure the GuardDuty detector was deleted
@@ -158,12 +159,12 @@ func TestNukeGuardDutyDetectorMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllGuardDutyDetectors(session1, []string{testDetectorId1}),
+		nukeAllGuardDutyDetectors(context.Background(), session1, []string{testDetectorId1}),
 	)
 
 	require.NoError(
 		t,
-		nukeAllGuardDutyDetectors(session2, []string{testDetectorId2}),
+		nukeAllGuardDutyDetectors(context.Background(), session2, []string{testDetectorId2}),
 	)
 
 	// Make sure the GuardDuty detector was deleted
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 )
 
@@ -20,6 +22,19 @@ func (gd GuardDuty) MaxBatchSize() int {
 	return 10
 }
 
-func (gd GuardDuty) Nuke(session *session.Session, detectorIds []string) error {
-	return nukeAllGuardDutyDetectors(session, detectorIds)
+func (gd GuardDuty) Nuke(ctx context.Context, session *session.Session, detectorIds []string) error {
+	return nukeAllGuardDutyDetectors(ctx, session, detectorIds)
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:         GuardDuty{}.ResourceName(),
+		Description:  "GuardDuty Detectors",
+		ConfigKey:    "GuardDuty",
+		SupportsTags: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			detectorIds, err := getAllGuardDutyDetectors(session, params.ExcludeAfter, params.Config, GuardDuty{}.MaxBatchSize())
+			return GuardDuty{detectorIds: detectorIds}, err
+		},
+	})
 }
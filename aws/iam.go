@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -20,19 +22,29 @@ import (
 
 // List all IAM users in the AWS account and returns a slice of the UserNames
 func getAllIamUsers(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 	input := &iam.ListUsersInput{}
 
-	var userNames []*string
-
-	// TODO: Probably use ListUsers together with ListUsersPages in case there are lots of users
-	output, err := svc.ListUsers(input)
+	var users []*iam.User
+	err := svc.ListUsersPages(input, func(page *iam.ListUsersOutput, lastPage bool) bool {
+		users = append(users, page.Users...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
-	for _, user := range output.Users {
-		if config.ShouldInclude(aws.StringValue(user.UserName), configObj.IAMUsers.IncludeRule.NamesRegExp, configObj.IAMUsers.ExcludeRule.NamesRegExp) && excludeAfter.After(*user.CreateDate) {
+	var userNames []*string
+	for _, user := range users {
+		if excludeAfter.After(*user.CreateDate) && configObj.IAMUsers.ShouldInclude(config.ResourceValue{Name: aws.StringValue(user.UserName), Time: aws.TimeValue(user.CreateDate)}) {
+			// ListUsers does not return tags, so they are only looked up for the users that would be nuked
+			tags, err := getIAMUserTags(svc, user.UserName)
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			if excludedByTag(IAMUsers{}.ResourceName(), GlobalRegion, aws.StringValue(user.UserName), tags) {
+				continue
+			}
 			userNames = append(userNames, user.UserName)
 		}
 	}
@@ -40,8 +52,32 @@ func getAllIamUsers(session *session.Session, excludeAfter time.Time, configObj
 	return userNames, nil
 }
 
-func detachUserPolicies(svc *iam.IAM, userName *string) error {
-	policiesOutput, err := svc.ListAttachedUserPolicies(&iam.ListAttachedUserPoliciesInput{
+// getIAMUserTags returns the tags of the given IAM user as a map of tag keys to values
+func getIAMUserTags(svc iamiface.IAMAPI, userName *string) (map[string]string, error) {
+	tags := make(map[string]string)
+	err := svc.ListUserTagsPages(
+		&iam.ListUserTagsInput{UserName: userName},
+		func(page *iam.ListUserTagsOutput, lastPage bool) bool {
+			for key, value := range iamTagsToMap(page.Tags) {
+				tags[key] = value
+			}
+			return !lastPage
+		},
+	)
+	return tags, err
+}
+
+// iamTagsToMap converts the tags of an IAM resource into a map of tag keys to values
+func iamTagsToMap(tags []*iam.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+func detachUserPolicies(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	policiesOutput, err := svc.ListAttachedUserPoliciesWithContext(ctx, &iam.ListAttachedUserPoliciesInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -50,7 +86,7 @@ func detachUserPolicies(svc *iam.IAM, userName *string) error {
 
 	for _, attachedPolicy := range policiesOutput.AttachedPolicies {
 		arn := attachedPolicy.PolicyArn
-		_, err = svc.DetachUserPolicy(&iam.DetachUserPolicyInput{
+		_, err = svc.DetachUserPolicyWithContext(ctx, &iam.DetachUserPolicyInput{
 			PolicyArn: arn,
 			UserName:  userName,
 		})
@@ -64,8 +100,8 @@ func detachUserPolicies(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteInlineUserPolicies(svc *iam.IAM, userName *string) error {
-	policyOutput, err := svc.ListUserPolicies(&iam.ListUserPoliciesInput{
+func deleteInlineUserPolicies(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	policyOutput, err := svc.ListUserPoliciesWithContext(ctx, &iam.ListUserPoliciesInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -74,7 +110,7 @@ func deleteInlineUserPolicies(svc *iam.IAM, userName *string) error {
 	}
 
 	for _, policyName := range policyOutput.PolicyNames {
-		_, err := svc.DeleteUserPolicy(&iam.DeleteUserPolicyInput{
+		_, err := svc.DeleteUserPolicyWithContext(ctx, &iam.DeleteUserPolicyInput{
 			PolicyName: policyName,
 			UserName:   userName,
 		})
@@ -88,8 +124,8 @@ func deleteInlineUserPolicies(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func removeUserFromGroups(svc *iam.IAM, userName *string) error {
-	groupsOutput, err := svc.ListGroupsForUser(&iam.ListGroupsForUserInput{
+func removeUserFromGroups(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	groupsOutput, err := svc.ListGroupsForUserWithContext(ctx, &iam.ListGroupsForUserInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -97,7 +133,7 @@ func removeUserFromGroups(svc *iam.IAM, userName *string) error {
 	}
 
 	for _, group := range groupsOutput.Groups {
-		_, err := svc.RemoveUserFromGroup(&iam.RemoveUserFromGroupInput{
+		_, err := svc.RemoveUserFromGroupWithContext(ctx, &iam.RemoveUserFromGroupInput{
 			GroupName: group.GroupName,
 			UserName:  userName,
 		})
@@ -111,15 +147,15 @@ func removeUserFromGroups(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteLoginProfile(svc *iam.IAM, userName *string) error {
-	return retry.DoWithRetry(
-		logging.Logger,
+func deleteLoginProfile(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	return retryWithContext(
+		ctx,
 		"Delete Login Profile",
 		10,
 		2*time.Second,
 		func() error {
 			// Delete Login Profile attached to the user
-			_, err := svc.DeleteLoginProfile(&iam.DeleteLoginProfileInput{
+			_, err := svc.DeleteLoginProfileWithContext(ctx, &iam.DeleteLoginProfileInput{
 				UserName: userName,
 			})
 			if err != nil {
@@ -145,8 +181,8 @@ func deleteLoginProfile(svc *iam.IAM, userName *string) error {
 		})
 }
 
-func deleteAccessKeys(svc *iam.IAM, userName *string) error {
-	output, err := svc.ListAccessKeys(&iam.ListAccessKeysInput{
+func deleteAccessKeys(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	output, err := svc.ListAccessKeysWithContext(ctx, &iam.ListAccessKeysInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -156,7 +192,7 @@ func deleteAccessKeys(svc *iam.IAM, userName *string) error {
 
 	for _, md := range output.AccessKeyMetadata {
 		accessKeyId := md.AccessKeyId
-		_, err := svc.DeleteAccessKey(&iam.DeleteAccessKeyInput{
+		_, err := svc.DeleteAccessKeyWithContext(ctx, &iam.DeleteAccessKeyInput{
 			AccessKeyId: accessKeyId,
 			UserName:    userName,
 		})
@@ -171,8 +207,8 @@ func deleteAccessKeys(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteSigningCertificate(svc *iam.IAM, userName *string) error {
-	output, err := svc.ListSigningCertificates(&iam.ListSigningCertificatesInput{
+func deleteSigningCertificate(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	output, err := svc.ListSigningCertificatesWithContext(ctx, &iam.ListSigningCertificatesInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -182,7 +218,7 @@ func deleteSigningCertificate(svc *iam.IAM, userName *string) error {
 
 	for _, cert := range output.Certificates {
 		certificateId := cert.CertificateId
-		_, err := svc.DeleteSigningCertificate(&iam.DeleteSigningCertificateInput{
+		_, err := svc.DeleteSigningCertificateWithContext(ctx, &iam.DeleteSigningCertificateInput{
 			CertificateId: certificateId,
 			UserName:      userName,
 		})
@@ -197,8 +233,8 @@ func deleteSigningCertificate(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteSSHPublicKeys(svc *iam.IAM, userName *string) error {
-	output, err := svc.ListSSHPublicKeys(&iam.ListSSHPublicKeysInput{
+func deleteSSHPublicKeys(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	output, err := svc.ListSSHPublicKeysWithContext(ctx, &iam.ListSSHPublicKeysInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -208,7 +244,7 @@ func deleteSSHPublicKeys(svc *iam.IAM, userName *string) error {
 
 	for _, key := range output.SSHPublicKeys {
 		keyId := key.SSHPublicKeyId
-		_, err := svc.DeleteSSHPublicKey(&iam.DeleteSSHPublicKeyInput{
+		_, err := svc.DeleteSSHPublicKeyWithContext(ctx, &iam.DeleteSSHPublicKeyInput{
 			SSHPublicKeyId: keyId,
 			UserName:       userName,
 		})
@@ -223,13 +259,13 @@ func deleteSSHPublicKeys(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteServiceSpecificCredentials(svc *iam.IAM, userName *string) error {
+func deleteServiceSpecificCredentials(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
 	services := []string{
 		"cassandra.amazonaws.com",
 		"codecommit.amazonaws.com",
 	}
 	for _, service := range services {
-		output, err := svc.ListServiceSpecificCredentials(&iam.ListServiceSpecificCredentialsInput{
+		output, err := svc.ListServiceSpecificCredentialsWithContext(ctx, &iam.ListServiceSpecificCredentialsInput{
 			ServiceName: aws.String(service),
 			UserName:    userName,
 		})
@@ -241,7 +277,7 @@ func deleteServiceSpecificCredentials(svc *iam.IAM, userName *string) error {
 		for _, metadata := range output.ServiceSpecificCredentials {
 			serviceSpecificCredentialId := metadata.ServiceSpecificCredentialId
 
-			_, err := svc.DeleteServiceSpecificCredential(&iam.DeleteServiceSpecificCredentialInput{
+			_, err := svc.DeleteServiceSpecificCredentialWithContext(ctx, &iam.DeleteServiceSpecificCredentialInput{
 				ServiceSpecificCredentialId: serviceSpecificCredentialId,
 				UserName:                    userName,
 			})
@@ -257,8 +293,8 @@ func deleteServiceSpecificCredentials(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteMFADevices(svc *iam.IAM, userName *string) error {
-	output, err := svc.ListMFADevices(&iam.ListMFADevicesInput{
+func deleteMFADevices(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	output, err := svc.ListMFADevicesWithContext(ctx, &iam.ListMFADevicesInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -270,7 +306,7 @@ func deleteMFADevices(svc *iam.IAM, userName *string) error {
 	for _, device := range output.MFADevices {
 		serialNumber := device.SerialNumber
 
-		_, err := svc.DeactivateMFADevice(&iam.DeactivateMFADeviceInput{
+		_, err := svc.DeactivateMFADeviceWithContext(ctx, &iam.DeactivateMFADeviceInput{
 			SerialNumber: serialNumber,
 			UserName:     userName,
 		})
@@ -286,7 +322,7 @@ func deleteMFADevices(svc *iam.IAM, userName *string) error {
 	for _, device := range output.MFADevices {
 		serialNumber := device.SerialNumber
 
-		_, err := svc.DeleteVirtualMFADevice(&iam.DeleteVirtualMFADeviceInput{
+		_, err := svc.DeleteVirtualMFADeviceWithContext(ctx, &iam.DeleteVirtualMFADeviceInput{
 			SerialNumber: serialNumber,
 		})
 		if err != nil {
@@ -300,8 +336,8 @@ func deleteMFADevices(svc *iam.IAM, userName *string) error {
 	return nil
 }
 
-func deleteUser(svc *iam.IAM, userName *string) error {
-	_, err := svc.DeleteUser(&iam.DeleteUserInput{
+func deleteUser(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
+	_, err := svc.DeleteUserWithContext(ctx, &iam.DeleteUserInput{
 		UserName: userName,
 	})
 	if err != nil {
@@ -312,12 +348,12 @@ func deleteUser(svc *iam.IAM, userName *string) error {
 }
 
 // Nuke a single user
-func nukeUser(svc *iam.IAM, userName *string) error {
+func nukeUser(ctx context.Context, svc iamiface.IAMAPI, userName *string) error {
 	// Functions used to really nuke an IAM User as a user can have many attached
 	// items we need delete/detach them before actually deleting it.
 	// NOTE: The actual user deletion should always be the last one. This way we
 	// can guarantee that it will fail if we forgot to delete/detach an item.
-	functions := []func(svc *iam.IAM, userName *string) error{
+	functions := []func(ctx context.Context, svc iamiface.IAMAPI, userName *string) error{
 		detachUserPolicies, // TODO: Add CLI option to delete the Policy as policies exist independently of the user
 		deleteInlineUserPolicies,
 		removeUserFromGroups, // TODO: Add CLI option to delete groups as groups exist independently of the user
@@ -331,7 +367,7 @@ func nukeUser(svc *iam.IAM, userName *string) error {
 	}
 
 	for _, fn := range functions {
-		if err := fn(svc, userName); err != nil {
+		if err := fn(ctx, svc, userName); err != nil {
 			return err
 		}
 	}
@@ -340,7 +376,7 @@ func nukeUser(svc *iam.IAM, userName *string) error {
 }
 
 // Delete all IAM Users
-func nukeAllIamUsers(session *session.Session, userNames []*string) error {
+func nukeAllIamUsers(ctx context.Context, session *session.Session, userNames []*string) error {
 	if len(userNames) == 0 {
 		logging.Logger.Info("No IAM Users to nuke")
 		return nil
@@ -349,18 +385,18 @@ func nukeAllIamUsers(session *session.Session, userNames []*string) error {
 	logging.Logger.Info("Deleting all IAM Users")
 
 	deletedUsers := 0
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 	multiErr := new(multierror.Error)
 
 	for _, userName := range userNames {
-		err := nukeUser(svc, userName)
+		err := nukeUser(ctx, svc, userName)
 		// Record status of this resource
 		e := report.Entry{
 			Identifier:   aws.StringValue(userName),
 			ResourceType: "IAM User",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Errorf("[Failed] %s", err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/gruntwork-cli/errors"
@@ -17,7 +19,7 @@ import (
 )
 
 func getAllIamGroups(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	var allIamGroups []*string
 	err := svc.ListGroupsPages(
@@ -38,8 +40,8 @@ func getAllIamGroups(session *session.Session, excludeAfter time.Time, configObj
 }
 
 // nukeAllIamGroups - delete all IAM groups.  Caller is responsible for pagination (no more than 100/request)
-func nukeAllIamGroups(session *session.Session, groupNames []*string) error {
-	svc := iam.New(session)
+func nukeAllIamGroups(ctx context.Context, session *session.Session, groupNames []*string) error {
+	svc := newIAMClient(session)
 
 	if len(groupNames) == 0 {
 		logging.Logger.Debug("No IAM Groups to nuke")
@@ -59,7 +61,7 @@ func nukeAllIamGroups(session *session.Session, groupNames []*string) error {
 	errChans := make([]chan error, len(groupNames))
 	for i, groupName := range groupNames {
 		errChans[i] = make(chan error, 1)
-		go deleteIamGroupAsync(wg, errChans[i], svc, groupName)
+		go deleteIamGroupAsync(ctx, wg, errChans[i], svc, groupName)
 	}
 	wg.Wait()
 
@@ -85,7 +87,7 @@ func nukeAllIamGroups(session *session.Session, groupNames []*string) error {
 }
 
 // deleteIamGroup - removes an IAM group from AWS, designed to run as a goroutine
-func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, groupName *string) {
+func deleteIamGroupAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc iamiface.IAMAPI, groupName *string) {
 	defer wg.Done()
 	var multierr *multierror.Error
 
@@ -93,13 +95,13 @@ func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, g
 	getGroupInput := &iam.GetGroupInput{
 		GroupName: groupName,
 	}
-	grp, err := svc.GetGroup(getGroupInput)
+	grp, err := svc.GetGroupWithContext(ctx, getGroupInput)
 	for _, user := range grp.Users {
 		unlinkUserInput := &iam.RemoveUserFromGroupInput{
 			UserName:  user.UserName,
 			GroupName: groupName,
 		}
-		_, err := svc.RemoveUserFromGroup(unlinkUserInput)
+		_, err := svc.RemoveUserFromGroupWithContext(ctx, unlinkUserInput)
 		if err != nil {
 			multierr = multierror.Append(multierr, err)
 		}
@@ -107,7 +109,7 @@ func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, g
 
 	//Detach any policies on the group
 	allPolicies := []*string{}
-	err = svc.ListAttachedGroupPoliciesPages(&iam.ListAttachedGroupPoliciesInput{GroupName: groupName},
+	err = svc.ListAttachedGroupPoliciesPagesWithContext(ctx, &iam.ListAttachedGroupPoliciesInput{GroupName: groupName},
 		func(page *iam.ListAttachedGroupPoliciesOutput, lastPage bool) bool {
 			for _, iamPolicy := range page.AttachedPolicies {
 				allPolicies = append(allPolicies, iamPolicy.PolicyArn)
@@ -121,12 +123,12 @@ func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, g
 			GroupName: groupName,
 			PolicyArn: policy,
 		}
-		_, err = svc.DetachGroupPolicy(unlinkPolicyInput)
+		_, err = svc.DetachGroupPolicyWithContext(ctx, unlinkPolicyInput)
 	}
 
 	// Detach any inline policies on the group
 	allInlinePolicyNames := []*string{}
-	err = svc.ListGroupPoliciesPages(&iam.ListGroupPoliciesInput{GroupName: groupName},
+	err = svc.ListGroupPoliciesPagesWithContext(ctx, &iam.ListGroupPoliciesInput{GroupName: groupName},
 		func(page *iam.ListGroupPoliciesOutput, lastPage bool) bool {
 			logging.Logger.Info("ListGroupPolicies response page: ", page)
 			for _, policyName := range page.PolicyNames {
@@ -138,14 +140,14 @@ func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, g
 
 	logging.Logger.Info("inline policies: ", allInlinePolicyNames)
 	for _, policyName := range allInlinePolicyNames {
-		_, err = svc.DeleteGroupPolicy(&iam.DeleteGroupPolicyInput{
+		_, err = svc.DeleteGroupPolicyWithContext(ctx, &iam.DeleteGroupPolicyInput{
 			GroupName:  groupName,
 			PolicyName: policyName,
 		})
 	}
 
 	//Delete the group
-	_, err = svc.DeleteGroup(&iam.DeleteGroupInput{
+	_, err = svc.DeleteGroupWithContext(ctx, &iam.DeleteGroupInput{
 		GroupName: groupName,
 	})
 	if err != nil {
@@ -159,7 +161,7 @@ func deleteIamGroupAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, g
 		ResourceType: "IAM Group",
 		Error:        multierr.ErrorOrNil(),
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- multierr.ErrorOrNil()
 }
@@ -174,11 +176,7 @@ func shouldIncludeIamGroup(iamGroup *iam.Group, excludeAfter time.Time, configOb
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(iamGroup.GroupName),
-		configObj.IAMGroups.IncludeRule.NamesRegExp,
-		configObj.IAMGroups.ExcludeRule.NamesRegExp,
-	)
+	return configObj.IAMGroups.ShouldInclude(config.ResourceValue{Name: aws.StringValue(iamGroup.GroupName)})
 }
 
 // TooManyIamGroupErr Custom Errors
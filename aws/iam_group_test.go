@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
@@ -183,7 +184,7 @@ func TestNukeIamGroups(t *testing.T) {
 	assert.Contains(t, awsgo.StringValueSlice(groupNames), emptyName)
 
 	//Nuke test entities
-	err = nukeAllIamGroups(localSession, []*string{&emptyName, &nonEmptyName})
+	err = nukeAllIamGroups(context.Background(), localSession, []*string{&emptyName, &nonEmptyName})
 	require.NoError(t, err)
 
 	//Assert test entities don't exist anymore
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -28,10 +30,23 @@ func (g IAMGroups) MaxBatchSize() int {
 }
 
 // Nuke - Destroy every group in this collection
-func (g IAMGroups) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllIamGroups(session, awsgo.StringSlice(identifiers)); err != nil {
+func (g IAMGroups) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllIamGroups(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        IAMGroups{}.ResourceName(),
+		Description: "IAM Groups",
+		Global:      true,
+		ConfigKey:   "IAMGroups",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllIamGroups(session, params.ExcludeAfter, params.Config)
+			return IAMGroups{GroupNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
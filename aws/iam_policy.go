@@ -1,9 +1,11 @@
 package aws
 
 import (
+	"context"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -17,7 +19,7 @@ import (
 
 // Returns the ARN of all customer managed policies
 func getAllLocalIamPolicies(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	var allIamPolicies []*string
 
@@ -41,8 +43,8 @@ func getAllLocalIamPolicies(session *session.Session, excludeAfter time.Time, co
 }
 
 // Delete all iam customer managed policies. Caller is responsible for pagination (no more than 100/request)
-func nukeAllIamPolicies(session *session.Session, policyArns []*string) error {
-	svc := iam.New(session)
+func nukeAllIamPolicies(ctx context.Context, session *session.Session, policyArns []*string) error {
+	svc := newIAMClient(session)
 
 	if len(policyArns) == 0 {
 		logging.Logger.Debug("No IAM Policies to nuke")
@@ -61,7 +63,7 @@ func nukeAllIamPolicies(session *session.Session, policyArns []*string) error {
 	errChans := make([]chan error, len(policyArns))
 	for i, arn := range policyArns {
 		errChans[i] = make(chan error, 1)
-		go deleteIamPolicyAsync(wg, errChans[i], svc, arn)
+		go deleteIamPolicyAsync(ctx, wg, errChans[i], svc, arn)
 	}
 	wg.Wait()
 
@@ -87,19 +89,19 @@ func nukeAllIamPolicies(session *session.Session, policyArns []*string) error {
 }
 
 // Removes an IAM Policy from AWS, designed to run as a goroutine
-func deleteIamPolicyAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, policyArn *string) {
+func deleteIamPolicyAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc iamiface.IAMAPI, policyArn *string) {
 	defer wg.Done()
 	var multierr *multierror.Error
 
 	//Detach any entities the policy is attached to
-	err := detachPolicyEntities(svc, policyArn)
+	err := detachPolicyEntities(ctx, svc, policyArn)
 	if err != nil {
 		multierr = multierror.Append(multierr, err)
 	}
 
 	//Get Old Policy Versions
 	var versionsToRemove []*string
-	err = svc.ListPolicyVersionsPages(&iam.ListPolicyVersionsInput{PolicyArn: policyArn},
+	err = svc.ListPolicyVersionsPagesWithContext(ctx, &iam.ListPolicyVersionsInput{PolicyArn: policyArn},
 		func(page *iam.ListPolicyVersionsOutput, lastPage bool) bool {
 			for _, policyVersion := range page.Versions {
 				if !*policyVersion.IsDefaultVersion {
@@ -114,13 +116,13 @@ func deleteIamPolicyAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM,
 
 	//Delete old policy versions
 	for _, versionId := range versionsToRemove {
-		_, err = svc.DeletePolicyVersion(&iam.DeletePolicyVersionInput{VersionId: versionId, PolicyArn: policyArn})
+		_, err = svc.DeletePolicyVersionWithContext(ctx, &iam.DeletePolicyVersionInput{VersionId: versionId, PolicyArn: policyArn})
 		if err != nil {
 			multierr = multierror.Append(multierr, err)
 		}
 	}
 	//Delete the policy
-	_, err = svc.DeletePolicy(&iam.DeletePolicyInput{PolicyArn: policyArn})
+	_, err = svc.DeletePolicyWithContext(ctx, &iam.DeletePolicyInput{PolicyArn: policyArn})
 	if err != nil {
 		multierr = multierror.Append(multierr, err)
 	} else {
@@ -132,16 +134,16 @@ func deleteIamPolicyAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM,
 		ResourceType: "IAM Policy",
 		Error:        multierr.ErrorOrNil(),
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- multierr.ErrorOrNil()
 }
 
-func detachPolicyEntities(svc *iam.IAM, policyArn *string) error {
+func detachPolicyEntities(ctx context.Context, svc iamiface.IAMAPI, policyArn *string) error {
 	var allPolicyGroups []*string
 	var allPolicyRoles []*string
 	var allPolicyUsers []*string
-	err := svc.ListEntitiesForPolicyPages(&iam.ListEntitiesForPolicyInput{PolicyArn: policyArn},
+	err := svc.ListEntitiesForPolicyPagesWithContext(ctx, &iam.ListEntitiesForPolicyInput{PolicyArn: policyArn},
 		func(page *iam.ListEntitiesForPolicyOutput, lastPage bool) bool {
 			for _, group := range page.PolicyGroups {
 				allPolicyGroups = append(allPolicyGroups, group.GroupName)
@@ -164,7 +166,7 @@ func detachPolicyEntities(svc *iam.IAM, policyArn *string) error {
 			UserName:  userName,
 			PolicyArn: policyArn,
 		}
-		_, err = svc.DetachUserPolicy(detachUserInput)
+		_, err = svc.DetachUserPolicyWithContext(ctx, detachUserInput)
 		if err != nil {
 			return err
 		}
@@ -175,7 +177,7 @@ func detachPolicyEntities(svc *iam.IAM, policyArn *string) error {
 			GroupName: groupName,
 			PolicyArn: policyArn,
 		}
-		_, err = svc.DetachGroupPolicy(detachGroupInput)
+		_, err = svc.DetachGroupPolicyWithContext(ctx, detachGroupInput)
 		if err != nil {
 			return err
 		}
@@ -186,7 +188,7 @@ func detachPolicyEntities(svc *iam.IAM, policyArn *string) error {
 			RoleName:  roleName,
 			PolicyArn: policyArn,
 		}
-		_, err = svc.DetachRolePolicy(detachRoleInput)
+		_, err = svc.DetachRolePolicyWithContext(ctx, detachRoleInput)
 		if err != nil {
 			return err
 		}
@@ -203,11 +205,10 @@ func shouldIncludeIamPolicy(iamPolicy *iam.Policy, excludeAfter time.Time, confi
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(iamPolicy.PolicyName),
-		configObj.IAMPolicies.IncludeRule.NamesRegExp,
-		configObj.IAMPolicies.ExcludeRule.NamesRegExp,
-	)
+	return configObj.IAMPolicies.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(iamPolicy.PolicyName),
+		ID:   aws.StringValue(iamPolicy.Arn),
+	})
 }
 
 // TooManyIamPolicyErr Custom Errors
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
@@ -201,7 +202,7 @@ func TestNukeIamPolicies(t *testing.T) {
 	assert.Contains(t, awsgo.StringValueSlice(policyArns), *entities.PolicyArn)
 
 	//Nuke test entities
-	err = nukeAllIamPolicies(localSession, []*string{&emptyPolicyArn, entities.PolicyArn})
+	err = nukeAllIamPolicies(context.Background(), localSession, []*string{&emptyPolicyArn, entities.PolicyArn})
 	require.NoError(t, err)
 
 	//Assert test entities don't exist anymore
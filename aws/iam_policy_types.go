@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,23 @@ func (p IAMPolicies) MaxBatchSize() int {
 }
 
 // Nuke - Destroy every group in this collection
-func (p IAMPolicies) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllIamPolicies(session, awsgo.StringSlice(identifiers)); err != nil {
+func (p IAMPolicies) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllIamPolicies(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        IAMPolicies{}.ResourceName(),
+		Description: "IAM Policies",
+		Global:      true,
+		ConfigKey:   "IAMPolicies",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllLocalIamPolicies(session, params.ExcludeAfter, params.Config)
+			return IAMPolicies{PolicyArns: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
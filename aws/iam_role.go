@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -19,15 +21,15 @@ import (
 
 // List all IAM Roles in the AWS account
 func getAllIamRoles(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
-	allIAMRoles := []*string{}
+	var candidateRoles []*string
 	err := svc.ListRolesPages(
 		&iam.ListRolesInput{},
 		func(page *iam.ListRolesOutput, lastPage bool) bool {
 			for _, iamRole := range page.Roles {
 				if shouldIncludeIAMRole(iamRole, excludeAfter, configObj) {
-					allIAMRoles = append(allIAMRoles, iamRole.RoleName)
+					candidateRoles = append(candidateRoles, iamRole.RoleName)
 				}
 			}
 			return !lastPage
@@ -36,20 +38,56 @@ func getAllIamRoles(session *session.Session, excludeAfter time.Time, configObj
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
+
+	// ListRoles does not return tags, so they are only looked up for the roles that would be nuked
+	allIAMRoles := []*string{}
+	for _, roleName := range candidateRoles {
+		tags, err := getIAMRoleTags(svc, roleName)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		if excludedByTag(IAMRoles{}.ResourceName(), GlobalRegion, aws.StringValue(roleName), tags) {
+			continue
+		}
+		allIAMRoles = append(allIAMRoles, roleName)
+	}
 	return allIAMRoles, nil
 }
 
-func deleteManagedRolePolicies(svc *iam.IAM, roleName *string) error {
-	policiesOutput, err := svc.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+// getIAMRoleTags returns the tags of the given IAM role as a map of tag keys to values
+func getIAMRoleTags(svc iamiface.IAMAPI, roleName *string) (map[string]string, error) {
+	tags := make(map[string]string)
+	input := &iam.ListRoleTagsInput{RoleName: roleName}
+	for {
+		output, err := svc.ListRoleTags(input)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range iamTagsToMap(output.Tags) {
+			tags[key] = value
+		}
+		if !aws.BoolValue(output.IsTruncated) {
+			return tags, nil
+		}
+		input.Marker = output.Marker
+	}
+}
+
+func deleteManagedRolePolicies(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error {
+	var attachedPolicies []*iam.AttachedPolicy
+	err := svc.ListAttachedRolePoliciesPagesWithContext(ctx, &iam.ListAttachedRolePoliciesInput{
 		RoleName: roleName,
+	}, func(page *iam.ListAttachedRolePoliciesOutput, lastPage bool) bool {
+		attachedPolicies = append(attachedPolicies, page.AttachedPolicies...)
+		return !lastPage
 	})
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
 
-	for _, attachedPolicy := range policiesOutput.AttachedPolicies {
+	for _, attachedPolicy := range attachedPolicies {
 		arn := attachedPolicy.PolicyArn
-		_, err = svc.DetachRolePolicy(&iam.DetachRolePolicyInput{
+		_, err = svc.DetachRolePolicyWithContext(ctx, &iam.DetachRolePolicyInput{
 			PolicyArn: arn,
 			RoleName:  roleName,
 		})
@@ -63,17 +101,21 @@ func deleteManagedRolePolicies(svc *iam.IAM, roleName *string) error {
 	return nil
 }
 
-func deleteInlineRolePolicies(svc *iam.IAM, roleName *string) error {
-	policyOutput, err := svc.ListRolePolicies(&iam.ListRolePoliciesInput{
+func deleteInlineRolePolicies(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error {
+	var policyNames []*string
+	err := svc.ListRolePoliciesPagesWithContext(ctx, &iam.ListRolePoliciesInput{
 		RoleName: roleName,
+	}, func(page *iam.ListRolePoliciesOutput, lastPage bool) bool {
+		policyNames = append(policyNames, page.PolicyNames...)
+		return !lastPage
 	})
 	if err != nil {
 		logging.Logger.Debugf("[Failed] %s", err)
 		return errors.WithStackTrace(err)
 	}
 
-	for _, policyName := range policyOutput.PolicyNames {
-		_, err := svc.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+	for _, policyName := range policyNames {
+		_, err := svc.DeleteRolePolicyWithContext(ctx, &iam.DeleteRolePolicyInput{
 			PolicyName: policyName,
 			RoleName:   roleName,
 		})
@@ -87,18 +129,22 @@ func deleteInlineRolePolicies(svc *iam.IAM, roleName *string) error {
 	return nil
 }
 
-func deleteInstanceProfilesFromRole(svc *iam.IAM, roleName *string) error {
-	profilesOutput, err := svc.ListInstanceProfilesForRole(&iam.ListInstanceProfilesForRoleInput{
+func deleteInstanceProfilesFromRole(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error {
+	var instanceProfiles []*iam.InstanceProfile
+	err := svc.ListInstanceProfilesForRolePagesWithContext(ctx, &iam.ListInstanceProfilesForRoleInput{
 		RoleName: roleName,
+	}, func(page *iam.ListInstanceProfilesForRoleOutput, lastPage bool) bool {
+		instanceProfiles = append(instanceProfiles, page.InstanceProfiles...)
+		return !lastPage
 	})
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
 
-	for _, profile := range profilesOutput.InstanceProfiles {
+	for _, profile := range instanceProfiles {
 
 		// Role needs to be removed from instance profile before it can be deleted
-		_, err := svc.RemoveRoleFromInstanceProfile(&iam.RemoveRoleFromInstanceProfileInput{
+		_, err := svc.RemoveRoleFromInstanceProfileWithContext(ctx, &iam.RemoveRoleFromInstanceProfileInput{
 			InstanceProfileName: profile.InstanceProfileName,
 			RoleName:            roleName,
 		})
@@ -106,7 +152,7 @@ func deleteInstanceProfilesFromRole(svc *iam.IAM, roleName *string) error {
 			logging.Logger.Debugf("[Failed] %s", err)
 			return errors.WithStackTrace(err)
 		} else {
-			_, err := svc.DeleteInstanceProfile(&iam.DeleteInstanceProfileInput{
+			_, err := svc.DeleteInstanceProfileWithContext(ctx, &iam.DeleteInstanceProfileInput{
 				InstanceProfileName: profile.InstanceProfileName,
 			})
 			if err != nil {
@@ -119,8 +165,8 @@ func deleteInstanceProfilesFromRole(svc *iam.IAM, roleName *string) error {
 	return nil
 }
 
-func deleteIamRole(svc *iam.IAM, roleName *string) error {
-	_, err := svc.DeleteRole(&iam.DeleteRoleInput{
+func deleteIamRole(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error {
+	_, err := svc.DeleteRoleWithContext(ctx, &iam.DeleteRoleInput{
 		RoleName: roleName,
 	})
 	if err != nil {
@@ -131,9 +177,9 @@ func deleteIamRole(svc *iam.IAM, roleName *string) error {
 }
 
 // Delete all IAM Roles
-func nukeAllIamRoles(session *session.Session, roleNames []*string) error {
+func nukeAllIamRoles(ctx context.Context, session *session.Session, roleNames []*string) error {
 	region := aws.StringValue(session.Config.Region)
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	if len(roleNames) == 0 {
 		logging.Logger.Debug("No IAM Roles to nuke")
@@ -156,7 +202,7 @@ func nukeAllIamRoles(session *session.Session, roleNames []*string) error {
 	errChans := make([]chan error, len(roleNames))
 	for i, roleName := range roleNames {
 		errChans[i] = make(chan error, 1)
-		go deleteIamRoleAsync(wg, errChans[i], svc, roleName)
+		go deleteIamRoleAsync(ctx, wg, errChans[i], svc, roleName)
 	}
 	wg.Wait()
 
@@ -206,14 +252,10 @@ func shouldIncludeIAMRole(iamRole *iam.Role, excludeAfter time.Time, configObj c
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(iamRole.RoleName),
-		configObj.IAMRoles.IncludeRule.NamesRegExp,
-		configObj.IAMRoles.ExcludeRule.NamesRegExp,
-	)
+	return configObj.IAMRoles.ShouldInclude(config.ResourceValue{Name: aws.StringValue(iamRole.RoleName), Time: aws.TimeValue(iamRole.CreateDate)})
 }
 
-func deleteIamRoleAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, roleName *string) {
+func deleteIamRoleAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc iamiface.IAMAPI, roleName *string) {
 	defer wg.Done()
 
 	var result *multierror.Error
@@ -222,7 +264,7 @@ func deleteIamRoleAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, ro
 	// items we need delete/detach them before actually deleting it.
 	// NOTE: The actual role deletion should always be the last one. This way we
 	// can guarantee that it will fail if we forgot to delete/detach an item.
-	functions := []func(svc *iam.IAM, roleName *string) error{
+	functions := []func(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error{
 		deleteInstanceProfilesFromRole,
 		deleteInlineRolePolicies,
 		deleteManagedRolePolicies,
@@ -230,7 +272,7 @@ func deleteIamRoleAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, ro
 	}
 
 	for _, fn := range functions {
-		if err := fn(svc, roleName); err != nil {
+		if err := fn(ctx, svc, roleName); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
@@ -241,7 +283,7 @@ func deleteIamRoleAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, ro
 		ResourceType: "IAM Role",
 		Error:        result.ErrorOrNil(),
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- result.ErrorOrNil()
 }
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -100,7 +101,7 @@ func TestCreateIamRole(t *testing.T) {
 	require.NoError(t, err)
 
 	err = createAndAttachInstanceProfile(t, session, name)
-	defer nukeAllIamRoles(session, []*string{&name})
+	defer nukeAllIamRoles(context.Background(), session, []*string{&name})
 	require.NoError(t, err)
 
 	roleNames, err = getAllIamRoles(session, time.Now(), config.Config{})
@@ -127,7 +128,7 @@ func TestNukeIamRoles(t *testing.T) {
 	err = createAndAttachInstanceProfile(t, session, name)
 	require.NoError(t, err)
 
-	err = nukeAllIamRoles(session, []*string{&name})
+	err = nukeAllIamRoles(context.Background(), session, []*string{&name})
 	require.NoError(t, err)
 }
 
@@ -151,7 +152,7 @@ func TestTimeFilterExclusionNewlyCreatedIamRole(t *testing.T) {
 
 	// Creates a role
 	err = createTestRole(t, session, name)
-	defer nukeAllIamRoles(session, []*string{&name})
+	defer nukeAllIamRoles(context.Background(), session, []*string{&name})
 
 	// Assert role is created
 	roleNames, err = getAllIamRoles(session, time.Now(), config.Config{})
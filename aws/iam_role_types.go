@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,24 @@ func (r IAMRoles) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (r IAMRoles) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllIamRoles(session, awsgo.StringSlice(identifiers)); err != nil {
+func (r IAMRoles) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllIamRoles(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            IAMRoles{}.ResourceName(),
+		Description:     "IAM Roles",
+		Global:          true,
+		ConfigKey:       "IAMRoles",
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllIamRoles(session, params.ExcludeAfter, params.Config)
+			return IAMRoles{RoleNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
@@ -12,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -21,7 +23,7 @@ import (
 
 // List all IAM Roles in the AWS account
 func getAllIamServiceLinkedRoles(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	allIAMServiceLinkedRoles := []*string{}
 	err := svc.ListRolesPages(
@@ -41,12 +43,12 @@ func getAllIamServiceLinkedRoles(session *session.Session, excludeAfter time.Tim
 	return allIAMServiceLinkedRoles, nil
 }
 
-func deleteIamServiceLinkedRole(svc *iam.IAM, roleName *string) error {
+func deleteIamServiceLinkedRole(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error {
 	// Deletion ID looks like this: "
 	//{
 	//	DeletionTaskId: "task/aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling_2/d3c4c9fc-7fd3-4a36-974a-afb0eb78f102"
 	//}
-	deletionData, err := svc.DeleteServiceLinkedRole(&iam.DeleteServiceLinkedRoleInput{
+	deletionData, err := svc.DeleteServiceLinkedRoleWithContext(ctx, &iam.DeleteServiceLinkedRoleInput{
 		RoleName: roleName,
 	})
 	if err != nil {
@@ -54,7 +56,9 @@ func deleteIamServiceLinkedRole(svc *iam.IAM, roleName *string) error {
 	}
 
 	// Wait for the deletion to complete
-	time.Sleep(3 * time.Second)
+	if err := sleepWithContext(ctx, 3*time.Second); err != nil {
+		return gruntworkerrors.WithStackTrace(err)
+	}
 
 	var deletionStatus *iam.GetServiceLinkedRoleDeletionStatusOutput
 
@@ -62,7 +66,7 @@ func deleteIamServiceLinkedRole(svc *iam.IAM, roleName *string) error {
 	for !done {
 		done = true
 		// Check if the deletion is complete
-		deletionStatus, err = svc.GetServiceLinkedRoleDeletionStatus(&iam.GetServiceLinkedRoleDeletionStatusInput{
+		deletionStatus, err = svc.GetServiceLinkedRoleDeletionStatusWithContext(ctx, &iam.GetServiceLinkedRoleDeletionStatusInput{
 			DeletionTaskId: deletionData.DeletionTaskId,
 		})
 		if err != nil {
@@ -71,7 +75,9 @@ func deleteIamServiceLinkedRole(svc *iam.IAM, roleName *string) error {
 		if aws.StringValue(deletionStatus.Status) == "IN_PROGRESS" {
 			logging.Logger.Debugf("Deletion of IAM ServiceLinked Role %s is still in progress", aws.StringValue(roleName))
 			done = false
-			time.Sleep(3 * time.Second)
+			if err := sleepWithContext(ctx, 3*time.Second); err != nil {
+				return gruntworkerrors.WithStackTrace(err)
+			}
 		}
 
 	}
@@ -85,9 +91,9 @@ func deleteIamServiceLinkedRole(svc *iam.IAM, roleName *string) error {
 }
 
 // Delete all IAM Roles
-func nukeAllIamServiceLinkedRoles(session *session.Session, roleNames []*string) error {
+func nukeAllIamServiceLinkedRoles(ctx context.Context, session *session.Session, roleNames []*string) error {
 	region := aws.StringValue(session.Config.Region)
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	if len(roleNames) == 0 {
 		logging.Logger.Debug("No IAM Service Linked Roles to nuke")
@@ -110,7 +116,7 @@ func nukeAllIamServiceLinkedRoles(session *session.Session, roleNames []*string)
 	errChans := make([]chan error, len(roleNames))
 	for i, roleName := range roleNames {
 		errChans[i] = make(chan error, 1)
-		go deleteIamServiceLinkedRoleAsync(wg, errChans[i], svc, roleName)
+		go deleteIamServiceLinkedRoleAsync(ctx, wg, errChans[i], svc, roleName)
 	}
 	wg.Wait()
 
@@ -151,14 +157,10 @@ func shouldIncludeIAMServiceLinkedRole(iamServiceLinkedRole *iam.Role, excludeAf
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(iamServiceLinkedRole.RoleName),
-		configObj.IAMServiceLinkedRoles.IncludeRule.NamesRegExp,
-		configObj.IAMServiceLinkedRoles.ExcludeRule.NamesRegExp,
-	)
+	return configObj.IAMServiceLinkedRoles.ShouldInclude(config.ResourceValue{Name: aws.StringValue(iamServiceLinkedRole.RoleName)})
 }
 
-func deleteIamServiceLinkedRoleAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, roleName *string) {
+func deleteIamServiceLinkedRoleAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc iamiface.IAMAPI, roleName *string) {
 	defer wg.Done()
 
 	var result *multierror.Error
@@ -167,12 +169,12 @@ func deleteIamServiceLinkedRoleAsync(wg *sync.WaitGroup, errChan chan error, svc
 	// items we need delete/detach them before actually deleting it.
 	// NOTE: The actual role deletion should always be the last one. This way we
 	// can guarantee that it will fail if we forgot to delete/detach an item.
-	functions := []func(svc *iam.IAM, roleName *string) error{
+	functions := []func(ctx context.Context, svc iamiface.IAMAPI, roleName *string) error{
 		deleteIamServiceLinkedRole,
 	}
 
 	for _, fn := range functions {
-		if err := fn(svc, roleName); err != nil {
+		if err := fn(ctx, svc, roleName); err != nil {
 			result = multierror.Append(result, err)
 		}
 	}
@@ -183,7 +185,7 @@ func deleteIamServiceLinkedRoleAsync(wg *sync.WaitGroup, errChan chan error, svc
 		ResourceType: "IAM Service Linked Role",
 		Error:        result.ErrorOrNil(),
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- result.ErrorOrNil()
 }
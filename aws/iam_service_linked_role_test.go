@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -95,7 +96,7 @@ func TestNukeIamServiceLinkedRoles(t *testing.T) {
 	err = createTestServiceLinkedRole(t, session, name, awsServiceName)
 	require.NoError(t, err)
 
-	err = nukeAllIamServiceLinkedRoles(session, []*string{&iamServiceLinkedRoleName})
+	err = nukeAllIamServiceLinkedRoles(context.Background(), session, []*string{&iamServiceLinkedRoleName})
 	require.NoError(t, err)
 
 	roleNames, err := getAllIamServiceLinkedRoles(session, time.Now(), config.Config{})
@@ -127,7 +128,7 @@ func TestTimeFilterExclusionNewlyCreatedIamServiceLinkedRole(t *testing.T) {
 
 	// Creates a role
 	err = createTestServiceLinkedRole(t, session, name, awsServiceName)
-	defer nukeAllIamRoles(session, []*string{&name})
+	defer nukeAllIamRoles(context.Background(), session, []*string{&name})
 
 	// Assert role is created
 	roleNames, err = getAllIamServiceLinkedRoles(session, time.Now(), config.Config{})
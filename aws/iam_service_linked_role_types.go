@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,23 @@ func (r IAMServiceLinkedRoles) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (r IAMServiceLinkedRoles) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllIamServiceLinkedRoles(session, awsgo.StringSlice(identifiers)); err != nil {
+func (r IAMServiceLinkedRoles) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllIamServiceLinkedRoles(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        IAMServiceLinkedRoles{}.ResourceName(),
+		Description: "IAM Service Linked Roles",
+		Global:      true,
+		ConfigKey:   "IAMServiceLinkedRoles",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllIamServiceLinkedRoles(session, params.ExcludeAfter, params.Config)
+			return IAMServiceLinkedRoles{RoleNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
@@ -79,7 +80,7 @@ func TestCreateIamUser(t *testing.T) {
 	assert.NotContains(t, awsgo.StringValueSlice(userNames), name)
 
 	err = createTestUser(t, session, name)
-	defer nukeAllIamUsers(session, []*string{&name})
+	defer nukeAllIamUsers(context.Background(), session, []*string{&name})
 	require.NoError(t, err)
 
 	userNames, err = getAllIamUsers(session, time.Now(), config.Config{})
@@ -103,7 +104,7 @@ func TestNukeIamUsers(t *testing.T) {
 	err = createTestUser(t, session, name)
 	require.NoError(t, err)
 
-	err = nukeAllIamUsers(session, []*string{&name})
+	err = nukeAllIamUsers(context.Background(), session, []*string{&name})
 	require.NoError(t, err)
 }
 
@@ -127,7 +128,7 @@ func TestTimeFilterExclusionNewlyCreatedIamUser(t *testing.T) {
 
 	// Creates a user
 	err = createTestUser(t, session, name)
-	defer nukeAllIamUsers(session, []*string{&name})
+	defer nukeAllIamUsers(context.Background(), session, []*string{&name})
 
 	// Assert user is created
 	userNames, err = getAllIamUsers(session, time.Now(), config.Config{})
@@ -475,6 +476,6 @@ func TestDeleteFullIamUser(t *testing.T) {
 	defer deleteUserExtraResources(userInfos, session)
 	require.NoError(t, err)
 
-	err = nukeAllIamUsers(session, []*string{userInfos.UserName})
+	err = nukeAllIamUsers(context.Background(), session, []*string{userInfos.UserName})
 	require.NoError(t, err)
 }
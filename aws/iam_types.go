@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,24 @@ func (u IAMUsers) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (u IAMUsers) Nuke(session *session.Session, users []string) error {
-	if err := nukeAllIamUsers(session, awsgo.StringSlice(users)); err != nil {
+func (u IAMUsers) Nuke(ctx context.Context, session *session.Session, users []string) error {
+	if err := nukeAllIamUsers(ctx, session, awsgo.StringSlice(users)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            IAMUsers{}.ResourceName(),
+		Description:     "IAM Users",
+		Global:          true,
+		ConfigKey:       "IAMUsers",
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllIamUsers(session, params.ExcludeAfter, params.Config)
+			return IAMUsers{UserNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,10 +1,13 @@
 package aws
 
 import (
+	"context"
 	"fmt"
+	"sort"
+	"time"
 
-	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 	"github.com/tnn-gruntwork-io/go-commons/collections"
 )
@@ -31,6 +34,143 @@ func ExtractResourcesForPrinting(account *AwsAccountResources) []string {
 	return resources
 }
 
+// ExtractResourcesForOutput converts the nested structure of AwsAccountResources into rows for machine-readable output,
+// sorted by region. Every row is stamped with the given discovery time.
+func ExtractResourcesForOutput(account *AwsAccountResources, discoveredAt time.Time) []ui.ResourceRow {
+	rows := []ui.ResourceRow{}
+	for _, region := range sortedRegions(account) {
+		for _, foundResources := range account.Resources[region].Resources {
+			for _, identifier := range foundResources.ResourceIdentifiers() {
+				rows = append(rows, ui.ResourceRow{
+					Identifier:   identifier,
+					ResourceType: foundResources.ResourceName(),
+					Region:       region,
+					Status:       ui.ResourceStatusFound,
+					Timestamp:    discoveredAt,
+				})
+			}
+		}
+	}
+	return rows
+}
+
+// ExtractExclusionsForPrinting converts the given exclusions into lines well-suited for printing line by line, next to
+// the output of ExtractResourcesForPrinting
+func ExtractExclusionsForPrinting(exclusions []report.Exclusion) []string {
+	var lines []string
+	for _, exclusion := range exclusions {
+		lines = append(lines, fmt.Sprintf("%s %s %s (%s)\n", ui.ResourceHighlightStyle.Render(exclusion.ResourceType), exclusion.Identifier, exclusion.Region, exclusion.Reason))
+	}
+	return lines
+}
+
+// ExtractExclusionsForOutput converts the given exclusions, as recorded in the report package while discovering
+// resources, into rows for machine-readable output
+func ExtractExclusionsForOutput(exclusions []report.Exclusion) []ui.ResourceRow {
+	rows := []ui.ResourceRow{}
+	for _, exclusion := range exclusions {
+		rows = append(rows, ui.ResourceRow{
+			Identifier:   exclusion.Identifier,
+			ResourceType: exclusion.ResourceType,
+			Region:       exclusion.Region,
+			Status:       ui.ResourceStatusExcluded,
+			Reason:       exclusion.Reason,
+			Timestamp:    exclusion.Timestamp,
+		})
+	}
+	return rows
+}
+
+// ExtractRunReportForOutput converts the outcome of nuking resources, as recorded in the report package, into rows for
+// machine-readable output, sorted by region, resource type and identifier. General errors are included as rows without
+// an identifier.
+func ExtractRunReportForOutput() []ui.ResourceRow {
+	records := report.GetRecords()
+	rows := []ui.ResourceRow{}
+	for _, key := range sortedRecordKeys(records) {
+		rows = append(rows, recordToRow(records[key], key.Region))
+	}
+
+	rows = append(rows, extractGeneralErrorsForOutput()...)
+	return rows
+}
+
+// sortedRecordKeys returns the keys of the given records sorted by region, resource type and identifier
+func sortedRecordKeys(records map[report.RecordKey]report.Entry) []report.RecordKey {
+	keys := make([]report.RecordKey, 0, len(records))
+	for key := range records {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Region != keys[j].Region {
+			return keys[i].Region < keys[j].Region
+		}
+		if keys[i].ResourceType != keys[j].ResourceType {
+			return keys[i].ResourceType < keys[j].ResourceType
+		}
+		return keys[i].Identifier < keys[j].Identifier
+	})
+	return keys
+}
+
+// recordToRow converts the recorded outcome of nuking a resource in the given region into a row for machine-readable
+// output
+func recordToRow(entry report.Entry, region string) ui.ResourceRow {
+	row := ui.ResourceRow{
+		Identifier:   entry.Identifier,
+		ResourceType: entry.ResourceType,
+		Region:       region,
+		Status:       ui.ResourceStatusDeleted,
+		Timestamp:    entry.Timestamp,
+	}
+	if report.IsCancelled(entry.Error) {
+		row.Status = ui.ResourceStatusCancelled
+	} else if entry.Error != nil {
+		row.Status = ui.ResourceStatusFailed
+		row.Error = entry.Error.Error()
+	} else if entry.Quarantined {
+		row.Status = ui.ResourceStatusQuarantined
+	}
+	return row
+}
+
+// extractGeneralErrorsForOutput converts the general errors recorded in the report package into rows without an
+// identifier, sorted by description
+func extractGeneralErrorsForOutput() []ui.ResourceRow {
+	rows := []ui.ResourceRow{}
+	generalErrors := report.GetErrors()
+	var descriptions []string
+	for description := range generalErrors {
+		descriptions = append(descriptions, description)
+	}
+	sort.Strings(descriptions)
+	for _, description := range descriptions {
+		generalError := generalErrors[description]
+		message := description
+		if generalError.Error != nil {
+			message = fmt.Sprintf("%s: %s", description, generalError.Error)
+		}
+		rows = append(rows, ui.ResourceRow{
+			ResourceType: generalError.ResourceType,
+			Status:       ui.ResourceStatusError,
+			Error:        message,
+			Timestamp:    generalError.Timestamp,
+		})
+	}
+
+	return rows
+}
+
+// sortedRegions returns the regions of the given account resources in alphabetical order
+func sortedRegions(account *AwsAccountResources) []string {
+	var regions []string
+	for region := range account.Resources {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
 func ensureValidResourceTypes(resourceTypes []string) ([]string, error) {
 	invalidresourceTypes := []string{}
 	for _, resourceType := range resourceTypes {
@@ -79,6 +219,11 @@ func HandleResourceTypeSelections(
 }
 
 func InspectResources(q *Query) (*AwsAccountResources, error) {
+	return InspectResourcesWithContext(context.Background(), q)
+}
+
+// InspectResourcesWithContext is InspectResources, but stops listing resources once the context is done
+func InspectResourcesWithContext(ctx context.Context, q *Query) (*AwsAccountResources, error) {
 	// Log which resource types will be inspected
 	logging.Logger.Info("The following resource types will be inspected:")
 	if len(q.ResourceTypes) > 0 {
@@ -91,6 +236,7 @@ func InspectResources(q *Query) (*AwsAccountResources, error) {
 		}
 	}
 
-	// NOTE: The inspect functionality currently does not support config file, so we short circuit the logic with an empty struct.
-	return GetAllResources(q.Regions, q.ExcludeAfter, q.ResourceTypes, config.Config{}, q.ListUnaliasedKMSKeys)
+	// Resources excluded by the config file rules are recorded in the report package along with the rule that excluded
+	// them, see report.GetExclusions
+	return GetAllResources(ctx, q.Regions, q.ExcludeAfter, q.ResourceTypes, q.Config, q.ListUnaliasedKMSKeys, q.Parallelism)
 }
@@ -1,11 +1,16 @@
 package aws
 
 import (
+	"errors"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 )
 
 func TestHandleResourceTypeSelectionsRejectsInvalid(t *testing.T) {
@@ -93,3 +98,53 @@ func TestHandleResourceTypeSelectionsFiltering(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractResourcesForOutput(t *testing.T) {
+	t.Parallel()
+
+	discoveredAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := ExtractResourcesForOutput(newTestPlanAccount([]string{"i-1"}, []string{"vol-1"}), discoveredAt)
+
+	require.Equal(t, []ui.ResourceRow{
+		{Identifier: "vol-1", ResourceType: "ebs", Region: "eu-west-1", Status: ui.ResourceStatusFound, Timestamp: discoveredAt},
+		{Identifier: "i-1", ResourceType: "ec2", Region: "us-east-1", Status: ui.ResourceStatusFound, Timestamp: discoveredAt},
+	}, rows)
+}
+
+func TestExtractExclusionsForOutput(t *testing.T) {
+	t.Parallel()
+
+	recordedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := ExtractExclusionsForOutput([]report.Exclusion{
+		{Identifier: "db-1", ResourceType: "rds", Region: "us-east-1", Reason: ExcludedByTagReason, Timestamp: recordedAt},
+	})
+
+	assert.Equal(t, []ui.ResourceRow{
+		{Identifier: "db-1", ResourceType: "rds", Region: "us-east-1", Status: ui.ResourceStatusExcluded, Reason: "excluded by tag", Timestamp: recordedAt},
+	}, rows)
+}
+
+func TestExtractRunReportForOutput(t *testing.T) {
+	report.ResetRecords()
+	report.ResetErrors()
+	defer report.ResetRecords()
+	defer report.ResetErrors()
+
+	recordedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	report.Record(report.Entry{Identifier: "i-1", ResourceType: "ec2", Region: "us-east-1", Timestamp: recordedAt})
+	report.Record(report.Entry{Identifier: "vol-1", ResourceType: "ebs", Region: "eu-west-1", Error: errors.New("VolumeInUse"), Timestamp: recordedAt})
+	report.Record(report.Entry{Identifier: "sg-1", ResourceType: "vpc", Timestamp: recordedAt})
+	// The same identifier in another region is reported separately
+	report.Record(report.Entry{Identifier: "i-1", ResourceType: "ec2", Region: "eu-west-1", Error: errors.New("UnauthorizedOperation"), Timestamp: recordedAt})
+	report.RecordError(report.GeneralError{ResourceType: "s3", Description: "Unable to retrieve S3 Buckets", Error: errors.New("AccessDenied"), Timestamp: recordedAt})
+
+	rows := ExtractRunReportForOutput()
+
+	assert.Equal(t, []ui.ResourceRow{
+		{Identifier: "sg-1", ResourceType: "vpc", Status: ui.ResourceStatusDeleted, Timestamp: recordedAt},
+		{Identifier: "vol-1", ResourceType: "ebs", Region: "eu-west-1", Status: ui.ResourceStatusFailed, Error: "VolumeInUse", Timestamp: recordedAt},
+		{Identifier: "i-1", ResourceType: "ec2", Region: "eu-west-1", Status: ui.ResourceStatusFailed, Error: "UnauthorizedOperation", Timestamp: recordedAt},
+		{Identifier: "i-1", ResourceType: "ec2", Region: "us-east-1", Status: ui.ResourceStatusDeleted, Timestamp: recordedAt},
+		{ResourceType: "s3", Status: ui.ResourceStatusError, Error: "Unable to retrieve S3 Buckets: AccessDenied", Timestamp: recordedAt},
+	}, rows)
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -20,11 +19,10 @@ import (
 )
 
 func getAllKinesisStreams(session *session.Session, configObj config.Config) ([]*string, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newKinesisClient(session)
 	if err != nil {
 		return []*string{}, errors.WithStackTrace(err)
 	}
-	svc := kinesis.NewFromConfig(cfg)
 
 	allStreams := []*string{}
 
@@ -53,20 +51,15 @@ func shouldIncludeKinesisStream(streamName *string, configObj config.Config) boo
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(streamName),
-		configObj.KinesisStream.IncludeRule.NamesRegExp,
-		configObj.KinesisStream.ExcludeRule.NamesRegExp,
-	)
+	return configObj.KinesisStream.ShouldInclude(config.ResourceValue{Name: aws.StringValue(streamName)})
 }
 
-func nukeAllKinesisStreams(session *session.Session, identifiers []*string) error {
+func nukeAllKinesisStreams(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newKinesisClient(session)
 	if err != nil {
 		return err
 	}
-	svc := kinesis.NewFromConfig(cfg)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No Kinesis Streams to nuke in region: %s", region)
@@ -89,7 +82,7 @@ func nukeAllKinesisStreams(session *session.Session, identifiers []*string) erro
 	errChans := make([]chan error, len(identifiers))
 	for i, streamName := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteKinesisStreamAsync(wg, errChans[i], svc, streamName, region)
+		go deleteKinesisStreamAsync(ctx, wg, errChans[i], svc, streamName, region)
 	}
 	wg.Wait()
 
@@ -117,15 +110,16 @@ func nukeAllKinesisStreams(session *session.Session, identifiers []*string) erro
 }
 
 func deleteKinesisStreamAsync(
+	ctx context.Context,
 	wg *sync.WaitGroup,
 	errChan chan error,
-	svc *kinesis.Client,
+	svc kinesisAPI,
 	streamName *string,
 	region string,
 ) {
 	defer wg.Done()
 	input := &kinesis.DeleteStreamInput{StreamName: streamName}
-	_, err := svc.DeleteStream(context.TODO(), input)
+	_, err := svc.DeleteStream(ctx, input)
 
 	// Record status of this resource
 	e := report.Entry{
@@ -133,7 +127,7 @@ func deleteKinesisStreamAsync(
 		ResourceType: "Kinesis Stream",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 
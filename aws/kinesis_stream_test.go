@@ -65,7 +65,7 @@ func TestNukeKinesisStreamOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllKinesisStreams(session, identifiers),
+		nukeAllKinesisStreams(context.Background(), session, identifiers),
 	)
 
 	assertKinesisStreamsDeleted(t, svc, identifiers)
@@ -96,7 +96,7 @@ func TestNukeKinesisStreamMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllKinesisStreams(session, sNames),
+		nukeAllKinesisStreams(context.Background(), session, sNames),
 	)
 
 	assertKinesisStreamsDeleted(t, svc, sNames)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,22 @@ func (k KinesisStreams) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (k KinesisStreams) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllKinesisStreams(session, aws.StringSlice(identifiers)); err != nil {
+func (k KinesisStreams) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllKinesisStreams(ctx, session, aws.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        KinesisStreams{}.ResourceName(),
+		Description: "Kinesis Streams",
+		ConfigKey:   "KinesisStream",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllKinesisStreams(session, params.Config)
+			return KinesisStreams{Names: aws.StringValueSlice(ids)}, err
+		},
+	})
+}
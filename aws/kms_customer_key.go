@@ -1,6 +1,9 @@
 package aws
 
 import (
+	"context"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -9,17 +12,19 @@ import (
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 	"github.com/hashicorp/go-multierror"
 )
 
 func getAllKmsUserKeys(session *session.Session, batchSize int, excludeAfter time.Time, configObj config.Config, allowDeleteUnaliasedKeys bool) ([]*string, map[string][]string, error) {
-	svc := kms.New(session)
+	svc := newKMSClient(session)
 	// Collect all keys in the account
 	// AWS managed keys are filtered out in shouldIncludeKmsUserKey
 	keys, err := listKeys(svc, batchSize)
@@ -50,7 +55,7 @@ func getAllKmsUserKeys(session *session.Session, batchSize int, excludeAfter tim
 		// If the keyId isn't found in the map, this returns an empty array
 		aliasesForKey := keyAliases[keyId]
 
-		go shouldIncludeKmsUserKey(&wg, resultsChan[id], svc, keyId, aliasesForKey, excludeAfter, configObj, allowDeleteUnaliasedKeys)
+		go shouldIncludeKmsUserKey(&wg, resultsChan[id], svc, aws.StringValue(session.Config.Region), keyId, aliasesForKey, excludeAfter, configObj, allowDeleteUnaliasedKeys)
 		id++
 	}
 	wg.Wait()
@@ -79,20 +84,22 @@ type KmsCheckIncludeResult struct {
 	Error error
 }
 
-func shouldIncludeKmsUserKey(wg *sync.WaitGroup, resultsChan chan *KmsCheckIncludeResult, svc *kms.KMS, key string,
+func shouldIncludeKmsUserKey(wg *sync.WaitGroup, resultsChan chan *KmsCheckIncludeResult, svc kmsiface.KMSAPI, region string, key string,
 	aliases []string, excludeAfter time.Time, configObj config.Config, allowDeleteUnaliasedKeys bool,
 ) {
 	defer wg.Done()
 	includedByName := false
-	// verify if key aliases matches configurations
+	// verify if key aliases matches configurations. The rules are evaluated without the exclusion handler, as a key is
+	// only excluded if none of its aliases is included.
+	excludedReason := ""
 	for _, alias := range aliases {
-		v := config.ShouldInclude(alias, configObj.KMSCustomerKeys.IncludeRule.NamesRegExp,
-			configObj.KMSCustomerKeys.ExcludeRule.NamesRegExp)
+		v, reason := configObj.KMSCustomerKeys.Evaluate(config.ResourceValue{Name: alias})
 		if v {
 			includedByName = true
 
 			break
 		}
+		excludedReason = reason
 	}
 
 	// Only delete keys without aliases if the user explicitly says so
@@ -108,6 +115,7 @@ func shouldIncludeKmsUserKey(wg *sync.WaitGroup, resultsChan chan *KmsCheckInclu
 	}
 
 	if !includedByName {
+		recordExclusion(KmsCustomerKeys{}.ResourceName(), region, key, excludedByConfigReason(excludedReason))
 		resultsChan <- &KmsCheckIncludeResult{KeyId: ""}
 		return
 	}
@@ -137,26 +145,54 @@ func shouldIncludeKmsUserKey(wg *sync.WaitGroup, resultsChan chan *KmsCheckInclu
 		resultsChan <- &KmsCheckIncludeResult{KeyId: ""}
 		return
 	}
+	// keys carrying the exclusion tag must never be scheduled for deletion, even if they are shared
+	tags, err := getKmsKeyTags(svc, key)
+	if err != nil {
+		resultsChan <- &KmsCheckIncludeResult{Error: err}
+		return
+	}
+	if excludedByTag(KmsCustomerKeys{}.ResourceName(), region, key, tags) ||
+		excludedByQuarantine(KmsCustomerKeys{}.ResourceName(), region, key, tags, excludeAfter) {
+		resultsChan <- &KmsCheckIncludeResult{KeyId: ""}
+		return
+	}
 	// put key in channel to be considered for removal
 	resultsChan <- &KmsCheckIncludeResult{KeyId: key}
 }
 
-func listKeyAliases(svc *kms.KMS, batchSize int) (map[string][]string, error) {
-	// map key - KMS key id, value list of aliases
-	aliases := map[string][]string{}
-	var next *string
+// getKmsKeyTags returns the tags of the given KMS key as a map of tag keys to values
+func getKmsKeyTags(svc kmsiface.KMSAPI, key string) (map[string]string, error) {
+	return getKmsKeyTagsWithContext(aws.BackgroundContext(), svc, key)
+}
 
+// getKmsKeyTagsWithContext is like getKmsKeyTags, but stops once the given context is done
+func getKmsKeyTagsWithContext(ctx context.Context, svc kmsiface.KMSAPI, key string) (map[string]string, error) {
+	tags := make(map[string]string)
+	input := &kms.ListResourceTagsInput{KeyId: aws.String(key)}
 	for {
-		list, err := svc.ListAliases(&kms.ListAliasesInput{
-			Marker: next,
-			Limit:  aws.Int64(int64(batchSize)),
-		})
+		output, err := svc.ListResourceTagsWithContext(ctx, input)
 		if err != nil {
-			return nil, errors.WithStackTrace(err)
+			return nil, err
+		}
+		for _, tag := range output.Tags {
+			tags[aws.StringValue(tag.TagKey)] = aws.StringValue(tag.TagValue)
 		}
+		if !aws.BoolValue(output.Truncated) {
+			return tags, nil
+		}
+		input.Marker = output.NextMarker
+	}
+}
+
+func listKeyAliases(svc kmsiface.KMSAPI, batchSize int) (map[string][]string, error) {
+	// map key - KMS key id, value list of aliases
+	aliases := map[string][]string{}
 
+	err := svc.ListAliasesPages(&kms.ListAliasesInput{
+		Limit: aws.Int64(int64(batchSize)),
+	}, func(page *kms.ListAliasesOutput, lastPage bool) bool {
 		// collect key aliases to map
-		for _, alias := range list.Aliases {
+		for _, alias := range page.Aliases {
 			key := alias.TargetKeyId
 			if key == nil {
 				continue
@@ -168,43 +204,36 @@ func listKeyAliases(svc *kms.KMS, batchSize int) (map[string][]string, error) {
 			list = append(list, *alias.AliasName)
 			aliases[*key] = list
 		}
-
-		if list.NextMarker == nil || len(*list.NextMarker) == 0 {
-			break
-		}
-		next = list.NextMarker
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 	return aliases, nil
 }
 
-func listKeys(svc *kms.KMS, batchSize int) ([]string, error) {
+func listKeys(svc kmsiface.KMSAPI, batchSize int) ([]string, error) {
 	var keyIds []string
-	var next *string
-
-	for {
-		list, err := svc.ListKeys(&kms.ListKeysInput{
-			Marker: next,
-			Limit:  aws.Int64(int64(batchSize)),
-		})
-
-		if err != nil {
-			return nil, errors.WithStackTrace(err)
-		}
 
-		for _, key := range list.Keys {
+	err := svc.ListKeysPages(&kms.ListKeysInput{
+		Limit: aws.Int64(int64(batchSize)),
+	}, func(page *kms.ListKeysOutput, lastPage bool) bool {
+		for _, key := range page.Keys {
 			keyIds = append(keyIds, *key.KeyId)
 		}
-
-		if list.NextMarker == nil || len(*list.NextMarker) == 0 {
-			break
-		}
-		next = list.NextMarker
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	return keyIds, nil
 }
 
-func nukeAllCustomerManagedKmsKeys(session *session.Session, keyIds []*string, keyAliases map[string][]string) error {
+// nukeAllCustomerManagedKmsKeys schedules the deletion of the given keys at the end of the given pending window, and
+// deletes their aliases. Unless runID is empty, the keys are first tagged with the run ID and their aliases, so that
+// 'cloud-nuke aws restore' can cancel their deletion.
+func nukeAllCustomerManagedKmsKeys(ctx context.Context, session *session.Session, keyIds []*string, keyAliases map[string][]string, pendingWindowDays int, runID string) error {
 	region := aws.StringValue(session.Config.Region)
 	if len(keyIds) == 0 {
 		logging.Logger.Debugf("No Customer Keys to nuke in region %s", region)
@@ -214,20 +243,20 @@ func nukeAllCustomerManagedKmsKeys(session *session.Session, keyIds []*string, k
 	// usage of go routines for parallel keys removal
 	// https://docs.aws.amazon.com/sdk-for-go/api/service/kms/#KMS.ScheduleKeyDeletion
 	logging.Logger.Debugf("Deleting Keys secrets in region %s", region)
-	svc := kms.New(session)
+	svc := newKMSClient(session)
 	wg := new(sync.WaitGroup)
 	wg.Add(len(keyIds))
 	errChans := make([]chan error, len(keyIds))
 	for i, secretID := range keyIds {
 		errChans[i] = make(chan error, 1)
-		go requestKeyDeletion(wg, errChans[i], svc, secretID)
+		go requestKeyDeletion(ctx, wg, errChans[i], svc, secretID, keyAliases[aws.StringValue(secretID)], pendingWindowDays, runID)
 	}
 	wg.Wait()
 
 	wgAlias := new(sync.WaitGroup)
 	wgAlias.Add(len(keyAliases))
 	for _, aliases := range keyAliases {
-		go deleteAliases(wgAlias, svc, aliases)
+		go deleteAliases(ctx, wgAlias, svc, aliases)
 	}
 	wgAlias.Wait()
 
@@ -247,12 +276,12 @@ func nukeAllCustomerManagedKmsKeys(session *session.Session, keyIds []*string, k
 	return errors.WithStackTrace(allErrs.ErrorOrNil())
 }
 
-func deleteAliases(wg *sync.WaitGroup, svc *kms.KMS, aliases []string) {
+func deleteAliases(ctx context.Context, wg *sync.WaitGroup, svc kmsiface.KMSAPI, aliases []string) {
 	defer wg.Done()
 
 	for _, aliasName := range aliases {
 		input := &kms.DeleteAliasInput{AliasName: &aliasName}
-		_, err := svc.DeleteAlias(input)
+		_, err := svc.DeleteAliasWithContext(ctx, input)
 
 		if err != nil {
 			logging.Logger.Errorf("[Failed] Failed deleting alias: %s", aliasName)
@@ -262,18 +291,167 @@ func deleteAliases(wg *sync.WaitGroup, svc *kms.KMS, aliases []string) {
 	}
 }
 
-func requestKeyDeletion(wg *sync.WaitGroup, errChan chan error, svc *kms.KMS, key *string) {
+func requestKeyDeletion(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc kmsiface.KMSAPI, key *string, aliases []string, pendingWindowDays int, runID string) {
 	defer wg.Done()
-	input := &kms.ScheduleKeyDeletionInput{KeyId: key, PendingWindowInDays: aws.Int64(int64(kmsRemovalWindow))}
-	_, err := svc.ScheduleKeyDeletion(input)
+	var err error
+	if runID != "" {
+		_, err = svc.TagResourceWithContext(ctx, &kms.TagResourceInput{
+			KeyId: key,
+			Tags: []*kms.Tag{
+				{TagKey: aws.String(RunIDTagKey), TagValue: aws.String(runID)},
+				{TagKey: aws.String(DeletedAliasesTagKey), TagValue: aws.String(deletedAliasesTagValue(aliases))},
+			},
+		})
+	}
+	// A key that could not be tagged could not be restored, so it is left alone
+	if err == nil {
+		input := &kms.ScheduleKeyDeletionInput{KeyId: key, PendingWindowInDays: aws.Int64(int64(pendingWindowDays))}
+		_, err = svc.ScheduleKeyDeletionWithContext(ctx, input)
+	}
 
 	// Record status of this resource
 	e := report.Entry{
 		Identifier:   aws.StringValue(key),
 		ResourceType: "Key Management Service (KMS) Key",
 		Error:        err,
+		Metadata:     map[string]string{metadataRecoveryWindowDays: strconv.Itoa(pendingWindowDays)},
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 }
+
+// quarantineCustomerManagedKmsKeys tags the given KMS keys with the quarantine tag and disables them, so that their
+// owners can enable them again if they still need them
+func quarantineCustomerManagedKmsKeys(ctx context.Context, session *session.Session, keyIds []*string) error {
+	svc := newKMSClient(session)
+
+	for _, keyId := range keyIds {
+		tags, err := getKmsKeyTagsWithContext(ctx, svc, aws.StringValue(keyId))
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		err = quarantineCustomerManagedKmsKey(ctx, svc, keyId, tags)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined KMS Customer Key: %s", aws.StringValue(keyId))
+		}
+		recordQuarantine(ctx, KmsCustomerKeys{}.ResourceName(), aws.StringValue(keyId), nil, err)
+	}
+	return nil
+}
+
+func quarantineCustomerManagedKmsKey(ctx context.Context, svc kmsiface.KMSAPI, keyId *string, tags map[string]string) error {
+	_, err := svc.TagResourceWithContext(ctx, &kms.TagResourceInput{
+		KeyId: keyId,
+		Tags:  []*kms.Tag{{TagKey: aws.String(QuarantineTagKey), TagValue: aws.String(quarantineTagValue(tags))}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.DisableKeyWithContext(ctx, &kms.DisableKeyInput{KeyId: keyId})
+	return errors.WithStackTrace(err)
+}
+
+// undoCustomerManagedKmsKeyQuarantine enables a quarantined KMS key again, and removes its quarantine tag
+func undoCustomerManagedKmsKeyQuarantine(session *session.Session, resource RunReportResource) error {
+	svc := newKMSClient(session)
+	_, err := svc.EnableKey(&kms.EnableKeyInput{KeyId: aws.String(resource.Identifier)})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UntagResource(&kms.UntagResourceInput{
+		KeyId:   aws.String(resource.Identifier),
+		TagKeys: aws.StringSlice([]string{QuarantineTagKey}),
+	})
+	return errors.WithStackTrace(err)
+}
+
+// undoCustomerManagedKmsKeyDeletion cancels the deletion of a KMS key that a run scheduled for deletion, and recreates
+// its aliases
+func undoCustomerManagedKmsKeyDeletion(session *session.Session, resource RunReportResource) error {
+	svc := newKMSClient(session)
+	tags, err := getKmsKeyTags(svc, resource.Identifier)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return restoreCustomerManagedKmsKey(svc, resource.Identifier, tags)
+}
+
+// maxKmsTagValueLength is the maximum length of the value of a KMS key tag
+const maxKmsTagValueLength = 256
+
+// deletedAliasesTagValue returns the value of the DeletedAliasesTagKey tag for a key with the given aliases. The
+// aliases that do not fit in a tag value are left out, so they are not recreated when the key is restored.
+func deletedAliasesTagValue(aliases []string) string {
+	value := ""
+	for _, alias := range aliases {
+		candidate := strings.TrimSpace(value + " " + alias)
+		if len(candidate) > maxKmsTagValueLength {
+			logging.Logger.Debugf("Alias %s does not fit in the %s tag, it will not be recreated if its key is restored", alias, DeletedAliasesTagKey)
+			continue
+		}
+		value = candidate
+	}
+	return value
+}
+
+// restoreCustomerManagedKmsKeys cancels the deletion of the KMS keys that the run with the given ID scheduled for
+// deletion, and returns the outcome for each of them
+func restoreCustomerManagedKmsKeys(session *session.Session, runID string) ([]ui.ResourceRow, error) {
+	svc := newKMSClient(session)
+	region := aws.StringValue(session.Config.Region)
+
+	keys, err := listKeys(svc, KmsCustomerKeys{}.MaxBatchSize())
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var rows []ui.ResourceRow
+	for _, key := range keys {
+		tags, err := getKmsKeyTags(svc, key)
+		if err != nil {
+			// The tags of keys managed by AWS or shared by other accounts may not be readable
+			logging.Logger.Debugf("Can't read the tags of KMS key %s: %s", key, err)
+			continue
+		}
+		if tags[RunIDTagKey] != runID {
+			continue
+		}
+
+		err = restoreCustomerManagedKmsKey(svc, key, tags)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Restored KMS Customer Key: %s", key)
+		}
+		rows = append(rows, restoredResourceRow(KmsCustomerKeys{}.ResourceName(), key, region, err))
+	}
+	return rows, nil
+}
+
+// restoreCustomerManagedKmsKey cancels the deletion of the given key, enables it and recreates its aliases
+func restoreCustomerManagedKmsKey(svc kmsiface.KMSAPI, key string, tags map[string]string) error {
+	_, err := svc.CancelKeyDeletion(&kms.CancelKeyDeletionInput{KeyId: aws.String(key)})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	// Cancelling the deletion leaves the key disabled
+	_, err = svc.EnableKey(&kms.EnableKeyInput{KeyId: aws.String(key)})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	for _, alias := range strings.Fields(tags[DeletedAliasesTagKey]) {
+		_, err = svc.CreateAlias(&kms.CreateAliasInput{AliasName: aws.String(alias), TargetKeyId: aws.String(key)})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	_, err = svc.UntagResource(&kms.UntagResourceInput{
+		KeyId:   aws.String(key),
+		TagKeys: aws.StringSlice([]string{RunIDTagKey, DeletedAliasesTagKey}),
+	})
+	return errors.WithStackTrace(err)
+}
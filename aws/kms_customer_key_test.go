@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"testing"
@@ -90,7 +91,7 @@ func TestRemoveKmsUserKeys(t *testing.T) {
 	createdKeyId := createKmsCustomerManagedKey(t, session)
 	_ = createKmsCustomerManagedKeyAlias(t, session, createdKeyId, keyAlias)
 
-	err = nukeAllCustomerManagedKmsKeys(session, []*string{&createdKeyId}, map[string][]string{"keyid": {keyAlias}})
+	err = nukeAllCustomerManagedKmsKeys(context.Background(), session, []*string{&createdKeyId}, map[string][]string{"keyid": {keyAlias}}, MinRecoveryWindowDays, "")
 	require.NoError(t, err)
 
 	// test if key is not included for removal second time, after being marked for deletion
@@ -119,7 +120,7 @@ func TestRemoveKmsUserKeysAllowUnaliased(t *testing.T) {
 
 	createdKeyId := createKmsCustomerManagedKey(t, session)
 
-	err = nukeAllCustomerManagedKmsKeys(session, []*string{&createdKeyId}, map[string][]string{})
+	err = nukeAllCustomerManagedKmsKeys(context.Background(), session, []*string{&createdKeyId}, map[string][]string{}, MinRecoveryWindowDays, "")
 	require.NoError(t, err)
 
 	// test if key is not included for removal second time, after being marked for deletion
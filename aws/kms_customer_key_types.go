@@ -1,15 +1,13 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
-// https://docs.aws.amazon.com/sdk-for-go/api/service/kms/#ScheduleKeyDeletionInput
-// must be between 7 and 30, inclusive
-const kmsRemovalWindow = 7
-
 type KmsCustomerKeys struct {
 	KeyIds     []string
 	KeyAliases map[string][]string
@@ -31,10 +29,42 @@ func (r KmsCustomerKeys) MaxBatchSize() int {
 }
 
 // Nuke - remove all customer managed keys
-func (c KmsCustomerKeys) Nuke(session *session.Session, keyIds []string) error {
-	if err := nukeAllCustomerManagedKmsKeys(session, awsgo.StringSlice(keyIds), c.KeyAliases); err != nil {
+func (c KmsCustomerKeys) Nuke(ctx context.Context, session *session.Session, keyIds []string) error {
+	return c.NukeRecoverably(ctx, session, keyIds, DefaultRecoveryWindows().Days(c.ResourceName()), "")
+}
+
+// NukeRecoverably - schedule the deletion of the customer managed keys at the end of the given pending window, during
+// which it can be cancelled
+func (c KmsCustomerKeys) NukeRecoverably(ctx context.Context, session *session.Session, keyIds []string, pendingWindowDays int, runID string) error {
+	if err := nukeAllCustomerManagedKmsKeys(ctx, session, awsgo.StringSlice(keyIds), c.KeyAliases, pendingWindowDays, runID); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Quarantine - disable the customer managed keys, so that their owners can enable them again
+func (c KmsCustomerKeys) Quarantine(ctx context.Context, session *session.Session, keyIds []string) error {
+	if err := quarantineCustomerManagedKmsKeys(ctx, session, awsgo.StringSlice(keyIds)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                   KmsCustomerKeys{}.ResourceName(),
+		Description:            "KMS Customer Keys",
+		ConfigKey:              "KMSCustomerKeys",
+		SupportsQuarantine:     true,
+		SupportsRecoveryWindow: true,
+		UndoQuarantine:         undoCustomerManagedKmsKeyQuarantine,
+		UndoDeletion:           undoCustomerManagedKmsKeyDeletion,
+		DependsOn:              []string{"ebs", "rds", "rds-cluster", "secretsmanager"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			keys, aliases, err := getAllKmsUserKeys(session, KmsCustomerKeys{}.MaxBatchSize(), params.ExcludeAfter, params.Config, params.AllowDeleteUnaliasedKeys)
+			return KmsCustomerKeys{KeyIds: awsgo.StringValueSlice(keys), KeyAliases: aliases}, err
+		},
+	})
+}
@@ -1,14 +1,17 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/lambda/lambdaiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -16,38 +19,43 @@ import (
 )
 
 func getAllLambdaFunctions(session *session.Session, excludeAfter time.Time, configObj config.Config, batchSize int) ([]*string, error) {
-	svc := lambda.New(session)
+	svc := newLambdaClient(session)
 
 	var result []*lambda.FunctionConfiguration
 
-	var next *string = nil
-	for {
-		list, err := svc.ListFunctions(&lambda.ListFunctionsInput{
-			Marker:   next,
-			MaxItems: awsgo.Int64(int64(batchSize)),
-		})
-		if err != nil {
-			return nil, errors.WithStackTrace(err)
-		}
-		result = append(result, list.Functions...)
-		if list.NextMarker == nil || len(*list.NextMarker) == 0 {
-			break
-		}
-		next = list.NextMarker
+	err := svc.ListFunctionsPages(&lambda.ListFunctionsInput{
+		MaxItems: awsgo.Int64(int64(batchSize)),
+	}, func(page *lambda.ListFunctionsOutput, lastPage bool) bool {
+		result = append(result, page.Functions...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	var names []*string
 
-	for _, lambda := range result {
-		if shouldIncludeLambdaFunction(lambda, excludeAfter, configObj) {
-			names = append(names, lambda.FunctionName)
+	for _, lambdaFn := range result {
+		// Tags are always looked up, so that functions carrying the exclusion tag are never nuked
+		output, err := svc.ListTags(&lambda.ListTagsInput{Resource: lambdaFn.FunctionArn})
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		tags := awsgo.StringValueMap(output.Tags)
+		region := awsgo.StringValue(session.Config.Region)
+		if excludedByTag(LambdaFunctions{}.ResourceName(), region, awsgo.StringValue(lambdaFn.FunctionName), tags) ||
+			excludedByQuarantine(LambdaFunctions{}.ResourceName(), region, awsgo.StringValue(lambdaFn.FunctionName), tags, excludeAfter) {
+			continue
+		}
+		if shouldIncludeLambdaFunction(lambdaFn, tags, excludeAfter, configObj) {
+			names = append(names, lambdaFn.FunctionName)
 		}
 	}
 
 	return names, nil
 }
 
-func shouldIncludeLambdaFunction(lambdaFn *lambda.FunctionConfiguration, excludeAfter time.Time, configObj config.Config) bool {
+func shouldIncludeLambdaFunction(lambdaFn *lambda.FunctionConfiguration, tags map[string]string, excludeAfter time.Time, configObj config.Config) bool {
 	if lambdaFn == nil {
 		return false
 	}
@@ -66,15 +74,15 @@ func shouldIncludeLambdaFunction(lambdaFn *lambda.FunctionConfiguration, exclude
 		return false
 	}
 
-	return config.ShouldInclude(
-		fnName,
-		configObj.LambdaFunction.IncludeRule.NamesRegExp,
-		configObj.LambdaFunction.ExcludeRule.NamesRegExp,
-	)
+	return configObj.LambdaFunction.ShouldInclude(config.ResourceValue{
+		Name: fnName,
+		Tags: tags,
+		Time: lastModifiedDateTime,
+	})
 }
 
-func nukeAllLambdaFunctions(session *session.Session, names []*string) error {
-	svc := lambda.New(session)
+func nukeAllLambdaFunctions(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newLambdaClient(session)
 
 	if len(names) == 0 {
 		logging.Logger.Debugf("No Lambda Functions to nuke in region %s", *session.Config.Region)
@@ -89,7 +97,7 @@ func nukeAllLambdaFunctions(session *session.Session, names []*string) error {
 			FunctionName: name,
 		}
 
-		_, err := svc.DeleteFunction(params)
+		_, err := svc.DeleteFunctionWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -97,7 +105,7 @@ func nukeAllLambdaFunctions(session *session.Session, names []*string) error {
 			ResourceType: "Lambda function",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Errorf("[Failed] %s: %s", *name, err)
@@ -115,3 +123,75 @@ func nukeAllLambdaFunctions(session *session.Session, names []*string) error {
 	logging.Logger.Debugf("[OK] %d Lambda Function(s) deleted in %s", len(deletedNames), *session.Config.Region)
 	return nil
 }
+
+// quarantineLambdaFunctions tags the given Lambda Functions with the quarantine tag and sets their reserved concurrency
+// to zero, so that they can no longer be invoked until their owners remove the concurrency limit
+func quarantineLambdaFunctions(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newLambdaClient(session)
+
+	for _, name := range names {
+		output, err := svc.GetFunctionWithContext(ctx, &lambda.GetFunctionInput{FunctionName: name})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		metadata := map[string]string{metadataArn: awsgo.StringValue(output.Configuration.FunctionArn)}
+		if output.Concurrency != nil && output.Concurrency.ReservedConcurrentExecutions != nil {
+			metadata[metadataReservedConcurrency] = strconv.FormatInt(*output.Concurrency.ReservedConcurrentExecutions, 10)
+		}
+
+		err = quarantineLambdaFunction(ctx, svc, name, output.Configuration.FunctionArn, awsgo.StringValueMap(output.Tags))
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined Lambda Function: %s", awsgo.StringValue(name))
+		}
+		recordQuarantine(ctx, LambdaFunctions{}.ResourceName(), awsgo.StringValue(name), metadata, err)
+	}
+	return nil
+}
+
+func quarantineLambdaFunction(ctx context.Context, svc lambdaiface.LambdaAPI, name *string, arn *string, tags map[string]string) error {
+	_, err := svc.TagResourceWithContext(ctx, &lambda.TagResourceInput{
+		Resource: arn,
+		Tags:     map[string]*string{QuarantineTagKey: awsgo.String(quarantineTagValue(tags))},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.PutFunctionConcurrencyWithContext(ctx, &lambda.PutFunctionConcurrencyInput{
+		FunctionName:                 name,
+		ReservedConcurrentExecutions: awsgo.Int64(0),
+	})
+	return errors.WithStackTrace(err)
+}
+
+// undoLambdaFunctionQuarantine gives a quarantined Lambda function its reserved concurrency back, or removes it if it
+// had none, and removes its quarantine tag
+func undoLambdaFunctionQuarantine(session *session.Session, resource RunReportResource) error {
+	svc := newLambdaClient(session)
+	name := awsgo.String(resource.Identifier)
+
+	var err error
+	if _, ok := resource.Metadata[metadataReservedConcurrency]; ok {
+		var reservedConcurrency int64
+		reservedConcurrency, err = metadataInt64(resource, metadataReservedConcurrency)
+		if err != nil {
+			return err
+		}
+		_, err = svc.PutFunctionConcurrency(&lambda.PutFunctionConcurrencyInput{
+			FunctionName:                 name,
+			ReservedConcurrentExecutions: awsgo.Int64(reservedConcurrency),
+		})
+	} else {
+		_, err = svc.DeleteFunctionConcurrency(&lambda.DeleteFunctionConcurrencyInput{FunctionName: name})
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UntagResource(&lambda.UntagResourceInput{
+		Resource: awsgo.String(resource.Metadata[metadataArn]),
+		TagKeys:  []*string{awsgo.String(QuarantineTagKey)},
+	})
+	return errors.WithStackTrace(err)
+}
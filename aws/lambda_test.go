@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"archive/zip"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"io"
@@ -147,7 +148,7 @@ func TestLambdaFunctionConfigFile(t *testing.T) {
 	excludedLambdaFunctionName := "cloud-nuke-test-" + util.UniqueID()
 	createTestLambdaFunction(t, session, excludedLambdaFunctionName)
 
-	defer nukeAllLambdaFunctions(session, []*string{&includedLambdaFunctionName, &excludedLambdaFunctionName})
+	defer nukeAllLambdaFunctions(context.Background(), session, []*string{&includedLambdaFunctionName, &excludedLambdaFunctionName})
 
 	excludeAfter := time.Now().Add(1 * time.Hour)
 	lambdaFunctions, err := getAllLambdaFunctions(session, excludeAfter, config.Config{
@@ -185,7 +186,7 @@ func TestNukeLambdaFunction(t *testing.T) {
 	createTestLambdaFunction(t, session, lambdaFunctionName2)
 
 	defer func() {
-		nukeAllLambdaFunctions(session, []*string{&lambdaFunctionName, &lambdaFunctionName2})
+		nukeAllLambdaFunctions(context.Background(), session, []*string{&lambdaFunctionName, &lambdaFunctionName2})
 
 		lambdaFunctionNames, _ := getAllLambdaFunctions(session, excludeAfter, config.Config{}, 1)
 
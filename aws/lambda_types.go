@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -25,8 +27,8 @@ func (lambda LambdaFunctions) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (lambda LambdaFunctions) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllLambdaFunctions(session, awsgo.StringSlice(identifiers)); err != nil {
+func (lambda LambdaFunctions) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllLambdaFunctions(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -40,3 +42,28 @@ type LambdaDeleteError struct {
 func (e LambdaDeleteError) Error() string {
 	return "Lambda Function:" + e.name + "was not deleted"
 }
+
+// Quarantine - set the reserved concurrency of the lambda functions to zero, so that they can no longer be invoked
+func (lambda LambdaFunctions) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineLambdaFunctions(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:               LambdaFunctions{}.ResourceName(),
+		Description:        "Lambda Functions",
+		ConfigKey:          "LambdaFunction",
+		SupportsTags:       true,
+		SupportsRuleAge:    true,
+		SupportsQuarantine: true,
+		UndoQuarantine:     undoLambdaFunctionQuarantine,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllLambdaFunctions(session, params.ExcludeAfter, params.Config, LambdaFunctions{}.MaxBatchSize())
+			return LambdaFunctions{LambdaFunctionNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -17,17 +18,18 @@ import (
 
 // Returns a formatted string of Launch config Names
 func getAllLaunchConfigurations(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := autoscaling.New(session)
-	result, err := svc.DescribeLaunchConfigurations(&autoscaling.DescribeLaunchConfigurationsInput{})
-	if err != nil {
-		return nil, errors.WithStackTrace(err)
-	}
-
+	svc := newAutoScalingClient(session)
 	var configNames []*string
-	for _, config := range result.LaunchConfigurations {
-		if shouldIncludeLaunchConfiguration(config, excludeAfter, configObj) {
-			configNames = append(configNames, config.LaunchConfigurationName)
+	err := svc.DescribeLaunchConfigurationsPages(&autoscaling.DescribeLaunchConfigurationsInput{}, func(page *autoscaling.DescribeLaunchConfigurationsOutput, lastPage bool) bool {
+		for _, config := range page.LaunchConfigurations {
+			if shouldIncludeLaunchConfiguration(config, excludeAfter, configObj) {
+				configNames = append(configNames, config.LaunchConfigurationName)
+			}
 		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	return configNames, nil
@@ -42,16 +44,12 @@ func shouldIncludeLaunchConfiguration(lc *autoscaling.LaunchConfiguration, exclu
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(lc.LaunchConfigurationName),
-		configObj.LaunchConfiguration.IncludeRule.NamesRegExp,
-		configObj.LaunchConfiguration.ExcludeRule.NamesRegExp,
-	)
+	return configObj.LaunchConfiguration.ShouldInclude(config.ResourceValue{Name: awsgo.StringValue(lc.LaunchConfigurationName)})
 }
 
 // Deletes all Launch configurations
-func nukeAllLaunchConfigurations(session *session.Session, configNames []*string) error {
-	svc := autoscaling.New(session)
+func nukeAllLaunchConfigurations(ctx context.Context, session *session.Session, configNames []*string) error {
+	svc := newAutoScalingClient(session)
 
 	if len(configNames) == 0 {
 		logging.Logger.Debugf("No Launch Configurations to nuke in region %s", *session.Config.Region)
@@ -66,7 +64,7 @@ func nukeAllLaunchConfigurations(session *session.Session, configNames []*string
 			LaunchConfigurationName: configName,
 		}
 
-		_, err := svc.DeleteLaunchConfiguration(params)
+		_, err := svc.DeleteLaunchConfigurationWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -74,7 +72,7 @@ func nukeAllLaunchConfigurations(session *session.Session, configNames []*string
 			ResourceType: "Launch configuration",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Errorf("[Failed] %s", err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -56,8 +57,8 @@ func TestListLaunchConfigurations(t *testing.T) {
 	createTestLaunchConfiguration(t, session, uniqueTestID)
 
 	// clean up after this test
-	defer nukeAllLaunchConfigurations(session, []*string{&uniqueTestID})
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllLaunchConfigurations(context.Background(), session, []*string{&uniqueTestID})
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
 	configNames, err := getAllLaunchConfigurations(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
@@ -96,7 +97,7 @@ func TestNukeLaunchConfigurations(t *testing.T) {
 	createTestLaunchConfiguration(t, session, uniqueTestID)
 
 	// clean up ec2 instance created by the above call
-	defer nukeAllEc2Instances(session, findEC2InstancesByNameTag(t, session, uniqueTestID))
+	defer nukeAllEc2Instances(context.Background(), session, findEC2InstancesByNameTag(t, session, uniqueTestID))
 
 	_, err = svc.DescribeLaunchConfigurations(&autoscaling.DescribeLaunchConfigurationsInput{
 		LaunchConfigurationNames: []*string{&uniqueTestID},
@@ -106,7 +107,7 @@ func TestNukeLaunchConfigurations(t *testing.T) {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	if err := nukeAllLaunchConfigurations(session, []*string{&uniqueTestID}); err != nil {
+	if err := nukeAllLaunchConfigurations(context.Background(), session, []*string{&uniqueTestID}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
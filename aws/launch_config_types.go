@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,23 @@ func (config LaunchConfigs) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (config LaunchConfigs) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllLaunchConfigurations(session, awsgo.StringSlice(identifiers)); err != nil {
+func (config LaunchConfigs) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllLaunchConfigurations(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        LaunchConfigs{}.ResourceName(),
+		Description: "Launch Configurations",
+		ConfigKey:   "LaunchConfiguration",
+		DependsOn:   []string{"asg"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllLaunchConfigurations(session, params.Region, params.ExcludeAfter, params.Config)
+			return LaunchConfigs{LaunchConfigurationNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
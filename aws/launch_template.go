@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -17,17 +18,21 @@ import (
 
 // Returns a formatted string of Launch Template Names
 func getAllLaunchTemplates(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
-	result, err := svc.DescribeLaunchTemplates(&ec2.DescribeLaunchTemplatesInput{})
-	if err != nil {
-		return nil, errors.WithStackTrace(err)
-	}
-
+	svc := newEC2Client(session)
 	var templateNames []*string
-	for _, template := range result.LaunchTemplates {
-		if shouldIncludeLaunchTemplate(template, excludeAfter, configObj) {
-			templateNames = append(templateNames, template.LaunchTemplateName)
+	err := svc.DescribeLaunchTemplatesPages(&ec2.DescribeLaunchTemplatesInput{}, func(page *ec2.DescribeLaunchTemplatesOutput, lastPage bool) bool {
+		for _, template := range page.LaunchTemplates {
+			if excludedByTag(LaunchTemplates{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(template.LaunchTemplateName), ec2TagsToMap(template.Tags)) {
+				continue
+			}
+			if shouldIncludeLaunchTemplate(template, excludeAfter, configObj) {
+				templateNames = append(templateNames, template.LaunchTemplateName)
+			}
 		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
 	}
 
 	return templateNames, nil
@@ -42,16 +47,16 @@ func shouldIncludeLaunchTemplate(lt *ec2.LaunchTemplate, excludeAfter time.Time,
 		return false
 	}
 
-	return config.ShouldInclude(
-		awsgo.StringValue(lt.LaunchTemplateName),
-		configObj.LaunchTemplate.IncludeRule.NamesRegExp,
-		configObj.LaunchTemplate.ExcludeRule.NamesRegExp,
-	)
+	return configObj.LaunchTemplate.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(lt.LaunchTemplateName),
+		Tags: ec2TagsToMap(lt.Tags),
+		Time: awsgo.TimeValue(lt.CreateTime),
+	})
 }
 
 // Deletes all Launch Templates
-func nukeAllLaunchTemplates(session *session.Session, templateNames []*string) error {
-	svc := ec2.New(session)
+func nukeAllLaunchTemplates(ctx context.Context, session *session.Session, templateNames []*string) error {
+	svc := newEC2Client(session)
 
 	if len(templateNames) == 0 {
 		logging.Logger.Debugf("No Launch Templates to nuke in region %s", *session.Config.Region)
@@ -66,7 +71,7 @@ func nukeAllLaunchTemplates(session *session.Session, templateNames []*string) e
 			LaunchTemplateName: templateName,
 		}
 
-		_, err := svc.DeleteLaunchTemplate(params)
+		_, err := svc.DeleteLaunchTemplateWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -74,7 +79,7 @@ func nukeAllLaunchTemplates(session *session.Session, templateNames []*string) e
 			ResourceType: "Launch template",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Errorf("[Failed] %s", err)
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -50,7 +51,7 @@ func TestListLaunchTemplates(t *testing.T) {
 	createTestLaunchTemplate(t, session, uniqueTestID)
 
 	// clean up after this test
-	defer nukeAllLaunchTemplates(session, []*string{&uniqueTestID})
+	defer nukeAllLaunchTemplates(context.Background(), session, []*string{&uniqueTestID})
 
 	templateNames, err := getAllLaunchTemplates(session, time.Now().Add(1*time.Hour*-1), config.Config{})
 
@@ -88,7 +89,7 @@ func TestNukeLaunchTemplates(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	assert.NoError(t, nukeAllLaunchTemplates(session, []*string{&uniqueTestID}))
+	assert.NoError(t, nukeAllLaunchTemplates(context.Background(), session, []*string{&uniqueTestID}))
 
 	groupNames, err := getAllLaunchTemplates(session, time.Now().Add(1*time.Hour), config.Config{})
 	assert.NoError(t, err, "Unable to fetch list of Launch Templates")
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,25 @@ func (template LaunchTemplates) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (template LaunchTemplates) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllLaunchTemplates(session, awsgo.StringSlice(identifiers)); err != nil {
+func (template LaunchTemplates) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllLaunchTemplates(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            LaunchTemplates{}.ResourceName(),
+		Description:     "Launch Templates",
+		ConfigKey:       "LaunchTemplate",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		DependsOn:       []string{"asg"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllLaunchTemplates(session, params.ExcludeAfter, params.Config)
+			return LaunchTemplates{LaunchTemplateNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"sort"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+)
+
+// CheckResourceLimits returns an error if the given resources exceed the total or per resource type limits. It must be
+// called before nuking anything, as a safeguard against filters that target far more resources than intended.
+func CheckResourceLimits(account *AwsAccountResources, limits config.ResourceLimits) error {
+	if total := account.TotalResourceCount(); limits.MaxResources > 0 && total > limits.MaxResources {
+		return ResourceLimitExceededError{Count: total, Limit: limits.MaxResources}
+	}
+
+	counts := map[string]int{}
+	for _, regionResource := range account.Resources {
+		for _, resources := range regionResource.Resources {
+			counts[resources.ResourceName()] += len(resources.ResourceIdentifiers())
+		}
+	}
+
+	// Resource types are checked in order, so that the same error is returned on every run
+	resourceTypes := make([]string, 0, len(limits.PerResourceType))
+	for resourceType := range limits.PerResourceType {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	for _, resourceType := range resourceTypes {
+		limit := limits.PerResourceType[resourceType]
+		if limit > 0 && counts[resourceType] > limit {
+			return ResourceLimitExceededError{ResourceType: resourceType, Count: counts[resourceType], Limit: limit}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+)
+
+func TestCheckResourceLimits(t *testing.T) {
+	t.Parallel()
+
+	account := &AwsAccountResources{
+		Resources: map[string]AwsRegionResource{
+			"us-east-1": {Resources: []AwsResources{
+				EC2Instances{InstanceIds: []string{"i-1", "i-2"}},
+				DBInstances{InstanceNames: []string{"db-1"}},
+			}},
+			"eu-west-1": {Resources: []AwsResources{
+				DBInstances{InstanceNames: []string{"db-2"}},
+				DBClusters{InstanceNames: []string{"cluster-1"}},
+			}},
+		},
+	}
+
+	assert.NoError(t, CheckResourceLimits(account, config.ResourceLimits{}))
+	assert.NoError(t, CheckResourceLimits(account, config.ResourceLimits{MaxResources: 5, PerResourceType: map[string]int{"rds": 3, "ec2": 2}}))
+	assert.Equal(
+		t,
+		ResourceLimitExceededError{Count: 5, Limit: 4},
+		CheckResourceLimits(account, config.ResourceLimits{MaxResources: 4}),
+	)
+	// RDS instances and clusters are both counted as rds resources, across all regions
+	assert.Equal(
+		t,
+		ResourceLimitExceededError{ResourceType: "rds", Count: 3, Limit: 2},
+		CheckResourceLimits(account, config.ResourceLimits{PerResourceType: map[string]int{"rds": 2, "s3": 1}}),
+	)
+}
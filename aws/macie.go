@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"context"
 	goerror "errors"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 
@@ -15,11 +17,11 @@ import (
 )
 
 // getAllMacieMemberAccounts will find and return any Macie accounts that were created via accepting an invite from another AWS Account
-// Unfortunately, the Macie API doesn't provide the metadata information we'd need to implement the excludeAfter or configObj patterns, so we
-// currently can only accept a session
-func getAllMacieMemberAccounts(session *session.Session) ([]string, error) {
-	svc := macie2.New(session)
-	stssvc := sts.New(session)
+// Unfortunately, the Macie API doesn't provide the metadata information we'd need to implement the excludeAfter pattern, so
+// the member account can only be excluded by its account ID in the config file
+func getAllMacieMemberAccounts(session *session.Session, configObj config.Config) ([]string, error) {
+	svc := newMacie2Client(session)
+	stssvc := newSTSClient(session)
 
 	allMacieAccounts := []string{}
 	output, err := svc.GetAdministratorAccount(&macie2.GetAdministratorAccountInput{})
@@ -53,15 +55,17 @@ func getAllMacieMemberAccounts(session *session.Session) ([]string, error) {
 
 			currentAccountId := aws.StringValue(output.Account)
 
-			allMacieAccounts = append(allMacieAccounts, currentAccountId)
+			if configObj.MacieMember.ShouldInclude(config.ResourceValue{Name: currentAccountId}) {
+				allMacieAccounts = append(allMacieAccounts, currentAccountId)
+			}
 		}
 	}
 
 	return allMacieAccounts, nil
 }
 
-func nukeAllMacieMemberAccounts(session *session.Session, identifiers []string) error {
-	svc := macie2.New(session)
+func nukeAllMacieMemberAccounts(ctx context.Context, session *session.Session, identifiers []string) error {
+	svc := newMacie2Client(session)
 	region := aws.StringValue(session.Config.Region)
 
 	if len(identifiers) == 0 {
@@ -72,7 +76,7 @@ func nukeAllMacieMemberAccounts(session *session.Session, identifiers []string)
 	logging.Logger.Debugf("Deleting Macie account membership and disabling Macie in %s", region)
 
 	for _, accountId := range identifiers {
-		_, disassociateErr := svc.DisassociateFromAdministratorAccount(&macie2.DisassociateFromAdministratorAccountInput{})
+		_, disassociateErr := svc.DisassociateFromAdministratorAccountWithContext(ctx, &macie2.DisassociateFromAdministratorAccountInput{})
 
 		if disassociateErr != nil {
 			telemetry.TrackEvent(commonTelemetry.EventContext{
@@ -83,7 +87,7 @@ func nukeAllMacieMemberAccounts(session *session.Session, identifiers []string)
 			return errors.WithStackTrace(disassociateErr)
 		}
 
-		_, err := svc.DisableMacie(&macie2.DisableMacieInput{})
+		_, err := svc.DisableMacieWithContext(ctx, &macie2.DisableMacieInput{})
 
 		// Record status of this resource
 		e := report.Entry{
@@ -91,7 +95,7 @@ func nukeAllMacieMemberAccounts(session *session.Session, identifiers []string)
 			ResourceType: "Macie member account",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			telemetry.TrackEvent(commonTelemetry.EventContext{
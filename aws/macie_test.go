@@ -11,9 +11,9 @@ package aws
 //
 //	acceptTestInvite(t, session)
 //	// Clean up after test by deleting the macie account association
-//	defer nukeAllMacieMemberAccounts(session, []string{accountId})
+//	defer nukeAllMacieMemberAccounts(context.Background(), session, []string{accountId})
 //
-//	retrievedAccountIds, lookupErr := getAllMacieMemberAccounts(session)
+//	retrievedAccountIds, lookupErr := getAllMacieMemberAccounts(session, config.Config{})
 //	require.NoError(t, lookupErr)
 //
 //	assert.Contains(t, retrievedAccountIds, accountId)
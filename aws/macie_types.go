@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
@@ -21,9 +23,22 @@ func (r MacieMember) MaxBatchSize() int {
 	return 10
 }
 
-func (r MacieMember) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllMacieMemberAccounts(session, identifiers); err != nil {
+func (r MacieMember) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllMacieMemberAccounts(ctx, session, identifiers); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        MacieMember{}.ResourceName(),
+		Description: "Macie Member Accounts",
+		ConfigKey:   "MacieMember",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			// Unfortunately, the Macie API doesn't provide the metadata information we'd need to implement the excludeAfter pattern
+			accountIds, err := getAllMacieMemberAccounts(session, params.Config)
+			return MacieMember{AccountIds: accountIds}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -11,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 	"github.com/tnn-gruntwork-io/go-commons/retry"
 	multierror "github.com/hashicorp/go-multierror"
@@ -21,7 +23,7 @@ import (
 )
 
 func getAllNatGateways(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
 	allNatGateways := []*string{}
 	input := &ec2.DescribeNatGatewaysInput{}
@@ -29,6 +31,9 @@ func getAllNatGateways(session *session.Session, excludeAfter time.Time, configO
 		input,
 		func(page *ec2.DescribeNatGatewaysOutput, lastPage bool) bool {
 			for _, ngw := range page.NatGateways {
+				if excludedByTag(NatGateways{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(ngw.NatGatewayId), ec2TagsToMap(ngw.Tags)) {
+					continue
+				}
 				if shouldIncludeNatGateway(ngw, excludeAfter, configObj) {
 					allNatGateways = append(allNatGateways, ngw.NatGatewayId)
 				}
@@ -58,11 +63,12 @@ func shouldIncludeNatGateway(ngw *ec2.NatGateway, excludeAfter time.Time, config
 		return false
 	}
 
-	return config.ShouldInclude(
-		getNatGatewayName(ngw),
-		configObj.NatGateway.IncludeRule.NamesRegExp,
-		configObj.NatGateway.ExcludeRule.NamesRegExp,
-	)
+	return configObj.NatGateway.ShouldInclude(config.ResourceValue{
+		Name: getNatGatewayName(ngw),
+		Tags: ec2TagsToMap(ngw.Tags),
+		ID:   aws.StringValue(ngw.NatGatewayId),
+		Time: aws.TimeValue(ngw.CreateTime),
+	})
 }
 
 func getNatGatewayName(ngw *ec2.NatGateway) string {
@@ -74,10 +80,10 @@ func getNatGatewayName(ngw *ec2.NatGateway) string {
 	return ""
 }
 
-func nukeAllNatGateways(session *session.Session, identifiers []*string) error {
+func nukeAllNatGateways(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No Nat Gateways to nuke in region %s", region)
@@ -100,7 +106,7 @@ func nukeAllNatGateways(session *session.Session, identifiers []*string) error {
 	errChans := make([]chan error, len(identifiers))
 	for i, ngwID := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteNatGatewayAsync(wg, errChans[i], svc, ngwID)
+		go deleteNatGatewayAsync(ctx, wg, errChans[i], svc, ngwID)
 	}
 	wg.Wait()
 
@@ -123,13 +129,13 @@ func nukeAllNatGateways(session *session.Session, identifiers []*string) error {
 	}
 
 	// Now wait until the NAT gateways are deleted
-	err := retry.DoWithRetry(
-		logging.Logger,
+	err := retryWithContext(
+		ctx,
 		"Waiting for all NAT gateways to be deleted.",
 		// Wait a maximum of 5 minutes: 10 seconds in between, up to 30 times
 		30, 10*time.Second,
 		func() error {
-			areDeleted, err := areAllNatGatewaysDeleted(svc, identifiers)
+			areDeleted, err := areAllNatGatewaysDeleted(ctx, svc, identifiers)
 			if err != nil {
 				return errors.WithStackTrace(retry.FatalError{Underlying: err})
 			}
@@ -151,10 +157,10 @@ func nukeAllNatGateways(session *session.Session, identifiers []*string) error {
 // areAllNatGatewaysDeleted returns true if all the requested NAT gateways have been deleted. This is determined by
 // querying for the statuses of all the NAT gateways, and checking if AWS knows about them (if not, the NAT gateway was
 // deleted and rolled off AWS DB) or if the status was updated to deleted.
-func areAllNatGatewaysDeleted(svc *ec2.EC2, identifiers []*string) (bool, error) {
+func areAllNatGatewaysDeleted(ctx context.Context, svc ec2iface.EC2API, identifiers []*string) (bool, error) {
 	// NOTE: we don't need to do pagination here, because the pagination is handled by the caller to this function,
 	// based on NatGateways.MaxBatchSize.
-	resp, err := svc.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{NatGatewayIds: identifiers})
+	resp, err := svc.DescribeNatGatewaysWithContext(ctx, &ec2.DescribeNatGatewaysInput{NatGatewayIds: identifiers})
 	if err != nil {
 		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "NatGatewayNotFound" {
 			return true, nil
@@ -179,11 +185,11 @@ func areAllNatGatewaysDeleted(svc *ec2.EC2, identifiers []*string) (bool, error)
 
 // deleteNatGatewaysAsync deletes the provided NAT Gateway asynchronously in a goroutine, using wait groups for
 // concurrency control and a return channel for errors.
-func deleteNatGatewayAsync(wg *sync.WaitGroup, errChan chan error, svc *ec2.EC2, ngwID *string) {
+func deleteNatGatewayAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc ec2iface.EC2API, ngwID *string) {
 	defer wg.Done()
 
 	input := &ec2.DeleteNatGatewayInput{NatGatewayId: ngwID}
-	_, err := svc.DeleteNatGateway(input)
+	_, err := svc.DeleteNatGatewayWithContext(ctx, input)
 
 	// Record status of this resource
 	e := report.Entry{
@@ -191,7 +197,7 @@ func deleteNatGatewayAsync(wg *sync.WaitGroup, errChan chan error, svc *ec2.EC2,
 		ResourceType: "NAT Gateway",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 }
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
 	"testing"
@@ -64,7 +65,7 @@ func TestListNatGatewaysWithConfigFile(t *testing.T) {
 	excludedNatGatewayName := "cloud-nuke-test-" + util.UniqueID()
 	includedNatGatewayID := createNatGatewayWithName(t, svc, region, includedNatGatewayName)
 	excludedNatGatewayID := createNatGatewayWithName(t, svc, region, excludedNatGatewayName)
-	defer nukeAllNatGateways(session, []*string{includedNatGatewayID, excludedNatGatewayID})
+	defer nukeAllNatGateways(context.Background(), session, []*string{includedNatGatewayID, excludedNatGatewayID})
 
 	natGatewayIds, err := getAllNatGateways(session, time.Now().Add(1*time.Hour), config.Config{
 		NatGateway: config.ResourceType{
@@ -124,7 +125,7 @@ func TestNukeNatGatewayOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllNatGateways(session, identifiers),
+		nukeAllNatGateways(context.Background(), session, identifiers),
 	)
 
 	// Make sure the NAT gateway is deleted.
@@ -151,7 +152,7 @@ func TestNukeNatGatewayMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllNatGateways(session, natGateways),
+		nukeAllNatGateways(context.Background(), session, natGateways),
 	)
 
 	// Make sure the NAT Gateway is deleted.
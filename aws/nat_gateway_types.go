@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,24 @@ func (secret NatGateways) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (ngw NatGateways) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllNatGateways(session, awsgo.StringSlice(identifiers)); err != nil {
+func (ngw NatGateways) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllNatGateways(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            NatGateways{}.ResourceName(),
+		Description:     "NAT Gateways",
+		ConfigKey:       "NatGateway",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllNatGateways(session, params.ExcludeAfter, params.Config)
+			return NatGateways{NatGatewayIDs: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,75 @@
+package aws
+
+import (
+	"sort"
+)
+
+// sortByDependencies orders the given registrations so that every resource type comes after all the resource types it
+// depends on. Resource types that do not depend on each other are ordered by key, so the result is deterministic. An
+// error is returned if a registration depends on an unknown resource type, or if the dependencies form a cycle.
+func sortByDependencies(registrations []ResourceRegistration) ([]ResourceRegistration, error) {
+	byKey := map[string]ResourceRegistration{}
+	keys := []string{}
+	for _, registration := range registrations {
+		byKey[registration.key()] = registration
+		keys = append(keys, registration.key())
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		for _, dependency := range byKey[key].DependsOn {
+			if _, ok := byKey[dependency]; !ok {
+				return nil, UnknownResourceDependencyError{ResourceType: key, Dependency: dependency}
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	sorted := make([]ResourceRegistration, 0, len(registrations))
+
+	// path holds the keys currently being visited, so that we can report the full cycle when we find one
+	var path []string
+	var visit func(key string) error
+	visit = func(key string) error {
+		switch state[key] {
+		case visited:
+			return nil
+		case visiting:
+			cycleStart := 0
+			for idx, pathKey := range path {
+				if pathKey == key {
+					cycleStart = idx
+				}
+			}
+			return ResourceDependencyCycleError{Cycle: append(append([]string{}, path[cycleStart:]...), key)}
+		}
+
+		state[key] = visiting
+		path = append(path, key)
+
+		dependencies := append([]string{}, byKey[key].DependsOn...)
+		sort.Strings(dependencies)
+		for _, dependency := range dependencies {
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[key] = visited
+		sorted = append(sorted, byKey[key])
+		return nil
+	}
+
+	for _, key := range keys {
+		if err := visit(key); err != nil {
+			return nil, err
+		}
+	}
+	return sorted, nil
+}
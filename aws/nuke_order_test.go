@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistration(key string, dependsOn ...string) ResourceRegistration {
+	return ResourceRegistration{Name: key, DependsOn: dependsOn}
+}
+
+func registrationKeys(registrations []ResourceRegistration) []string {
+	var keys []string
+	for _, registration := range registrations {
+		keys = append(keys, registration.key())
+	}
+	return keys
+}
+
+func TestSortByDependencies(t *testing.T) {
+	t.Parallel()
+
+	sorted, err := sortByDependencies([]ResourceRegistration{
+		testRegistration("vpc", "ec2", "nat-gateway"),
+		testRegistration("ebs", "ec2"),
+		testRegistration("nat-gateway"),
+		testRegistration("ec2", "asg"),
+		testRegistration("asg"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"asg", "ec2", "ebs", "nat-gateway", "vpc"}, registrationKeys(sorted))
+}
+
+func TestSortByDependenciesIsDeterministic(t *testing.T) {
+	t.Parallel()
+
+	first, err := sortByDependencies([]ResourceRegistration{testRegistration("b"), testRegistration("c"), testRegistration("a")})
+	require.NoError(t, err)
+	second, err := sortByDependencies([]ResourceRegistration{testRegistration("c"), testRegistration("a"), testRegistration("b")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, registrationKeys(first))
+	assert.Equal(t, registrationKeys(first), registrationKeys(second))
+}
+
+func TestSortByDependenciesDetectsCycles(t *testing.T) {
+	t.Parallel()
+
+	_, err := sortByDependencies([]ResourceRegistration{
+		testRegistration("a", "b"),
+		testRegistration("b", "c"),
+		testRegistration("c", "a"),
+		testRegistration("d"),
+	})
+	require.Error(t, err)
+	cycleErr, isCycleErr := err.(ResourceDependencyCycleError)
+	require.True(t, isCycleErr)
+	assert.Equal(t, []string{"a", "b", "c", "a"}, cycleErr.Cycle)
+}
+
+func TestSortByDependenciesRejectsUnknownDependencies(t *testing.T) {
+	t.Parallel()
+
+	_, err := sortByDependencies([]ResourceRegistration{testRegistration("a", "missing")})
+	assert.Equal(t, UnknownResourceDependencyError{ResourceType: "a", Dependency: "missing"}, err)
+}
+
+func TestNukeDependencies(t *testing.T) {
+	t.Parallel()
+
+	// EBS volumes depend on EC2 instances, which depend on Auto Scaling Groups, so without instances the volumes wait for
+	// the groups. SQS queues depend on nothing.
+	dependencies := nukeDependencies([]AwsResources{ASGroups{}, SqsQueue{}, EBSVolumes{}, EC2Instances{}})
+	assert.Equal(t, [][]int{nil, nil, {3}, {0}}, dependencies)
+
+	dependencies = nukeDependencies([]AwsResources{ASGroups{}, SqsQueue{}, EBSVolumes{}})
+	assert.Equal(t, [][]int{nil, nil, {0}}, dependencies)
+
+	// RDS clusters share their name with RDS instances, and depend on them
+	dependencies = nukeDependencies([]AwsResources{DBInstances{}, selectedResources{AwsResources: DBClusters{}}})
+	assert.Equal(t, [][]int{nil, {0}}, dependencies)
+}
@@ -0,0 +1,170 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/throttling"
+	"github.com/tnn-gruntwork-io/go-commons/collections"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// NukeOptions configures how NukeAllResources deletes the resources it is given
+type NukeOptions struct {
+	// MaxPasses is the maximum number of deletion passes. After the first pass, every pass only re-attempts the
+	// resources that failed with a retryable error in the previous one. A value below 1 is treated as 1.
+	MaxPasses int
+	// PassBackoff is how long to wait before starting each retry pass. It is doubled after every pass, to give
+	// dependent resources (e.g. terminating instances) time to go away.
+	PassBackoff time.Duration
+	// Parallelism is the maximum number of regions nuked at the same time, and of resource types nuked at the same time
+	// within each region. A value below 1 is treated as 1.
+	Parallelism int
+	// Limits caps the number of resources that may be nuked. Nothing is nuked if they are exceeded.
+	Limits config.ResourceLimits
+	// Mode selects whether the resources are deleted or quarantined. The zero value deletes them.
+	Mode NukeMode
+	// PreserveData selects the resource types whose data is backed up before they are deleted
+	PreserveData DataPreservation
+	// RecoveryWindows sets how long the deleted resources of the types that support it can be restored. The default
+	// windows apply to the resource types it does not hold.
+	RecoveryWindows RecoveryWindows
+	// RunID identifies the run. It is put on the backups taken of preserved data and on the resources that can be
+	// restored. NukeAllResources generates one if it is empty.
+	RunID string
+	// OnOutcome, if set, is called with the recorded outcome of every resource of a batch once the batch is done, along
+	// with the region and the resource type (as returned by ResourceName) of the resource. Regions nuked in parallel
+	// call it from several goroutines at once.
+	OnOutcome func(region string, resourceType string, entry report.Entry)
+}
+
+// DefaultNukeOptions returns the options used by the CLI when no flags override them
+func DefaultNukeOptions() NukeOptions {
+	return NukeOptions{
+		MaxPasses:       3,
+		PassBackoff:     30 * time.Second,
+		Parallelism:     DefaultParallelism,
+		Mode:            NukeModeDelete,
+		RecoveryWindows: DefaultRecoveryWindows(),
+	}
+}
+
+// retryableErrorCodes are the AWS error codes that indicate a resource could not be deleted because another resource
+// still depends on it or still uses it. Such errors are likely to go away once the resources nuked earlier in the same
+// run are fully gone.
+var retryableErrorCodes = []string{
+	"DependencyViolation",
+	"ResourceInUse",
+	"ResourceInUseException",
+	"VolumeInUse",
+	"InvalidIPAddress.InUse",
+}
+
+// isRetryableNukeError returns true if the given deletion error is worth retrying in a later pass
+func isRetryableNukeError(err error) bool {
+	if err == nil {
+		return false
+	}
+	// Resources that were still throttled once the batch gave up on them are also worth another pass
+	if throttling.IsThrottlingError(err) {
+		return true
+	}
+	// Only the error code is looked at, as other errors, such as permission errors, may mention a resource in use in
+	// their message without going away in a later pass
+	awsErr, isAwsErr := errors.Unwrap(err).(awserr.Error)
+	return isAwsErr && collections.ListContainsElement(retryableErrorCodes, awsErr.Code())
+}
+
+// selectedResources narrows a set of resources down to a subset of their identifiers, e.g. the ones that should be
+// nuked again
+type selectedResources struct {
+	AwsResources
+	identifiers []string
+}
+
+func (r selectedResources) ResourceIdentifiers() []string {
+	return r.identifiers
+}
+
+// unwrapSelectedResources returns the resources wrapped by selectedResources, as the wrapper hides the optional methods
+// of the resource type, such as Quarantine
+func unwrapSelectedResources(resources AwsResources) AwsResources {
+	for {
+		selected, ok := resources.(selectedResources)
+		if !ok {
+			return resources
+		}
+		resources = selected.AwsResources
+	}
+}
+
+// collectRetryableFailures returns the subset of the given resources whose last recorded deletion attempt failed with
+// a retryable error, preserving the region and nuke order of the original resources
+func collectRetryableFailures(account *AwsAccountResources) *AwsAccountResources {
+	records := report.GetRecords()
+	failures := &AwsAccountResources{
+		Resources: make(map[string]AwsRegionResource),
+	}
+
+	for region, resourcesInRegion := range account.Resources {
+		failuresInRegion := AwsRegionResource{}
+		for _, resources := range resourcesInRegion.Resources {
+			var identifiers []string
+			for _, identifier := range resources.ResourceIdentifiers() {
+				key := report.RecordKey{Region: region, ResourceType: resources.ResourceName(), Identifier: identifier}
+				if entry, ok := records[key]; ok && isRetryableNukeError(entry.Error) {
+					identifiers = append(identifiers, identifier)
+				}
+			}
+			if len(identifiers) > 0 {
+				failuresInRegion.Resources = append(failuresInRegion.Resources, selectedResources{AwsResources: resources, identifiers: identifiers})
+			}
+		}
+		if len(failuresInRegion.Resources) > 0 {
+			failures.Resources[region] = failuresInRegion
+		}
+	}
+
+	return failures
+}
+
+// nukeInPasses calls nukePass with the given resources, then keeps calling it with the resources that failed with a
+// retryable error, until they are all gone, a pass makes no progress, the maximum number of passes is reached or the
+// context is done
+func nukeInPasses(ctx context.Context, account *AwsAccountResources, options NukeOptions, nukePass func(*AwsAccountResources) error) error {
+	pending := account
+	backoff := options.PassBackoff
+	for pass := 1; ; pass++ {
+		logging.Logger.Debugf("Starting nuke pass %d with %d resources", pass, pending.TotalResourceCount())
+		if err := nukePass(pending); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		failures := collectRetryableFailures(pending)
+		failureCount := failures.TotalResourceCount()
+		switch {
+		case failureCount == 0:
+			return nil
+		case pass >= options.MaxPasses:
+			logging.Logger.Debugf("%d resources still failed after %d passes", failureCount, pass)
+			return nil
+		case failureCount == pending.TotalResourceCount():
+			logging.Logger.Debugf("No progress was made in pass %d, not retrying the %d remaining resources", pass, failureCount)
+			return nil
+		}
+
+		logging.Logger.Infof("Retrying %d resources that failed due to dependencies in %s", failureCount, backoff)
+		if err := sleepWithContext(ctx, backoff); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		backoff *= 2
+		pending = failures
+	}
+}
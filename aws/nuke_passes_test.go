@@ -0,0 +1,325 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/throttling"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// fakeFlakyResources fails to delete each identifier as many times as configured in failures, then succeeds
+type fakeFlakyResources struct {
+	ids      []string
+	failures map[string]int
+	err      error
+	attempts map[string]int
+}
+
+func (r *fakeFlakyResources) ResourceName() string          { return "fake" }
+func (r *fakeFlakyResources) ResourceIdentifiers() []string { return r.ids }
+func (r *fakeFlakyResources) MaxBatchSize() int             { return 10 }
+func (r *fakeFlakyResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	for _, identifier := range identifiers {
+		r.attempts[identifier]++
+		var err error
+		if r.attempts[identifier] <= r.failures[identifier] {
+			err = r.err
+		}
+		record(ctx, report.Entry{Identifier: identifier, ResourceType: "fake", Error: err})
+	}
+	return nil
+}
+
+func newFakeFlakyAccount(resources *fakeFlakyResources) *AwsAccountResources {
+	resources.attempts = map[string]int{}
+	return &AwsAccountResources{
+		Resources: map[string]AwsRegionResource{
+			"us-east-1": {Resources: []AwsResources{resources}},
+		},
+	}
+}
+
+func nukeFakePass(account *AwsAccountResources) error {
+	for region, resourcesInRegion := range account.Resources {
+		for _, resources := range resourcesInRegion.Resources {
+			ctx := withRecordScope(context.Background(), region, resources.ResourceName())
+			if err := resources.Nuke(ctx, nil, resources.ResourceIdentifiers()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestIsRetryableNukeError(t *testing.T) {
+	t.Parallel()
+
+	dependencyErr := awserr.New("DependencyViolation", "The vpc has dependencies and cannot be deleted.", nil)
+	assert.True(t, isRetryableNukeError(dependencyErr))
+	assert.True(t, isRetryableNukeError(errors.WithStackTrace(dependencyErr)))
+	assert.True(t, isRetryableNukeError(awserr.New("VolumeInUse", "vol-1 is attached to i-1", nil)))
+	assert.False(t, isRetryableNukeError(awserr.New("AccessDenied", "not allowed", nil)))
+	assert.False(t, isRetryableNukeError(awserr.New("AuthFailure", "not allowed", nil)))
+	assert.False(t, isRetryableNukeError(awserr.New("ConflictException", "another operation is in progress", nil)))
+	// Codes mentioned in the message of another error do not make it retryable
+	assert.False(t, isRetryableNukeError(fmt.Errorf("failed to delete: %s", dependencyErr)))
+	assert.False(t, isRetryableNukeError(awserr.New("UnauthorizedOperation", "not allowed to resolve DependencyViolation", nil)))
+	assert.False(t, isRetryableNukeError(nil))
+}
+
+func TestNukeInPassesRetriesDependencyFailures(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{
+		ids:      []string{"passes-vpc-1", "passes-vpc-2", "passes-vpc-3"},
+		failures: map[string]int{"passes-vpc-1": 1, "passes-vpc-2": 2},
+		err:      awserr.New("DependencyViolation", "has dependencies", nil),
+	}
+	err := nukeInPasses(context.Background(), newFakeFlakyAccount(resources), NukeOptions{MaxPasses: 5}, nukeFakePass)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"passes-vpc-1": 2, "passes-vpc-2": 3, "passes-vpc-3": 1}, resources.attempts)
+	for _, identifier := range resources.ids {
+		entry, _ := report.GetRecord("us-east-1", "fake", identifier)
+		assert.NoError(t, entry.Error)
+	}
+}
+
+func TestNukeInPassesStopsAtMaxPasses(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{
+		ids:      []string{"max-passes-1", "max-passes-2"},
+		failures: map[string]int{"max-passes-1": 10},
+		err:      awserr.New("DependencyViolation", "has dependencies", nil),
+	}
+	err := nukeInPasses(context.Background(), newFakeFlakyAccount(resources), NukeOptions{MaxPasses: 1}, nukeFakePass)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, resources.attempts["max-passes-1"])
+	entry, _ := report.GetRecord("us-east-1", "fake", "max-passes-1")
+	assert.Error(t, entry.Error)
+}
+
+func TestNukeInPassesStopsWithoutProgress(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{
+		ids:      []string{"no-progress-1", "no-progress-2"},
+		failures: map[string]int{"no-progress-1": 10},
+		err:      awserr.New("DependencyViolation", "has dependencies", nil),
+	}
+	err := nukeInPasses(context.Background(), newFakeFlakyAccount(resources), NukeOptions{MaxPasses: 10}, nukeFakePass)
+	require.NoError(t, err)
+
+	// The first pass deletes one of them, and the second makes no progress on the other
+	assert.Equal(t, map[string]int{"no-progress-1": 2, "no-progress-2": 1}, resources.attempts)
+}
+
+func TestNukeInPassesDoesNotRetryOtherErrors(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{
+		ids:      []string{"access-denied-1", "access-denied-2"},
+		failures: map[string]int{"access-denied-1": 1},
+		err:      awserr.New("AccessDenied", "not allowed", nil),
+	}
+	err := nukeInPasses(context.Background(), newFakeFlakyAccount(resources), NukeOptions{MaxPasses: 3}, nukeFakePass)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"access-denied-1": 1, "access-denied-2": 1}, resources.attempts)
+}
+
+func TestNukeInPassesStopsWhenCancelled(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{
+		ids:      []string{"cancelled-passes-1"},
+		failures: map[string]int{"cancelled-passes-1": 10},
+		err:      awserr.New("DependencyViolation", "has dependencies", nil),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := nukeInPasses(ctx, newFakeFlakyAccount(resources), NukeOptions{MaxPasses: 10, PassBackoff: time.Hour}, func(account *AwsAccountResources) error {
+		defer cancel()
+		return nukeFakePass(account)
+	})
+
+	assert.True(t, report.IsCancelled(err))
+	assert.Equal(t, 1, resources.attempts["cancelled-passes-1"])
+}
+
+func TestNukeAllResourcesInRegionRecordsCancelled(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{ids: []string{"cancelled-region-1", "cancelled-region-2"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	nukeAllResourcesInRegion(ctx, newFakeFlakyAccount(resources), "us-east-1", nil, NukeOptions{})
+
+	assert.Empty(t, resources.attempts)
+	for _, identifier := range resources.ids {
+		entry, _ := report.GetRecord("us-east-1", "fake", identifier)
+		assert.True(t, report.IsCancelled(entry.Error))
+	}
+}
+
+// fakeThrottledResources gets throttled after deleting the first identifier of the first batch it is given, and gives
+// up on the rest of that batch without recording them, as resource types deleting a whole batch at once do
+type fakeThrottledResources struct {
+	fakeFlakyResources
+	throttled bool
+}
+
+func (r *fakeThrottledResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	for _, identifier := range identifiers {
+		r.attempts[identifier]++
+		if !r.throttled && identifier != identifiers[0] {
+			r.throttled = true
+			return awserr.New("Throttling", "Rate exceeded", nil)
+		}
+		record(ctx, report.Entry{Identifier: identifier, ResourceType: "fake"})
+	}
+	return nil
+}
+
+func TestNukeAllResourcesInRegionRetriesThrottledBatch(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+	originalBackoff := throttledBatchBackoff
+	throttledBatchBackoff = throttling.Backoff{Base: time.Millisecond, Max: time.Millisecond}
+	defer func() { throttledBatchBackoff = originalBackoff }()
+
+	resources := &fakeThrottledResources{fakeFlakyResources: fakeFlakyResources{ids: []string{"throttled-1", "throttled-2", "throttled-3"}}}
+	account := &AwsAccountResources{Resources: map[string]AwsRegionResource{"us-east-1": {Resources: []AwsResources{resources}}}}
+	resources.attempts = map[string]int{}
+	nukeAllResourcesInRegion(context.Background(), account, "us-east-1", nil, NukeOptions{})
+
+	// The batch is nuked again, without the resource that was deleted before the throttling
+	assert.Equal(t, map[string]int{"throttled-1": 1, "throttled-2": 2, "throttled-3": 1}, resources.attempts)
+	for _, identifier := range resources.ids {
+		entry, ok := report.GetRecord("us-east-1", "fake", identifier)
+		require.True(t, ok)
+		assert.NoError(t, entry.Error)
+	}
+}
+
+// fakeCancellingResources deletes the first identifier of the batch it is given, then the run is cancelled and it
+// gives up on the rest of the batch without recording them
+type fakeCancellingResources struct {
+	fakeFlakyResources
+	cancel context.CancelFunc
+}
+
+func (r *fakeCancellingResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	record(ctx, report.Entry{Identifier: identifiers[0], ResourceType: "fake"})
+	r.cancel()
+	return ctx.Err()
+}
+
+func TestNukeAllResourcesInRegionRecordsCancelledRestOfBatch(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	resources := &fakeCancellingResources{
+		fakeFlakyResources: fakeFlakyResources{ids: []string{"cancelled-batch-1", "cancelled-batch-2", "cancelled-batch-3"}},
+		cancel:             cancel,
+	}
+	account := &AwsAccountResources{Resources: map[string]AwsRegionResource{"us-east-1": {Resources: []AwsResources{resources}}}}
+	nukeAllResourcesInRegion(ctx, account, "us-east-1", nil, NukeOptions{})
+
+	entry, _ := report.GetRecord("us-east-1", "fake", "cancelled-batch-1")
+	assert.NoError(t, entry.Error)
+	for _, identifier := range resources.ids[1:] {
+		entry, ok := report.GetRecord("us-east-1", "fake", identifier)
+		require.True(t, ok)
+		assert.True(t, report.IsCancelled(entry.Error))
+	}
+}
+
+func TestNukeAllResourcesInRegionNotifiesOutcomesWithRegion(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	var regions []string
+	options := NukeOptions{OnOutcome: func(region string, resourceType string, entry report.Entry) {
+		assert.Equal(t, "fake", resourceType)
+		assert.Equal(t, "outcome-1", entry.Identifier)
+		regions = append(regions, region)
+	}}
+	// The same identifier in two regions is notified with the region it was nuked in each time
+	for _, region := range []string{"us-east-1", "eu-west-1"} {
+		resources := &fakeFlakyResources{ids: []string{"outcome-1"}, attempts: map[string]int{}}
+		account := &AwsAccountResources{Resources: map[string]AwsRegionResource{region: {Resources: []AwsResources{resources}}}}
+		nukeAllResourcesInRegion(context.Background(), account, region, nil, options)
+	}
+
+	assert.Equal(t, []string{"us-east-1", "eu-west-1"}, regions)
+}
+
+func TestCollectRetryableFailuresKeepsOutcomesOfEachRegion(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	// The same identifier failed in one region and was deleted in the other, so only the failure is retried
+	failing := &fakeFlakyResources{ids: []string{"shared-1"}, failures: map[string]int{"shared-1": 1}, err: awserr.New("DependencyViolation", "has dependencies", nil)}
+	deleted := &fakeFlakyResources{ids: []string{"shared-1"}}
+	account := newFakeFlakyAccount(failing)
+	account.Resources["eu-west-1"] = AwsRegionResource{Resources: []AwsResources{deleted}}
+	deleted.attempts = map[string]int{}
+	require.NoError(t, nukeFakePass(account))
+
+	failures := collectRetryableFailures(account)
+	assert.Equal(t, []string{"us-east-1"}, sortedRegions(failures))
+	failed, _ := report.GetRecord("us-east-1", "fake", "shared-1")
+	assert.Error(t, failed.Error)
+	succeeded, ok := report.GetRecord("eu-west-1", "fake", "shared-1")
+	require.True(t, ok)
+	assert.NoError(t, succeeded.Error)
+}
+
+// fakeBlockingResources waits until the other resource types of the region are being nuked too
+type fakeBlockingResources struct {
+	fakeFlakyResources
+	name    string
+	started chan string
+}
+
+func (r *fakeBlockingResources) ResourceName() string { return r.name }
+func (r *fakeBlockingResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	r.started <- r.name
+	for len(r.started) < cap(r.started) {
+		time.Sleep(time.Millisecond)
+	}
+	return nil
+}
+
+func TestNukeAllResourcesInRegionNukesIndependentTypesConcurrently(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	// Neither type is registered, so they do not depend on each other and only complete if nuked at the same time
+	started := make(chan string, 2)
+	account := &AwsAccountResources{Resources: map[string]AwsRegionResource{"us-east-1": {Resources: []AwsResources{
+		&fakeBlockingResources{fakeFlakyResources: fakeFlakyResources{ids: []string{"a"}}, name: "fake-a", started: started},
+		&fakeBlockingResources{fakeFlakyResources: fakeFlakyResources{ids: []string{"b"}}, name: "fake-b", started: started},
+	}}}}
+	nukeAllResourcesInRegion(context.Background(), account, "us-east-1", nil, NukeOptions{Parallelism: 2})
+
+	assert.Len(t, started, 2)
+}
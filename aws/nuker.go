@@ -0,0 +1,186 @@
+package aws
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/pterm/pterm"
+	"github.com/sirupsen/logrus"
+	"github.com/tnn-gruntwork-io/cloud-nuke/externalcreds"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/progressbar"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// nukerMutex serializes Nuker runs, as the run state of cloud-nuke is kept in package-level variables
+var nukerMutex = &sync.Mutex{}
+
+// Nuker inspects and nukes AWS resources in-process, for embedding cloud-nuke in other tools. Unlike the CLI, it never
+// writes to stdout: logs go to Logger, the outcome of every resource is passed to OnResource as soon as it is known, and
+// the results are returned as rows, exactly as written by `--output-format json`. It does not send telemetry.
+//
+// cloud-nuke keeps the state of a run in package-level variables, so a process runs at most one Inspect or Nuke call
+// at a time. Concurrent calls wait for each other.
+type Nuker struct {
+	// Credentials are used for all AWS API calls. If nil, the default credential chain is used.
+	Credentials *credentials.Credentials
+	// Query selects the regions, resource types, age and config file rules of the resources. Its regions and resource
+	// types are resolved again with Credentials, so it can be created with NewQuery or as a plain struct.
+	Query *Query
+	// Options tune how resources are nuked. If nil, DefaultNukeOptions is used. The limits of Query.Config apply as
+	// well, the lowest limit wins, and its preserve_data and recovery_window_days settings override the data
+	// preservation and recovery windows of Options for the resource types they set.
+	Options *NukeOptions
+	// Logger receives the cloud-nuke logs. If nil, logs are discarded.
+	Logger *logrus.Logger
+	// OnResource, if set, is called with every resource as soon as its outcome is known: found or excluded once the
+	// resources are discovered, deleted or failed once the batch they are nuked in is done. It may be called from
+	// several goroutines at once.
+	OnResource func(ui.ResourceRow)
+}
+
+// NukerResult is the outcome of an Inspect or Nuke call
+type NukerResult struct {
+	// Account holds the resources that were discovered by Inspect, or that were targeted by Nuke
+	Account *AwsAccountResources
+	// RunID identifies the run of Nuke, as put on the backups taken of preserved data and on the resources that can be
+	// restored. It is empty for Inspect.
+	RunID string
+	// Resources holds a row for every resource, as well as a row without an identifier for every error that is not
+	// specific to a single resource
+	Resources []ui.ResourceRow
+}
+
+// Inspect discovers the resources selected by the query without nuking anything. Resources excluded by the config
+// file rules are part of the result, along with the reason why they were excluded. Once the context is done, no further
+// resource types are listed and the error of the context is returned.
+func (nuker *Nuker) Inspect(ctx context.Context) (*NukerResult, error) {
+	var result *NukerResult
+	err := nuker.run(func(query *Query) error {
+		account, err := GetAllResources(ctx, query.Regions, query.ExcludeAfter, query.ResourceTypes, query.Config, query.ListUnaliasedKMSKeys, query.Parallelism)
+		if err != nil {
+			return err
+		}
+
+		rows := append(ExtractResourcesForOutput(account, time.Now().UTC()), ExtractExclusionsForOutput(report.GetExclusions())...)
+		nuker.notify(rows)
+		errorRows := extractGeneralErrorsForOutput()
+		nuker.notify(errorRows)
+		result = &NukerResult{Account: account, Resources: append(rows, errorRows...)}
+		return nil
+	})
+	return result, err
+}
+
+// Nuke discovers the resources selected by the query and nukes them, unless the account is not allowed by the account
+// rules of the config file or the resources exceed the limits. There is no confirmation prompt. Once the context is
+// done, the resources that were not nuked yet are reported as cancelled, and both the result and the error of the
+// context are returned.
+func (nuker *Nuker) Nuke(ctx context.Context) (*NukerResult, error) {
+	var result *NukerResult
+	err := nuker.run(func(query *Query) error {
+		if err := CheckAccount(query.Regions[0], query.Config.Accounts); err != nil {
+			return err
+		}
+
+		options := DefaultNukeOptions()
+		if nuker.Options != nil {
+			options = *nuker.Options
+		}
+		options.Limits = options.Limits.Stricter(query.Config.Limits)
+		options.PreserveData = options.PreserveData.WithConfig(query.Config)
+		options.RecoveryWindows = options.RecoveryWindows.WithConfig(query.Config)
+		if options.RunID == "" {
+			options.RunID = NewRunID()
+		}
+
+		account, err := GetAllResources(ctx, query.Regions, query.ExcludeAfter, query.ResourceTypes, query.Config, query.ListUnaliasedKMSKeys, query.Parallelism)
+		if err != nil {
+			return err
+		}
+		nuker.notify(ExtractExclusionsForOutput(report.GetExclusions()))
+
+		var nukeErr error
+		if account.TotalResourceCount() > 0 {
+			onOutcome := options.OnOutcome
+			options.OnOutcome = func(region string, resourceType string, entry report.Entry) {
+				if onOutcome != nil {
+					onOutcome(region, resourceType, entry)
+				}
+				nuker.notify([]ui.ResourceRow{recordToRow(entry, region)})
+			}
+			nukeErr = NukeAllResources(ctx, account, query.Regions, options)
+			progressbar.GetProgressbar().Stop()
+			if nukeErr != nil && !report.IsCancelled(nukeErr) {
+				return nukeErr
+			}
+		}
+		nuker.notify(extractGeneralErrorsForOutput())
+
+		result = &NukerResult{Account: account, RunID: options.RunID, Resources: ExtractRunReportForOutput()}
+		return nukeErr
+	})
+	return result, err
+}
+
+// run calls fn with a validated copy of the query, after pointing the package-level state of cloud-nuke at the
+// settings of the nuker. The previous state is restored once fn returns.
+func (nuker *Nuker) run(fn func(query *Query) error) error {
+	if nuker.Query == nil {
+		return errors.WithStackTrace(MissingNukerQueryError{})
+	}
+
+	nukerMutex.Lock()
+	defer nukerMutex.Unlock()
+
+	previousLogger := logging.Logger
+	previousConfig := externalcreds.Config()
+	previousOutput := pterm.Output
+	previousTelemetry := telemetry.IsEnabled()
+	defer func() {
+		logging.Logger = previousLogger
+		externalcreds.Set(previousConfig)
+		pterm.Output = previousOutput
+		telemetry.SetEnabled(previousTelemetry)
+	}()
+
+	logger := nuker.Logger
+	if logger == nil {
+		logger = logrus.New()
+		logger.SetOutput(io.Discard)
+	}
+	logging.Logger = logrus.NewEntry(logger)
+	if nuker.Credentials != nil {
+		externalcreds.Set(awsgo.NewConfig().WithCredentials(nuker.Credentials))
+	}
+	// The spinners and progress bar of the CLI are printed with pterm
+	pterm.DisableOutput()
+	// Telemetry is only set up by the CLI, and is not sent on behalf of the tools embedding cloud-nuke
+	telemetry.SetEnabled(false)
+
+	report.ResetRecords()
+	report.ResetErrors()
+
+	query := *nuker.Query
+	if err := query.Validate(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return fn(&query)
+}
+
+// notify passes the given rows to the OnResource callback, if any
+func (nuker *Nuker) notify(rows []ui.ResourceRow) {
+	if nuker.OnResource == nil {
+		return
+	}
+	for _, row := range rows {
+		nuker.OnResource(row)
+	}
+}
@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/pterm/pterm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/externalcreds"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestNukerRequiresQuery(t *testing.T) {
+	t.Parallel()
+
+	_, err := (&Nuker{}).Inspect(context.Background())
+	assert.Equal(t, MissingNukerQueryError{}, errors.Unwrap(err))
+}
+
+// Not parallel, as the nuker changes package-level variables while it runs
+func TestNukerRestoresGlobals(t *testing.T) {
+	previousLogger := logging.Logger
+	previousConfig := externalcreds.Config()
+
+	nuker := &Nuker{
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+		// An invalid query fails before any AWS API call is made
+		Query: &Query{ResourceTypes: []string{"ec2"}, ExcludeResourceTypes: []string{"ebs"}},
+	}
+	_, err := nuker.Nuke(context.Background())
+	assert.Equal(t, ResourceTypeAndExcludeFlagsBothPassedError{}, errors.Unwrap(err))
+
+	assert.Same(t, previousLogger, logging.Logger)
+	assert.Same(t, previousConfig, externalcreds.Config())
+	assert.True(t, pterm.Output)
+	// The query of the nuker is left untouched
+	assert.Equal(t, []string{"ec2"}, nuker.Query.ResourceTypes)
+}
+
+// fakeEC2Regions enables a single region
+type fakeEC2Regions struct {
+	ec2iface.EC2API
+}
+
+func (fake *fakeEC2Regions) DescribeRegions(*ec2.DescribeRegionsInput) (*ec2.DescribeRegionsOutput, error) {
+	return &ec2.DescribeRegionsOutput{Regions: []*ec2.Region{{RegionName: awsgo.String("us-east-1")}}}, nil
+}
+
+func TestNukerInspectsWithoutTelemetryOffline(t *testing.T) {
+	// Telemetry is on until InitTelemetry is called, which tools embedding cloud-nuke never do
+	previousTelemetry := telemetry.IsEnabled()
+	telemetry.SetEnabled(true)
+	defer telemetry.SetEnabled(previousTelemetry)
+
+	fake := newFakeSQS()
+	queue := fake.addQueue("queue", time.Now().Add(-time.Hour), nil)
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(&fakeEC2Regions{}))
+	useFakeClient(t, &newSQSClient, sqsiface.SQSAPI(fake))
+
+	var found []string
+	nuker := &Nuker{
+		Query: &Query{Regions: []string{"us-east-1"}, ResourceTypes: []string{"sqs"}, ExcludeAfter: time.Now()},
+		OnResource: func(row ui.ResourceRow) {
+			found = append(found, row.Identifier)
+			assert.False(t, telemetry.IsEnabled())
+		},
+	}
+	result, err := nuker.Inspect(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{queue}, found)
+	assert.Equal(t, []string{queue}, result.Account.Resources["us-east-1"].Resources[0].ResourceIdentifiers())
+	assert.True(t, telemetry.IsEnabled())
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -30,7 +32,7 @@ type oidcProvider struct {
 // information to implement the filters, we use goroutines to asynchronously and concurrently fetch the details for all
 // the providers that are found in the account.
 func getAllOIDCProviders(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := iam.New(session)
+	svc := newIAMClient(session)
 
 	output, err := svc.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
 	if err != nil {
@@ -57,7 +59,7 @@ func getAllOIDCProviders(session *session.Session, excludeAfter time.Time, confi
 
 // getAllOIDCProviderDetails fetches the details of the given list of OpenID Connect Providers so that we can make
 // informed decisions about which ones should be included in the nuking procedure.
-func getAllOIDCProviderDetails(svc *iam.IAM, providerARNs []*string) ([]oidcProvider, error) {
+func getAllOIDCProviderDetails(svc iamiface.IAMAPI, providerARNs []*string) ([]oidcProvider, error) {
 	numRetrieving := len(providerARNs)
 
 	// Schedule goroutines to retrieve the provider details async.
@@ -102,16 +104,15 @@ func shouldIncludeOIDCProvider(provider oidcProvider, excludeAfter time.Time, co
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(provider.ProviderURL),
-		configObj.OIDCProvider.IncludeRule.NamesRegExp,
-		configObj.OIDCProvider.ExcludeRule.NamesRegExp,
-	)
+	return configObj.OIDCProvider.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(provider.ProviderURL),
+		ID:   aws.StringValue(provider.ARN),
+	})
 }
 
 // getOIDCProviderDetailAsync is a routine for fetching the details of a single OpenID Connect Provider. This function
 // is designed to be called in a goroutine.
-func getOIDCProviderDetailAsync(wg *sync.WaitGroup, resultChan chan *oidcProvider, errChan chan error, svc *iam.IAM, providerARN *string) {
+func getOIDCProviderDetailAsync(wg *sync.WaitGroup, resultChan chan *oidcProvider, errChan chan error, svc iamiface.IAMAPI, providerARN *string) {
 	defer wg.Done()
 
 	resp, err := svc.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{OpenIDConnectProviderArn: providerARN})
@@ -141,8 +142,8 @@ func getOIDCProviderDetailAsync(wg *sync.WaitGroup, resultChan chan *oidcProvide
 }
 
 // nukeAllOIDCProviders deletes all the given OpenID Connect Providers from the account.
-func nukeAllOIDCProviders(session *session.Session, identifiers []*string) error {
-	svc := iam.New(session)
+func nukeAllOIDCProviders(ctx context.Context, session *session.Session, identifiers []*string) error {
+	svc := newIAMClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No OIDC Providers to nuke")
@@ -165,7 +166,7 @@ func nukeAllOIDCProviders(session *session.Session, identifiers []*string) error
 	errChans := make([]chan error, len(identifiers))
 	for i, providerARN := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteOIDCProviderAsync(wg, errChans[i], svc, providerARN)
+		go deleteOIDCProviderAsync(ctx, wg, errChans[i], svc, providerARN)
 	}
 	wg.Wait()
 
@@ -195,17 +196,17 @@ func nukeAllOIDCProviders(session *session.Session, identifiers []*string) error
 
 // deleteOIDCProviderAsync deletes the provided OIDC Provider asynchronously in a goroutine, using wait groups for
 // concurrency control and a return channel for errors.
-func deleteOIDCProviderAsync(wg *sync.WaitGroup, errChan chan error, svc *iam.IAM, providerARN *string) {
+func deleteOIDCProviderAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc iamiface.IAMAPI, providerARN *string) {
 	defer wg.Done()
 
-	_, err := svc.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{OpenIDConnectProviderArn: providerARN})
+	_, err := svc.DeleteOpenIDConnectProviderWithContext(ctx, &iam.DeleteOpenIDConnectProviderInput{OpenIDConnectProviderArn: providerARN})
 	// Record status of this resource
 	e := report.Entry{
 		Identifier:   aws.StringValue(providerARN),
 		ResourceType: "OIDC Provider",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 }
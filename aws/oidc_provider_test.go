@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"regexp"
@@ -110,7 +111,7 @@ func TestNukeOIDCProviderOne(t *testing.T) {
 	identifiers := []*string{oidcProviderARN}
 	require.NoError(
 		t,
-		nukeAllOIDCProviders(session, identifiers),
+		nukeAllOIDCProviders(context.Background(), session, identifiers),
 	)
 
 	// Make sure the OIDC Provider is deleted.
@@ -134,7 +135,7 @@ func TestNukeOIDCProviderMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllOIDCProviders(session, providers),
+		nukeAllOIDCProviders(context.Background(), session, providers),
 	)
 
 	// Make sure all OIDCProviders are deleted.
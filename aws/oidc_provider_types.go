@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,23 @@ func (oidcprovider OIDCProviders) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (oidcprovider OIDCProviders) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllOIDCProviders(session, awsgo.StringSlice(identifiers)); err != nil {
+func (oidcprovider OIDCProviders) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllOIDCProviders(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        OIDCProviders{}.ResourceName(),
+		Description: "OIDC Providers",
+		Global:      true,
+		ConfigKey:   "OIDCProvider",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllOIDCProviders(session, params.ExcludeAfter, params.Config)
+			return OIDCProviders{ProviderARNs: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
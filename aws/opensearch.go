@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -10,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/opensearchservice"
+	"github.com/aws/aws-sdk-go/service/opensearchservice/opensearchserviceiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
@@ -51,7 +53,7 @@ func getOpenSearchDomainsToNuke(awsSession *session.Session, excludeAfter time.T
 
 // getAllActiveOpenSearchDomains filters all active OpenSearch domains, which are those that have the `Created` flag true and `Deleted` flag false.
 func getAllActiveOpenSearchDomains(awsSession *session.Session) ([]*opensearchservice.DomainStatus, error) {
-	svc := opensearchservice.New(awsSession)
+	svc := newOpenSearchClient(awsSession)
 
 	allDomains := []*string{}
 	resp, err := svc.ListDomainNames(&opensearchservice.ListDomainNamesInput{})
@@ -63,17 +65,20 @@ func getAllActiveOpenSearchDomains(awsSession *session.Session) ([]*opensearchse
 		allDomains = append(allDomains, domain.DomainName)
 	}
 
-	input := &opensearchservice.DescribeDomainsInput{DomainNames: allDomains}
-	describedDomains, describeErr := svc.DescribeDomains(input)
-	if describeErr != nil {
-		logging.Logger.Errorf("Error describing Domains from input %s: ", input)
-		return nil, errors.WithStackTrace(describeErr)
-	}
-
+	// DescribeDomains accepts at most 5 domain names per call
 	filteredDomains := []*opensearchservice.DomainStatus{}
-	for _, domain := range describedDomains.DomainStatusList {
-		if aws.BoolValue(domain.Created) && aws.BoolValue(domain.Deleted) == false {
-			filteredDomains = append(filteredDomains, domain)
+	for _, batch := range split(aws.StringValueSlice(allDomains), 5) {
+		input := &opensearchservice.DescribeDomainsInput{DomainNames: aws.StringSlice(batch)}
+		describedDomains, describeErr := svc.DescribeDomains(input)
+		if describeErr != nil {
+			logging.Logger.Errorf("Error describing Domains from input %s: ", input)
+			return nil, errors.WithStackTrace(describeErr)
+		}
+
+		for _, domain := range describedDomains.DomainStatusList {
+			if aws.BoolValue(domain.Created) && aws.BoolValue(domain.Deleted) == false {
+				filteredDomains = append(filteredDomains, domain)
+			}
 		}
 	}
 	return filteredDomains, nil
@@ -90,17 +95,13 @@ func shouldIncludeOpenSearchDomain(domain *opensearchservice.DomainStatus, first
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(domain.DomainName),
-		configObj.OpenSearchDomain.IncludeRule.NamesRegExp,
-		configObj.OpenSearchDomain.ExcludeRule.NamesRegExp,
-	)
+	return configObj.OpenSearchDomain.ShouldInclude(config.ResourceValue{Name: aws.StringValue(domain.DomainName)})
 }
 
 // Tag an OpenSearch Domain identified by the given ARN when it's first seen by cloud-nuke
 func tagOpenSearchDomainWhenFirstSeen(awsSession *session.Session, domainARN *string, timestamp time.Time) error {
 	logging.Logger.Debugf("Tagging the OpenSearch Domain with ARN %s with first seen timestamp", aws.StringValue(domainARN))
-	svc := opensearchservice.New(awsSession)
+	svc := newOpenSearchClient(awsSession)
 	firstSeenTime := formatTimestampTag(timestamp)
 
 	input := &opensearchservice.AddTagsInput{
@@ -124,7 +125,7 @@ func tagOpenSearchDomainWhenFirstSeen(awsSession *session.Session, domainARN *st
 func getFirstSeenOpenSearchDomainTag(awsSession *session.Session, domainARN *string) (time.Time, error) {
 	var firstSeenTime time.Time
 
-	svc := opensearchservice.New(awsSession)
+	svc := newOpenSearchClient(awsSession)
 	input := &opensearchservice.ListTagsInput{ARN: domainARN}
 	domainTags, err := svc.ListTags(input)
 	if err != nil {
@@ -150,10 +151,10 @@ func getFirstSeenOpenSearchDomainTag(awsSession *session.Session, domainARN *str
 // nukeAllOpenSearchDomains nukes the given list of OpenSearch domains concurrently. Note that the opensearchservice API
 // does not support bulk delete, so this routine will spawn a goroutine for each domain that needs to be nuked so that
 // they can be issued concurrently.
-func nukeAllOpenSearchDomains(session *session.Session, identifiers []*string) error {
+func nukeAllOpenSearchDomains(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := opensearchservice.New(session)
+	svc := newOpenSearchClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No OpenSearch Domains to nuke in region %s", region)
@@ -175,7 +176,7 @@ func nukeAllOpenSearchDomains(session *session.Session, identifiers []*string) e
 	errChans := make([]chan error, len(identifiers))
 	for i, domainName := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteOpenSearchDomainAsync(wg, errChans[i], svc, domainName)
+		go deleteOpenSearchDomainAsync(ctx, wg, errChans[i], svc, domainName)
 	}
 	wg.Wait()
 
@@ -198,13 +199,13 @@ func nukeAllOpenSearchDomains(session *session.Session, identifiers []*string) e
 	}
 
 	// Now wait until the OpenSearch Domains are deleted
-	err := retry.DoWithRetry(
-		logging.Logger,
+	err := retryWithContext(
+		ctx,
 		"Waiting for all OpenSearch Domains to be deleted.",
 		// Wait a maximum of 5 minutes: 10 seconds in between, up to 30 times
 		30, 10*time.Second,
 		func() error {
-			resp, err := svc.DescribeDomains(&opensearchservice.DescribeDomainsInput{DomainNames: identifiers})
+			resp, err := svc.DescribeDomainsWithContext(ctx, &opensearchservice.DescribeDomainsInput{DomainNames: identifiers})
 			if err != nil {
 				return errors.WithStackTrace(retry.FatalError{Underlying: err})
 			}
@@ -225,11 +226,11 @@ func nukeAllOpenSearchDomains(session *session.Session, identifiers []*string) e
 
 // deleteOpenSearchDomainAsync deletes the provided OpenSearch Domain asynchronously in a goroutine, using wait groups
 // for concurrency control and a return channel for errors.
-func deleteOpenSearchDomainAsync(wg *sync.WaitGroup, errChan chan error, svc *opensearchservice.OpenSearchService, domainName *string) {
+func deleteOpenSearchDomainAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc opensearchserviceiface.OpenSearchServiceAPI, domainName *string) {
 	defer wg.Done()
 
 	input := &opensearchservice.DeleteDomainInput{DomainName: domainName}
-	_, err := svc.DeleteDomain(input)
+	_, err := svc.DeleteDomainWithContext(ctx, input)
 
 	// Record status of this resource
 	e := report.Entry{
@@ -237,7 +238,7 @@ func deleteOpenSearchDomainAsync(wg *sync.WaitGroup, errChan chan error, svc *op
 		ResourceType: "OpenSearch Domain",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	errChan <- err
 }
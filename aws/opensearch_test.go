@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
 	"testing"
@@ -106,7 +107,7 @@ func TestCanNukeOpenSearchDomain(t *testing.T) {
 	// We ignore errors in the delete call here, because it is intended to be a stop gap in case there is a bug in nuke.
 	defer deleteOpenSearchDomain(t, awsSession, domain.DomainName, false)
 
-	require.NoError(t, nukeAllOpenSearchDomains(awsSession, []*string{domain.DomainName}))
+	require.NoError(t, nukeAllOpenSearchDomains(context.Background(), awsSession, []*string{domain.DomainName}))
 
 	allLeftDomains, err := getAllActiveOpenSearchDomains(awsSession)
 	require.NoError(t, err)
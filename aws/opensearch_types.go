@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -30,9 +32,21 @@ func (domains OpenSearchDomains) MaxBatchSize() int {
 }
 
 // Nuke nukes all OpenSearch domain resources
-func (domains OpenSearchDomains) Nuke(awsSession *session.Session, identifiers []string) error {
-	if err := nukeAllOpenSearchDomains(awsSession, awsgo.StringSlice(identifiers)); err != nil {
+func (domains OpenSearchDomains) Nuke(ctx context.Context, awsSession *session.Session, identifiers []string) error {
+	if err := nukeAllOpenSearchDomains(ctx, awsSession, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        OpenSearchDomains{}.ResourceName(),
+		Description: "OpenSearch Domains",
+		ConfigKey:   "OpenSearchDomain",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getOpenSearchDomainsToNuke(session, params.ExcludeAfter, params.Config)
+			return OpenSearchDomains{DomainNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
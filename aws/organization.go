@@ -0,0 +1,204 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/tnn-gruntwork-io/cloud-nuke/externalcreds"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/go-commons/collections"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// DefaultOrganizationRoleName is the role that AWS Organizations creates in the accounts it creates, which lets the
+// management account administer them
+const DefaultOrganizationRoleName = "OrganizationAccountAccessRole"
+
+// OrganizationAccount is a member account of an AWS Organization
+type OrganizationAccount struct {
+	ID   string
+	Name string
+	// Partition is the partition of the account, e.g. aws or aws-us-gov, which is part of the ARN of its roles
+	Partition string
+}
+
+func (account OrganizationAccount) String() string {
+	if account.Name == "" {
+		return account.ID
+	}
+	return fmt.Sprintf("%s (%s)", account.ID, account.Name)
+}
+
+// RoleArn returns the ARN of the role with the given name in the account
+func (account OrganizationAccount) RoleArn(roleName string) string {
+	return arn.ARN{Partition: account.Partition, Service: "iam", AccountID: account.ID, Resource: "role/" + roleName}.String()
+}
+
+// ListOrganizationAccounts returns the active accounts of the AWS Organization of the current credentials, sorted by
+// ID, using the API endpoint of the given region. If account IDs or organizational unit IDs are given, only the listed
+// accounts and the accounts in the listed units, including the units nested in them, are returned. The management
+// account of the organization and the account of the current credentials are always left out: cloud-nuke assumes roles
+// in the other accounts from there.
+func ListOrganizationAccounts(region string, accountIDs []string, unitIDs []string) ([]OrganizationAccount, error) {
+	session := newSession(region)
+	svc := newOrganizationsClient(session)
+
+	var accounts []*organizations.Account
+	if len(accountIDs) == 0 && len(unitIDs) == 0 {
+		err := svc.ListAccountsPages(&organizations.ListAccountsInput{}, func(page *organizations.ListAccountsOutput, lastPage bool) bool {
+			accounts = append(accounts, page.Accounts...)
+			return !lastPage
+		})
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+	}
+	if len(accountIDs) > 0 {
+		listed, err := findOrganizationAccounts(svc, accountIDs)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, listed...)
+	}
+	for _, unitID := range unitIDs {
+		inUnit, err := listOrganizationalUnitAccounts(svc, unitID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, inUnit...)
+	}
+
+	organization, err := svc.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	managementAccountID := awsgo.StringValue(organization.Organization.MasterAccountId)
+	callerAccountID, err := getCallerAccountId(session)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := []OrganizationAccount{}
+	seen := map[string]bool{}
+	for _, account := range accounts {
+		id := awsgo.StringValue(account.Id)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		switch {
+		case id == managementAccountID:
+			logging.Logger.Infof("Skipping account %s, which is the management account of the organization", id)
+		case id == callerAccountID:
+			logging.Logger.Infof("Skipping account %s, whose credentials are used to assume roles in the other accounts", id)
+		case awsgo.StringValue(account.Status) != organizations.AccountStatusActive:
+			logging.Logger.Infof("Skipping account %s, which is %s", id, awsgo.StringValue(account.Status))
+		default:
+			selected = append(selected, newOrganizationAccount(account))
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+	return selected, nil
+}
+
+// findOrganizationAccounts returns the accounts with the given IDs, or an error if one of them is not a member of
+// the organization
+func findOrganizationAccounts(svc organizationsiface.OrganizationsAPI, accountIDs []string) ([]*organizations.Account, error) {
+	var accounts []*organizations.Account
+	err := svc.ListAccountsPages(&organizations.ListAccountsInput{}, func(page *organizations.ListAccountsOutput, lastPage bool) bool {
+		for _, account := range page.Accounts {
+			if collections.ListContainsElement(accountIDs, awsgo.StringValue(account.Id)) {
+				accounts = append(accounts, account)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	for _, accountID := range accountIDs {
+		found := false
+		for _, account := range accounts {
+			found = found || awsgo.StringValue(account.Id) == accountID
+		}
+		if !found {
+			return nil, errors.WithStackTrace(UnknownOrganizationAccountError{AccountID: accountID})
+		}
+	}
+	return accounts, nil
+}
+
+// listOrganizationalUnitAccounts returns the accounts in the organizational unit with the given ID, and in the units
+// nested in it
+func listOrganizationalUnitAccounts(svc organizationsiface.OrganizationsAPI, unitID string) ([]*organizations.Account, error) {
+	var accounts []*organizations.Account
+	err := svc.ListAccountsForParentPages(&organizations.ListAccountsForParentInput{ParentId: awsgo.String(unitID)}, func(page *organizations.ListAccountsForParentOutput, lastPage bool) bool {
+		accounts = append(accounts, page.Accounts...)
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var childIDs []string
+	err = svc.ListOrganizationalUnitsForParentPages(&organizations.ListOrganizationalUnitsForParentInput{ParentId: awsgo.String(unitID)}, func(page *organizations.ListOrganizationalUnitsForParentOutput, lastPage bool) bool {
+		for _, unit := range page.OrganizationalUnits {
+			childIDs = append(childIDs, awsgo.StringValue(unit.Id))
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	for _, childID := range childIDs {
+		inChild, err := listOrganizationalUnitAccounts(svc, childID)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, inChild...)
+	}
+	return accounts, nil
+}
+
+func newOrganizationAccount(account *organizations.Account) OrganizationAccount {
+	partition := "aws"
+	if parsed, err := arn.Parse(awsgo.StringValue(account.Arn)); err == nil {
+		partition = parsed.Partition
+	}
+	return OrganizationAccount{
+		ID:        awsgo.StringValue(account.Id),
+		Name:      awsgo.StringValue(account.Name),
+		Partition: partition,
+	}
+}
+
+// AssumeOrganizationRole makes cloud-nuke act on the given account, through the role with the given name, until the
+// returned function is called. The role is assumed with the current credentials, using the API endpoint of the given
+// region, and the returned function makes cloud-nuke use the current credentials again. An error is returned if the
+// role cannot be assumed, in which case the current credentials are kept.
+func AssumeOrganizationRole(region string, account OrganizationAccount, roleName string) (func(), error) {
+	previousConfig := externalcreds.Config()
+	restore := func() { externalcreds.Set(previousConfig) }
+
+	roleArn := account.RoleArn(roleName)
+	externalcreds.Set(awsgo.NewConfig().WithCredentials(newAssumeRoleCredentials(newSession(region), roleArn)))
+
+	// Credentials are only retrieved when they are first used, so the role is checked right away, which also makes
+	// sure that cloud-nuke never acts on another account than the one it was asked to
+	accountID, err := getCallerAccountId(newSession(region))
+	if err != nil {
+		restore()
+		return nil, errors.WithStackTrace(AssumeOrganizationRoleError{RoleArn: roleArn, Underlying: errors.Unwrap(err)})
+	}
+	if accountID != account.ID {
+		restore()
+		return nil, errors.WithStackTrace(UnexpectedOrganizationAccountError{RoleArn: roleArn, AccountID: accountID})
+	}
+	return restore, nil
+}
@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/externalcreds"
+	goerrors "github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// fakeOrganizations is an organization whose accounts and organizational units are keyed by the ID of their parent
+type fakeOrganizations struct {
+	organizationsiface.OrganizationsAPI
+	managementAccountID string
+	accounts            map[string][]*organizations.Account
+	units               map[string][]string
+}
+
+func (fake *fakeOrganizations) ListAccountsPages(input *organizations.ListAccountsInput, fn func(*organizations.ListAccountsOutput, bool) bool) error {
+	var accounts []*organizations.Account
+	for _, inParent := range fake.accounts {
+		accounts = append(accounts, inParent...)
+	}
+	fn(&organizations.ListAccountsOutput{Accounts: accounts}, true)
+	return nil
+}
+
+func (fake *fakeOrganizations) ListAccountsForParentPages(input *organizations.ListAccountsForParentInput, fn func(*organizations.ListAccountsForParentOutput, bool) bool) error {
+	fn(&organizations.ListAccountsForParentOutput{Accounts: fake.accounts[awsgo.StringValue(input.ParentId)]}, true)
+	return nil
+}
+
+func (fake *fakeOrganizations) ListOrganizationalUnitsForParentPages(input *organizations.ListOrganizationalUnitsForParentInput, fn func(*organizations.ListOrganizationalUnitsForParentOutput, bool) bool) error {
+	var units []*organizations.OrganizationalUnit
+	for _, id := range fake.units[awsgo.StringValue(input.ParentId)] {
+		units = append(units, &organizations.OrganizationalUnit{Id: awsgo.String(id)})
+	}
+	fn(&organizations.ListOrganizationalUnitsForParentOutput{OrganizationalUnits: units}, true)
+	return nil
+}
+
+func (fake *fakeOrganizations) DescribeOrganization(input *organizations.DescribeOrganizationInput) (*organizations.DescribeOrganizationOutput, error) {
+	return &organizations.DescribeOrganizationOutput{Organization: &organizations.Organization{MasterAccountId: awsgo.String(fake.managementAccountID)}}, nil
+}
+
+func newFakeOrganizationAccount(id string, status string) *organizations.Account {
+	return &organizations.Account{
+		Id:     awsgo.String(id),
+		Name:   awsgo.String("sandbox-" + id),
+		Arn:    awsgo.String("arn:aws:organizations::100000000000:account/o-example/" + id),
+		Status: awsgo.String(status),
+	}
+}
+
+// fakeSTS answers GetCallerIdentity with the access key ID of the credentials it was created with, which the tests set
+// to the ID of the account the credentials belong to
+type fakeSTS struct {
+	stsiface.STSAPI
+	credentials *credentials.Credentials
+}
+
+func (fake fakeSTS) GetCallerIdentity(input *sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error) {
+	value, err := fake.credentials.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &sts.GetCallerIdentityOutput{Account: awsgo.String(value.AccessKeyID)}, nil
+}
+
+// useFakeAccountCredentials makes cloud-nuke use credentials for the given account until the test finishes, and makes
+// assuming a role return credentials for the account the role is in, or for the account accountOfRole returns
+func useFakeAccountCredentials(t *testing.T, accountID string, accountOfRole func(roleArn string) string) {
+	previousConfig := externalcreds.Config()
+	externalcreds.Set(awsgo.NewConfig().WithCredentials(credentials.NewStaticCredentials(accountID, "secret", "")))
+
+	originalSTSClient := newSTSClient
+	newSTSClient = func(session *session.Session) stsiface.STSAPI {
+		return fakeSTS{credentials: session.Config.Credentials}
+	}
+	originalAssumeRoleCredentials := newAssumeRoleCredentials
+	newAssumeRoleCredentials = func(session *session.Session, roleArn string) *credentials.Credentials {
+		return credentials.NewStaticCredentials(accountOfRole(roleArn), "secret", "token")
+	}
+
+	t.Cleanup(func() {
+		externalcreds.Set(previousConfig)
+		newSTSClient = originalSTSClient
+		newAssumeRoleCredentials = originalAssumeRoleCredentials
+	})
+}
+
+func accountOfRoleArn(roleArn string) string {
+	parsed, _ := arn.Parse(roleArn)
+	return parsed.AccountID
+}
+
+func accountIDs(accounts []OrganizationAccount) []string {
+	ids := []string{}
+	for _, account := range accounts {
+		ids = append(ids, account.ID)
+	}
+	return ids
+}
+
+func TestListOrganizationAccountsOffline(t *testing.T) {
+	useFakeAccountCredentials(t, "900000000000", accountOfRoleArn)
+	useFakeClient(t, &newOrganizationsClient, organizationsiface.OrganizationsAPI(&fakeOrganizations{
+		managementAccountID: "100000000000",
+		accounts: map[string][]*organizations.Account{
+			"r-root": {
+				newFakeOrganizationAccount("100000000000", organizations.AccountStatusActive),
+				newFakeOrganizationAccount("900000000000", organizations.AccountStatusActive),
+				newFakeOrganizationAccount("400000000000", organizations.AccountStatusActive),
+			},
+			"ou-sandbox": {
+				newFakeOrganizationAccount("200000000000", organizations.AccountStatusActive),
+				newFakeOrganizationAccount("210000000000", organizations.AccountStatusSuspended),
+			},
+			"ou-nested": {newFakeOrganizationAccount("300000000000", organizations.AccountStatusActive)},
+		},
+		units: map[string][]string{"r-root": {"ou-sandbox"}, "ou-sandbox": {"ou-nested"}},
+	}))
+
+	// The management account, the account of the current credentials and suspended accounts are left out
+	accounts, err := ListOrganizationAccounts("us-east-1", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200000000000", "300000000000", "400000000000"}, accountIDs(accounts))
+	assert.Equal(t, OrganizationAccount{ID: "200000000000", Name: "sandbox-200000000000", Partition: "aws"}, accounts[0])
+
+	accounts, err = ListOrganizationAccounts("us-east-1", nil, []string{"ou-sandbox"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200000000000", "300000000000"}, accountIDs(accounts))
+
+	accounts, err = ListOrganizationAccounts("us-east-1", []string{"400000000000", "300000000000"}, []string{"ou-nested"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"300000000000", "400000000000"}, accountIDs(accounts))
+
+	_, err = ListOrganizationAccounts("us-east-1", []string{"500000000000"}, nil)
+	assert.Equal(t, UnknownOrganizationAccountError{AccountID: "500000000000"}, goerrors.Unwrap(err))
+}
+
+func TestAssumeOrganizationRoleOffline(t *testing.T) {
+	account := OrganizationAccount{ID: "200000000000", Partition: "aws-us-gov"}
+	assert.Equal(t, "arn:aws-us-gov:iam::200000000000:role/OrganizationAccountAccessRole", account.RoleArn(DefaultOrganizationRoleName))
+
+	useFakeAccountCredentials(t, "900000000000", func(roleArn string) string {
+		if accountOfRoleArn(roleArn) == "300000000000" {
+			return "310000000000"
+		}
+		return accountOfRoleArn(roleArn)
+	})
+	callerAccountID := func() string {
+		accountID, err := getCallerAccountId(newSession("us-east-1"))
+		require.NoError(t, err)
+		return accountID
+	}
+
+	restore, err := AssumeOrganizationRole("us-east-1", account, DefaultOrganizationRoleName)
+	require.NoError(t, err)
+	assert.Equal(t, "200000000000", callerAccountID())
+	restore()
+	assert.Equal(t, "900000000000", callerAccountID())
+
+	// cloud-nuke refuses to act on an account that it was not asked to
+	_, err = AssumeOrganizationRole("us-east-1", OrganizationAccount{ID: "300000000000", Partition: "aws"}, DefaultOrganizationRoleName)
+	assert.Equal(t, UnexpectedOrganizationAccountError{RoleArn: "arn:aws:iam::300000000000:role/OrganizationAccountAccessRole", AccountID: "310000000000"}, goerrors.Unwrap(err))
+	assert.Equal(t, "900000000000", callerAccountID())
+
+	newAssumeRoleCredentials = func(session *session.Session, roleArn string) *credentials.Credentials {
+		return credentials.NewCredentials(&credentials.ErrorProvider{Err: errors.New("AccessDenied"), ProviderName: "fake"})
+	}
+	_, err = AssumeOrganizationRole("us-east-1", account, DefaultOrganizationRoleName)
+	assert.IsType(t, AssumeOrganizationRoleError{}, goerrors.Unwrap(err))
+	assert.Equal(t, "900000000000", callerAccountID())
+}
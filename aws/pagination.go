@@ -0,0 +1,25 @@
+package aws
+
+import (
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// paginate lists every page of an AWS API operation for which the SDK has no Pages method or paginator. listPage is
+// called with the token returned by the previous call, starting with nil, until it returns an empty token. Listers
+// must use it, or the Pages method or paginator of the SDK, for every paginated operation: a single call only returns
+// the first page, and silently misses the other resources.
+func paginate(listPage func(token *string) (*string, error)) error {
+	var token *string
+	for {
+		next, err := listPage(token)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		// Also stop if the same token is returned again, rather than requesting the same page forever
+		if awsgo.StringValue(next) == "" || awsgo.StringValue(next) == awsgo.StringValue(token) {
+			return nil
+		}
+		token = next
+	}
+}
@@ -0,0 +1,50 @@
+package aws
+
+import (
+	"fmt"
+	"testing"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginateFollowsTokens(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]*string{"": awsgo.String("page-2"), "page-2": awsgo.String("page-3"), "page-3": nil}
+	var requested []string
+	err := paginate(func(token *string) (*string, error) {
+		requested = append(requested, awsgo.StringValue(token))
+		return pages[awsgo.StringValue(token)], nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", "page-2", "page-3"}, requested)
+}
+
+func TestPaginateStopsOnRepeatedToken(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	err := paginate(func(token *string) (*string, error) {
+		calls++
+		return awsgo.String("same-token"), nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestPaginateReturnsError(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	err := paginate(func(token *string) (*string, error) {
+		calls++
+		if calls == 2 {
+			return nil, fmt.Errorf("throttled")
+		}
+		return awsgo.String(fmt.Sprintf("page-%d", calls+1)), nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
@@ -0,0 +1,59 @@
+package aws
+
+import "sync"
+
+// DefaultParallelism is the number of regions (and resource types within a region) that are scanned or nuked at the
+// same time when no other value is configured
+const DefaultParallelism = 4
+
+// runInParallel calls fn once for every index in [0, count), with at most parallelism calls running at the same time,
+// and returns once all of them have completed. A parallelism below 1 is treated as 1.
+func runInParallel(count int, parallelism int, fn func(index int)) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	wg := new(sync.WaitGroup)
+	semaphore := make(chan struct{}, parallelism)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			fn(index)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// runAfterDependencies is like runInParallel, except that fn is only called for an index once it has completed for
+// all the indexes listed in dependencies[index]. The dependencies must not form a cycle.
+func runAfterDependencies(count int, parallelism int, dependencies [][]int, fn func(index int)) {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	done := make([]chan struct{}, count)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	wg := new(sync.WaitGroup)
+	semaphore := make(chan struct{}, parallelism)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer close(done[index])
+			// Waiting for the dependencies before taking a slot keeps the slots for the calls that can run
+			for _, dependency := range dependencies[index] {
+				<-done[dependency]
+			}
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			fn(index)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInParallelCallsEveryIndexOnce(t *testing.T) {
+	t.Parallel()
+
+	mutex := sync.Mutex{}
+	calls := map[int]int{}
+	runInParallel(20, 3, func(index int) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls[index]++
+	})
+
+	assert.Len(t, calls, 20)
+	for index, count := range calls {
+		assert.Equal(t, 1, count, "index %d", index)
+	}
+}
+
+func TestRunInParallelBoundsConcurrency(t *testing.T) {
+	t.Parallel()
+
+	for _, parallelism := range []int{0, 1, 4} {
+		mutex := sync.Mutex{}
+		running := 0
+		maxRunning := 0
+		runInParallel(16, parallelism, func(index int) {
+			mutex.Lock()
+			running++
+			if running > maxRunning {
+				maxRunning = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+		})
+
+		expectedMax := parallelism
+		if expectedMax < 1 {
+			expectedMax = 1
+		}
+		assert.LessOrEqual(t, maxRunning, expectedMax, "parallelism %d", parallelism)
+	}
+}
+
+func TestRunAfterDependenciesWaitsForDependencies(t *testing.T) {
+	t.Parallel()
+
+	// 2 and 3 depend on 0, and 4 depends on 2 and 1
+	dependencies := [][]int{{}, {}, {0}, {0}, {2, 1}}
+	mutex := sync.Mutex{}
+	completed := map[int]bool{}
+	runAfterDependencies(len(dependencies), 2, dependencies, func(index int) {
+		mutex.Lock()
+		for _, dependency := range dependencies[index] {
+			assert.True(t, completed[dependency], "%d ran before its dependency %d", index, dependency)
+		}
+		mutex.Unlock()
+
+		time.Sleep(time.Millisecond)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		completed[index] = true
+	})
+
+	assert.Len(t, completed, len(dependencies))
+}
+
+func TestRunAfterDependenciesRunsIndependentCallsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	// Every call waits for the other one, so they only complete if they run at the same time
+	started := make(chan struct{}, 2)
+	runAfterDependencies(2, 2, [][]int{{}, {}}, func(index int) {
+		started <- struct{}{}
+		for len(started) < 2 {
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
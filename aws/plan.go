@@ -0,0 +1,160 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// PlanVersion is the version of the plan file format written by WritePlan. ReadPlan refuses plans of other versions.
+const PlanVersion = 1
+
+// Plan is a reviewable record of the resources selected for nuking, written with `cloud-nuke aws --out-plan` and
+// nuked with `cloud-nuke aws apply`
+type Plan struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	// AccountID is the ID of the account whose resources are planned, which is the only account the plan may be applied
+	// to. It is empty in plans written before the account was recorded.
+	AccountID string `json:"accountId,omitempty"`
+	// Regions and ResourceTypes are the scope of the discovery that produced the plan. Applying the plan only scans
+	// this scope again.
+	Regions                  []string          `json:"regions"`
+	ResourceTypes            []string          `json:"resourceTypes"`
+	AllowDeleteUnaliasedKeys bool              `json:"allowDeleteUnaliasedKeys"`
+	Resources                []PlannedResource `json:"resources"`
+	// Mode is the mode in which the planned resources are to be nuked, which applying the plan keeps. It is empty in
+	// plans written before the mode was recorded.
+	Mode NukeMode `json:"mode,omitempty"`
+	// Accounts and Limits are the account rules and resource limits in effect when the plan was written, from the
+	// config file and the flags, which applying the plan enforces again
+	Accounts config.AccountRules   `json:"accounts"`
+	Limits   config.ResourceLimits `json:"limits"`
+}
+
+// PlannedResource is a single resource selected for nuking
+type PlannedResource struct {
+	Region       string `json:"region"`
+	ResourceType string `json:"resourceType"`
+	Identifier   string `json:"identifier"`
+	// Reason explains why the resource was selected for nuking
+	Reason string `json:"reason"`
+}
+
+// NewPlan records the given discovered resources in a plan. The remaining arguments describe how the resources were
+// discovered: they are used to scan the account again when the plan is applied, and to explain why each resource was
+// selected.
+func NewPlan(account *AwsAccountResources, regions []string, resourceTypes []string, excludeAfter time.Time, configFilePath string, allowDeleteUnaliasedKeys bool) *Plan {
+	plan := &Plan{
+		Version:                  PlanVersion,
+		CreatedAt:                time.Now().UTC(),
+		Regions:                  regions,
+		ResourceTypes:            resourceTypes,
+		AllowDeleteUnaliasedKeys: allowDeleteUnaliasedKeys,
+		Resources:                []PlannedResource{},
+	}
+
+	for _, region := range regions {
+		resourcesInRegion, ok := account.Resources[region]
+		if !ok {
+			continue
+		}
+		for _, resources := range resourcesInRegion.Resources {
+			reason := fmt.Sprintf("resource type %s selected and created before %s", resources.ResourceName(), excludeAfter.UTC().Format(time.RFC3339))
+			if configFilePath != "" {
+				reason = fmt.Sprintf("%s, not excluded by config file %s", reason, configFilePath)
+			}
+			for _, identifier := range resources.ResourceIdentifiers() {
+				plan.Resources = append(plan.Resources, PlannedResource{
+					Region:       region,
+					ResourceType: resources.ResourceName(),
+					Identifier:   identifier,
+					Reason:       reason,
+				})
+			}
+		}
+	}
+
+	return plan
+}
+
+// WritePlan writes the plan to the given path as JSON
+func WritePlan(plan *Plan, path string) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// ReadPlan reads a plan written by WritePlan
+func ReadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidPlanFileError{Path: path, Underlying: err})
+	}
+
+	plan := &Plan{}
+	if err := json.Unmarshal(data, plan); err != nil {
+		return nil, errors.WithStackTrace(InvalidPlanFileError{Path: path, Underlying: err})
+	}
+	if plan.Version != PlanVersion {
+		return nil, errors.WithStackTrace(UnsupportedPlanVersionError{Path: path, Version: plan.Version})
+	}
+	if len(plan.Regions) == 0 {
+		return nil, errors.WithStackTrace(InvalidPlanFileError{Path: path, Underlying: fmt.Errorf("no regions")})
+	}
+	return plan, nil
+}
+
+// SelectResources narrows freshly discovered resources down to the ones listed in the plan, so that resources created
+// after the plan was written are left alone. It also returns the planned resources that were not found anymore.
+func (plan *Plan) SelectResources(account *AwsAccountResources) (*AwsAccountResources, []PlannedResource) {
+	planned := map[PlannedResource]bool{}
+	for _, resource := range plan.Resources {
+		planned[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] = false
+	}
+
+	selected := &AwsAccountResources{
+		Resources: make(map[string]AwsRegionResource),
+	}
+	for region, resourcesInRegion := range account.Resources {
+		selectedInRegion := AwsRegionResource{}
+		for _, resources := range resourcesInRegion.Resources {
+			var identifiers []string
+			for _, identifier := range resources.ResourceIdentifiers() {
+				key := plannedResourceKey(region, resources.ResourceName(), identifier)
+				if _, ok := planned[key]; ok {
+					planned[key] = true
+					identifiers = append(identifiers, identifier)
+				}
+			}
+			if len(identifiers) > 0 {
+				selectedInRegion.Resources = append(selectedInRegion.Resources, selectedResources{AwsResources: resources, identifiers: identifiers})
+			}
+		}
+		if len(selectedInRegion.Resources) > 0 {
+			selected.Resources[region] = selectedInRegion
+		}
+	}
+
+	var missing []PlannedResource
+	for _, resource := range plan.Resources {
+		if !planned[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] {
+			missing = append(missing, resource)
+		}
+	}
+	return selected, missing
+}
+
+// plannedResourceKey returns the fields that identify a planned resource, without its reason
+func plannedResourceKey(region string, resourceType string, identifier string) PlannedResource {
+	return PlannedResource{Region: region, ResourceType: resourceType, Identifier: identifier}
+}
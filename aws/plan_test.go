@@ -0,0 +1,120 @@
+package aws
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func newTestPlanAccount(instanceIds []string, volumeIds []string) *AwsAccountResources {
+	return &AwsAccountResources{
+		Resources: map[string]AwsRegionResource{
+			"us-east-1": {Resources: []AwsResources{EC2Instances{InstanceIds: instanceIds}}},
+			"eu-west-1": {Resources: []AwsResources{EBSVolumes{VolumeIds: volumeIds}}},
+		},
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	t.Parallel()
+
+	account := newTestPlanAccount([]string{"i-1", "i-2"}, []string{"vol-1"})
+	excludeAfter := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	plan := NewPlan(account, []string{"us-east-1", "eu-west-1", GlobalRegion}, []string{"ec2", "ebs"}, excludeAfter, "config.yaml", true)
+
+	assert.Equal(t, PlanVersion, plan.Version)
+	assert.True(t, plan.AllowDeleteUnaliasedKeys)
+	assert.Equal(t, []string{"ec2", "ebs"}, plan.ResourceTypes)
+	require.Len(t, plan.Resources, 3)
+	assert.Equal(t, PlannedResource{
+		Region:       "us-east-1",
+		ResourceType: "ec2",
+		Identifier:   "i-1",
+		Reason:       "resource type ec2 selected and created before 2022-01-02T03:04:05Z, not excluded by config file config.yaml",
+	}, plan.Resources[0])
+	assert.Equal(t, "i-2", plan.Resources[1].Identifier)
+	assert.Equal(t, "eu-west-1", plan.Resources[2].Region)
+	assert.Equal(t, "vol-1", plan.Resources[2].Identifier)
+}
+
+func TestWriteAndReadPlan(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "plan.json")
+	plan := NewPlan(newTestPlanAccount([]string{"i-1"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1"}, []string{"ec2", "ebs"}, time.Now(), "", false)
+	plan.AccountID = "123456789012"
+	plan.Mode = NukeModeQuarantine
+	plan.Accounts = config.AccountRules{Allowed: []string{"sandbox"}, Blocked: []string{"123456789012"}}
+	plan.Limits = config.ResourceLimits{MaxResources: 10, PerResourceType: map[string]int{"rds": 2}}
+	require.NoError(t, WritePlan(plan, path))
+
+	readPlan, err := ReadPlan(path)
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", readPlan.AccountID)
+	assert.Equal(t, NukeModeQuarantine, readPlan.Mode)
+	assert.Equal(t, plan.Accounts, readPlan.Accounts)
+	assert.Equal(t, plan.Limits, readPlan.Limits)
+	assert.Equal(t, plan.Regions, readPlan.Regions)
+	assert.Equal(t, plan.ResourceTypes, readPlan.ResourceTypes)
+	assert.Equal(t, plan.Resources, readPlan.Resources)
+	assert.True(t, plan.CreatedAt.Equal(readPlan.CreatedAt))
+}
+
+func TestReadPlanErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := ReadPlan(filepath.Join(dir, "missing.json"))
+	assert.IsType(t, InvalidPlanFileError{}, errors.Unwrap(err))
+
+	garbagePath := filepath.Join(dir, "garbage.json")
+	require.NoError(t, os.WriteFile(garbagePath, []byte("not json"), 0644))
+	_, err = ReadPlan(garbagePath)
+	assert.IsType(t, InvalidPlanFileError{}, errors.Unwrap(err))
+
+	futurePath := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(futurePath, []byte(`{"version": 2, "regions": ["us-east-1"]}`), 0644))
+	_, err = ReadPlan(futurePath)
+	assert.Equal(t, UnsupportedPlanVersionError{Path: futurePath, Version: 2}, errors.Unwrap(err))
+}
+
+func TestPlanSelectResources(t *testing.T) {
+	t.Parallel()
+
+	plan := NewPlan(newTestPlanAccount([]string{"i-1", "i-2"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1"}, []string{"ec2", "ebs"}, time.Now(), "", false)
+
+	// Since the plan was written, i-2 was deleted and i-3 and vol-2 were created
+	discovered := newTestPlanAccount([]string{"i-1", "i-3"}, []string{"vol-1", "vol-2"})
+	selected, missing := plan.SelectResources(discovered)
+
+	require.Len(t, selected.Resources, 2)
+	assert.Equal(t, []string{"i-1"}, selected.Resources["us-east-1"].Resources[0].ResourceIdentifiers())
+	assert.Equal(t, "ec2", selected.Resources["us-east-1"].Resources[0].ResourceName())
+	assert.Equal(t, []string{"vol-1"}, selected.Resources["eu-west-1"].Resources[0].ResourceIdentifiers())
+	require.Len(t, missing, 1)
+	assert.Equal(t, "i-2", missing[0].Identifier)
+}
+
+func TestPlanSelectResourcesIgnoresOtherRegions(t *testing.T) {
+	t.Parallel()
+
+	plan := &Plan{
+		Version: PlanVersion,
+		Regions: []string{"us-east-1"},
+		Resources: []PlannedResource{
+			{Region: "us-east-1", ResourceType: "ebs", Identifier: "vol-1"},
+		},
+	}
+
+	// The same identifier in another region is a different resource
+	selected, missing := plan.SelectResources(newTestPlanAccount(nil, []string{"vol-1"}))
+	assert.Empty(t, selected.Resources)
+	assert.Len(t, missing, 1)
+}
@@ -0,0 +1,140 @@
+package aws
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+)
+
+const (
+	// RunIDTagKey is the tag put on the backups taken of preserved data. Its value is the ID of the run that took them.
+	RunIDTagKey = "cloud-nuke-run-id"
+	// PreservedFromTagKey is the tag holding the identifier of the resource a backup was taken of
+	PreservedFromTagKey = "cloud-nuke-preserved-from"
+
+	// preservedDataNamePrefix starts the name of every backup taken of preserved data, which tells them apart for the
+	// services whose backups cannot be tagged
+	preservedDataNamePrefix = "cloud-nuke-"
+	// maxPreservedDataNameLength is the maximum length of a backup name accepted by all the supported services
+	maxPreservedDataNameLength = 255
+)
+
+// DefaultPreservedDataRetention is how long the backups taken of preserved data are kept when the config file does not
+// set older_than for PreservedData. It keeps a recovery path open even if cloud-nuke runs again right away.
+const DefaultPreservedDataRetention = 30 * 24 * time.Hour
+
+// NewRunID returns a new ID for a nuke run, made of the current time and a random suffix. It only contains lowercase
+// letters, digits and single hyphens, so that it can be used in the names of backups.
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	// crypto/rand only fails without a source of randomness, in which case the time alone has to tell runs apart
+	_, _ = rand.Read(suffix)
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
+
+// DataPreservation selects the resource types whose data is backed up before they are deleted
+type DataPreservation struct {
+	// Enabled is true if data is preserved for every resource type that supports it, as with --preserve-data
+	Enabled bool `json:"enabled"`
+	// PerResourceType overrides Enabled for the resource types it holds, keyed by resource type name (e.g. rds)
+	PerResourceType map[string]bool `json:"perResourceType,omitempty"`
+}
+
+// NewDataPreservation returns the data preservation selected with --preserve-data (enabled) and the preserve_data
+// settings of the given config. Registrations sharing a name, such as RDS instances and clusters, preserve data if
+// either of them sets preserve_data to true.
+func NewDataPreservation(enabled bool, configObj config.Config) DataPreservation {
+	return DataPreservation{Enabled: enabled}.WithConfig(configObj)
+}
+
+// WithConfig returns a copy of the data preservation, overridden by the preserve_data settings of the given config for
+// the resource types they set
+func (preservation DataPreservation) WithConfig(configObj config.Config) DataPreservation {
+	fromConfig := map[string]bool{}
+	for _, registration := range registrations {
+		if registration.ConfigKey == "" {
+			continue
+		}
+		preserveData := configObj.GetResourceType(registration.ConfigKey).PreserveData
+		if preserveData == nil {
+			continue
+		}
+		fromConfig[registration.Name] = fromConfig[registration.Name] || *preserveData
+	}
+
+	overridden := DataPreservation{Enabled: preservation.Enabled, PerResourceType: map[string]bool{}}
+	for resourceType, enabled := range preservation.PerResourceType {
+		overridden.PerResourceType[resourceType] = enabled
+	}
+	for resourceType, enabled := range fromConfig {
+		overridden.PerResourceType[resourceType] = enabled
+	}
+	return overridden
+}
+
+// IsEnabled returns true if the data of the resources of the given type must be backed up before they are deleted
+func (preservation DataPreservation) IsEnabled(resourceType string) bool {
+	if enabled, ok := preservation.PerResourceType[resourceType]; ok {
+		return enabled
+	}
+	return preservation.Enabled
+}
+
+// DataPreservingResources is implemented by the resource types that can back up their data before being deleted. Their
+// registration must set SupportsDataPreservation.
+type DataPreservingResources interface {
+	AwsResources
+	// NukePreservingData deletes the resources with the given identifiers like Nuke, after backing up their data. The
+	// backups are named with preservedDataName, and tagged with preservedDataTags where the service allows it.
+	// Resources whose backup fails are recorded as failed and left alone.
+	NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error
+}
+
+// preservedDataName returns the name of the backup taken of the resource with the given identifier during the given
+// run. Names too long for every service to accept are truncated, and end with a hash of the full name so that the
+// resources whose identifiers only differ past the cut get different names.
+func preservedDataName(runID string, identifier string) string {
+	name := fmt.Sprintf("%s%s-%s", preservedDataNamePrefix, runID, identifier)
+	if len(name) > maxPreservedDataNameLength {
+		hash := sha256.Sum256([]byte(name))
+		suffix := "-" + hex.EncodeToString(hash[:])[:8]
+		// RDS rejects names with consecutive hyphens
+		name = strings.TrimRight(name[:maxPreservedDataNameLength-len(suffix)], "-") + suffix
+	}
+	// RDS and ElastiCache reject names ending with a hyphen
+	return strings.TrimRight(name, "-")
+}
+
+// isPreservedDuringRun returns true if the given tags of an existing backup show that it was taken of the resource with
+// the given identifier during the given run, as happens when an interrupted run is resumed
+func isPreservedDuringRun(tags map[string]string, runID string, identifier string) bool {
+	return tags[RunIDTagKey] == runID && tags[PreservedFromTagKey] == identifier
+}
+
+// preservedDataTags returns the tags to put on the backup taken of the resource with the given identifier during the
+// given run
+func preservedDataTags(runID string, identifier string) map[string]string {
+	return map[string]string{
+		RunIDTagKey:         runID,
+		PreservedFromTagKey: identifier,
+	}
+}
+
+// preservedDataExcludeAfter returns the cutoff time for the backups taken of preserved data. Unless the config file sets
+// older_than for PreservedData, backups are kept for at least DefaultPreservedDataRetention, whatever --older-than says.
+func preservedDataExcludeAfter(excludeAfter time.Time, configObj config.Config) time.Time {
+	if configObj.PreservedData.OlderThan != nil {
+		return excludeAfter
+	}
+	if retentionCutoff := time.Now().Add(-DefaultPreservedDataRetention); retentionCutoff.Before(excludeAfter) {
+		return retentionCutoff
+	}
+	return excludeAfter
+}
@@ -0,0 +1,143 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+// fakeDataPreservingResources records which identifiers were deleted, and under which run ID their data was preserved
+type fakeDataPreservingResources struct {
+	ids       []string
+	nuked     []string
+	preserved map[string]string
+}
+
+func (r *fakeDataPreservingResources) ResourceName() string          { return "fake" }
+func (r *fakeDataPreservingResources) ResourceIdentifiers() []string { return r.ids }
+func (r *fakeDataPreservingResources) MaxBatchSize() int             { return 10 }
+func (r *fakeDataPreservingResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	r.nuked = append(r.nuked, identifiers...)
+	return nil
+}
+func (r *fakeDataPreservingResources) NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error {
+	for _, identifier := range identifiers {
+		r.preserved[identifier] = runID
+	}
+	return r.Nuke(ctx, session, identifiers)
+}
+
+func TestNewRunID(t *testing.T) {
+	t.Parallel()
+
+	runID := NewRunID()
+	assert.Regexp(t, regexp.MustCompile(`^\d{8}-\d{6}-[0-9a-f]{6}$`), runID)
+	assert.NotEqual(t, runID, NewRunID())
+}
+
+func TestPreservedDataName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "cloud-nuke-run-1-my-db", preservedDataName("run-1", "my-db"))
+
+	// Names are truncated to the length accepted by every service, and end with a hash of the full name
+	name := preservedDataName("run-1", strings.Repeat("a", 237)+"-b")
+	assert.Len(t, name, maxPreservedDataNameLength)
+	assert.NotEqual(t, name, preservedDataName("run-1", strings.Repeat("a", 237)+"-c"))
+
+	// without ending with a hyphen, or holding consecutive hyphens when cut right after one
+	name = preservedDataName("run-1", strings.Repeat("a", 228)+"-"+strings.Repeat("b", 20))
+	assert.False(t, strings.HasSuffix(name, "-"))
+	assert.NotContains(t, name, "--")
+}
+
+func TestIsPreservedDuringRun(t *testing.T) {
+	t.Parallel()
+
+	tags := preservedDataTags("run-1", "my-db")
+	assert.True(t, isPreservedDuringRun(tags, "run-1", "my-db"))
+	assert.False(t, isPreservedDuringRun(tags, "run-2", "my-db"))
+	assert.False(t, isPreservedDuringRun(tags, "run-1", "other-db"))
+	assert.False(t, isPreservedDuringRun(map[string]string{}, "run-1", "my-db"))
+}
+
+func TestDataPreservationWithConfig(t *testing.T) {
+	t.Parallel()
+
+	preserve, skip := true, false
+	preservation := DataPreservation{Enabled: true, PerResourceType: map[string]bool{"ebs": true, "s3": true}}
+	overridden := preservation.WithConfig(config.Config{
+		EBSVolume:   config.ResourceType{PreserveData: &skip},
+		DBInstances: config.ResourceType{PreserveData: &preserve},
+	})
+
+	assert.Equal(t, map[string]bool{"ebs": false, "s3": true, "rds": true}, overridden.PerResourceType)
+	assert.True(t, overridden.Enabled)
+	// The data preservation it was made from is left untouched
+	assert.Equal(t, map[string]bool{"ebs": true, "s3": true}, preservation.PerResourceType)
+}
+
+func TestNewDataPreservation(t *testing.T) {
+	t.Parallel()
+
+	preserve, skip := true, false
+	configObj := config.Config{
+		DBInstances: config.ResourceType{PreserveData: &skip},
+		DBClusters:  config.ResourceType{PreserveData: &preserve},
+		EBSVolume:   config.ResourceType{PreserveData: &skip},
+	}
+
+	preservation := NewDataPreservation(true, configObj)
+	// RDS instances and clusters share a name, and preserve data if either of them asks for it
+	assert.True(t, preservation.IsEnabled("rds"))
+	assert.False(t, preservation.IsEnabled("ebs"))
+	assert.True(t, preservation.IsEnabled("dynamodb"))
+
+	preservation = NewDataPreservation(false, configObj)
+	assert.True(t, preservation.IsEnabled("rds"))
+	assert.False(t, preservation.IsEnabled("dynamodb"))
+}
+
+func TestPreservedDataExcludeAfter(t *testing.T) {
+	t.Parallel()
+
+	longAgo := time.Now().Add(-60 * 24 * time.Hour)
+	assert.Equal(t, longAgo, preservedDataExcludeAfter(longAgo, config.Config{}))
+	assert.WithinDuration(t, time.Now().Add(-DefaultPreservedDataRetention), preservedDataExcludeAfter(time.Now(), config.Config{}), time.Minute)
+
+	recently := time.Now().Add(-time.Hour)
+	configObj := config.Config{PreservedData: config.ResourceType{OlderThan: &config.Duration{Duration: time.Hour}}}
+	assert.Equal(t, recently, preservedDataExcludeAfter(recently, configObj))
+}
+
+func TestNukeBatchPreservesDataOfSelectedResourceTypes(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeDataPreservingResources{ids: []string{"a", "b"}, preserved: map[string]string{}}
+	selected := selectedResources{AwsResources: resources, identifiers: []string{"a", "b"}}
+
+	options := NukeOptions{PreserveData: DataPreservation{Enabled: true}, RunID: "run-1"}
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"a"}, options))
+	assert.Equal(t, map[string]string{"a": "run-1"}, resources.preserved)
+
+	options.PreserveData.PerResourceType = map[string]bool{"fake": false}
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"b"}, options))
+	assert.Equal(t, map[string]string{"a": "run-1"}, resources.preserved)
+	assert.Equal(t, []string{"a", "b"}, resources.nuked)
+}
+
+func TestNukePreservedDataRejectsUnknownIdentifiers(t *testing.T) {
+	t.Parallel()
+
+	identifier := "arn:aws:s3:::my-bucket"
+	assert.Equal(t, UnknownPreservedDataError{Identifier: identifier}, nukePreservedData(context.Background(), nil, identifier))
+}
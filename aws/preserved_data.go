@@ -0,0 +1,248 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elasticache"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// getAllPreservedData returns the backups taken of preserved data that are old enough to be deleted: the ARNs of RDS
+// snapshots, RDS cluster snapshots, DynamoDB backups and Elasticache snapshots, and the IDs of EBS snapshots
+func getAllPreservedData(awsSession *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	excludeAfter = preservedDataExcludeAfter(excludeAfter, configObj)
+
+	var identifiers []*string
+	for _, list := range []func(*session.Session, string, time.Time, config.Config) ([]*string, error){
+		getPreservedRdsSnapshots,
+		getPreservedRdsClusterSnapshots,
+		getPreservedDynamoDBBackups,
+		getPreservedElasticacheSnapshots,
+		getPreservedEbsSnapshots,
+	} {
+		found, err := list(awsSession, region, excludeAfter, configObj)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		identifiers = append(identifiers, found...)
+	}
+	return identifiers, nil
+}
+
+// shouldIncludePreservedData returns true if the backup with the given identifier, name, tags and creation time may be
+// deleted
+func shouldIncludePreservedData(region string, identifier string, name string, tags map[string]string, createdAt time.Time, excludeAfter time.Time, configObj config.Config) bool {
+	if excludedByTag(PreservedData{}.ResourceName(), region, identifier, tags) {
+		return false
+	}
+	if createdAt.After(excludeAfter) {
+		return false
+	}
+	return configObj.PreservedData.ShouldInclude(config.ResourceValue{
+		Name: name,
+		ID:   identifier,
+		Time: createdAt,
+	})
+}
+
+func getPreservedRdsSnapshots(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newRDSClient(session)
+
+	var arns []*string
+	err := svc.DescribeDBSnapshotsPages(&rds.DescribeDBSnapshotsInput{SnapshotType: aws.String("manual")}, func(page *rds.DescribeDBSnapshotsOutput, lastPage bool) bool {
+		for _, snapshot := range page.DBSnapshots {
+			tags := rdsTagsToMap(snapshot.TagList)
+			if _, ok := tags[RunIDTagKey]; !ok || aws.StringValue(snapshot.Status) != "available" {
+				continue
+			}
+			if shouldIncludePreservedData(region, aws.StringValue(snapshot.DBSnapshotArn), aws.StringValue(snapshot.DBSnapshotIdentifier), tags, aws.TimeValue(snapshot.SnapshotCreateTime), excludeAfter, configObj) {
+				arns = append(arns, snapshot.DBSnapshotArn)
+			}
+		}
+		return !lastPage
+	})
+	return arns, errors.WithStackTrace(err)
+}
+
+func getPreservedRdsClusterSnapshots(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newRDSClient(session)
+
+	var arns []*string
+	err := svc.DescribeDBClusterSnapshotsPages(&rds.DescribeDBClusterSnapshotsInput{SnapshotType: aws.String("manual")}, func(page *rds.DescribeDBClusterSnapshotsOutput, lastPage bool) bool {
+		for _, snapshot := range page.DBClusterSnapshots {
+			tags := rdsTagsToMap(snapshot.TagList)
+			if _, ok := tags[RunIDTagKey]; !ok || aws.StringValue(snapshot.Status) != "available" {
+				continue
+			}
+			if shouldIncludePreservedData(region, aws.StringValue(snapshot.DBClusterSnapshotArn), aws.StringValue(snapshot.DBClusterSnapshotIdentifier), tags, aws.TimeValue(snapshot.SnapshotCreateTime), excludeAfter, configObj) {
+				arns = append(arns, snapshot.DBClusterSnapshotArn)
+			}
+		}
+		return !lastPage
+	})
+	return arns, errors.WithStackTrace(err)
+}
+
+// getPreservedDynamoDBBackups looks for the backups by name, as DynamoDB backups cannot be tagged
+func getPreservedDynamoDBBackups(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newDynamoDBClient(session)
+
+	var arns []*string
+	err := paginate(func(token *string) (*string, error) {
+		output, err := svc.ListBackups(&dynamodb.ListBackupsInput{
+			BackupType:              aws.String(dynamodb.BackupTypeFilterUser),
+			ExclusiveStartBackupArn: token,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, backup := range output.BackupSummaries {
+			if !strings.HasPrefix(aws.StringValue(backup.BackupName), preservedDataNamePrefix) || aws.StringValue(backup.BackupStatus) != dynamodb.BackupStatusAvailable {
+				continue
+			}
+			if shouldIncludePreservedData(region, aws.StringValue(backup.BackupArn), aws.StringValue(backup.BackupName), nil, aws.TimeValue(backup.BackupCreationDateTime), excludeAfter, configObj) {
+				arns = append(arns, backup.BackupArn)
+			}
+		}
+		return output.LastEvaluatedBackupArn, nil
+	})
+	return arns, errors.WithStackTrace(err)
+}
+
+func getPreservedElasticacheSnapshots(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newElastiCacheClient(session)
+
+	var snapshots []*elasticache.Snapshot
+	err := svc.DescribeSnapshotsPages(&elasticache.DescribeSnapshotsInput{SnapshotSource: aws.String("manual")}, func(page *elasticache.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, snapshot := range page.Snapshots {
+			// Only the snapshots named like preserved data need their tags looked up
+			if strings.HasPrefix(aws.StringValue(snapshot.SnapshotName), preservedDataNamePrefix) && aws.StringValue(snapshot.SnapshotStatus) == "available" {
+				snapshots = append(snapshots, snapshot)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var arns []*string
+	for _, snapshot := range snapshots {
+		output, err := svc.ListTagsForResource(&elasticache.ListTagsForResourceInput{ResourceName: snapshot.ARN})
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		tags := map[string]string{}
+		for _, tag := range output.TagList {
+			tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+		}
+		if _, ok := tags[RunIDTagKey]; !ok {
+			continue
+		}
+		var createdAt time.Time
+		if len(snapshot.NodeSnapshots) > 0 {
+			createdAt = aws.TimeValue(snapshot.NodeSnapshots[0].SnapshotCreateTime)
+		}
+		if shouldIncludePreservedData(region, aws.StringValue(snapshot.ARN), aws.StringValue(snapshot.SnapshotName), tags, createdAt, excludeAfter, configObj) {
+			arns = append(arns, snapshot.ARN)
+		}
+	}
+	return arns, nil
+}
+
+func getPreservedEbsSnapshots(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
+
+	input := &ec2.DescribeSnapshotsInput{
+		OwnerIds: []*string{aws.String("self")},
+		Filters: []*ec2.Filter{
+			{Name: aws.String("tag-key"), Values: []*string{aws.String(RunIDTagKey)}},
+			{Name: aws.String("status"), Values: aws.StringSlice([]string{"completed", "error"})},
+		},
+	}
+	var snapshotIds []*string
+	err := svc.DescribeSnapshotsPages(input, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		for _, snapshot := range page.Snapshots {
+			tags := ec2TagsToMap(snapshot.Tags)
+			if shouldIncludePreservedData(region, aws.StringValue(snapshot.SnapshotId), tags["Name"], tags, aws.TimeValue(snapshot.StartTime), excludeAfter, configObj) {
+				snapshotIds = append(snapshotIds, snapshot.SnapshotId)
+			}
+		}
+		return !lastPage
+	})
+	return snapshotIds, errors.WithStackTrace(err)
+}
+
+// nukeAllPreservedData deletes the given backups taken of preserved data, as returned by getAllPreservedData
+func nukeAllPreservedData(ctx context.Context, session *session.Session, identifiers []*string) error {
+	if len(identifiers) == 0 {
+		logging.Logger.Debugf("No preserved data to nuke in region %s", *session.Config.Region)
+		return nil
+	}
+
+	logging.Logger.Debugf("Deleting all preserved data in region %s", *session.Config.Region)
+	var deleted int
+	for _, identifier := range identifiers {
+		err := nukePreservedData(ctx, session, aws.StringValue(identifier))
+
+		// Record status of this resource
+		record(ctx, report.Entry{
+			Identifier:   aws.StringValue(identifier),
+			ResourceType: "Preserved Data",
+			Error:        err,
+		})
+
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			deleted++
+			logging.Logger.Debugf("Deleted preserved data: %s", aws.StringValue(identifier))
+		}
+	}
+
+	logging.Logger.Debugf("[OK] %d preserved data backup(s) deleted in %s", deleted, *session.Config.Region)
+	return nil
+}
+
+// nukePreservedData deletes a single backup, using the service its identifier belongs to
+func nukePreservedData(ctx context.Context, session *session.Session, identifier string) error {
+	if strings.HasPrefix(identifier, "snap-") {
+		_, err := newEC2Client(session).DeleteSnapshotWithContext(ctx, &ec2.DeleteSnapshotInput{SnapshotId: aws.String(identifier)})
+		return errors.WithStackTrace(err)
+	}
+
+	parsed, err := arn.Parse(identifier)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	switch {
+	case parsed.Service == "rds" && strings.HasPrefix(parsed.Resource, "snapshot:"):
+		_, err = newRDSClient(session).DeleteDBSnapshotWithContext(ctx, &rds.DeleteDBSnapshotInput{
+			DBSnapshotIdentifier: aws.String(strings.TrimPrefix(parsed.Resource, "snapshot:")),
+		})
+	case parsed.Service == "rds" && strings.HasPrefix(parsed.Resource, "cluster-snapshot:"):
+		_, err = newRDSClient(session).DeleteDBClusterSnapshotWithContext(ctx, &rds.DeleteDBClusterSnapshotInput{
+			DBClusterSnapshotIdentifier: aws.String(strings.TrimPrefix(parsed.Resource, "cluster-snapshot:")),
+		})
+	case parsed.Service == "dynamodb":
+		_, err = newDynamoDBClient(session).DeleteBackupWithContext(ctx, &dynamodb.DeleteBackupInput{BackupArn: aws.String(identifier)})
+	case parsed.Service == "elasticache" && strings.HasPrefix(parsed.Resource, "snapshot:"):
+		_, err = newElastiCacheClient(session).DeleteSnapshotWithContext(ctx, &elasticache.DeleteSnapshotInput{
+			SnapshotName: aws.String(strings.TrimPrefix(parsed.Resource, "snapshot:")),
+		})
+	default:
+		return UnknownPreservedDataError{Identifier: identifier}
+	}
+	return errors.WithStackTrace(err)
+}
@@ -0,0 +1,59 @@
+package aws
+
+import (
+	"context"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// PreservedData - represents the backups taken of the data of resources deleted with --preserve-data
+type PreservedData struct {
+	Identifiers []string
+}
+
+// ResourceName - the simple name of the aws resource
+func (data PreservedData) ResourceName() string {
+	return "preserved-data"
+}
+
+// ResourceIdentifiers - The ARNs of the backups, or the snapshot IDs of EBS snapshots
+func (data PreservedData) ResourceIdentifiers() []string {
+	return data.Identifiers
+}
+
+func (data PreservedData) MaxBatchSize() int {
+	// Tentative batch size to ensure AWS doesn't throttle
+	return 49
+}
+
+// Nuke - nuke 'em all!!!
+func (data PreservedData) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllPreservedData(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+type UnknownPreservedDataError struct {
+	Identifier string
+}
+
+func (err UnknownPreservedDataError) Error() string {
+	return "Unknown kind of preserved data: " + err.Identifier
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            PreservedData{}.ResourceName(),
+		Description:     "Preserved Data",
+		ConfigKey:       "PreservedData",
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllPreservedData(session, params.Region, params.ExcludeAfter, params.Config)
+			return PreservedData{Identifiers: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
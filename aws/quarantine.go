@@ -0,0 +1,133 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+// QuarantineTagKey is the tag put on quarantined resources. Its value is the time at which the resource was first
+// quarantined, in RFC 3339 format.
+const QuarantineTagKey = "cloud-nuke-quarantined"
+
+// NukeMode selects what NukeAllResources does to the resources it is given
+type NukeMode string
+
+const (
+	// NukeModeDelete deletes the resources. It is the default mode.
+	NukeModeDelete NukeMode = "delete"
+	// NukeModeQuarantine makes the resources unusable in a reversible way instead of deleting them, e.g. by stopping
+	// EC2 instances or disabling KMS keys, and tags them with QuarantineTagKey
+	NukeModeQuarantine NukeMode = "quarantine"
+)
+
+// NukeModes lists the supported modes
+var NukeModes = []NukeMode{NukeModeDelete, NukeModeQuarantine}
+
+// ParseNukeMode returns the mode with the given name. An empty name is the delete mode.
+func ParseNukeMode(name string) (NukeMode, error) {
+	if name == "" {
+		return NukeModeDelete, nil
+	}
+	for _, mode := range NukeModes {
+		if string(mode) == name {
+			return mode, nil
+		}
+	}
+	return "", UnknownNukeModeError{Mode: name}
+}
+
+// QuarantinableResources is implemented by the resource types that can be quarantined instead of deleted. Their
+// registration must set SupportsQuarantine.
+type QuarantinableResources interface {
+	AwsResources
+	// Quarantine makes the resources with the given identifiers unusable in a way their owners can undo, and tags them
+	// with QuarantineTagKey. Resources that are already quarantined keep the time at which they were first quarantined.
+	// The outcome of every resource is recorded with recordQuarantine.
+	Quarantine(ctx context.Context, session *session.Session, identifiers []string) error
+}
+
+// QuarantinableResourceTypes returns the names of the resource types that can be quarantined, sorted by name
+func QuarantinableResourceTypes() []string {
+	resourceTypes := []string{}
+	for _, registration := range registrations {
+		if registration.SupportsQuarantine {
+			resourceTypes = append(resourceTypes, registration.Name)
+		}
+	}
+	sort.Strings(resourceTypes)
+	return resourceTypes
+}
+
+// SelectQuarantinableResourceTypes narrows the given resource types down to the ones that can be quarantined. If the
+// resource types were explicitly selected, e.g. with --resource-type, an error is returned instead for the first one
+// that cannot be quarantined.
+func SelectQuarantinableResourceTypes(resourceTypes []string, explicitlySelected bool) ([]string, error) {
+	quarantinable := QuarantinableResourceTypes()
+	selected := []string{}
+	for _, resourceType := range resourceTypes {
+		if IsValidResourceType(resourceType, quarantinable) {
+			selected = append(selected, resourceType)
+		} else if explicitlySelected {
+			return nil, QuarantineNotSupportedError{ResourceType: resourceType}
+		}
+	}
+	return selected, nil
+}
+
+// quarantineTime returns the time at which the resource with the given tags was quarantined. It returns false if the
+// resource was not quarantined, or if the value of its quarantine tag is not a valid time.
+func quarantineTime(tags map[string]string) (time.Time, bool) {
+	value, ok := tags[QuarantineTagKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	quarantinedAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return quarantinedAt, true
+}
+
+// quarantineTagValue returns the value of the quarantine tag to put on a resource with the given tags: the time at which
+// it was first quarantined, or the current time if it is not quarantined yet. Quarantining a resource again thus does
+// not restart its grace period.
+func quarantineTagValue(tags map[string]string) string {
+	if quarantinedAt, ok := quarantineTime(tags); ok {
+		return quarantinedAt.UTC().Format(time.RFC3339)
+	}
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// excludedByQuarantine returns true if the given tags show that the resource was quarantined after excludeAfter, in
+// which case it is recorded as excluded. The age of a quarantined resource is thus measured from the time it was
+// quarantined rather than from its creation, so that --older-than sets the grace period during which its owners can
+// rescue it. A quarantine tag whose value is not a valid time also excludes the resource, as it cannot tell how long the
+// resource has been quarantined. Listers of resource types that support quarantine must skip resources for which it
+// returns true.
+func excludedByQuarantine(resourceType string, region string, identifier string, tags map[string]string, excludeAfter time.Time) bool {
+	if _, ok := tags[QuarantineTagKey]; !ok {
+		return false
+	}
+	quarantinedAt, ok := quarantineTime(tags)
+	if ok && !quarantinedAt.After(excludeAfter) {
+		return false
+	}
+	recordExclusion(resourceType, region, identifier, QuarantinedRecentlyReason)
+	return true
+}
+
+// recordQuarantine records the outcome of quarantining a resource, along with the metadata that UndoQuarantine needs
+// to bring it back into service
+func recordQuarantine(ctx context.Context, resourceType string, identifier string, metadata map[string]string, err error) {
+	record(ctx, report.Entry{
+		Identifier:   identifier,
+		ResourceType: resourceType,
+		Error:        err,
+		Quarantined:  true,
+		Metadata:     metadata,
+	})
+}
@@ -0,0 +1,122 @@
+package aws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+// fakeQuarantinableResources records which identifiers were deleted and which were quarantined
+type fakeQuarantinableResources struct {
+	ids         []string
+	nuked       []string
+	quarantined []string
+}
+
+func (r *fakeQuarantinableResources) ResourceName() string          { return "fake" }
+func (r *fakeQuarantinableResources) ResourceIdentifiers() []string { return r.ids }
+func (r *fakeQuarantinableResources) MaxBatchSize() int             { return 10 }
+func (r *fakeQuarantinableResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	r.nuked = append(r.nuked, identifiers...)
+	return nil
+}
+func (r *fakeQuarantinableResources) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	r.quarantined = append(r.quarantined, identifiers...)
+	for _, identifier := range identifiers {
+		recordQuarantine(ctx, r.ResourceName(), identifier, nil, nil)
+	}
+	return nil
+}
+
+func TestParseNukeMode(t *testing.T) {
+	t.Parallel()
+
+	mode, err := ParseNukeMode("")
+	require.NoError(t, err)
+	assert.Equal(t, NukeModeDelete, mode)
+
+	mode, err = ParseNukeMode("quarantine")
+	require.NoError(t, err)
+	assert.Equal(t, NukeModeQuarantine, mode)
+
+	_, err = ParseNukeMode("stop")
+	assert.Equal(t, UnknownNukeModeError{Mode: "stop"}, err)
+}
+
+func TestSelectQuarantinableResourceTypes(t *testing.T) {
+	t.Parallel()
+
+	resourceTypes, err := SelectQuarantinableResourceTypes([]string{"ec2", "sqs", "kmscustomerkeys"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ec2", "kmscustomerkeys"}, resourceTypes)
+
+	_, err = SelectQuarantinableResourceTypes([]string{"ec2", "sqs"}, true)
+	assert.Equal(t, QuarantineNotSupportedError{ResourceType: "sqs"}, err)
+}
+
+func TestQuarantineTagValueKeepsFirstQuarantineTime(t *testing.T) {
+	t.Parallel()
+
+	firstQuarantinedAt := "2023-01-02T03:04:05Z"
+	assert.Equal(t, firstQuarantinedAt, quarantineTagValue(map[string]string{QuarantineTagKey: firstQuarantinedAt}))
+
+	value := quarantineTagValue(map[string]string{"team": "platform"})
+	quarantinedAt, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), quarantinedAt, time.Minute)
+}
+
+func TestExcludedByQuarantine(t *testing.T) {
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	excludeAfter := time.Now().Add(-24 * time.Hour)
+	quarantinedLongAgo := excludeAfter.Add(-time.Hour).UTC().Format(time.RFC3339)
+	quarantinedRecently := excludeAfter.Add(time.Hour).UTC().Format(time.RFC3339)
+
+	assert.False(t, excludedByQuarantine("ec2", "us-east-1", "i-untagged", map[string]string{}, excludeAfter))
+	assert.False(t, excludedByQuarantine("ec2", "us-east-1", "i-expired", map[string]string{QuarantineTagKey: quarantinedLongAgo}, excludeAfter))
+	assert.True(t, excludedByQuarantine("ec2", "us-east-1", "i-recent", map[string]string{QuarantineTagKey: quarantinedRecently}, excludeAfter))
+	assert.True(t, excludedByQuarantine("ec2", "us-east-1", "i-invalid", map[string]string{QuarantineTagKey: "yesterday"}, excludeAfter))
+
+	exclusions := report.GetExclusions()
+	require.Len(t, exclusions, 2)
+	assert.Equal(t, "i-invalid", exclusions[0].Identifier)
+	assert.Equal(t, "i-recent", exclusions[1].Identifier)
+	assert.Equal(t, QuarantinedRecentlyReason, exclusions[1].Reason)
+}
+
+func TestNukeBatchQuarantinesSelectedResources(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeQuarantinableResources{ids: []string{"a", "b"}}
+	selected := selectedResources{AwsResources: resources, identifiers: []string{"a"}}
+
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"a"}, NukeOptions{Mode: NukeModeQuarantine}))
+	assert.Equal(t, []string{"a"}, resources.quarantined)
+	assert.Empty(t, resources.nuked)
+	entry, _ := report.GetRecord("", "fake", "a")
+	assert.True(t, entry.Quarantined)
+
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"b"}, NukeOptions{}))
+	assert.Equal(t, []string{"b"}, resources.nuked)
+}
+
+func TestNukeBatchRecordsResourcesThatCannotBeQuarantined(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeFlakyResources{ids: []string{"a"}, attempts: map[string]int{}}
+	require.NoError(t, nukeBatch(context.Background(), resources, nil, []string{"a"}, NukeOptions{Mode: NukeModeQuarantine}))
+
+	assert.Empty(t, resources.attempts)
+	entry, _ := report.GetRecord("", "fake", "a")
+	assert.True(t, entry.Quarantined)
+	assert.Equal(t, QuarantineNotSupportedError{ResourceType: "fake"}, entry.Error)
+}
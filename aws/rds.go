@@ -1,31 +1,45 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
 func getAllRdsInstances(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := rds.New(session)
+	svc := newRDSClient(session)
 
-	result, err := svc.DescribeDBInstances(&rds.DescribeDBInstancesInput{})
+	var allDBInstances []*rds.DBInstance
+	err := svc.DescribeDBInstancesPages(&rds.DescribeDBInstancesInput{}, func(page *rds.DescribeDBInstancesOutput, lastPage bool) bool {
+		allDBInstances = append(allDBInstances, page.DBInstances...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var names []*string
 
-	for _, database := range result.DBInstances {
+	for _, database := range allDBInstances {
+		region := aws.StringValue(session.Config.Region)
+		tags := rdsTagsToMap(database.TagList)
+		if excludedByTag(DBInstances{}.ResourceName(), region, aws.StringValue(database.DBInstanceIdentifier), tags) ||
+			excludedByQuarantine(DBInstances{}.ResourceName(), region, aws.StringValue(database.DBInstanceIdentifier), tags, excludeAfter) {
+			continue
+		}
 		if shouldIncludeDbInstance(database, excludeAfter, configObj) {
 			names = append(names, database.DBInstanceIdentifier)
 		}
@@ -43,15 +57,16 @@ func shouldIncludeDbInstance(database *rds.DBInstance, excludeAfter time.Time, c
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(database.DBName),
-		configObj.DBInstances.IncludeRule.NamesRegExp,
-		configObj.DBInstances.ExcludeRule.NamesRegExp,
-	)
+	return configObj.DBInstances.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(database.DBName),
+		Tags: rdsTagsToMap(database.TagList),
+		ID:   aws.StringValue(database.DBInstanceIdentifier),
+		Time: aws.TimeValue(database.InstanceCreateTime),
+	})
 }
 
-func nukeAllRdsInstances(session *session.Session, names []*string) error {
-	svc := rds.New(session)
+func nukeAllRdsInstances(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newRDSClient(session)
 
 	if len(names) == 0 {
 		logging.Logger.Debugf("No RDS DB Instance to nuke in region %s", *session.Config.Region)
@@ -85,7 +100,7 @@ func nukeAllRdsInstances(session *session.Session, names []*string) error {
 	if len(deletedNames) > 0 {
 		for _, name := range deletedNames {
 
-			err := svc.WaitUntilDBInstanceDeleted(&rds.DescribeDBInstancesInput{
+			err := svc.WaitUntilDBInstanceDeletedWithContext(ctx, &rds.DescribeDBInstancesInput{
 				DBInstanceIdentifier: name,
 			})
 
@@ -95,7 +110,7 @@ func nukeAllRdsInstances(session *session.Session, names []*string) error {
 				ResourceType: "RDS Instance",
 				Error:        err,
 			}
-			report.Record(e)
+			record(ctx, e)
 
 			if err != nil {
 				telemetry.TrackEvent(commonTelemetry.EventContext{
@@ -112,3 +127,157 @@ func nukeAllRdsInstances(session *session.Session, names []*string) error {
 	logging.Logger.Debugf("[OK] %d RDS DB Instance(s) deleted in %s", len(deletedNames), *session.Config.Region)
 	return nil
 }
+
+// rdsTagsToMap converts the tags of an RDS resource into a map of tag keys to values, as used by config rules
+func rdsTagsToMap(tags []*rds.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+// quarantineRdsInstances tags the given RDS DB Instances with the quarantine tag and stops them, so that their owners
+// can start them again if they still need them. Note that AWS starts stopped DB Instances again after seven days.
+func quarantineRdsInstances(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newRDSClient(session)
+
+	for _, name := range names {
+		output, err := svc.DescribeDBInstancesWithContext(ctx, &rds.DescribeDBInstancesInput{DBInstanceIdentifier: name})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if len(output.DBInstances) == 0 {
+			continue
+		}
+
+		database := output.DBInstances[0]
+		metadata := map[string]string{
+			metadataArn:           aws.StringValue(database.DBInstanceArn),
+			metadataPreviousState: aws.StringValue(database.DBInstanceStatus),
+		}
+		err = quarantineRdsInstance(ctx, svc, database)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined RDS DB Instance: %s", aws.StringValue(name))
+		}
+		recordQuarantine(ctx, DBInstances{}.ResourceName(), aws.StringValue(name), metadata, err)
+	}
+	return nil
+}
+
+func quarantineRdsInstance(ctx context.Context, svc rdsiface.RDSAPI, database *rds.DBInstance) error {
+	_, err := svc.AddTagsToResourceWithContext(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: database.DBInstanceArn,
+		Tags:         []*rds.Tag{{Key: aws.String(QuarantineTagKey), Value: aws.String(quarantineTagValue(rdsTagsToMap(database.TagList)))}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if aws.StringValue(database.DBInstanceStatus) == "stopped" {
+		return nil
+	}
+	_, err = svc.StopDBInstanceWithContext(ctx, &rds.StopDBInstanceInput{DBInstanceIdentifier: database.DBInstanceIdentifier})
+	return errors.WithStackTrace(err)
+}
+
+// undoRdsQuarantine starts a quarantined RDS DB instance or cluster again, unless it was already stopped when it was
+// quarantined, and removes its quarantine tag. DB instances and clusters share the "rds" resource type, so they are
+// told apart by their ARN.
+func undoRdsQuarantine(session *session.Session, resource RunReportResource) error {
+	svc := newRDSClient(session)
+	arn := resource.Metadata[metadataArn]
+	if arn == "" {
+		return MissingUndoMetadataError{Identifier: resource.Identifier, Key: metadataArn}
+	}
+
+	if resource.Metadata[metadataPreviousState] != "stopped" {
+		var err error
+		if strings.Contains(arn, ":cluster:") {
+			_, err = svc.StartDBCluster(&rds.StartDBClusterInput{DBClusterIdentifier: aws.String(resource.Identifier)})
+		} else {
+			_, err = svc.StartDBInstance(&rds.StartDBInstanceInput{DBInstanceIdentifier: aws.String(resource.Identifier)})
+		}
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	_, err := svc.RemoveTagsFromResource(&rds.RemoveTagsFromResourceInput{
+		ResourceName: aws.String(arn),
+		TagKeys:      []*string{aws.String(QuarantineTagKey)},
+	})
+	return errors.WithStackTrace(err)
+}
+
+// preserveRdsInstances takes a snapshot of each of the given RDS DB Instances, tagged with the run ID, and returns the
+// names of the instances that are safe to delete. Members of an Aurora cluster have no data of their own, so they are
+// preserved by the snapshot of their cluster instead. Instances that could not be snapshotted are recorded as failed.
+func preserveRdsInstances(ctx context.Context, session *session.Session, names []*string, runID string) []*string {
+	svc := newRDSClient(session)
+
+	var preserved []*string
+	for _, name := range names {
+		err := preserveRdsInstance(ctx, svc, name, runID)
+		if err != nil {
+			logging.Logger.Errorf("[Failed] %s: %s", aws.StringValue(name), err)
+			record(ctx, report.Entry{
+				Identifier:   aws.StringValue(name),
+				ResourceType: "RDS Instance",
+				Error:        DataPreservationError{Identifier: aws.StringValue(name), Underlying: err},
+			})
+			continue
+		}
+		preserved = append(preserved, name)
+	}
+	return preserved
+}
+
+func preserveRdsInstance(ctx context.Context, svc rdsiface.RDSAPI, name *string, runID string) error {
+	output, err := svc.DescribeDBInstances(&rds.DescribeDBInstancesInput{DBInstanceIdentifier: name})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if len(output.DBInstances) == 0 || output.DBInstances[0].DBClusterIdentifier != nil {
+		return nil
+	}
+
+	snapshotName := aws.String(preservedDataName(runID, aws.StringValue(name)))
+	_, err = svc.CreateDBSnapshot(&rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: name,
+		DBSnapshotIdentifier: snapshotName,
+		Tags:                 rdsTags(preservedDataTags(runID, aws.StringValue(name))),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBSnapshotAlreadyExistsFault {
+		err = existingRdsSnapshotError(svc, snapshotName, runID, aws.StringValue(name), err)
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	logging.Logger.Debugf("Waiting for snapshot %s of RDS DB Instance %s", aws.StringValue(snapshotName), aws.StringValue(name))
+	err = svc.WaitUntilDBSnapshotAvailableWithContext(ctx, &rds.DescribeDBSnapshotsInput{DBSnapshotIdentifier: snapshotName})
+	return errors.WithStackTrace(err)
+}
+
+// existingRdsSnapshotError returns nil if the existing snapshot with the given name was taken of the RDS DB Instance
+// with the given name during the given run, which was then interrupted and resumed, or the given error otherwise
+func existingRdsSnapshotError(svc rdsiface.RDSAPI, snapshotName *string, runID string, name string, err error) error {
+	output, describeErr := svc.DescribeDBSnapshots(&rds.DescribeDBSnapshotsInput{DBSnapshotIdentifier: snapshotName})
+	if describeErr != nil || len(output.DBSnapshots) == 0 {
+		return err
+	}
+	if !isPreservedDuringRun(rdsTagsToMap(output.DBSnapshots[0].TagList), runID, name) {
+		return err
+	}
+	logging.Logger.Debugf("Snapshot %s of RDS DB Instance %s was already taken by run %s", aws.StringValue(snapshotName), name, runID)
+	return nil
+}
+
+// rdsTags converts a map of tag keys to values into RDS tags
+func rdsTags(tags map[string]string) []*rds.Tag {
+	var rdsTags []*rds.Tag
+	for key, value := range tags {
+		rdsTags = append(rdsTags, &rds.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return rdsTags
+}
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -10,12 +12,13 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
-func waitUntilRdsClusterDeleted(svc *rds.RDS, input *rds.DescribeDBClustersInput) error {
+func waitUntilRdsClusterDeleted(ctx context.Context, svc rdsiface.RDSAPI, input *rds.DescribeDBClustersInput) error {
 	// wait up to 15 minutes
 	for i := 0; i < 90; i++ {
 		_, err := svc.DescribeDBClusters(input)
@@ -27,25 +30,41 @@ func waitUntilRdsClusterDeleted(svc *rds.RDS, input *rds.DescribeDBClustersInput
 			return err
 		}
 
-		time.Sleep(10 * time.Second)
+		if err := sleepWithContext(ctx, 10*time.Second); err != nil {
+			return err
+		}
 		logging.Logger.Debug("Waiting for RDS Cluster to be deleted")
 	}
 
 	return RdsDeleteError{name: *input.DBClusterIdentifier}
 }
 
-func getAllRdsClusters(session *session.Session, excludeAfter time.Time) ([]*string, error) {
-	svc := rds.New(session)
+func getAllRdsClusters(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newRDSClient(session)
 
-	result, err := svc.DescribeDBClusters(&rds.DescribeDBClustersInput{})
+	var allDBClusters []*rds.DBCluster
+	err := svc.DescribeDBClustersPages(&rds.DescribeDBClustersInput{}, func(page *rds.DescribeDBClustersOutput, lastPage bool) bool {
+		allDBClusters = append(allDBClusters, page.DBClusters...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var names []*string
 
-	for _, database := range result.DBClusters {
-		if excludeAfter.After(*database.ClusterCreateTime) {
+	for _, database := range allDBClusters {
+		tags := rdsTagsToMap(database.TagList)
+		region := aws.StringValue(session.Config.Region)
+		if excludedByTag(DBClusters{}.ResourceName(), region, aws.StringValue(database.DBClusterIdentifier), tags) ||
+			excludedByQuarantine(DBClusters{}.ResourceName(), region, aws.StringValue(database.DBClusterIdentifier), tags, excludeAfter) {
+			continue
+		}
+		if excludeAfter.After(*database.ClusterCreateTime) && configObj.DBClusters.ShouldInclude(config.ResourceValue{
+			Name: aws.StringValue(database.DBClusterIdentifier),
+			Tags: tags,
+			Time: aws.TimeValue(database.ClusterCreateTime),
+		}) {
 			names = append(names, database.DBClusterIdentifier)
 		}
 	}
@@ -53,8 +72,8 @@ func getAllRdsClusters(session *session.Session, excludeAfter time.Time) ([]*str
 	return names, nil
 }
 
-func nukeAllRdsClusters(session *session.Session, names []*string) error {
-	svc := rds.New(session)
+func nukeAllRdsClusters(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newRDSClient(session)
 
 	if len(names) == 0 {
 		logging.Logger.Debugf("No RDS DB Cluster to nuke in region %s", *session.Config.Region)
@@ -78,7 +97,7 @@ func nukeAllRdsClusters(session *session.Session, names []*string) error {
 			ResourceType: "RDS Cluster",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s: %s", *name, err)
@@ -96,7 +115,7 @@ func nukeAllRdsClusters(session *session.Session, names []*string) error {
 	if len(deletedNames) > 0 {
 		for _, name := range deletedNames {
 
-			err := waitUntilRdsClusterDeleted(svc, &rds.DescribeDBClustersInput{
+			err := waitUntilRdsClusterDeleted(ctx, svc, &rds.DescribeDBClustersInput{
 				DBClusterIdentifier: name,
 			})
 			if err != nil {
@@ -109,3 +128,102 @@ func nukeAllRdsClusters(session *session.Session, names []*string) error {
 	logging.Logger.Debugf("[OK] %d RDS DB Cluster(s) nuked in %s", len(deletedNames), *session.Config.Region)
 	return nil
 }
+
+// quarantineRdsClusters tags the given RDS DB Clusters with the quarantine tag and stops them, so that their owners can
+// start them again if they still need them. Note that AWS starts stopped DB Clusters again after seven days.
+func quarantineRdsClusters(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newRDSClient(session)
+
+	for _, name := range names {
+		output, err := svc.DescribeDBClustersWithContext(ctx, &rds.DescribeDBClustersInput{DBClusterIdentifier: name})
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if len(output.DBClusters) == 0 {
+			continue
+		}
+
+		database := output.DBClusters[0]
+		metadata := map[string]string{
+			metadataArn:           aws.StringValue(database.DBClusterArn),
+			metadataPreviousState: aws.StringValue(database.Status),
+		}
+		err = quarantineRdsCluster(ctx, svc, database)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined RDS DB Cluster: %s", aws.StringValue(name))
+		}
+		recordQuarantine(ctx, DBClusters{}.ResourceName(), aws.StringValue(name), metadata, err)
+	}
+	return nil
+}
+
+func quarantineRdsCluster(ctx context.Context, svc rdsiface.RDSAPI, database *rds.DBCluster) error {
+	_, err := svc.AddTagsToResourceWithContext(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: database.DBClusterArn,
+		Tags:         []*rds.Tag{{Key: aws.String(QuarantineTagKey), Value: aws.String(quarantineTagValue(rdsTagsToMap(database.TagList)))}},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if aws.StringValue(database.Status) == "stopped" {
+		return nil
+	}
+	_, err = svc.StopDBClusterWithContext(ctx, &rds.StopDBClusterInput{DBClusterIdentifier: database.DBClusterIdentifier})
+	return errors.WithStackTrace(err)
+}
+
+// preserveRdsClusters takes a snapshot of each of the given RDS DB Clusters, tagged with the run ID, and returns the
+// names of the clusters that are safe to delete. Clusters that could not be snapshotted are recorded as failed.
+func preserveRdsClusters(ctx context.Context, session *session.Session, names []*string, runID string) []*string {
+	svc := newRDSClient(session)
+
+	var preserved []*string
+	for _, name := range names {
+		err := preserveRdsCluster(ctx, svc, name, runID)
+		if err != nil {
+			logging.Logger.Errorf("[Failed] %s: %s", aws.StringValue(name), err)
+			record(ctx, report.Entry{
+				Identifier:   aws.StringValue(name),
+				ResourceType: "RDS Cluster",
+				Error:        DataPreservationError{Identifier: aws.StringValue(name), Underlying: err},
+			})
+			continue
+		}
+		preserved = append(preserved, name)
+	}
+	return preserved
+}
+
+func preserveRdsCluster(ctx context.Context, svc rdsiface.RDSAPI, name *string, runID string) error {
+	snapshotName := aws.String(preservedDataName(runID, aws.StringValue(name)))
+	_, err := svc.CreateDBClusterSnapshot(&rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         name,
+		DBClusterSnapshotIdentifier: snapshotName,
+		Tags:                        rdsTags(preservedDataTags(runID, aws.StringValue(name))),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == rds.ErrCodeDBClusterSnapshotAlreadyExistsFault {
+		err = existingRdsClusterSnapshotError(svc, snapshotName, runID, aws.StringValue(name), err)
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	logging.Logger.Debugf("Waiting for snapshot %s of RDS DB Cluster %s", aws.StringValue(snapshotName), aws.StringValue(name))
+	err = svc.WaitUntilDBClusterSnapshotAvailableWithContext(ctx, &rds.DescribeDBClusterSnapshotsInput{DBClusterSnapshotIdentifier: snapshotName})
+	return errors.WithStackTrace(err)
+}
+
+// existingRdsClusterSnapshotError returns nil if the existing snapshot with the given name was taken of the RDS DB
+// Cluster with the given name during the given run, which was then interrupted and resumed, or the given error otherwise
+func existingRdsClusterSnapshotError(svc rdsiface.RDSAPI, snapshotName *string, runID string, name string, err error) error {
+	output, describeErr := svc.DescribeDBClusterSnapshots(&rds.DescribeDBClusterSnapshotsInput{DBClusterSnapshotIdentifier: snapshotName})
+	if describeErr != nil || len(output.DBClusterSnapshots) == 0 {
+		return err
+	}
+	if !isPreservedDuringRun(rdsTagsToMap(output.DBClusterSnapshots[0].TagList), runID, name) {
+		return err
+	}
+	logging.Logger.Debugf("Snapshot %s of RDS DB Cluster %s was already taken by run %s", aws.StringValue(snapshotName), name, runID)
+	return nil
+}
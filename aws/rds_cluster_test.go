@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
 	"testing"
@@ -49,14 +51,14 @@ func TestNukeRDSCluster(t *testing.T) {
 	createTestRDSCluster(t, session, rdsName)
 
 	defer func() {
-		nukeAllRdsClusters(session, []*string{&rdsName})
+		nukeAllRdsClusters(context.Background(), session, []*string{&rdsName})
 
-		rdsNames, _ := getAllRdsClusters(session, excludeAfter)
+		rdsNames, _ := getAllRdsClusters(session, excludeAfter, config.Config{})
 
 		assert.NotContains(t, awsgo.StringValueSlice(rdsNames), strings.ToLower(rdsName))
 	}()
 
-	rds, err := getAllRdsClusters(session, excludeAfter)
+	rds, err := getAllRdsClusters(session, excludeAfter, config.Config{})
 
 	if err != nil {
 		assert.Failf(t, "Unable to fetch list of RDS DB Clusters", errors.WithStackTrace(err).Error())
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -14,6 +16,11 @@ func (instance DBClusters) ResourceName() string {
 	return "rds"
 }
 
+// registrationKey - DBClusters share their name with DBInstances, so they are registered under another key
+func (instance DBClusters) registrationKey() string {
+	return "rds-cluster"
+}
+
 // ResourceIdentifiers - The instance names of the rds db instances
 func (instance DBClusters) ResourceIdentifiers() []string {
 	return instance.InstanceNames
@@ -25,10 +32,48 @@ func (instance DBClusters) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (instance DBClusters) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllRdsClusters(session, awsgo.StringSlice(identifiers)); err != nil {
+func (instance DBClusters) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllRdsClusters(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Quarantine - stop the RDS DB clusters, so that their owners can start them again
+func (instance DBClusters) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineRdsClusters(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+// NukePreservingData - take a snapshot of the RDS DB clusters, then nuke the ones that were snapshotted
+func (instance DBClusters) NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error {
+	preserved := preserveRdsClusters(ctx, session, awsgo.StringSlice(identifiers), runID)
+	if err := nukeAllRdsClusters(ctx, session, preserved); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                     DBClusters{}.ResourceName(),
+		Key:                      DBClusters{}.registrationKey(),
+		Description:              "RDS Clusters",
+		ConfigKey:                "DBClusters",
+		SupportsTags:             true,
+		SupportsRuleAge:          true,
+		SupportsQuarantine:       true,
+		UndoQuarantine:           undoRdsQuarantine,
+		SupportsDataPreservation: true,
+		DependsOn:                []string{"rds"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllRdsClusters(session, params.ExcludeAfter, params.Config)
+			return DBClusters{InstanceNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"strings"
@@ -81,7 +82,7 @@ func TestNukeRDSInstance(t *testing.T) {
 	createTestRDSInstance(t, session, rdsName)
 
 	defer func() {
-		nukeAllRdsInstances(session, []*string{&rdsName})
+		nukeAllRdsInstances(context.Background(), session, []*string{&rdsName})
 
 		rdsNames, _ := getAllRdsInstances(session, excludeAfter, config.Config{})
 
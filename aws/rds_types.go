@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -25,8 +27,8 @@ func (instance DBInstances) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (instance DBInstances) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllRdsInstances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (instance DBInstances) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllRdsInstances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -40,3 +42,39 @@ type RdsDeleteError struct {
 func (e RdsDeleteError) Error() string {
 	return "RDS DB Instance:" + e.name + "was not deleted"
 }
+
+// Quarantine - stop the RDS DB instances, so that their owners can start them again
+func (instance DBInstances) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineRdsInstances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// NukePreservingData - take a snapshot of the RDS DB instances, then nuke the ones that were snapshotted
+func (instance DBInstances) NukePreservingData(ctx context.Context, session *session.Session, identifiers []string, runID string) error {
+	preserved := preserveRdsInstances(ctx, session, awsgo.StringSlice(identifiers), runID)
+	if err := nukeAllRdsInstances(ctx, session, preserved); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                     DBInstances{}.ResourceName(),
+		Description:              "RDS Instances",
+		ConfigKey:                "DBInstances",
+		SupportsTags:             true,
+		SupportsRuleAge:          true,
+		SupportsQuarantine:       true,
+		UndoQuarantine:           undoRdsQuarantine,
+		SupportsDataPreservation: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllRdsInstances(session, params.ExcludeAfter, params.Config)
+			return DBInstances{InstanceNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -0,0 +1,97 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/rds"
+	"github.com/aws/aws-sdk-go/service/rds/rdsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRDSSnapshots is an in-process RDS backend holding the snapshots of DB instances and clusters in memory. Only the
+// snapshot operations used by cloud-nuke are implemented, calling any other operation panics.
+type fakeRDSSnapshots struct {
+	rdsiface.RDSAPI
+
+	snapshots        map[string][]*rds.Tag
+	clusterSnapshots map[string][]*rds.Tag
+}
+
+func (fake *fakeRDSSnapshots) DescribeDBInstances(input *rds.DescribeDBInstancesInput) (*rds.DescribeDBInstancesOutput, error) {
+	return &rds.DescribeDBInstancesOutput{DBInstances: []*rds.DBInstance{{DBInstanceIdentifier: input.DBInstanceIdentifier}}}, nil
+}
+
+func (fake *fakeRDSSnapshots) CreateDBSnapshot(input *rds.CreateDBSnapshotInput) (*rds.CreateDBSnapshotOutput, error) {
+	if _, ok := fake.snapshots[awsgo.StringValue(input.DBSnapshotIdentifier)]; ok {
+		return nil, awserr.New(rds.ErrCodeDBSnapshotAlreadyExistsFault, "The snapshot already exists", nil)
+	}
+	fake.snapshots[awsgo.StringValue(input.DBSnapshotIdentifier)] = input.Tags
+	return &rds.CreateDBSnapshotOutput{}, nil
+}
+
+func (fake *fakeRDSSnapshots) DescribeDBSnapshots(input *rds.DescribeDBSnapshotsInput) (*rds.DescribeDBSnapshotsOutput, error) {
+	tags, ok := fake.snapshots[awsgo.StringValue(input.DBSnapshotIdentifier)]
+	if !ok {
+		return &rds.DescribeDBSnapshotsOutput{}, nil
+	}
+	return &rds.DescribeDBSnapshotsOutput{DBSnapshots: []*rds.DBSnapshot{{DBSnapshotIdentifier: input.DBSnapshotIdentifier, TagList: tags}}}, nil
+}
+
+func (fake *fakeRDSSnapshots) WaitUntilDBSnapshotAvailableWithContext(ctx awsgo.Context, input *rds.DescribeDBSnapshotsInput, opts ...request.WaiterOption) error {
+	return nil
+}
+
+func (fake *fakeRDSSnapshots) CreateDBClusterSnapshot(input *rds.CreateDBClusterSnapshotInput) (*rds.CreateDBClusterSnapshotOutput, error) {
+	if _, ok := fake.clusterSnapshots[awsgo.StringValue(input.DBClusterSnapshotIdentifier)]; ok {
+		return nil, awserr.New(rds.ErrCodeDBClusterSnapshotAlreadyExistsFault, "The snapshot already exists", nil)
+	}
+	fake.clusterSnapshots[awsgo.StringValue(input.DBClusterSnapshotIdentifier)] = input.Tags
+	return &rds.CreateDBClusterSnapshotOutput{}, nil
+}
+
+func (fake *fakeRDSSnapshots) DescribeDBClusterSnapshots(input *rds.DescribeDBClusterSnapshotsInput) (*rds.DescribeDBClusterSnapshotsOutput, error) {
+	tags, ok := fake.clusterSnapshots[awsgo.StringValue(input.DBClusterSnapshotIdentifier)]
+	if !ok {
+		return &rds.DescribeDBClusterSnapshotsOutput{}, nil
+	}
+	return &rds.DescribeDBClusterSnapshotsOutput{DBClusterSnapshots: []*rds.DBClusterSnapshot{{DBClusterSnapshotIdentifier: input.DBClusterSnapshotIdentifier, TagList: tags}}}, nil
+}
+
+func (fake *fakeRDSSnapshots) WaitUntilDBClusterSnapshotAvailableWithContext(ctx awsgo.Context, input *rds.DescribeDBClusterSnapshotsInput, opts ...request.WaiterOption) error {
+	return nil
+}
+
+func TestPreserveRdsInstanceWhenResumedOffline(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeRDSSnapshots{snapshots: map[string][]*rds.Tag{}}
+	require.NoError(t, preserveRdsInstance(context.Background(), fake, awsgo.String("my-db"), "run-1"))
+
+	// The snapshot taken before the run was interrupted counts as preserved when it is resumed
+	assert.NoError(t, preserveRdsInstance(context.Background(), fake, awsgo.String("my-db"), "run-1"))
+
+	// but a snapshot with the same name taken by another run does not
+	fake.snapshots[preservedDataName("run-2", "my-db")] = rdsTags(preservedDataTags("run-1", "my-db"))
+	err := preserveRdsInstance(context.Background(), fake, awsgo.String("my-db"), "run-2")
+	require.Error(t, err)
+}
+
+func TestPreserveRdsClusterWhenResumedOffline(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeRDSSnapshots{clusterSnapshots: map[string][]*rds.Tag{}}
+	require.NoError(t, preserveRdsCluster(context.Background(), fake, awsgo.String("my-cluster"), "run-1"))
+
+	// The snapshot taken before the run was interrupted counts as preserved when it is resumed
+	assert.NoError(t, preserveRdsCluster(context.Background(), fake, awsgo.String("my-cluster"), "run-1"))
+
+	// but a snapshot with the same name taken by another run does not
+	fake.clusterSnapshots[preservedDataName("run-2", "my-cluster")] = rdsTags(preservedDataTags("run-1", "my-cluster"))
+	err := preserveRdsCluster(context.Background(), fake, awsgo.String("my-cluster"), "run-2")
+	require.Error(t, err)
+}
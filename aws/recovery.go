@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+)
+
+const (
+	// MinRecoveryWindowDays and MaxRecoveryWindowDays bound the recovery windows accepted by KMS and Secrets Manager
+	MinRecoveryWindowDays = 7
+	MaxRecoveryWindowDays = 30
+
+	// DeletedAliasesTagKey is the tag put on the KMS keys scheduled for deletion, holding the space-separated aliases
+	// that were deleted along with them, so that restoring the key recreates them
+	DeletedAliasesTagKey = "cloud-nuke-deleted-aliases"
+)
+
+// RecoveryWindows holds the number of days during which the resources of each type that supports it can be restored
+// after being deleted, keyed by resource type name. 0 means deleting them without recovery.
+type RecoveryWindows map[string]int
+
+// DefaultRecoveryWindows returns the recovery windows used unless the config file or flags set others: KMS keys are
+// deleted after the shortest pending window, and Secrets Manager secrets are deleted without recovery.
+func DefaultRecoveryWindows() RecoveryWindows {
+	return RecoveryWindows{
+		KmsCustomerKeys{}.ResourceName():       MinRecoveryWindowDays,
+		SecretsManagerSecrets{}.ResourceName(): 0,
+	}
+}
+
+// NewRecoveryWindows returns the default recovery windows, overridden by the recovery_window_days settings of the given
+// config
+func NewRecoveryWindows(configObj config.Config) RecoveryWindows {
+	return DefaultRecoveryWindows().WithConfig(configObj)
+}
+
+// WithConfig returns a copy of the recovery windows, overridden by the recovery_window_days settings of the given config
+func (windows RecoveryWindows) WithConfig(configObj config.Config) RecoveryWindows {
+	overridden := RecoveryWindows{}
+	for resourceType, days := range windows {
+		overridden[resourceType] = days
+	}
+	for _, registration := range registrations {
+		if !registration.SupportsRecoveryWindow {
+			continue
+		}
+		if days := configObj.GetResourceType(registration.ConfigKey).RecoveryWindowDays; days != nil {
+			overridden[registration.Name] = *days
+		}
+	}
+	return overridden
+}
+
+// Days returns the recovery window of the given resource type, or its default one if it is not set
+func (windows RecoveryWindows) Days(resourceType string) int {
+	if days, ok := windows[resourceType]; ok {
+		return days
+	}
+	return DefaultRecoveryWindows()[resourceType]
+}
+
+// ValidateRecoveryWindow returns an InvalidRecoveryWindowError if the given number of days is not a valid recovery
+// window for the given resource type. Only Secrets Manager secrets can be deleted without recovery.
+func ValidateRecoveryWindow(resourceType string, days int) error {
+	if days == 0 && resourceType == (SecretsManagerSecrets{}).ResourceName() {
+		return nil
+	}
+	if days < MinRecoveryWindowDays || days > MaxRecoveryWindowDays {
+		return InvalidRecoveryWindowError{ResourceType: resourceType, Days: days}
+	}
+	return nil
+}
+
+// RecoverableResources is implemented by the resource types whose deletion is scheduled at the end of a recovery
+// window, during which 'cloud-nuke aws restore' can restore them. Their registration must set SupportsRecoveryWindow.
+type RecoverableResources interface {
+	AwsResources
+	// NukeRecoverably deletes the resources with the given identifiers like Nuke, but keeps them recoverable for the
+	// given number of days. Unless runID is empty, the resources are tagged with RunIDTagKey so that the run can be
+	// restored.
+	NukeRecoverably(ctx context.Context, session *session.Session, identifiers []string, recoveryWindowDays int, runID string) error
+}
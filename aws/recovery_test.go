@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+// fakeRecoverableResources records the recovery window and run ID with which identifiers were deleted
+type fakeRecoverableResources struct {
+	ids                []string
+	recoveryWindowDays map[string]int
+	runIDs             map[string]string
+}
+
+func (r *fakeRecoverableResources) ResourceName() string          { return "kmscustomerkeys" }
+func (r *fakeRecoverableResources) ResourceIdentifiers() []string { return r.ids }
+func (r *fakeRecoverableResources) MaxBatchSize() int             { return 10 }
+func (r *fakeRecoverableResources) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	return r.NukeRecoverably(ctx, session, identifiers, 0, "")
+}
+func (r *fakeRecoverableResources) NukeRecoverably(ctx context.Context, session *session.Session, identifiers []string, recoveryWindowDays int, runID string) error {
+	for _, identifier := range identifiers {
+		r.recoveryWindowDays[identifier] = recoveryWindowDays
+		r.runIDs[identifier] = runID
+	}
+	return nil
+}
+
+func TestNewRecoveryWindows(t *testing.T) {
+	t.Parallel()
+
+	windows := NewRecoveryWindows(config.Config{})
+	assert.Equal(t, MinRecoveryWindowDays, windows.Days("kmscustomerkeys"))
+	assert.Equal(t, 0, windows.Days("secretsmanager"))
+
+	days := 14
+	windows = NewRecoveryWindows(config.Config{SecretsManagerSecrets: config.ResourceType{RecoveryWindowDays: &days}})
+	assert.Equal(t, MinRecoveryWindowDays, windows.Days("kmscustomerkeys"))
+	assert.Equal(t, 14, windows.Days("secretsmanager"))
+
+	// Resource types missing from the windows use their default one
+	assert.Equal(t, MinRecoveryWindowDays, RecoveryWindows(nil).Days("kmscustomerkeys"))
+}
+
+func TestRecoveryWindowsWithConfig(t *testing.T) {
+	t.Parallel()
+
+	days := 14
+	windows := RecoveryWindows{"kmscustomerkeys": 30}
+	overridden := windows.WithConfig(config.Config{SecretsManagerSecrets: config.ResourceType{RecoveryWindowDays: &days}})
+
+	assert.Equal(t, RecoveryWindows{"kmscustomerkeys": 30, "secretsmanager": 14}, overridden)
+	// The windows they were made from are left untouched
+	assert.Equal(t, RecoveryWindows{"kmscustomerkeys": 30}, windows)
+}
+
+func TestValidateRecoveryWindow(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateRecoveryWindow("kmscustomerkeys", 7))
+	assert.NoError(t, ValidateRecoveryWindow("kmscustomerkeys", 30))
+	assert.NoError(t, ValidateRecoveryWindow("secretsmanager", 0))
+	assert.Equal(t, InvalidRecoveryWindowError{ResourceType: "kmscustomerkeys", Days: 0}, ValidateRecoveryWindow("kmscustomerkeys", 0))
+	assert.Equal(t, InvalidRecoveryWindowError{ResourceType: "secretsmanager", Days: 6}, ValidateRecoveryWindow("secretsmanager", 6))
+	assert.Equal(t, InvalidRecoveryWindowError{ResourceType: "secretsmanager", Days: 31}, ValidateRecoveryWindow("secretsmanager", 31))
+}
+
+func TestNukeBatchKeepsResourcesRecoverable(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	resources := &fakeRecoverableResources{ids: []string{"a", "b"}, recoveryWindowDays: map[string]int{}, runIDs: map[string]string{}}
+	selected := selectedResources{AwsResources: resources, identifiers: []string{"a", "b"}}
+
+	options := NukeOptions{RecoveryWindows: RecoveryWindows{"kmscustomerkeys": 30}, RunID: "run-1"}
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"a"}, options))
+	require.NoError(t, nukeBatch(context.Background(), selected, nil, []string{"b"}, NukeOptions{RunID: "run-2"}))
+
+	assert.Equal(t, map[string]int{"a": 30, "b": MinRecoveryWindowDays}, resources.recoveryWindowDays)
+	assert.Equal(t, map[string]string{"a": "run-1", "b": "run-2"}, resources.runIDs)
+}
+
+func TestDeletedAliasesTagValue(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "", deletedAliasesTagValue(nil))
+	assert.Equal(t, "alias/app alias/app-old", deletedAliasesTagValue([]string{"alias/app", "alias/app-old"}))
+
+	// Aliases that do not fit in a tag value are left out
+	long := "alias/" + strings.Repeat("a", 250)
+	assert.Equal(t, "alias/app", deletedAliasesTagValue([]string{"alias/app", long}))
+}
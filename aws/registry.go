@@ -0,0 +1,243 @@
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/go-commons/collections"
+)
+
+// ListParams holds everything a ResourceLister may need to discover the resources of its type in a single region
+type ListParams struct {
+	// Region is the region being scanned. Listers of global resource types receive GlobalRegion.
+	Region string
+	// TargetRegions is the full list of regions targeted by this run
+	TargetRegions []string
+	// ExcludeAfter is the cutoff time; resources created after it must not be returned
+	ExcludeAfter time.Time
+	// Config holds the include/exclude rules read from the optional config file
+	Config config.Config
+	// AllowDeleteUnaliasedKeys is true when KMS keys without aliases may be targeted
+	AllowDeleteUnaliasedKeys bool
+
+	// cache is shared by all listers across all regions of a single GetAllResources call, for resource types whose
+	// listing APIs return results for every region at once (e.g. S3)
+	cache *listCache
+}
+
+// listCache holds the per-region results of listing APIs that return the resources of every region at once. As regions
+// are scanned concurrently, listers must hold the lock while looking up and filling the cache, so that the listing is
+// only done once.
+type listCache struct {
+	sync.Mutex
+	resourcesPerRegion map[string]map[string][]*string
+}
+
+func newListCache() *listCache {
+	return &listCache{resourcesPerRegion: map[string]map[string][]*string{}}
+}
+
+// ResourceLister discovers the resources of a single type. It returns an AwsResources value holding the identifiers
+// that are eligible for nuking; partial results may be returned alongside an error.
+type ResourceLister func(session *session.Session, params ListParams) (AwsResources, error)
+
+// ResourceRegistration describes a resource type that cloud-nuke knows how to discover and nuke
+type ResourceRegistration struct {
+	// Name is the resource type name accepted by --resource-type, and must match AwsResources.ResourceName. Several
+	// registrations may share a name: RDS instances and Aurora clusters are both selected with "rds".
+	Name string
+	// Key uniquely identifies the registration. It defaults to Name, so it only needs to be set when the name is
+	// shared with another registration.
+	Key string
+	// Description is a human readable, plural description of the resource type (e.g. "EC2 Instances"), used in
+	// telemetry and error reporting
+	Description string
+	// Global is true for resource types that do not belong to a region, such as IAM users
+	Global bool
+	// ConfigKey is the key under which the resource type's include/exclude rules are defined in the config file, or
+	// empty if the resource type does not support config rules
+	ConfigKey string
+	// SupportsTags is true if the lister evaluates the tag matchers of the config rules against the tags of each
+	// resource. Config files defining tag rules for resource types that do not support them are rejected.
+	SupportsTags bool
+	// SupportsRuleAge is true if the lister passes the creation time of each resource to the config rules, so that
+	// include and exclude rules can set their own older_than. Config files setting it for other resource types are
+	// rejected.
+	SupportsRuleAge bool
+	// SupportsQuarantine is true if the AwsResources value returned by the lister implements QuarantinableResources,
+	// and the lister skips resources for which excludedByQuarantine returns true
+	SupportsQuarantine bool
+	// SupportsDataPreservation is true if the AwsResources value returned by the lister implements
+	// DataPreservingResources. Config files setting preserve_data for other resource types are rejected.
+	SupportsDataPreservation bool
+	// SupportsRecoveryWindow is true if the AwsResources value returned by the lister implements RecoverableResources.
+	// Config files setting recovery_window_days for other resource types are rejected.
+	SupportsRecoveryWindow bool
+	// UndoQuarantine, if set, brings a resource of this type that a run quarantined back into service, for
+	// 'cloud-nuke aws undo'. Resource types that support quarantine record what it needs in the metadata of the outcome.
+	UndoQuarantine UndoFunc
+	// UndoDeletion, if set, restores a resource of this type that a run deleted while keeping it recoverable, for
+	// 'cloud-nuke aws undo'. It is only called during the recovery window recorded in the metadata of the outcome.
+	UndoDeletion UndoFunc
+	// DependsOn lists the keys of the resource types that must be nuked before this one, typically because their
+	// resources use resources of this type. For example, EBS volumes depend on EC2 instances, as a volume cannot be
+	// deleted while it is attached to an instance.
+	DependsOn []string
+	// List discovers the resources of this type. The returned AwsResources value is used to nuke them.
+	List ResourceLister
+}
+
+// key returns the unique key of the registration
+func (r ResourceRegistration) key() string {
+	if r.Key != "" {
+		return r.Key
+	}
+	return r.Name
+}
+
+// keyedResources is implemented by the AwsResources of resource types registered with a Key other than their name
+type keyedResources interface {
+	registrationKey() string
+}
+
+// registrationKeyOf returns the key of the registration of the given resources
+func registrationKeyOf(resources AwsResources) string {
+	if keyed, ok := unwrapSelectedResources(resources).(keyedResources); ok {
+		return keyed.registrationKey()
+	}
+	return resources.ResourceName()
+}
+
+var registrations = map[string]ResourceRegistration{}
+
+// RegisterResourceType makes a resource type available to cloud-nuke. Resource types register themselves from an
+// init function in the file that defines them, so adding a new resource type does not require touching the discovery
+// or nuke logic. It panics if the registration is invalid or if its key is already taken.
+func RegisterResourceType(registration ResourceRegistration) {
+	if registration.Name == "" || registration.List == nil {
+		panic(fmt.Sprintf("invalid registration for resource type %q: name and lister are required", registration.Name))
+	}
+	if _, exists := registrations[registration.key()]; exists {
+		panic(fmt.Sprintf("resource type %q is registered more than once", registration.key()))
+	}
+	registrations[registration.key()] = registration
+}
+
+// GetResourceRegistrations returns all registered resource types, in the order in which they should be nuked. An
+// error is returned if the dependencies declared by the resource types are invalid.
+func GetResourceRegistrations() ([]ResourceRegistration, error) {
+	all := make([]ResourceRegistration, 0, len(registrations))
+	for _, registration := range registrations {
+		all = append(all, registration)
+	}
+	return sortByDependencies(all)
+}
+
+// validateConfig returns an error if the config defines rules that the given registrations cannot evaluate, or limits
+// for resource types that are not registered
+func validateConfig(registrations []ResourceRegistration, configObj config.Config) error {
+	for _, registration := range registrations {
+		if registration.ConfigKey == "" {
+			continue
+		}
+		resourceType := configObj.GetResourceType(registration.ConfigKey)
+		if !registration.SupportsTags && resourceType.HasTagRules() {
+			return TagRulesNotSupportedError{ConfigKey: registration.ConfigKey}
+		}
+		if !registration.SupportsRuleAge && resourceType.HasRuleAge() {
+			return RuleAgeNotSupportedError{ConfigKey: registration.ConfigKey}
+		}
+		if !registration.SupportsDataPreservation && resourceType.PreserveData != nil {
+			return DataPreservationNotSupportedError{ConfigKey: registration.ConfigKey}
+		}
+		if resourceType.RecoveryWindowDays != nil {
+			if !registration.SupportsRecoveryWindow {
+				return RecoveryWindowNotSupportedError{ConfigKey: registration.ConfigKey}
+			}
+			if err := ValidateRecoveryWindow(registration.Name, *resourceType.RecoveryWindowDays); err != nil {
+				return err
+			}
+		}
+	}
+
+	for resourceType := range configObj.Limits.PerResourceType {
+		known := false
+		for _, registration := range registrations {
+			known = known || registration.Name == resourceType
+		}
+		if !known {
+			return UnknownResourceTypeLimitError{ResourceType: resourceType}
+		}
+	}
+	return nil
+}
+
+// withResourceTypeAge returns the params to pass to the lister of the given registration, in which the cutoff time is
+// taken from the older_than setting of its type in the config file, if any, instead of --older-than
+func withResourceTypeAge(params ListParams, registration ResourceRegistration) ListParams {
+	if registration.ConfigKey == "" {
+		return params
+	}
+	if olderThan := params.Config.GetResourceType(registration.ConfigKey).OlderThan; olderThan != nil {
+		params.ExcludeAfter = time.Now().Add(-olderThan.Duration)
+	}
+	return params
+}
+
+// getRegistrationsForRegion filters the given registrations down to the selected resource types that should be scanned
+// in the given region, preserving their order
+func getRegistrationsForRegion(registrations []ResourceRegistration, region string, resourceTypes []string) []ResourceRegistration {
+	var selected []ResourceRegistration
+	for _, registration := range registrations {
+		if registration.Global != (region == GlobalRegion) {
+			continue
+		}
+		if !IsNukeable(registration.Name, resourceTypes) {
+			continue
+		}
+		selected = append(selected, registration)
+	}
+	return selected
+}
+
+// ListResourceTypes - Returns list of resources which can be passed to --resource-type
+func ListResourceTypes() []string {
+	resourceTypes := []string{}
+	for _, registration := range registrations {
+		if !collections.ListContainsElement(resourceTypes, registration.Name) {
+			resourceTypes = append(resourceTypes, registration.Name)
+		}
+	}
+	sort.Strings(resourceTypes)
+	return resourceTypes
+}
+
+// GetNukeOrder returns a human readable description of the order in which the selected resource types are nuked, one
+// line per resource type, including the resource types each of them waits for
+func GetNukeOrder(resourceTypes []string) ([]string, error) {
+	registrations, err := GetResourceRegistrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, registration := range registrations {
+		if !IsNukeable(registration.Name, resourceTypes) {
+			continue
+		}
+		line := fmt.Sprintf("%d. %s", len(lines)+1, registration.key())
+		if registration.Global {
+			line = fmt.Sprintf("%s [global]", line)
+		}
+		if len(registration.DependsOn) > 0 {
+			line = fmt.Sprintf("%s (after %s)", line, strings.Join(registration.DependsOn, ", "))
+		}
+		lines = append(lines, line)
+	}
+	return lines, nil
+}
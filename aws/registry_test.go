@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+)
+
+func TestResourceRegistrationsAreConsistent(t *testing.T) {
+	t.Parallel()
+
+	configKeys := map[string]bool{}
+	configType := reflect.TypeOf(config.Config{})
+	for i := 0; i < configType.NumField(); i++ {
+		configKeys[configType.Field(i).Tag.Get("yaml")] = true
+	}
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	for _, registration := range registrations {
+		assert.NotEmpty(t, registration.Description, "registration %s has no description", registration.key())
+		if registration.ConfigKey != "" {
+			assert.True(t, configKeys[registration.ConfigKey], "registration %s uses unknown config key %s", registration.key(), registration.ConfigKey)
+		}
+		if registration.SupportsTags {
+			assert.NotEmpty(t, registration.ConfigKey, "registration %s supports tag rules but has no config key", registration.key())
+		}
+	}
+}
+
+func TestGetResourceRegistrationsRespectsDependencies(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	position := map[string]int{}
+	for idx, registration := range registrations {
+		position[registration.key()] = idx
+	}
+	for _, registration := range registrations {
+		for _, dependency := range registration.DependsOn {
+			assert.Less(t, position[dependency], position[registration.key()], "%s must be nuked before %s", dependency, registration.key())
+		}
+	}
+}
+
+func TestGetRegistrationsForRegion(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	for _, registration := range getRegistrationsForRegion(registrations, GlobalRegion, nil) {
+		assert.True(t, registration.Global)
+	}
+
+	rdsRegistrations := getRegistrationsForRegion(registrations, "us-east-1", []string{DBInstances{}.ResourceName()})
+	require.Len(t, rdsRegistrations, 2)
+	assert.Equal(t, "rds", rdsRegistrations[0].key())
+	assert.Equal(t, "rds-cluster", rdsRegistrations[1].key())
+	for _, registration := range rdsRegistrations {
+		assert.False(t, registration.Global)
+		assert.Equal(t, "rds", registration.Name)
+	}
+}
+
+func TestListResourceTypesIsSortedAndUnique(t *testing.T) {
+	t.Parallel()
+
+	resourceTypes := ListResourceTypes()
+	assert.True(t, sort.StringsAreSorted(resourceTypes))
+
+	seen := map[string]bool{}
+	for _, resourceType := range resourceTypes {
+		assert.False(t, seen[resourceType], "resource type %s is listed more than once", resourceType)
+		seen[resourceType] = true
+	}
+	assert.Contains(t, resourceTypes, EC2Instances{}.ResourceName())
+}
+
+func TestValidateConfigRejectsUnsupportedTagRules(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	tagRule := config.FilterRule{Tags: []config.TagMatcher{{Key: "env"}}}
+	assert.NoError(t, validateConfig(registrations, config.Config{}))
+	assert.NoError(t, validateConfig(registrations, config.Config{EC2: config.ResourceType{IncludeRule: tagRule}}))
+	assert.Equal(
+		t,
+		TagRulesNotSupportedError{ConfigKey: "CloudWatchDashboard"},
+		validateConfig(registrations, config.Config{CloudWatchDashboard: config.ResourceType{ExcludeRule: tagRule}}),
+	)
+}
+
+func TestValidateConfigRejectsUnsupportedRuleAge(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	ageRule := config.FilterRule{OlderThan: &config.Duration{Duration: time.Hour}}
+	assert.NoError(t, validateConfig(registrations, config.Config{EC2: config.ResourceType{ExcludeRule: ageRule}}))
+	assert.NoError(t, validateConfig(registrations, config.Config{CloudWatchDashboard: config.ResourceType{OlderThan: &config.Duration{Duration: time.Hour}}}))
+	assert.Equal(
+		t,
+		RuleAgeNotSupportedError{ConfigKey: "CloudWatchDashboard"},
+		validateConfig(registrations, config.Config{CloudWatchDashboard: config.ResourceType{IncludeRule: ageRule}}),
+	)
+}
+
+func TestWithResourceTypeAge(t *testing.T) {
+	t.Parallel()
+
+	excludeAfter := time.Now().Add(-time.Hour)
+	params := ListParams{
+		ExcludeAfter: excludeAfter,
+		Config:       config.Config{EC2: config.ResourceType{OlderThan: &config.Duration{Duration: 4 * time.Hour}}},
+	}
+
+	ec2Params := withResourceTypeAge(params, ResourceRegistration{Name: "ec2", ConfigKey: "EC2"})
+	assert.WithinDuration(t, time.Now().Add(-4*time.Hour), ec2Params.ExcludeAfter, time.Minute)
+
+	// Resource types without their own age keep the one passed with --older-than
+	assert.Equal(t, excludeAfter, withResourceTypeAge(params, ResourceRegistration{Name: "ebs", ConfigKey: "EBSVolume"}).ExcludeAfter)
+	assert.Equal(t, excludeAfter, withResourceTypeAge(params, ResourceRegistration{Name: "elb"}).ExcludeAfter)
+}
+
+func TestValidateConfigRejectsUnknownResourceTypeLimits(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	assert.NoError(t, validateConfig(registrations, config.Config{Limits: config.ResourceLimits{PerResourceType: map[string]int{"rds": 5}}}))
+	assert.Equal(
+		t,
+		UnknownResourceTypeLimitError{ResourceType: "rds-instances"},
+		validateConfig(registrations, config.Config{Limits: config.ResourceLimits{PerResourceType: map[string]int{"rds-instances": 5}}}),
+	)
+}
+
+func TestValidateConfigRejectsUnsupportedDataPreservation(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	preserve := true
+	assert.NoError(t, validateConfig(registrations, config.Config{DynamoDB: config.ResourceType{PreserveData: &preserve}}))
+	assert.Equal(
+		t,
+		DataPreservationNotSupportedError{ConfigKey: "SQS"},
+		validateConfig(registrations, config.Config{SQS: config.ResourceType{PreserveData: &preserve}}),
+	)
+}
+
+func TestValidateConfigRejectsInvalidRecoveryWindows(t *testing.T) {
+	t.Parallel()
+
+	registrations, err := GetResourceRegistrations()
+	require.NoError(t, err)
+
+	days := func(days int) *int { return &days }
+	assert.NoError(t, validateConfig(registrations, config.Config{KMSCustomerKeys: config.ResourceType{RecoveryWindowDays: days(30)}}))
+	assert.NoError(t, validateConfig(registrations, config.Config{SecretsManagerSecrets: config.ResourceType{RecoveryWindowDays: days(0)}}))
+	assert.Equal(
+		t,
+		InvalidRecoveryWindowError{ResourceType: "kmscustomerkeys", Days: 0},
+		validateConfig(registrations, config.Config{KMSCustomerKeys: config.ResourceType{RecoveryWindowDays: days(0)}}),
+	)
+	assert.Equal(
+		t,
+		RecoveryWindowNotSupportedError{ConfigKey: "SQS"},
+		validateConfig(registrations, config.Config{SQS: config.ResourceType{RecoveryWindowDays: days(7)}}),
+	)
+}
@@ -0,0 +1,62 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// resourceRestorers restore the resources of a region that a run kept recoverable, for each resource type that
+// supports a recovery window
+var resourceRestorers = []func(context.Context, *session.Session, string) ([]ui.ResourceRow, error){
+	func(ctx context.Context, session *session.Session, runID string) ([]ui.ResourceRow, error) {
+		return restoreCustomerManagedKmsKeys(session, runID)
+	},
+	restoreSecretsManagerSecrets,
+}
+
+// RestoreRun cancels the deletion of the KMS keys, and restores the Secrets Manager secrets, that the run with the
+// given ID deleted in the given regions, as long as their recovery window has not ended. It returns the outcome for
+// every resource it found, sorted by region. Once the context is done, the remaining regions are skipped.
+func RestoreRun(ctx context.Context, regions []string, runID string) ([]ui.ResourceRow, error) {
+	rows := []ui.ResourceRow{}
+	for _, region := range regions {
+		if err := ctx.Err(); err != nil {
+			return rows, errors.WithStackTrace(err)
+		}
+
+		logging.Logger.Debugf("Restoring resources deleted by run %s in region %s", runID, region)
+		session, err := newAWSSession(region)
+		if err != nil {
+			return rows, err
+		}
+		for _, restore := range resourceRestorers {
+			restored, err := restore(ctx, session, runID)
+			if err != nil {
+				return rows, errors.WithStackTrace(err)
+			}
+			rows = append(rows, restored...)
+		}
+	}
+	return rows, nil
+}
+
+// restoredResourceRow returns the row reporting the outcome of restoring a single resource
+func restoredResourceRow(resourceType string, identifier string, region string, err error) ui.ResourceRow {
+	row := ui.ResourceRow{
+		Identifier:   identifier,
+		ResourceType: resourceType,
+		Region:       region,
+		Status:       ui.ResourceStatusRestored,
+		Timestamp:    time.Now().UTC(),
+	}
+	if err != nil {
+		row.Status = ui.ResourceStatusFailed
+		row.Error = err.Error()
+	}
+	return row
+}
@@ -0,0 +1,185 @@
+package aws
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/go-commons/collections"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// RunReportVersion is the version of the run report format written by SaveRunReport. ReadRunReport refuses run reports
+// of other versions.
+const RunReportVersion = 1
+
+// RunReport is the outcome of a nuke run, persisted so that 'cloud-nuke aws undo' can later reverse what the run did
+// to the resources that can still be restored
+type RunReport struct {
+	Version   int       `json:"version"`
+	RunID     string    `json:"runId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// AccountID is the ID of the account whose resources the run nuked, which is the only account it may be undone in.
+	// It is empty in run reports written before the account was recorded.
+	AccountID string `json:"accountId,omitempty"`
+	// PreserveData is the data preservation used by the run, which tells which deleted resources were backed up
+	PreserveData DataPreservation    `json:"preserveData"`
+	Resources    []RunReportResource `json:"resources"`
+}
+
+// RunReportResource is the outcome of nuking a single resource, along with what is needed to undo it
+type RunReportResource struct {
+	Region       string `json:"region"`
+	ResourceType string `json:"resourceType"`
+	Identifier   string `json:"identifier"`
+	// Status is the status of the resource in the run report output: deleted, quarantined, failed or cancelled
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Metadata is the metadata recorded with the outcome, see report.Entry
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// DefaultRunReportDir returns the directory in which run reports are saved unless --report-dir says otherwise:
+// .cloud-nuke/runs in the home directory of the user
+func DefaultRunReportDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".cloud-nuke", "runs")
+	}
+	return filepath.Join(home, ".cloud-nuke", "runs")
+}
+
+// NewRunReport returns the report of the run with the given options, made of the outcome of nuking resources, as
+// recorded in the report package. Like ExtractRunReportForOutput, the resources are sorted by region, resource type and
+// identifier.
+func NewRunReport(options NukeOptions) *RunReport {
+	records := report.GetRecords()
+	now := time.Now().UTC()
+	runReport := &RunReport{
+		Version:      RunReportVersion,
+		RunID:        options.RunID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		PreserveData: options.PreserveData,
+		Resources:    []RunReportResource{},
+	}
+
+	for _, key := range sortedRecordKeys(records) {
+		runReport.Resources = append(runReport.Resources, newRunReportResource(records[key], key.Region, key.ResourceType))
+	}
+	return runReport
+}
+
+// newRunReportResource converts the recorded outcome of nuking a resource of the given type in the given region. The
+// resource type is passed separately, as some resource types record their outcome under a description rather than
+// their name.
+func newRunReportResource(entry report.Entry, region string, resourceType string) RunReportResource {
+	row := recordToRow(entry, region)
+	return RunReportResource{
+		Region:       region,
+		ResourceType: resourceType,
+		Identifier:   entry.Identifier,
+		Status:       row.Status,
+		Error:        row.Error,
+		Timestamp:    row.Timestamp,
+		Metadata:     entry.Metadata,
+	}
+}
+
+// RunReportPath returns the path of the report of the run with the given ID in the given directory
+func RunReportPath(dir string, runID string) (string, error) {
+	if runID == "" || runID != filepath.Base(runID) || strings.HasPrefix(runID, ".") {
+		return "", errors.WithStackTrace(InvalidRunIDError{RunID: runID})
+	}
+	return filepath.Join(dir, runID+".json"), nil
+}
+
+// SaveRunReport writes the given run report as JSON to the given directory, creating it if needed. A run can be
+// saved several times, e.g. when it is resumed: the outcomes already saved for the run are kept, unless the given run
+// report has a newer outcome for the same resource.
+func SaveRunReport(runReport *RunReport, dir string) error {
+	path, err := RunReportPath(dir, runReport.RunID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if saved, err := ReadRunReport(dir, runReport.RunID); err == nil {
+		runReport = mergeRunReports(saved, runReport)
+	} else if _, notFound := errors.Unwrap(err).(RunReportNotFoundError); !notFound {
+		return err
+	}
+
+	data, err := json.MarshalIndent(runReport, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return writeFileAtomically(path, data)
+}
+
+// mergeRunReports returns the saved run report, updated with the outcomes of the given newer run report
+func mergeRunReports(saved *RunReport, newer *RunReport) *RunReport {
+	merged := *newer
+	merged.CreatedAt = saved.CreatedAt
+	if merged.AccountID == "" {
+		merged.AccountID = saved.AccountID
+	}
+	merged.Resources = []RunReportResource{}
+
+	updated := map[PlannedResource]bool{}
+	for _, resource := range newer.Resources {
+		updated[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] = true
+	}
+	for _, resource := range saved.Resources {
+		if !updated[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] {
+			merged.Resources = append(merged.Resources, resource)
+		}
+	}
+	merged.Resources = append(merged.Resources, newer.Resources...)
+	return &merged
+}
+
+// ReadRunReport reads the report of the run with the given ID, saved in the given directory by SaveRunReport
+func ReadRunReport(dir string, runID string) (*RunReport, error) {
+	path, err := RunReportPath(dir, runID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errors.WithStackTrace(RunReportNotFoundError{RunID: runID, Dir: dir})
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidRunReportError{Path: path, Underlying: err})
+	}
+
+	runReport := &RunReport{}
+	if err := json.Unmarshal(data, runReport); err != nil {
+		return nil, errors.WithStackTrace(InvalidRunReportError{Path: path, Underlying: err})
+	}
+	if runReport.Version != RunReportVersion {
+		return nil, errors.WithStackTrace(UnsupportedRunReportVersionError{Path: path, Version: runReport.Version})
+	}
+	return runReport, nil
+}
+
+// Regions returns the regions of the resources of the run report, sorted by name, leaving out the resources that have
+// no region
+func (runReport *RunReport) Regions() []string {
+	var regions []string
+	for _, resource := range runReport.Resources {
+		if resource.Region != "" && resource.Region != GlobalRegion && !collections.ListContainsElement(regions, resource.Region) {
+			regions = append(regions, resource.Region)
+		}
+	}
+	sort.Strings(regions)
+	return regions
+}
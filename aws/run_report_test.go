@@ -0,0 +1,89 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	goerrors "github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestNewRunReport(t *testing.T) {
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	ctx := withRecordScope(context.Background(), "us-east-1", "fake")
+	record(ctx, report.Entry{Identifier: "i-1", ResourceType: "EC2 Instance", Quarantined: true, Metadata: map[string]string{metadataPreviousState: "running"}})
+	record(ctx, report.Entry{Identifier: "i-2", ResourceType: "EC2 Instance", Error: errors.New("UnauthorizedOperation")})
+	// The same identifier in another region is reported separately
+	record(withRecordScope(context.Background(), "eu-west-1", "fake"), report.Entry{Identifier: "i-1", ResourceType: "EC2 Instance"})
+	report.Record(report.Entry{Identifier: "sub-resource", ResourceType: "Sub Resource"})
+
+	runReport := NewRunReport(NukeOptions{RunID: "run-1", PreserveData: DataPreservation{Enabled: true}})
+	assert.Equal(t, RunReportVersion, runReport.Version)
+	assert.Equal(t, "run-1", runReport.RunID)
+	assert.True(t, runReport.PreserveData.Enabled)
+	require.Len(t, runReport.Resources, 4)
+
+	// Resources recorded without a region come first
+	assert.Equal(t, RunReportResource{ResourceType: "Sub Resource", Identifier: "sub-resource", Status: ui.ResourceStatusDeleted, Timestamp: runReport.Resources[0].Timestamp}, runReport.Resources[0])
+	// Resources are reported under the name of the resource type they were nuked as, with their region
+	assert.Equal(t, "eu-west-1", runReport.Resources[1].Region)
+	assert.Equal(t, "i-1", runReport.Resources[1].Identifier)
+	assert.Equal(t, ui.ResourceStatusDeleted, runReport.Resources[1].Status)
+	assert.Equal(t, "us-east-1", runReport.Resources[2].Region)
+	assert.Equal(t, "fake", runReport.Resources[2].ResourceType)
+	assert.Equal(t, ui.ResourceStatusQuarantined, runReport.Resources[2].Status)
+	assert.Equal(t, map[string]string{metadataPreviousState: "running"}, runReport.Resources[2].Metadata)
+	assert.Equal(t, ui.ResourceStatusFailed, runReport.Resources[3].Status)
+	assert.Equal(t, "UnauthorizedOperation", runReport.Resources[3].Error)
+}
+
+func TestSaveRunReportMergesOutcomesOfTheSameRun(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "runs")
+	first := &RunReport{Version: RunReportVersion, RunID: "run-1", AccountID: "123456789012", CreatedAt: time.Now().UTC().Add(-time.Hour), Resources: []RunReportResource{
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-1", Status: ui.ResourceStatusDeleted},
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-2", Status: ui.ResourceStatusFailed},
+	}}
+	require.NoError(t, SaveRunReport(first, dir))
+
+	// Resuming the run saves the outcome of the resources that were left
+	resumed := &RunReport{Version: RunReportVersion, RunID: "run-1", CreatedAt: time.Now().UTC(), Resources: []RunReportResource{
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-2", Status: ui.ResourceStatusDeleted},
+	}}
+	require.NoError(t, SaveRunReport(resumed, dir))
+
+	saved, err := ReadRunReport(dir, "run-1")
+	require.NoError(t, err)
+	assert.Equal(t, first.CreatedAt, saved.CreatedAt)
+	assert.Equal(t, "123456789012", saved.AccountID)
+	assert.Equal(t, []RunReportResource{
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-1", Status: ui.ResourceStatusDeleted},
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-2", Status: ui.ResourceStatusDeleted},
+	}, saved.Resources)
+	assert.Equal(t, []string{"us-east-1"}, saved.Regions())
+}
+
+func TestReadRunReportErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	_, err := ReadRunReport(dir, "run-1")
+	assert.Equal(t, RunReportNotFoundError{RunID: "run-1", Dir: dir}, goerrors.Unwrap(err))
+
+	_, err = ReadRunReport(dir, "../run-1")
+	assert.Equal(t, InvalidRunIDError{RunID: "../run-1"}, goerrors.Unwrap(err))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "run-2.json"), []byte(`{"version": 2}`), 0644))
+	_, err = ReadRunReport(dir, "run-2")
+	assert.Equal(t, UnsupportedRunReportVersionError{Path: filepath.Join(dir, "run-2.json"), Version: 2}, goerrors.Unwrap(err))
+}
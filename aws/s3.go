@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
@@ -15,6 +17,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
@@ -23,7 +26,7 @@ import (
 )
 
 // getS3BucketRegion returns S3 Bucket region.
-func getS3BucketRegion(svc *s3.S3, bucketName string) (string, error) {
+func getS3BucketRegion(svc s3iface.S3API, bucketName string) (string, error) {
 	input := &s3.GetBucketLocationInput{
 		Bucket: aws.String(bucketName),
 	}
@@ -42,7 +45,12 @@ func getS3BucketRegion(svc *s3.S3, bucketName string) (string, error) {
 }
 
 // getS3BucketTags returns S3 Bucket tags.
-func getS3BucketTags(svc *s3.S3, bucketName string) ([]map[string]string, error) {
+func getS3BucketTags(svc s3iface.S3API, bucketName string) ([]map[string]string, error) {
+	return getS3BucketTagsWithContext(aws.BackgroundContext(), svc, bucketName)
+}
+
+// getS3BucketTagsWithContext is like getS3BucketTags, but stops once the given context is done
+func getS3BucketTagsWithContext(ctx context.Context, svc s3iface.S3API, bucketName string) ([]map[string]string, error) {
 	input := &s3.GetBucketTaggingInput{
 		Bucket: aws.String(bucketName),
 	}
@@ -51,7 +59,7 @@ func getS3BucketTags(svc *s3.S3, bucketName string) ([]map[string]string, error)
 
 	// Please note that svc argument should be created from a session object which is
 	// in the same region as the bucket or GetBucketTagging will fail.
-	result, err := svc.GetBucketTagging(input)
+	result, err := svc.GetBucketTaggingWithContext(ctx, input)
 	if err != nil {
 		if aerr, ok := err.(awserr.Error); ok {
 			switch aerr.Code() {
@@ -103,7 +111,7 @@ func getAllS3Buckets(awsSession *session.Session, excludeAfter time.Time,
 		return nil, fmt.Errorf("Invalid batchsize - %d - should be > 0", batchSize)
 	}
 
-	svc := s3.New(awsSession)
+	svc := newS3Client(awsSession)
 	input := &s3.ListBucketsInput{}
 	output, err := svc.ListBuckets(input)
 	if err != nil {
@@ -146,20 +154,20 @@ func getAllS3Buckets(awsSession *session.Session, excludeAfter time.Time,
 }
 
 // getRegions creates s3 clients for target regions
-func getRegionClients(regions []string) (map[string]*s3.S3, error) {
-	regionClients := make(map[string]*s3.S3)
+func getRegionClients(regions []string) (map[string]s3iface.S3API, error) {
+	regionClients := make(map[string]s3iface.S3API)
 	for _, region := range regions {
 		logging.Logger.Debugf("S3 - creating session - region %s", region)
 
 		awsSession := newSession(region)
 
-		regionClients[region] = s3.New(awsSession)
+		regionClients[region] = newS3Client(awsSession)
 	}
 	return regionClients, nil
 }
 
 // getBucketNamesPerRegions gets valid bucket names concurrently from list of target buckets
-func getBucketNamesPerRegion(svc *s3.S3, targetBuckets []*s3.Bucket, excludeAfter time.Time, regionClients map[string]*s3.S3,
+func getBucketNamesPerRegion(svc s3iface.S3API, targetBuckets []*s3.Bucket, excludeAfter time.Time, regionClients map[string]s3iface.S3API,
 	bucketNameSubStr string, configObj config.Config,
 ) (map[string][]*string, error) {
 	bucketNamesPerRegion := make(map[string][]*string)
@@ -204,7 +212,7 @@ func getBucketNamesPerRegion(svc *s3.S3, targetBuckets []*s3.Bucket, excludeAfte
 }
 
 // getBucketInfo populates the local S3Bucket struct for the passed AWS bucket
-func getBucketInfo(svc *s3.S3, bucket *s3.Bucket, excludeAfter time.Time, regionClients map[string]*s3.S3, bucketCh chan<- *S3Bucket, configObj config.Config) {
+func getBucketInfo(svc s3iface.S3API, bucket *s3.Bucket, excludeAfter time.Time, regionClients map[string]s3iface.S3API, bucketCh chan<- *S3Bucket, configObj config.Config) {
 	var bucketData S3Bucket
 	bucketData.Name = aws.StringValue(bucket.Name)
 	bucketData.CreationDate = aws.TimeValue(bucket.CreationDate)
@@ -241,6 +249,16 @@ func getBucketInfo(svc *s3.S3, bucket *s3.Bucket, excludeAfter time.Time, region
 	bucketData.Tags = bucketTags
 	if !hasValidTags(bucketData.Tags) {
 		bucketData.InvalidReason = "Matched tag filter"
+		recordExclusion(S3Buckets{}.ResourceName(), bucketData.Region, bucketData.Name, ExcludedByTagReason)
+		bucketCh <- &bucketData
+		return
+	}
+	tags := make(map[string]string)
+	for _, tagSet := range bucketData.Tags {
+		tags[tagSet["Key"]] = tagSet["Value"]
+	}
+	if excludedByQuarantine(S3Buckets{}.ResourceName(), bucketData.Region, bucketData.Name, tags, excludeAfter) {
+		bucketData.InvalidReason = "Quarantined recently"
 		bucketCh <- &bucketData
 		return
 	}
@@ -253,8 +271,11 @@ func getBucketInfo(svc *s3.S3, bucket *s3.Bucket, excludeAfter time.Time, region
 	}
 
 	// Check if the bucket matches config file rules
-	if !config.ShouldInclude(bucketData.Name, configObj.S3.IncludeRule.NamesRegExp, configObj.S3.ExcludeRule.NamesRegExp) {
+	// The rules are evaluated without the exclusion handler, as it would record the bucket in the region being scanned
+	// rather than in the region of the bucket
+	if include, reason := configObj.S3.Evaluate(config.ResourceValue{Name: bucketData.Name, Tags: tags, Time: bucketData.CreationDate}); !include {
 		bucketData.InvalidReason = "Filtered by config file rules"
+		recordExclusion(S3Buckets{}.ResourceName(), bucketData.Region, bucketData.Name, excludedByConfigReason(reason))
 		bucketCh <- &bucketData
 		return
 	}
@@ -269,7 +290,7 @@ func getBucketInfo(svc *s3.S3, bucket *s3.Bucket, excludeAfter time.Time, region
 // does not provide any API for getting the object count, and the only way to do that is to iterate through all the
 // objects. For memory and time efficiency, we opted to delete the objects as we retrieve each page, which means we
 // don't know how many are left until we complete all the operations.
-func emptyBucket(svc *s3.S3, bucketName *string, isVersioned bool, batchSize int) error {
+func emptyBucket(ctx context.Context, svc s3iface.S3API, bucketName *string, isVersioned bool, batchSize int) error {
 	// Since the error may happen in the inner function handler for the pager, we need a function scoped variable that
 	// the inner function can set when there is an error.
 	var errOut error
@@ -277,7 +298,8 @@ func emptyBucket(svc *s3.S3, bucketName *string, isVersioned bool, batchSize int
 
 	// Handle versioned buckets.
 	if isVersioned {
-		err := svc.ListObjectVersionsPages(
+		err := svc.ListObjectVersionsPagesWithContext(
+			ctx,
 			&s3.ListObjectVersionsInput{
 				Bucket:  bucketName,
 				MaxKeys: aws.Int64(int64(batchSize)),
@@ -313,7 +335,8 @@ func emptyBucket(svc *s3.S3, bucketName *string, isVersioned bool, batchSize int
 	}
 
 	// Handle non versioned buckets.
-	err := svc.ListObjectsV2Pages(
+	err := svc.ListObjectsV2PagesWithContext(
+		ctx,
 		&s3.ListObjectsV2Input{
 			Bucket:  bucketName,
 			MaxKeys: aws.Int64(int64(batchSize)),
@@ -341,7 +364,7 @@ func emptyBucket(svc *s3.S3, bucketName *string, isVersioned bool, batchSize int
 }
 
 // deleteObjects will delete the provided objects (unversioned) from the specified bucket.
-func deleteObjects(svc *s3.S3, bucketName *string, objects []*s3.Object) error {
+func deleteObjects(svc s3iface.S3API, bucketName *string, objects []*s3.Object) error {
 	if len(objects) == 0 {
 		logging.Logger.Debugf("No objects returned in page")
 		return nil
@@ -366,7 +389,7 @@ func deleteObjects(svc *s3.S3, bucketName *string, objects []*s3.Object) error {
 }
 
 // deleteObjectVersions will delete the provided object versions from the specified bucket.
-func deleteObjectVersions(svc *s3.S3, bucketName *string, objectVersions []*s3.ObjectVersion) error {
+func deleteObjectVersions(svc s3iface.S3API, bucketName *string, objectVersions []*s3.ObjectVersion) error {
 	if len(objectVersions) == 0 {
 		logging.Logger.Debugf("No object versions returned in page")
 		return nil
@@ -392,7 +415,7 @@ func deleteObjectVersions(svc *s3.S3, bucketName *string, objectVersions []*s3.O
 }
 
 // deleteDeletionMarkers will delete the provided deletion markers from the specified bucket.
-func deleteDeletionMarkers(svc *s3.S3, bucketName *string, objectDelMarkers []*s3.DeleteMarkerEntry) error {
+func deleteDeletionMarkers(svc s3iface.S3API, bucketName *string, objectDelMarkers []*s3.DeleteMarkerEntry) error {
 	if len(objectDelMarkers) == 0 {
 		logging.Logger.Debugf("No deletion markers returned in page")
 		return nil
@@ -418,7 +441,7 @@ func deleteDeletionMarkers(svc *s3.S3, bucketName *string, objectDelMarkers []*s
 }
 
 // nukeAllS3BucketObjects batch deletes all objects in an S3 bucket
-func nukeAllS3BucketObjects(svc *s3.S3, bucketName *string, batchSize int) error {
+func nukeAllS3BucketObjects(ctx context.Context, svc s3iface.S3API, bucketName *string, batchSize int) error {
 	versioningResult, err := svc.GetBucketVersioning(&s3.GetBucketVersioningInput{
 		Bucket: bucketName,
 	})
@@ -433,7 +456,7 @@ func nukeAllS3BucketObjects(svc *s3.S3, bucketName *string, batchSize int) error
 	}
 
 	logging.Logger.Debugf("Emptying bucket %s", aws.StringValue(bucketName))
-	if err := emptyBucket(svc, bucketName, isVersioned, batchSize); err != nil {
+	if err := emptyBucket(ctx, svc, bucketName, isVersioned, batchSize); err != nil {
 		return err
 	}
 	logging.Logger.Debugf("[OK] - successfully emptied bucket %s", aws.StringValue(bucketName))
@@ -441,7 +464,7 @@ func nukeAllS3BucketObjects(svc *s3.S3, bucketName *string, batchSize int) error
 }
 
 // nukeEmptyS3Bucket deletes an empty S3 bucket
-func nukeEmptyS3Bucket(svc *s3.S3, bucketName *string, verifyBucketDeletion bool) error {
+func nukeEmptyS3Bucket(ctx context.Context, svc s3iface.S3API, bucketName *string, verifyBucketDeletion bool) error {
 	_, err := svc.DeleteBucket(&s3.DeleteBucketInput{
 		Bucket: bucketName,
 	})
@@ -458,7 +481,7 @@ func nukeEmptyS3Bucket(svc *s3.S3, bucketName *string, verifyBucketDeletion bool
 	const maxRetries = 3
 	for i := 0; i < maxRetries; i++ {
 		logging.Logger.Debugf("Waiting until bucket (%s) deletion is propagated (attempt %d / %d)", aws.StringValue(bucketName), i+1, maxRetries)
-		err = svc.WaitUntilBucketNotExists(&s3.HeadBucketInput{
+		err = svc.WaitUntilBucketNotExistsWithContext(ctx, &s3.HeadBucketInput{
 			Bucket: bucketName,
 		})
 		// Exit early if no error
@@ -472,7 +495,7 @@ func nukeEmptyS3Bucket(svc *s3.S3, bucketName *string, verifyBucketDeletion bool
 	return err
 }
 
-func nukeS3BucketPolicy(svc *s3.S3, bucketName *string) error {
+func nukeS3BucketPolicy(svc s3iface.S3API, bucketName *string) error {
 	_, err := svc.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
 		Bucket: aws.String(*bucketName),
 	})
@@ -480,8 +503,8 @@ func nukeS3BucketPolicy(svc *s3.S3, bucketName *string) error {
 }
 
 // nukeAllS3Buckets deletes all S3 buckets passed as input
-func nukeAllS3Buckets(awsSession *session.Session, bucketNames []*string, objectBatchSize int) (delCount int, err error) {
-	svc := s3.New(awsSession)
+func nukeAllS3Buckets(ctx context.Context, awsSession *session.Session, bucketNames []*string, objectBatchSize int) (delCount int, err error) {
+	svc := newS3Client(awsSession)
 	verifyBucketDeletion := true
 
 	if len(bucketNames) == 0 {
@@ -499,7 +522,7 @@ func nukeAllS3Buckets(awsSession *session.Session, bucketNames []*string, object
 		bucketName := bucketNames[bucketIndex]
 		logging.Logger.Debugf("Deleting - %d/%d - Bucket: %s", bucketIndex+1, totalCount, *bucketName)
 
-		err = nukeAllS3BucketObjects(svc, bucketName, objectBatchSize)
+		err = nukeAllS3BucketObjects(ctx, svc, bucketName, objectBatchSize)
 		if err != nil {
 			logging.Logger.Debugf("[Failed] - %d/%d - Bucket: %s - object deletion error - %s", bucketIndex+1, totalCount, *bucketName, err)
 			telemetry.TrackEvent(commonTelemetry.EventContext{
@@ -523,7 +546,7 @@ func nukeAllS3Buckets(awsSession *session.Session, bucketNames []*string, object
 			continue
 		}
 
-		err = nukeEmptyS3Bucket(svc, bucketName, verifyBucketDeletion)
+		err = nukeEmptyS3Bucket(ctx, svc, bucketName, verifyBucketDeletion)
 		if err != nil {
 			logging.Logger.Debugf("[Failed] - %d/%d - Bucket: %s - bucket deletion error - %s", bucketIndex+1, totalCount, *bucketName, err)
 			telemetry.TrackEvent(commonTelemetry.EventContext{
@@ -541,7 +564,7 @@ func nukeAllS3Buckets(awsSession *session.Session, bucketNames []*string, object
 			ResourceType: "S3 Bucket",
 			Error:        multiErr.ErrorOrNil(),
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		logging.Logger.Debugf("[OK] - %d/%d - Bucket: %s - deleted", bucketIndex+1, totalCount, *bucketName)
 		delCount++
@@ -549,3 +572,115 @@ func nukeAllS3Buckets(awsSession *session.Session, bucketNames []*string, object
 
 	return delCount, multiErr.ErrorOrNil()
 }
+
+// quarantineS3Buckets tags the given S3 buckets with the quarantine tag and blocks all public access to them. The
+// objects are left untouched, so that their owners can lift the block if they still need the buckets.
+func quarantineS3Buckets(ctx context.Context, awsSession *session.Session, bucketNames []*string) error {
+	svc := newS3Client(awsSession)
+
+	for _, bucketName := range bucketNames {
+		// A bucket that cannot be read is recorded as failed, the others are still quarantined
+		var metadata map[string]string
+		bucketTags, err := getS3BucketTagsWithContext(ctx, svc, aws.StringValue(bucketName))
+		if err == nil {
+			metadata, err = getS3BucketQuarantineMetadata(ctx, svc, bucketName)
+		}
+		if err == nil {
+			err = quarantineS3Bucket(ctx, svc, bucketName, bucketTags)
+		}
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Quarantined S3 bucket: %s", aws.StringValue(bucketName))
+		}
+		recordQuarantine(ctx, S3Buckets{}.ResourceName(), aws.StringValue(bucketName), metadata, err)
+	}
+	return nil
+}
+
+func quarantineS3Bucket(ctx context.Context, svc s3iface.S3API, bucketName *string, bucketTags []map[string]string) error {
+	// PutBucketTagging replaces the whole tag set, so the existing tags are written back with the quarantine tag
+	tags := make(map[string]string)
+	var tagSet []*s3.Tag
+	for _, tag := range bucketTags {
+		tags[tag["Key"]] = tag["Value"]
+		if tag["Key"] != QuarantineTagKey {
+			tagSet = append(tagSet, &s3.Tag{Key: aws.String(tag["Key"]), Value: aws.String(tag["Value"])})
+		}
+	}
+	tagSet = append(tagSet, &s3.Tag{Key: aws.String(QuarantineTagKey), Value: aws.String(quarantineTagValue(tags))})
+
+	_, err := svc.PutBucketTaggingWithContext(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  bucketName,
+		Tagging: &s3.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.PutPublicAccessBlockWithContext(ctx, &s3.PutPublicAccessBlockInput{
+		Bucket: bucketName,
+		PublicAccessBlockConfiguration: &s3.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(true),
+			BlockPublicPolicy:     aws.Bool(true),
+			IgnorePublicAcls:      aws.Bool(true),
+			RestrictPublicBuckets: aws.Bool(true),
+		},
+	})
+	return errors.WithStackTrace(err)
+}
+
+// getS3BucketQuarantineMetadata returns the metadata needed to undo quarantining the given bucket: its public access
+// block configuration, if it has one
+func getS3BucketQuarantineMetadata(ctx context.Context, svc s3iface.S3API, bucketName *string) (map[string]string, error) {
+	output, err := svc.GetPublicAccessBlockWithContext(ctx, &s3.GetPublicAccessBlockInput{Bucket: bucketName})
+	if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchPublicAccessBlockConfiguration" {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	configuration, err := json.Marshal(output.PublicAccessBlockConfiguration)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return map[string]string{metadataPublicAccessBlock: string(configuration)}, nil
+}
+
+// undoS3BucketQuarantine gives a quarantined S3 bucket its public access block configuration back, or removes it if it
+// had none, and removes its quarantine tag
+func undoS3BucketQuarantine(awsSession *session.Session, resource RunReportResource) error {
+	svc := newS3Client(awsSession)
+	bucketName := aws.String(resource.Identifier)
+
+	var err error
+	if value, ok := resource.Metadata[metadataPublicAccessBlock]; ok {
+		configuration := &s3.PublicAccessBlockConfiguration{}
+		if err := json.Unmarshal([]byte(value), configuration); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		_, err = svc.PutPublicAccessBlock(&s3.PutPublicAccessBlockInput{Bucket: bucketName, PublicAccessBlockConfiguration: configuration})
+	} else {
+		_, err = svc.DeletePublicAccessBlock(&s3.DeletePublicAccessBlockInput{Bucket: bucketName})
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	// PutBucketTagging replaces the whole tag set, so the other tags are written back without the quarantine tag
+	bucketTags, err := getS3BucketTags(svc, resource.Identifier)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	var tagSet []*s3.Tag
+	for _, tag := range bucketTags {
+		if tag["Key"] != QuarantineTagKey {
+			tagSet = append(tagSet, &s3.Tag{Key: aws.String(tag["Key"]), Value: aws.String(tag["Value"])})
+		}
+	}
+	if len(tagSet) == 0 {
+		_, err = svc.DeleteBucketTagging(&s3.DeleteBucketTaggingInput{Bucket: bucketName})
+	} else {
+		_, err = svc.PutBucketTagging(&s3.PutBucketTaggingInput{Bucket: bucketName, Tagging: &s3.Tagging{TagSet: tagSet}})
+	}
+	return errors.WithStackTrace(err)
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
@@ -192,7 +193,7 @@ func testListS3Bucket(t *testing.T, args TestListS3BucketArgs) {
 	// This is required so that the defer cleanup call always gets the right bucket region
 	// to delete
 	defer func() {
-		_, err := nukeAllS3Buckets(awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
+		_, err := nukeAllS3Buckets(context.Background(), awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
 		if args.shouldError {
 			assert.Error(t, err)
 		} else {
@@ -371,10 +372,10 @@ func testNukeS3Bucket(t *testing.T, args TestNukeS3BucketArgs) {
 	// It ensures that all S3 buckets created as part of this test will be nuked after the test has run.
 	// This is necessary, as some test cases are expected to fail & test that the buckets with invalid args are not nuked.
 	// For more details, look at Github issue-140: https://github.com/tnn-gruntwork-io/cloud-nuke/issues/140
-	defer nukeAllS3Buckets(awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
+	defer nukeAllS3Buckets(context.Background(), awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
 
 	// Nuke the test bucket
-	delCount, err := nukeAllS3Buckets(awsParams.awsSession, []*string{aws.String(bucketName)}, args.objectBatchsize)
+	delCount, err := nukeAllS3Buckets(context.Background(), awsParams.awsSession, []*string{aws.String(bucketName)}, args.objectBatchsize)
 	if args.shouldError {
 		require.Error(t, err)
 	} else {
@@ -561,7 +562,7 @@ func TestFilterS3Bucket_Config(t *testing.T) {
 	cleanupBuckets, err := getAllS3Buckets(awsParams.awsSession, time.Now().Add(1*time.Hour), []string{awsParams.region}, "", 100, *configObj)
 	require.NoError(t, err, "Failed to list S3 Buckets in ca-central-1")
 
-	_, err = nukeAllS3Buckets(awsParams.awsSession, cleanupBuckets[awsParams.region], 1000)
+	_, err = nukeAllS3Buckets(context.Background(), awsParams.awsSession, cleanupBuckets[awsParams.region], 1000)
 	require.NoError(t, err)
 
 	// Create test buckets in ca-central-1
@@ -624,7 +625,7 @@ func TestFilterS3Bucket_Config(t *testing.T) {
 
 	// Clean up test buckets
 	defer func() {
-		_, err := nukeAllS3Buckets(awsParams.awsSession, aws.StringSlice(bucketNames), 1000)
+		_, err := nukeAllS3Buckets(context.Background(), awsParams.awsSession, aws.StringSlice(bucketNames), 1000)
 		assert.NoError(t, err)
 	}()
 	t.Run("config tests", func(t *testing.T) {
@@ -697,10 +698,10 @@ func TestNukeS3BucketWithBucketPolicy(t *testing.T) {
 		awsParams.svc.DeleteBucketPolicy(&s3.DeleteBucketPolicyInput{
 			Bucket: aws.String(bucketName),
 		})
-		nukeAllS3Buckets(awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
+		nukeAllS3Buckets(context.Background(), awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
 	}()
 
-	_, err = nukeAllS3Buckets(awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
+	_, err = nukeAllS3Buckets(context.Background(), awsParams.awsSession, []*string{aws.String(bucketName)}, 1000)
 	require.NoError(t, err)
 
 }
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
@@ -41,8 +43,8 @@ func (bucket S3Buckets) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (bucket S3Buckets) Nuke(session *session.Session, identifiers []string) error {
-	delCount, err := nukeAllS3Buckets(session, aws.StringSlice(identifiers), bucket.ObjectMaxBatchSize())
+func (bucket S3Buckets) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	delCount, err := nukeAllS3Buckets(ctx, session, aws.StringSlice(identifiers), bucket.ObjectMaxBatchSize())
 
 	totalCount := len(identifiers)
 	if delCount > 0 {
@@ -58,3 +60,55 @@ func (bucket S3Buckets) Nuke(session *session.Session, identifiers []string) err
 
 	return nil
 }
+
+// Quarantine - block all public access to the S3 buckets, leaving their objects untouched
+func (bucket S3Buckets) Quarantine(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := quarantineS3Buckets(ctx, session, aws.StringSlice(identifiers)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:               S3Buckets{}.ResourceName(),
+		Description:        "S3 Buckets",
+		ConfigKey:          "s3",
+		SupportsTags:       true,
+		SupportsRuleAge:    true,
+		SupportsQuarantine: true,
+		UndoQuarantine:     undoS3BucketQuarantine,
+		DependsOn:          []string{"cloudtrail"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			// AWS S3 buckets list operation lists all buckets irrespective of regions.
+			// For each bucket we have to make a separate call to find the bucket region.
+			// Hence for x buckets and a total of y target regions - we need to make:
+			// (x + 1) * y calls i.e. 1 call to list all x buckets, x calls to find out
+			// each bucket's region and repeat the process for each of the y regions.
+
+			// getAllS3Buckets returns a map of regions to buckets and we call it only once -
+			// thereby reducing total calls from (x + 1) * y to only (x + 1) for the first region -
+			// followed by a cache lookup for rest of the regions.
+
+			// Cache lookup to check if we already obtained bucket names per region
+			params.cache.Lock()
+			defer params.cache.Unlock()
+
+			var err error
+			bucketNamesPerRegion, ok := params.cache.resourcesPerRegion["S3"]
+			if !ok {
+				bucketNamesPerRegion, err = getAllS3Buckets(
+					session,
+					params.ExcludeAfter,
+					params.TargetRegions,
+					"",
+					S3Buckets{}.MaxConcurrentGetSize(),
+					params.Config,
+				)
+				params.cache.resourcesPerRegion["S3"] = bucketNamesPerRegion
+			}
+			return S3Buckets{Names: aws.StringValueSlice(bucketNamesPerRegion[params.Region])}, err
+		},
+	})
+}
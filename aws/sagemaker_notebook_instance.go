@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -16,33 +17,44 @@ import (
 )
 
 func getAllNotebookInstances(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := sagemaker.New(session)
+	svc := newSageMakerClient(session)
 
-	result, err := svc.ListNotebookInstances(&sagemaker.ListNotebookInstancesInput{})
+	var allNotebookInstances []*sagemaker.NotebookInstanceSummary
+	err := svc.ListNotebookInstancesPages(&sagemaker.ListNotebookInstancesInput{}, func(page *sagemaker.ListNotebookInstancesOutput, lastPage bool) bool {
+		allNotebookInstances = append(allNotebookInstances, page.NotebookInstances...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var names []*string
 
-	for _, notebook := range result.NotebookInstances {
-		if notebook.CreationTime == nil {
-			continue
+	for _, notebook := range allNotebookInstances {
+		if shouldIncludeNotebookInstance(notebook, excludeAfter, configObj) {
+			names = append(names, notebook.NotebookInstanceName)
 		}
-		if !excludeAfter.After(awsgo.TimeValue(notebook.CreationTime)) {
-			continue
-		}
-		if !config.ShouldInclude(awsgo.StringValue(notebook.NotebookInstanceName), configObj.S3.IncludeRule.NamesRegExp, configObj.S3.ExcludeRule.NamesRegExp) {
-			continue
-		}
-		names = append(names, notebook.NotebookInstanceName)
 	}
 
 	return names, nil
 }
 
-func nukeAllNotebookInstances(session *session.Session, names []*string) error {
-	svc := sagemaker.New(session)
+func shouldIncludeNotebookInstance(notebook *sagemaker.NotebookInstanceSummary, excludeAfter time.Time, configObj config.Config) bool {
+	if notebook == nil || notebook.CreationTime == nil {
+		return false
+	}
+	if !excludeAfter.After(awsgo.TimeValue(notebook.CreationTime)) {
+		return false
+	}
+
+	return configObj.SageMakerNotebook.ShouldInclude(config.ResourceValue{
+		Name: awsgo.StringValue(notebook.NotebookInstanceName),
+		Time: awsgo.TimeValue(notebook.CreationTime),
+	})
+}
+
+func nukeAllNotebookInstances(ctx context.Context, session *session.Session, names []*string) error {
+	svc := newSageMakerClient(session)
 
 	if len(names) == 0 {
 		logging.Logger.Debugf("No Sagemaker Notebook Instance to nuke in region %s", *session.Config.Region)
@@ -70,7 +82,7 @@ func nukeAllNotebookInstances(session *session.Session, names []*string) error {
 			})
 		}
 
-		err = svc.WaitUntilNotebookInstanceStopped(&sagemaker.DescribeNotebookInstanceInput{
+		err = svc.WaitUntilNotebookInstanceStoppedWithContext(ctx, &sagemaker.DescribeNotebookInstanceInput{
 			NotebookInstanceName: name,
 		})
 
@@ -103,7 +115,7 @@ func nukeAllNotebookInstances(session *session.Session, names []*string) error {
 	if len(deletedNames) > 0 {
 		for _, name := range deletedNames {
 
-			err := svc.WaitUntilNotebookInstanceDeleted(&sagemaker.DescribeNotebookInstanceInput{
+			err := svc.WaitUntilNotebookInstanceDeletedWithContext(ctx, &sagemaker.DescribeNotebookInstanceInput{
 				NotebookInstanceName: name,
 			})
 
@@ -113,7 +125,7 @@ func nukeAllNotebookInstances(session *session.Session, names []*string) error {
 				ResourceType: "SageMaker Notebook Instance",
 				Error:        err,
 			}
-			report.Record(e)
+			record(ctx, e)
 
 			if err != nil {
 				logging.Logger.Errorf("[Failed] %s", err)
@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -60,6 +62,26 @@ func createTestNotebookInstance(t *testing.T, session *session.Session, name str
 	waitUntilNotebookInstanceCreated(svc, &name)
 }
 
+func TestShouldIncludeNotebookInstance(t *testing.T) {
+	t.Parallel()
+
+	excludeRule := config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile("^keep-")}}}
+	configObj := config.Config{
+		SageMakerNotebook: config.ResourceType{ExcludeRule: excludeRule},
+		// The rules of S3 buckets do not apply to notebook instances
+		S3: config.ResourceType{ExcludeRule: config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile(".*")}}}},
+	}
+
+	excludeAfter := time.Now()
+	createdAt := excludeAfter.Add(-time.Hour)
+	notebook := func(name string) *sagemaker.NotebookInstanceSummary {
+		return &sagemaker.NotebookInstanceSummary{NotebookInstanceName: awsgo.String(name), CreationTime: &createdAt}
+	}
+	assert.True(t, shouldIncludeNotebookInstance(notebook("nuke-me"), excludeAfter, configObj))
+	assert.False(t, shouldIncludeNotebookInstance(notebook("keep-me"), excludeAfter, configObj))
+	assert.False(t, shouldIncludeNotebookInstance(&sagemaker.NotebookInstanceSummary{NotebookInstanceName: awsgo.String("unknown-age")}, excludeAfter, configObj))
+}
+
 func TestNukeNotebookInstance(t *testing.T) {
 	telemetry.InitTelemetry("cloud-nuke", "", "")
 	t.Parallel()
@@ -86,7 +108,7 @@ func TestNukeNotebookInstance(t *testing.T) {
 	createTestNotebookInstance(t, session, notebookName, *role.Arn)
 
 	defer func() {
-		nukeAllNotebookInstances(session, []*string{&notebookName})
+		nukeAllNotebookInstances(context.Background(), session, []*string{&notebookName})
 
 		notebookNames, _ := getAllNotebookInstances(session, excludeAfter, config.Config{})
 
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -25,8 +27,8 @@ func (instance SageMakerNotebookInstances) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (instance SageMakerNotebookInstances) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllNotebookInstances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (instance SageMakerNotebookInstances) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllNotebookInstances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -40,3 +42,15 @@ type SageMakerNotebookInstanceDeleteError struct {
 func (e SageMakerNotebookInstanceDeleteError) Error() string {
 	return "SageMaker Notebook Instance:" + e.name + "was not deleted"
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:        SageMakerNotebookInstances{}.ResourceName(),
+		Description: "SageMaker Notebook Instances",
+		ConfigKey:   "SageMakerNotebook",
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllNotebookInstances(session, params.ExcludeAfter, params.Config)
+			return SageMakerNotebookInstances{InstanceNames: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,24 +1,29 @@
 package aws
 
 import (
+	"context"
+
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
 	multierror "github.com/hashicorp/go-multierror"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
 	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
 func getAllSecretsManagerSecrets(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	svc := secretsmanager.New(session)
+	svc := newSecretsManagerClient(session)
 
 	allSecrets := []*string{}
 	input := &secretsmanager.ListSecretsInput{}
@@ -26,6 +31,9 @@ func getAllSecretsManagerSecrets(session *session.Session, excludeAfter time.Tim
 		input,
 		func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
 			for _, secret := range page.SecretList {
+				if excludedByTag(SecretsManagerSecrets{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(secret.ARN), secretTagsToMap(secret.Tags)) {
+					continue
+				}
 				if shouldIncludeSecret(secret, excludeAfter, configObj) {
 					allSecrets = append(allSecrets, secret.ARN)
 				}
@@ -53,17 +61,21 @@ func shouldIncludeSecret(secret *secretsmanager.SecretListEntry, excludeAfter ti
 		return false
 	}
 
-	return config.ShouldInclude(
-		aws.StringValue(secret.Name),
-		configObj.SecretsManagerSecrets.IncludeRule.NamesRegExp,
-		configObj.SecretsManagerSecrets.ExcludeRule.NamesRegExp,
-	)
+	return configObj.SecretsManagerSecrets.ShouldInclude(config.ResourceValue{
+		Name: aws.StringValue(secret.Name),
+		Tags: secretTagsToMap(secret.Tags),
+		ID:   aws.StringValue(secret.ARN),
+		Time: referenceTime,
+	})
 }
 
-func nukeAllSecretsManagerSecrets(session *session.Session, identifiers []*string) error {
+// nukeAllSecretsManagerSecrets deletes the given secrets, keeping them recoverable for the given number of days unless
+// it is 0. Unless runID is empty, recoverable secrets are first tagged with the run ID, so that 'cloud-nuke aws restore'
+// can restore them.
+func nukeAllSecretsManagerSecrets(ctx context.Context, session *session.Session, identifiers []*string, recoveryWindowDays int, runID string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	svc := secretsmanager.New(session)
+	svc := newSecretsManagerClient(session)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No Secrets Manager Secrets to nuke in region %s", region)
@@ -77,7 +89,7 @@ func nukeAllSecretsManagerSecrets(session *session.Session, identifiers []*strin
 	errChans := make([]chan error, len(identifiers))
 	for i, secretID := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteSecretAsync(wg, errChans[i], svc, secretID)
+		go deleteSecretAsync(ctx, wg, errChans[i], svc, secretID, recoveryWindowDays, runID)
 	}
 	wg.Wait()
 
@@ -99,12 +111,12 @@ func nukeAllSecretsManagerSecrets(session *session.Session, identifiers []*strin
 
 // deleteSecretAsync deletes the provided secrets manager secret. Intended to be run in a goroutine, using wait groups
 // and a return channel for errors.
-func deleteSecretAsync(wg *sync.WaitGroup, errChan chan error, svc *secretsmanager.SecretsManager, secretID *string) {
+func deleteSecretAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc secretsmanageriface.SecretsManagerAPI, secretID *string, recoveryWindowDays int, runID string) {
 	defer wg.Done()
 
 	// If this region's secret is primary, and it has replicated secrets, remove replication first.
 	// Get replications
-	secret, err := svc.DescribeSecret(&secretsmanager.DescribeSecretInput{
+	secret, err := svc.DescribeSecretWithContext(ctx, &secretsmanager.DescribeSecretInput{
 		SecretId: secretID,
 	})
 
@@ -117,7 +129,7 @@ func deleteSecretAsync(wg *sync.WaitGroup, errChan chan error, svc *secretsmanag
 			replicationRegion = append(replicationRegion, replicationStatus.Region)
 		}
 
-		_, err = svc.RemoveRegionsFromReplication(&secretsmanager.RemoveRegionsFromReplicationInput{
+		_, err = svc.RemoveRegionsFromReplicationWithContext(ctx, &secretsmanager.RemoveRegionsFromReplicationInput{
 			SecretId:             secretID,
 			RemoveReplicaRegions: replicationRegion,
 		})
@@ -127,7 +139,22 @@ func deleteSecretAsync(wg *sync.WaitGroup, errChan chan error, svc *secretsmanag
 		ForceDeleteWithoutRecovery: aws.Bool(true),
 		SecretId:                   secretID,
 	}
-	_, err = svc.DeleteSecret(input)
+	if recoveryWindowDays > 0 {
+		input = &secretsmanager.DeleteSecretInput{
+			RecoveryWindowInDays: aws.Int64(int64(recoveryWindowDays)),
+			SecretId:             secretID,
+		}
+		if runID != "" {
+			_, err = svc.TagResourceWithContext(ctx, &secretsmanager.TagResourceInput{
+				SecretId: secretID,
+				Tags:     []*secretsmanager.Tag{{Key: aws.String(RunIDTagKey), Value: aws.String(runID)}},
+			})
+		}
+	}
+	// A secret that could not be tagged could not be restored, so it is left alone
+	if err == nil {
+		_, err = svc.DeleteSecretWithContext(ctx, input)
+	}
 
 	// Record status of this resource
 	e := report.Entry{
@@ -135,7 +162,70 @@ func deleteSecretAsync(wg *sync.WaitGroup, errChan chan error, svc *secretsmanag
 		ResourceType: "Secrets Manager Secret",
 		Error:        err,
 	}
-	report.Record(e)
+	if recoveryWindowDays > 0 {
+		e.Metadata = map[string]string{metadataRecoveryWindowDays: strconv.Itoa(recoveryWindowDays)}
+	}
+	record(ctx, e)
 
 	errChan <- err
 }
+
+// secretTagsToMap converts the tags of a secret into a map of tag keys to values, as used by config rules
+func secretTagsToMap(tags []*secretsmanager.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+// restoreSecretsManagerSecrets restores the secrets that the run with the given ID scheduled for deletion, and returns
+// the outcome for each of them. Secrets deleted without recovery cannot be restored.
+func restoreSecretsManagerSecrets(ctx context.Context, session *session.Session, runID string) ([]ui.ResourceRow, error) {
+	svc := newSecretsManagerClient(session)
+	region := aws.StringValue(session.Config.Region)
+
+	var secretIDs []*string
+	input := &secretsmanager.ListSecretsInput{
+		Filters:                []*secretsmanager.Filter{{Key: aws.String(secretsmanager.FilterNameStringTypeTagKey), Values: []*string{aws.String(RunIDTagKey)}}},
+		IncludePlannedDeletion: aws.Bool(true),
+	}
+	err := svc.ListSecretsPagesWithContext(ctx, input, func(page *secretsmanager.ListSecretsOutput, lastPage bool) bool {
+		for _, secret := range page.SecretList {
+			if secret.DeletedDate != nil && secretTagsToMap(secret.Tags)[RunIDTagKey] == runID {
+				secretIDs = append(secretIDs, secret.ARN)
+			}
+		}
+		return !lastPage
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var rows []ui.ResourceRow
+	for _, secretID := range secretIDs {
+		err := restoreSecretsManagerSecret(svc, secretID)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Restored Secrets Manager Secret: %s", aws.StringValue(secretID))
+		}
+		rows = append(rows, restoredResourceRow(SecretsManagerSecrets{}.ResourceName(), aws.StringValue(secretID), region, err))
+	}
+	return rows, nil
+}
+
+// restoreSecretsManagerSecret cancels the deletion of the given secret, and removes its run ID tag
+func restoreSecretsManagerSecret(svc secretsmanageriface.SecretsManagerAPI, secretID *string) error {
+	_, err := svc.RestoreSecret(&secretsmanager.RestoreSecretInput{SecretId: secretID})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	_, err = svc.UntagResource(&secretsmanager.UntagResourceInput{SecretId: secretID, TagKeys: aws.StringSlice([]string{RunIDTagKey})})
+	return errors.WithStackTrace(err)
+}
+
+// undoSecretsManagerSecretDeletion restores a secret that a run deleted with a recovery window
+func undoSecretsManagerSecretDeletion(session *session.Session, resource RunReportResource) error {
+	return restoreSecretsManagerSecret(newSecretsManagerClient(session), aws.String(resource.Identifier))
+}
@@ -1,6 +1,7 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
@@ -90,7 +91,7 @@ func TestNukeSecretOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllSecretsManagerSecrets(session, aws.StringSlice([]string{arn})),
+		nukeAllSecretsManagerSecrets(context.Background(), session, aws.StringSlice([]string{arn}), 0, ""),
 	)
 
 	// Make sure the secret is deleted.
@@ -119,7 +120,7 @@ func TestNukeSecretMoreThanOne(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllSecretsManagerSecrets(session, aws.StringSlice(secretArns)),
+		nukeAllSecretsManagerSecrets(context.Background(), session, aws.StringSlice(secretArns), 0, ""),
 	)
 
 	// Make sure the secret is deleted.
@@ -145,7 +146,7 @@ func TestNukeSecretReplica(t *testing.T) {
 
 	require.NoError(
 		t,
-		nukeAllSecretsManagerSecrets(session, aws.StringSlice([]string{arn})),
+		nukeAllSecretsManagerSecrets(context.Background(), session, aws.StringSlice([]string{arn}), 0, ""),
 	)
 
 	// Make sure the secret is deleted.
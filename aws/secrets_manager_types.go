@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -29,10 +31,31 @@ func (secret SecretsManagerSecrets) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (secret SecretsManagerSecrets) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllSecretsManagerSecrets(session, awsgo.StringSlice(identifiers)); err != nil {
+func (secret SecretsManagerSecrets) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	return secret.NukeRecoverably(ctx, session, identifiers, DefaultRecoveryWindows().Days(secret.ResourceName()), "")
+}
+
+// NukeRecoverably - delete the secrets, keeping them recoverable for the given number of days unless it is 0
+func (secret SecretsManagerSecrets) NukeRecoverably(ctx context.Context, session *session.Session, identifiers []string, recoveryWindowDays int, runID string) error {
+	if err := nukeAllSecretsManagerSecrets(ctx, session, awsgo.StringSlice(identifiers), recoveryWindowDays, runID); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:                   SecretsManagerSecrets{}.ResourceName(),
+		Description:            "Secrets Manager Secrets",
+		ConfigKey:              "SecretsManager",
+		SupportsTags:           true,
+		SupportsRuleAge:        true,
+		SupportsRecoveryWindow: true,
+		UndoDeletion:           undoSecretsManagerSecretDeletion,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllSecretsManagerSecrets(session, params.ExcludeAfter, params.Config)
+			return SecretsManagerSecrets{SecretIDs: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
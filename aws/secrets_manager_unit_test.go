@@ -0,0 +1,242 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/collections"
+)
+
+// fakeSecretsManager is an in-process Secrets Manager backend holding secrets in memory. Only the operations used by
+// cloud-nuke are implemented, calling any other operation panics.
+type fakeSecretsManager struct {
+	secretsmanageriface.SecretsManagerAPI
+
+	mutex   sync.Mutex
+	secrets []*secretsmanager.SecretListEntry
+	// replicas holds the regions each secret is replicated to
+	replicas map[string][]string
+}
+
+func (fake *fakeSecretsManager) addSecret(name string, createdAt time.Time, lastAccessedAt *time.Time, tags map[string]string) string {
+	arn := "arn:aws:secretsmanager:us-east-1:123456789012:secret:" + name
+	secret := &secretsmanager.SecretListEntry{
+		ARN:              awsgo.String(arn),
+		Name:             awsgo.String(name),
+		CreatedDate:      awsgo.Time(createdAt),
+		LastAccessedDate: lastAccessedAt,
+	}
+	for key, value := range tags {
+		secret.Tags = append(secret.Tags, &secretsmanager.Tag{Key: awsgo.String(key), Value: awsgo.String(value)})
+	}
+	fake.secrets = append(fake.secrets, secret)
+	return arn
+}
+
+// ListSecretsPages returns the secrets that are not scheduled for deletion, unless IncludePlannedDeletion is set
+func (fake *fakeSecretsManager) ListSecretsPages(input *secretsmanager.ListSecretsInput, fn func(*secretsmanager.ListSecretsOutput, bool) bool) error {
+	return fake.listSecretsPages(awsgo.BoolValue(input.IncludePlannedDeletion), fn)
+}
+
+// ListSecretsPagesWithContext also returns the secrets that are scheduled for deletion when IncludePlannedDeletion is set
+func (fake *fakeSecretsManager) ListSecretsPagesWithContext(ctx awsgo.Context, input *secretsmanager.ListSecretsInput, fn func(*secretsmanager.ListSecretsOutput, bool) bool, opts ...request.Option) error {
+	return fake.listSecretsPages(awsgo.BoolValue(input.IncludePlannedDeletion), fn)
+}
+
+func (fake *fakeSecretsManager) listSecretsPages(includePlannedDeletion bool, fn func(*secretsmanager.ListSecretsOutput, bool) bool) error {
+	fake.mutex.Lock()
+	var secrets []*secretsmanager.SecretListEntry
+	for _, secret := range fake.secrets {
+		if secret.DeletedDate == nil || includePlannedDeletion {
+			secrets = append(secrets, secret)
+		}
+	}
+	fake.mutex.Unlock()
+
+	// Return one secret per page, to make sure every page is looked at
+	for i, secret := range secrets {
+		if !fn(&secretsmanager.ListSecretsOutput{SecretList: []*secretsmanager.SecretListEntry{secret}}, i == len(secrets)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (fake *fakeSecretsManager) DescribeSecretWithContext(_ awsgo.Context, input *secretsmanager.DescribeSecretInput, _ ...request.Option) (*secretsmanager.DescribeSecretOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if fake.find(input.SecretId) < 0 {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "Secrets Manager can't find the specified secret.", nil)
+	}
+	output := &secretsmanager.DescribeSecretOutput{ARN: input.SecretId}
+	for _, region := range fake.replicas[awsgo.StringValue(input.SecretId)] {
+		output.ReplicationStatus = append(output.ReplicationStatus, &secretsmanager.ReplicationStatusType{Region: awsgo.String(region)})
+	}
+	return output, nil
+}
+
+func (fake *fakeSecretsManager) RemoveRegionsFromReplicationWithContext(_ awsgo.Context, input *secretsmanager.RemoveRegionsFromReplicationInput, _ ...request.Option) (*secretsmanager.RemoveRegionsFromReplicationOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	delete(fake.replicas, awsgo.StringValue(input.SecretId))
+	return &secretsmanager.RemoveRegionsFromReplicationOutput{}, nil
+}
+
+func (fake *fakeSecretsManager) DeleteSecretWithContext(_ awsgo.Context, input *secretsmanager.DeleteSecretInput, _ ...request.Option) (*secretsmanager.DeleteSecretOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if len(fake.replicas[awsgo.StringValue(input.SecretId)]) > 0 {
+		return nil, awserr.New(secretsmanager.ErrCodeInvalidRequestException, "You can't delete secret that has replicas.", nil)
+	}
+	i := fake.find(input.SecretId)
+	if i < 0 {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "Secrets Manager can't find the specified secret.", nil)
+	}
+	if input.RecoveryWindowInDays != nil {
+		fake.secrets[i].DeletedDate = awsgo.Time(time.Now())
+	} else {
+		fake.secrets = append(fake.secrets[:i], fake.secrets[i+1:]...)
+	}
+	return &secretsmanager.DeleteSecretOutput{ARN: input.SecretId}, nil
+}
+
+func (fake *fakeSecretsManager) RestoreSecret(input *secretsmanager.RestoreSecretInput) (*secretsmanager.RestoreSecretOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	i := fake.find(input.SecretId)
+	if i < 0 {
+		return nil, awserr.New(secretsmanager.ErrCodeResourceNotFoundException, "Secrets Manager can't find the specified secret.", nil)
+	}
+	fake.secrets[i].DeletedDate = nil
+	return &secretsmanager.RestoreSecretOutput{ARN: input.SecretId}, nil
+}
+
+func (fake *fakeSecretsManager) TagResourceWithContext(_ awsgo.Context, input *secretsmanager.TagResourceInput, _ ...request.Option) (*secretsmanager.TagResourceOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	secret := fake.secrets[fake.find(input.SecretId)]
+	secret.Tags = append(secret.Tags, input.Tags...)
+	return &secretsmanager.TagResourceOutput{}, nil
+}
+
+func (fake *fakeSecretsManager) UntagResource(input *secretsmanager.UntagResourceInput) (*secretsmanager.UntagResourceOutput, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	secret := fake.secrets[fake.find(input.SecretId)]
+	var kept []*secretsmanager.Tag
+	for _, tag := range secret.Tags {
+		if !collections.ListContainsElement(awsgo.StringValueSlice(input.TagKeys), awsgo.StringValue(tag.Key)) {
+			kept = append(kept, tag)
+		}
+	}
+	secret.Tags = kept
+	return &secretsmanager.UntagResourceOutput{}, nil
+}
+
+// find returns the index of the secret with the given ARN, or -1. The mutex must be held.
+func (fake *fakeSecretsManager) find(arn *string) int {
+	for i, secret := range fake.secrets {
+		if awsgo.StringValue(secret.ARN) == awsgo.StringValue(arn) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestListSecretsManagerSecretsOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	now := time.Now()
+	fake := &fakeSecretsManager{}
+	oldSecret := fake.addSecret("old-secret", now.Add(-2*time.Hour), nil, nil)
+	fake.addSecret("new-secret", now, nil, nil)
+	// The last access time takes precedence over the creation time
+	fake.addSecret("recently-used-secret", now.Add(-2*time.Hour), awsgo.Time(now), nil)
+	excludedSecret := fake.addSecret("excluded-secret", now.Add(-2*time.Hour), nil, map[string]string{AwsResourceExclusionTagKey: "true"})
+	fake.addSecret("protected-secret", now.Add(-2*time.Hour), nil, nil)
+	useFakeClient(t, &newSecretsManagerClient, secretsmanageriface.SecretsManagerAPI(fake))
+
+	configObj := config.Config{SecretsManagerSecrets: config.ResourceType{
+		ExcludeRule: config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile("^protected-")}}},
+	}}
+	arns, err := getAllSecretsManagerSecrets(newFakeSession(t, "us-east-1"), now.Add(-1*time.Hour), configObj)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{oldSecret}, awsgo.StringValueSlice(arns))
+	exclusions := report.GetExclusions()
+	require.Len(t, exclusions, 1)
+	assert.Equal(t, excludedSecret, exclusions[0].Identifier)
+}
+
+func TestNukeSecretsManagerSecretsOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	fake := &fakeSecretsManager{replicas: map[string][]string{}}
+	secret := fake.addSecret("secret", time.Now(), nil, nil)
+	replicatedSecret := fake.addSecret("replicated-secret", time.Now(), nil, nil)
+	fake.replicas[replicatedSecret] = []string{"eu-west-1", "us-west-2"}
+	useFakeClient(t, &newSecretsManagerClient, secretsmanageriface.SecretsManagerAPI(fake))
+
+	err := nukeAllSecretsManagerSecrets(context.Background(), newFakeSession(t, "us-east-1"), awsgo.StringSlice([]string{secret, replicatedSecret}), 0, "")
+	require.NoError(t, err)
+
+	// Replication is removed before deleting the secret
+	assert.Empty(t, fake.secrets)
+	records := report.GetRecords()
+	assert.NoError(t, records[report.RecordKey{ResourceType: "Secrets Manager Secret", Identifier: secret}].Error)
+	assert.NoError(t, records[report.RecordKey{ResourceType: "Secrets Manager Secret", Identifier: replicatedSecret}].Error)
+}
+
+func TestRestoreSecretsManagerSecretsOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	fake := &fakeSecretsManager{replicas: map[string][]string{}}
+	secret := fake.addSecret("secret", time.Now(), nil, nil)
+	otherRunSecret := fake.addSecret("other-run-secret", time.Now(), nil, nil)
+	useFakeClient(t, &newSecretsManagerClient, secretsmanageriface.SecretsManagerAPI(fake))
+	session := newFakeSession(t, "us-east-1")
+
+	require.NoError(t, SecretsManagerSecrets{}.NukeRecoverably(context.Background(), session, []string{secret}, 7, "run-1"))
+	require.NoError(t, SecretsManagerSecrets{}.NukeRecoverably(context.Background(), session, []string{otherRunSecret}, 7, "run-2"))
+	// Secrets scheduled for deletion are no longer listed
+	arns, err := getAllSecretsManagerSecrets(session, time.Now(), config.Config{})
+	require.NoError(t, err)
+	assert.Empty(t, arns)
+
+	rows, err := restoreSecretsManagerSecrets(context.Background(), session, "run-1")
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, secret, rows[0].Identifier)
+	assert.Equal(t, ui.ResourceStatusRestored, rows[0].Status)
+
+	arns, err = getAllSecretsManagerSecrets(session, time.Now(), config.Config{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{secret}, awsgo.StringValueSlice(arns))
+	assert.NotContains(t, secretTagsToMap(fake.secrets[0].Tags), RunIDTagKey)
+}
@@ -1,8 +1,10 @@
 package aws
 
 import (
+	"context"
 	"time"
 
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 
@@ -16,8 +18,8 @@ import (
 )
 
 // Returns a formatted string of Snapshot snapshot ids
-func getAllSnapshots(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := ec2.New(session)
+func getAllSnapshots(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
 
 	// status - The status of the snapshot (pending | completed | error).
 	// Since the output of this function is used to delete the returned snapshots
@@ -30,14 +32,35 @@ func getAllSnapshots(session *session.Session, region string, excludeAfter time.
 		Filters:  []*ec2.Filter{&status_filter},
 	}
 
-	output, err := svc.DescribeSnapshots(params)
+	var allSnapshots []*ec2.Snapshot
+	err := svc.DescribeSnapshotsPages(params, func(page *ec2.DescribeSnapshotsOutput, lastPage bool) bool {
+		allSnapshots = append(allSnapshots, page.Snapshots...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var snapshotIds []*string
-	for _, snapshot := range output.Snapshots {
-		if excludeAfter.After(*snapshot.StartTime) && !SnapshotHasAWSBackupTag(snapshot.Tags) {
+	for _, snapshot := range allSnapshots {
+		tags := ec2TagsToMap(snapshot.Tags)
+		if excludedByTag(Snapshots{}.ResourceName(), region, awsgo.StringValue(snapshot.SnapshotId), tags) {
+			continue
+		}
+		if !excludeAfter.After(*snapshot.StartTime) || SnapshotHasAWSBackupTag(snapshot.Tags) {
+			continue
+		}
+		// Snapshots of preserved data are kept for their own retention period by the preserved-data resource type
+		if _, ok := tags[RunIDTagKey]; ok {
+			continue
+		}
+		// Snapshots have no name of their own, so the config rules are matched against their Name tag
+		if configObj.EBSSnapshot.ShouldInclude(config.ResourceValue{
+			Name: tags["Name"],
+			Tags: tags,
+			ID:   awsgo.StringValue(snapshot.SnapshotId),
+			Time: awsgo.TimeValue(snapshot.StartTime),
+		}) {
 			snapshotIds = append(snapshotIds, snapshot.SnapshotId)
 		}
 	}
@@ -62,8 +85,8 @@ func SnapshotHasAWSBackupTag(tags []*ec2.Tag) bool {
 }
 
 // Deletes all Snapshots
-func nukeAllSnapshots(session *session.Session, snapshotIds []*string) error {
-	svc := ec2.New(session)
+func nukeAllSnapshots(ctx context.Context, session *session.Session, snapshotIds []*string) error {
+	svc := newEC2Client(session)
 
 	if len(snapshotIds) == 0 {
 		logging.Logger.Debugf("No Snapshots to nuke in region %s", *session.Config.Region)
@@ -78,7 +101,7 @@ func nukeAllSnapshots(session *session.Session, snapshotIds []*string) error {
 			SnapshotId: snapshotID,
 		}
 
-		_, err := svc.DeleteSnapshot(params)
+		_, err := svc.DeleteSnapshotWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -86,7 +109,7 @@ func nukeAllSnapshots(session *session.Session, snapshotIds []*string) error {
 			ResourceType: "EBS Snapshot",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
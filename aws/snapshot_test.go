@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -59,17 +61,17 @@ func TestListSnapshots(t *testing.T) {
 	snapshot := createTestSnapshot(t, session, uniqueTestID)
 
 	// clean up after this test
-	defer nukeAllSnapshots(session, []*string{snapshot.SnapshotId})
-	defer nukeAllEbsVolumes(session, findEBSVolumesByNameTag(t, session, uniqueTestID))
+	defer nukeAllSnapshots(context.Background(), session, []*string{snapshot.SnapshotId})
+	defer nukeAllEbsVolumes(context.Background(), session, findEBSVolumesByNameTag(t, session, uniqueTestID))
 
-	snapshots, err := getAllSnapshots(session, region, time.Now().Add(1*time.Hour*-1))
+	snapshots, err := getAllSnapshots(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of Snapshots")
 	}
 
 	assert.NotContains(t, awsgo.StringValueSlice(snapshots), *snapshot.SnapshotId)
 
-	snapshots, err = getAllSnapshots(session, region, time.Now().Add(1*time.Hour))
+	snapshots, err = getAllSnapshots(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of Snapshots")
 	}
@@ -99,7 +101,7 @@ func TestNukeSnapshots(t *testing.T) {
 	snapshot := createTestSnapshot(t, session, uniqueTestID)
 
 	// clean up ec2 instance created by the above call
-	defer nukeAllEbsVolumes(session, findEBSVolumesByNameTag(t, session, uniqueTestID))
+	defer nukeAllEbsVolumes(context.Background(), session, findEBSVolumesByNameTag(t, session, uniqueTestID))
 
 	_, err = svc.DescribeSnapshots(&ec2.DescribeSnapshotsInput{
 		SnapshotIds: []*string{snapshot.SnapshotId},
@@ -109,11 +111,11 @@ func TestNukeSnapshots(t *testing.T) {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	if err := nukeAllSnapshots(session, []*string{snapshot.SnapshotId}); err != nil {
+	if err := nukeAllSnapshots(context.Background(), session, []*string{snapshot.SnapshotId}); err != nil {
 		assert.Fail(t, errors.WithStackTrace(err).Error())
 	}
 
-	snapshots, err := getAllSnapshots(session, region, time.Now().Add(1*time.Hour))
+	snapshots, err := getAllSnapshots(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	if err != nil {
 		assert.Fail(t, "Unable to fetch list of Snapshots")
 	}
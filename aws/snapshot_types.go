@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,25 @@ func (snapshot Snapshots) MaxBatchSize() int {
 }
 
 // Nuke - nuke 'em all!!!
-func (snapshot Snapshots) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllSnapshots(session, awsgo.StringSlice(identifiers)); err != nil {
+func (snapshot Snapshots) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllSnapshots(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            Snapshots{}.ResourceName(),
+		Description:     "Snapshots",
+		ConfigKey:       "EBSSnapshot",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		DependsOn:       []string{"ami"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllSnapshots(session, params.Region, params.ExcludeAfter, params.Config)
+			return Snapshots{SnapshotIds: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
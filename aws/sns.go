@@ -4,10 +4,10 @@ import (
 	"context"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"strings"
 	"sync"
 	"time"
 
-	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -19,11 +19,10 @@ import (
 )
 
 func getAllSNSTopics(session *session.Session, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newSNSClient(session)
 	if err != nil {
 		return []*string{}, errors.WithStackTrace(err)
 	}
-	svc := sns.NewFromConfig(cfg)
 
 	allSNSTopics := []*string{}
 
@@ -35,20 +34,52 @@ func getAllSNSTopics(session *session.Session, excludeAfter time.Time, configObj
 			return []*string{}, errors.WithStackTrace(err)
 		}
 		for _, topic := range resp.Topics {
+			tags, err := getSNSTopicTags(svc, topic.TopicArn)
+			if err != nil {
+				return []*string{}, errors.WithStackTrace(err)
+			}
+			if excludedByTag(SNSTopic{}.ResourceName(), aws.StringValue(session.Config.Region), aws.StringValue(topic.TopicArn), tags) {
+				continue
+			}
+			if !configObj.SNS.ShouldInclude(config.ResourceValue{
+				Name: snsTopicName(aws.StringValue(topic.TopicArn)),
+				Tags: tags,
+				ID:   aws.StringValue(topic.TopicArn),
+			}) {
+				continue
+			}
 			allSNSTopics = append(allSNSTopics, topic.TopicArn)
 		}
 	}
 	return allSNSTopics, nil
 }
 
-func nukeAllSNSTopics(session *session.Session, identifiers []*string) error {
+// snsTopicName returns the name of the topic with the given ARN, which is its last segment
+func snsTopicName(topicArn string) string {
+	return topicArn[strings.LastIndex(topicArn, ":")+1:]
+}
+
+// getSNSTopicTags returns the tags of the given SNS topic as a map of tag keys to values
+func getSNSTopicTags(svc snsAPI, topicArn *string) (map[string]string, error) {
+	output, err := svc.ListTagsForResource(context.TODO(), &sns.ListTagsForResourceInput{ResourceArn: topicArn})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make(map[string]string)
+	for _, tag := range output.Tags {
+		tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return tags, nil
+}
+
+func nukeAllSNSTopics(ctx context.Context, session *session.Session, identifiers []*string) error {
 	region := aws.StringValue(session.Config.Region)
 
-	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(), awsconfig.WithRegion(aws.StringValue(session.Config.Region)))
+	svc, err := newSNSClient(session)
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
-	svc := sns.NewFromConfig(cfg)
 
 	if len(identifiers) == 0 {
 		logging.Logger.Debugf("No SNS Topics to nuke in region %s", region)
@@ -66,7 +97,7 @@ func nukeAllSNSTopics(session *session.Session, identifiers []*string) error {
 	errChans := make([]chan error, len(identifiers))
 	for i, topicArn := range identifiers {
 		errChans[i] = make(chan error, 1)
-		go deleteSNSTopicAsync(wg, errChans[i], svc, topicArn, region)
+		go deleteSNSTopicAsync(ctx, wg, errChans[i], svc, topicArn, region)
 	}
 	wg.Wait()
 
@@ -89,7 +120,7 @@ func nukeAllSNSTopics(session *session.Session, identifiers []*string) error {
 	return nil
 }
 
-func deleteSNSTopicAsync(wg *sync.WaitGroup, errChan chan error, svc *sns.Client, topicArn *string, region string) {
+func deleteSNSTopicAsync(ctx context.Context, wg *sync.WaitGroup, errChan chan error, svc snsAPI, topicArn *string, region string) {
 	defer wg.Done()
 
 	deleteParam := &sns.DeleteTopicInput{
@@ -98,7 +129,7 @@ func deleteSNSTopicAsync(wg *sync.WaitGroup, errChan chan error, svc *sns.Client
 
 	logging.Logger.Debugf("Deleting SNS Topic (arn=%s) in region: %s", aws.StringValue(topicArn), region)
 
-	_, err := svc.DeleteTopic(context.TODO(), deleteParam)
+	_, err := svc.DeleteTopic(ctx, deleteParam)
 
 	errChan <- err
 
@@ -108,7 +139,7 @@ func deleteSNSTopicAsync(wg *sync.WaitGroup, errChan chan error, svc *sns.Client
 		ResourceType: "SNS Topic",
 		Error:        err,
 	}
-	report.Record(e)
+	record(ctx, e)
 
 	if err == nil {
 		logging.Logger.Debugf("[OK] Deleted SNS Topic (arn=%s) in region: %s", aws.StringValue(topicArn), region)
@@ -82,7 +82,7 @@ func TestListSNSTopics(t *testing.T) {
 	testSNSTopic, createTestSNSTopicErr := createTestSNSTopic(t, session, snsTopicName)
 	require.NoError(t, createTestSNSTopicErr)
 	// clean up after this test
-	defer nukeAllSNSTopics(session, []*string{testSNSTopic.Arn})
+	defer nukeAllSNSTopics(context.Background(), session, []*string{testSNSTopic.Arn})
 
 	snsTopicArns, err := getAllSNSTopics(session, time.Now(), config.Config{})
 	if err != nil {
@@ -107,7 +107,7 @@ func TestNukeSNSTopicOne(t *testing.T) {
 	testSNSTopic, createTestSNSTopicErr := createTestSNSTopic(t, session, snsTopicName)
 	require.NoError(t, createTestSNSTopicErr)
 
-	nukeErr := nukeAllSNSTopics(session, []*string{testSNSTopic.Arn})
+	nukeErr := nukeAllSNSTopics(context.Background(), session, []*string{testSNSTopic.Arn})
 	require.NoError(t, nukeErr)
 
 	// Make sure the SNS Topic was deleted
@@ -134,7 +134,7 @@ func TestNukeSNSTopicMoreThanOne(t *testing.T) {
 	testSNSTopic2, createTestErr2 := createTestSNSTopic(t, session, testSNSTopicName2)
 	require.NoError(t, createTestErr2)
 
-	nukeErr := nukeAllSNSTopics(session, []*string{testSNSTopic.Arn, testSNSTopic2.Arn})
+	nukeErr := nukeAllSNSTopics(context.Background(), session, []*string{testSNSTopic.Arn, testSNSTopic2.Arn})
 	require.NoError(t, nukeErr)
 
 	// Make sure the SNS topics were deleted
@@ -144,3 +144,9 @@ func TestNukeSNSTopicMoreThanOne(t *testing.T) {
 	assert.NotContains(t, aws.StringValueSlice(snsTopicArns), aws.StringValue(testSNSTopic.Arn))
 	assert.NotContains(t, aws.StringValueSlice(snsTopicArns), aws.StringValue(testSNSTopic2.Arn))
 }
+
+func TestSNSTopicName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "golden-topic", snsTopicName("arn:aws:sns:us-east-1:123456789012:golden-topic"))
+}
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -22,8 +24,8 @@ func (s SNSTopic) MaxBatchSize() int {
 	return 50
 }
 
-func (s SNSTopic) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllSNSTopics(session, awsgo.StringSlice(identifiers)); err != nil {
+func (s SNSTopic) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllSNSTopics(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 	return nil
@@ -36,3 +38,16 @@ type TooManySNSTopicsErr struct{}
 func (err TooManySNSTopicsErr) Error() string {
 	return "Too many SNS Topics requested at once."
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:         SNSTopic{}.ResourceName(),
+		Description:  "SNS Topics",
+		ConfigKey:    "SNS",
+		SupportsTags: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllSNSTopics(session, params.ExcludeAfter, params.Config)
+			return SNSTopic{Arns: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,9 +1,12 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -16,8 +19,8 @@ import (
 )
 
 // Returns a formatted string of SQS Queue URLs
-func getAllSqsQueue(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := sqs.New(session)
+func getAllSqsQueue(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newSQSClient(session)
 
 	result := []*string{}
 	paginator := func(output *sqs.ListQueuesOutput, lastPage bool) bool {
@@ -54,6 +57,21 @@ func getAllSqsQueue(session *session.Session, region string, excludeAfter time.T
 
 		// Compare time as int64
 		if excludeAfter.Unix() > createdAtInt {
+			tags, err := svc.ListQueueTags(&sqs.ListQueueTagsInput{QueueUrl: queue})
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			if excludedByTag(SqsQueue{}.ResourceName(), region, awsgo.StringValue(queue), awsgo.StringValueMap(tags.Tags)) {
+				continue
+			}
+			if !configObj.SQS.ShouldInclude(config.ResourceValue{
+				Name: sqsQueueName(awsgo.StringValue(queue)),
+				Tags: awsgo.StringValueMap(tags.Tags),
+				ID:   awsgo.StringValue(queue),
+				Time: time.Unix(createdAtInt, 0),
+			}) {
+				continue
+			}
 			urls = append(urls, queue)
 		}
 	}
@@ -61,9 +79,14 @@ func getAllSqsQueue(session *session.Session, region string, excludeAfter time.T
 	return urls, nil
 }
 
+// sqsQueueName returns the name of the queue with the given URL, which is its last path segment
+func sqsQueueName(url string) string {
+	return url[strings.LastIndex(url, "/")+1:]
+}
+
 // Deletes all Elastic Load Balancers
-func nukeAllSqsQueues(session *session.Session, urls []*string) error {
-	svc := sqs.New(session)
+func nukeAllSqsQueues(ctx context.Context, session *session.Session, urls []*string) error {
+	svc := newSQSClient(session)
 
 	if len(urls) == 0 {
 		logging.Logger.Debugf("No SQS Queues to nuke in region %s", *session.Config.Region)
@@ -78,7 +101,7 @@ func nukeAllSqsQueues(session *session.Session, urls []*string) error {
 			QueueUrl: url,
 		}
 
-		_, err := svc.DeleteQueue(params)
+		_, err := svc.DeleteQueueWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -86,7 +109,7 @@ func nukeAllSqsQueues(session *session.Session, urls []*string) error {
 			ResourceType: "SQS Queue",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -68,20 +70,20 @@ func TestListSqsQueue(t *testing.T) {
 	}
 
 	// clean up after this test
-	defer nukeAllSqsQueues(session, queueList)
+	defer nukeAllSqsQueues(context.Background(), session, queueList)
 
 	// timestamps to test
 	oneHourAgo := time.Now().Add(1 * time.Hour * -1)
 	oneHourFromNow := time.Now().Add(1 * time.Hour)
 
-	urls, err := getAllSqsQueue(session, region, oneHourAgo)
+	urls, err := getAllSqsQueue(session, region, oneHourAgo, config.Config{})
 	require.NoError(t, err)
 
 	for _, queue := range queueList {
 		assert.NotContains(t, awsgo.StringValueSlice(urls), awsgo.StringValue(queue))
 	}
 
-	urls, err = getAllSqsQueue(session, region, oneHourFromNow)
+	urls, err = getAllSqsQueue(session, region, oneHourFromNow, config.Config{})
 	require.NoError(t, err)
 
 	for _, queue := range queueList {
@@ -105,24 +107,31 @@ func TestNukeSqsQueue(t *testing.T) {
 	queueUrl := createTestQueue(t, session, queueName)
 	oneHourFromNow := time.Now().Add(1 * time.Hour)
 
-	urls, err := getAllSqsQueue(session, region, oneHourFromNow)
+	urls, err := getAllSqsQueue(session, region, oneHourFromNow, config.Config{})
 	require.NoError(t, err)
 	assert.Contains(t, awsgo.StringValueSlice(urls), awsgo.StringValue(queueUrl))
 
-	err = nukeAllSqsQueues(session, []*string{queueUrl})
+	err = nukeAllSqsQueues(context.Background(), session, []*string{queueUrl})
 	require.NoError(t, err)
 
 	// SQS Queue deletion takes up to 60 seconds to be finished. See https://docs.aws.amazon.com/sdk-for-go/api/service/sqs/#SQS.DeleteQueue
 	for retry := 0; retry <= 6; retry++ {
-		urls, err = getAllSqsQueue(session, region, oneHourFromNow)
+		urls, err = getAllSqsQueue(session, region, oneHourFromNow, config.Config{})
 		if err == nil {
 			break
 		}
 
 		sleepMessage := "SQS Queue still available. Waiting 10 seconds to check again."
 		sleepFor := 10 * time.Second
-		sleepWithMessage(sleepFor, sleepMessage)
+		require.NoError(t, sleepWithMessage(context.Background(), sleepFor, sleepMessage))
 	}
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(urls), awsgo.StringValue(queueUrl))
 }
+
+func TestSqsQueueName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "golden-queue", sqsQueueName("https://sqs.us-east-1.amazonaws.com/123456789012/golden-queue"))
+	assert.Equal(t, "golden-queue", sqsQueueName("golden-queue"))
+}
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,10 +29,24 @@ func (queue SqsQueue) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (queue SqsQueue) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllSqsQueues(session, awsgo.StringSlice(identifiers)); err != nil {
+func (queue SqsQueue) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllSqsQueues(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            SqsQueue{}.ResourceName(),
+		Description:     "SQS Queues",
+		ConfigKey:       "SQS",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			ids, err := getAllSqsQueue(session, params.Region, params.ExcludeAfter, params.Config)
+			return SqsQueue{QueueUrls: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
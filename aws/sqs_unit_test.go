@@ -0,0 +1,184 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/aws/aws-sdk-go/service/sqs/sqsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+)
+
+// fakeSQS is an in-process SQS backend holding queues in memory. Only the operations used by cloud-nuke are
+// implemented, calling any other operation panics.
+type fakeSQS struct {
+	sqsiface.SQSAPI
+
+	mutex  sync.Mutex
+	urls   []string
+	queues map[string]fakeSQSQueue
+	// deleteErrors makes DeleteQueue fail for the given queue URLs
+	deleteErrors map[string]error
+}
+
+type fakeSQSQueue struct {
+	createdAt time.Time
+	tags      map[string]string
+}
+
+func newFakeSQS() *fakeSQS {
+	return &fakeSQS{queues: map[string]fakeSQSQueue{}, deleteErrors: map[string]error{}}
+}
+
+func (fake *fakeSQS) addQueue(name string, createdAt time.Time, tags map[string]string) string {
+	url := "https://sqs.us-east-1.amazonaws.com/123456789012/" + name
+	fake.urls = append(fake.urls, url)
+	fake.queues[url] = fakeSQSQueue{createdAt: createdAt, tags: tags}
+	return url
+}
+
+func (fake *fakeSQS) ListQueuesPages(input *sqs.ListQueuesInput, fn func(*sqs.ListQueuesOutput, bool) bool) error {
+	fake.mutex.Lock()
+	urls := append([]string{}, fake.urls...)
+	fake.mutex.Unlock()
+
+	pageSize := int(awsgo.Int64Value(input.MaxResults))
+	if pageSize < 1 {
+		// Without MaxResults, ListQueues returns up to 1000 queues
+		pageSize = 1000
+	}
+	for start := 0; start < len(urls) || start == 0; start += pageSize {
+		end := start + pageSize
+		if end > len(urls) {
+			end = len(urls)
+		}
+		if !fn(&sqs.ListQueuesOutput{QueueUrls: awsgo.StringSlice(urls[start:end])}, end == len(urls)) {
+			break
+		}
+	}
+	return nil
+}
+
+func (fake *fakeSQS) GetQueueAttributes(input *sqs.GetQueueAttributesInput) (*sqs.GetQueueAttributesOutput, error) {
+	queue, err := fake.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	createdAt := strconv.FormatInt(queue.createdAt.Unix(), 10)
+	return &sqs.GetQueueAttributesOutput{Attributes: map[string]*string{"CreatedTimestamp": awsgo.String(createdAt)}}, nil
+}
+
+func (fake *fakeSQS) ListQueueTags(input *sqs.ListQueueTagsInput) (*sqs.ListQueueTagsOutput, error) {
+	queue, err := fake.queue(input.QueueUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &sqs.ListQueueTagsOutput{Tags: awsgo.StringMap(queue.tags)}, nil
+}
+
+func (fake *fakeSQS) DeleteQueueWithContext(_ awsgo.Context, input *sqs.DeleteQueueInput, _ ...request.Option) (*sqs.DeleteQueueOutput, error) {
+	if _, err := fake.queue(input.QueueUrl); err != nil {
+		return nil, err
+	}
+
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	url := awsgo.StringValue(input.QueueUrl)
+	if err := fake.deleteErrors[url]; err != nil {
+		return nil, err
+	}
+	delete(fake.queues, url)
+	for i := range fake.urls {
+		if fake.urls[i] == url {
+			fake.urls = append(fake.urls[:i], fake.urls[i+1:]...)
+			break
+		}
+	}
+	return &sqs.DeleteQueueOutput{}, nil
+}
+
+func (fake *fakeSQS) queue(url *string) (fakeSQSQueue, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	queue, ok := fake.queues[awsgo.StringValue(url)]
+	if !ok {
+		return fakeSQSQueue{}, awserr.New(sqs.ErrCodeQueueDoesNotExist, "The specified queue does not exist.", nil)
+	}
+	return queue, nil
+}
+
+func TestListSqsQueuesOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	now := time.Now()
+	fake := newFakeSQS()
+	oldQueue := fake.addQueue("old-queue", now.Add(-2*time.Hour), nil)
+	fake.addQueue("new-queue", now, nil)
+	excludedQueue := fake.addQueue("excluded-queue", now.Add(-2*time.Hour), map[string]string{AwsResourceExclusionTagKey: "true"})
+	fake.addQueue("protected-queue", now.Add(-2*time.Hour), nil)
+	// More queues than fit in a single page of results
+	var pagedQueues []string
+	for i := 0; i < 12; i++ {
+		pagedQueues = append(pagedQueues, fake.addQueue(fmt.Sprintf("paged-queue-%d", i), now.Add(-2*time.Hour), nil))
+	}
+	useFakeClient(t, &newSQSClient, sqsiface.SQSAPI(fake))
+
+	configObj := config.Config{SQS: config.ResourceType{
+		ExcludeRule: config.FilterRule{NamesRegExp: []config.Expression{{RE: *regexp.MustCompile("^protected-")}}},
+	}}
+	urls, err := getAllSqsQueue(newFakeSession(t, "us-east-1"), "us-east-1", now.Add(-1*time.Hour), configObj)
+	require.NoError(t, err)
+
+	assert.Equal(t, append([]string{oldQueue}, pagedQueues...), awsgo.StringValueSlice(urls))
+	exclusions := report.GetExclusions()
+	require.Len(t, exclusions, 1)
+	assert.Equal(t, excludedQueue, exclusions[0].Identifier)
+	assert.Equal(t, ExcludedByTagReason, exclusions[0].Reason)
+}
+
+func TestNukeSqsQueuesOffline(t *testing.T) {
+	telemetry.InitTelemetry("cloud-nuke", "", "")
+	report.ResetRecords()
+	defer report.ResetRecords()
+
+	fake := newFakeSQS()
+	queue := fake.addQueue("queue", time.Now(), nil)
+	lockedQueue := fake.addQueue("locked-queue", time.Now(), nil)
+	fake.deleteErrors[lockedQueue] = awserr.New("AccessDenied", "not allowed", nil)
+	useFakeClient(t, &newSQSClient, sqsiface.SQSAPI(fake))
+
+	err := nukeAllSqsQueues(context.Background(), newFakeSession(t, "us-east-1"), awsgo.StringSlice([]string{queue, lockedQueue}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{lockedQueue}, fake.urls)
+	records := report.GetRecords()
+	assert.NoError(t, records[report.RecordKey{ResourceType: "SQS Queue", Identifier: queue}].Error)
+	assert.Error(t, records[report.RecordKey{ResourceType: "SQS Queue", Identifier: lockedQueue}].Error)
+}
+
+func TestFakeSQSListQueuesPagesWithoutMaxResults(t *testing.T) {
+	fake := newFakeSQS()
+	queue := fake.addQueue("queue", time.Now(), nil)
+
+	var urls []string
+	err := fake.ListQueuesPages(&sqs.ListQueuesInput{}, func(page *sqs.ListQueuesOutput, lastPage bool) bool {
+		urls = append(urls, awsgo.StringValueSlice(page.QueueUrls)...)
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{queue}, urls)
+}
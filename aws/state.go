@@ -0,0 +1,337 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// RunStateVersion is the version of the state file format written by WriteRunState. ReadRunState refuses state files of
+// other versions.
+const RunStateVersion = 1
+
+const (
+	// ResourceStatePending is the state of a resource that was selected for nuking, but has no recorded outcome yet
+	ResourceStatePending = "pending"
+	// ResourceStateDeleted is the state of a resource that was nuked, or that no longer exists
+	ResourceStateDeleted = "deleted"
+	// ResourceStateFailed is the state of a resource that could not be nuked
+	ResourceStateFailed = "failed"
+)
+
+// RunState records the progress of a nuke run, so that an interrupted run can be resumed with
+// `cloud-nuke aws --resume`
+type RunState struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// AccountID is the ID of the account whose resources the run nukes, which is the only account it may be resumed in.
+	// It is empty in state files written before the account was recorded.
+	AccountID string `json:"accountId,omitempty"`
+	// Regions and ResourceTypes are the scope of the discovery that selected the resources. Resuming the run only
+	// scans the part of this scope that still has resources left to nuke.
+	Regions                  []string        `json:"regions"`
+	ResourceTypes            []string        `json:"resourceTypes"`
+	AllowDeleteUnaliasedKeys bool            `json:"allowDeleteUnaliasedKeys"`
+	Resources                []ResourceState `json:"resources"`
+	// RunID, Mode, PreserveData and RecoveryWindows are the ID of the run, the mode, the data preservation and the
+	// recovery windows it used, which are kept when it is resumed so that the remaining resources are nuked in the same
+	// way
+	RunID           string            `json:"runId,omitempty"`
+	Mode            NukeMode          `json:"mode,omitempty"`
+	PreserveData    *DataPreservation `json:"preserveData,omitempty"`
+	RecoveryWindows RecoveryWindows   `json:"recoveryWindows,omitempty"`
+	// Accounts and Limits are the account rules and resource limits in effect when the run started, from the config
+	// file and the flags, which resuming the run enforces again
+	Accounts config.AccountRules   `json:"accounts"`
+	Limits   config.ResourceLimits `json:"limits"`
+
+	mutex sync.Mutex
+	// index holds the position in Resources of every resource, keyed by region, resource type and identifier. It is
+	// built on first use, as Resources is also filled in by ReadRunState.
+	index map[PlannedResource]int
+	// changed signals the checkpoint writer that the state was updated, while checkpointing
+	changed chan struct{}
+	// writeMutex serializes writing the state file, so that an older snapshot never replaces a newer one
+	writeMutex sync.Mutex
+}
+
+// checkpointInterval is how long the checkpoint writer waits after an update before writing the state file, so that the
+// outcomes recorded meanwhile are written at once
+var checkpointInterval = time.Second
+
+// ResourceState is the progress of nuking a single resource
+type ResourceState struct {
+	Region       string `json:"region"`
+	ResourceType string `json:"resourceType"`
+	Identifier   string `json:"identifier"`
+	Status       string `json:"status"`
+	// Error is the reason why a failed resource could not be nuked
+	Error string `json:"error,omitempty"`
+}
+
+// NewRunState records the given resources, which are about to be nuked, as pending. The remaining arguments describe
+// how the resources were discovered, so that they can be discovered again when the run is resumed.
+func NewRunState(account *AwsAccountResources, regions []string, resourceTypes []string, allowDeleteUnaliasedKeys bool) *RunState {
+	now := time.Now().UTC()
+	state := &RunState{
+		Version:                  RunStateVersion,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+		Regions:                  regions,
+		ResourceTypes:            resourceTypes,
+		AllowDeleteUnaliasedKeys: allowDeleteUnaliasedKeys,
+		Resources:                []ResourceState{},
+	}
+
+	for _, region := range regions {
+		resourcesInRegion, ok := account.Resources[region]
+		if !ok {
+			continue
+		}
+		for _, resources := range resourcesInRegion.Resources {
+			for _, identifier := range resources.ResourceIdentifiers() {
+				state.Resources = append(state.Resources, ResourceState{
+					Region:       region,
+					ResourceType: resources.ResourceName(),
+					Identifier:   identifier,
+					Status:       ResourceStatePending,
+				})
+			}
+		}
+	}
+
+	return state
+}
+
+// WriteRunState writes the state to the given path as JSON. The file is replaced atomically, so that an interruption
+// while writing never leaves a truncated state file behind.
+func WriteRunState(state *RunState, path string) error {
+	state.writeMutex.Lock()
+	defer state.writeMutex.Unlock()
+
+	state.mutex.Lock()
+	data, err := json.MarshalIndent(state, "", "  ")
+	state.mutex.Unlock()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return writeFileAtomically(path, data)
+}
+
+// writeFileAtomically writes the given data to the given path through a temporary file that replaces it, so that an
+// interruption while writing never leaves a truncated file behind
+func writeFileAtomically(path string, data []byte) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// ReadRunState reads a state file written by WriteRunState
+func ReadRunState(path string) (*RunState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidStateFileError{Path: path, Underlying: err})
+	}
+
+	state := &RunState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, errors.WithStackTrace(InvalidStateFileError{Path: path, Underlying: err})
+	}
+	if state.Version != RunStateVersion {
+		return nil, errors.WithStackTrace(UnsupportedStateVersionError{Path: path, Version: state.Version})
+	}
+	if len(state.Regions) == 0 {
+		return nil, errors.WithStackTrace(InvalidStateFileError{Path: path, Underlying: fmt.Errorf("no regions")})
+	}
+	return state, nil
+}
+
+// Checkpoint writes the state to the given path, and then writes it again from a single goroutine whenever
+// RecordOutcome updates it, at most once every checkpointInterval. The returned function stops checkpointing, after
+// writing the updates that were not written yet.
+func (state *RunState) Checkpoint(path string) (func(), error) {
+	if err := WriteRunState(state, path); err != nil {
+		return nil, err
+	}
+
+	changed := make(chan struct{}, 1)
+	stopped := make(chan struct{})
+	done := make(chan struct{})
+	state.mutex.Lock()
+	state.changed = changed
+	state.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-changed:
+			case <-stopped:
+				return
+			}
+			select {
+			case <-time.After(checkpointInterval):
+			case <-stopped:
+				return
+			}
+			state.writeCheckpoint(path)
+		}
+	}()
+
+	return func() {
+		state.mutex.Lock()
+		state.changed = nil
+		state.mutex.Unlock()
+		close(stopped)
+		<-done
+		state.writeCheckpoint(path)
+	}, nil
+}
+
+// writeCheckpoint writes the state to the given path. Failing to checkpoint must not interrupt the run, the state file
+// is only needed if the run is interrupted, so errors are only logged.
+func (state *RunState) writeCheckpoint(path string) {
+	if err := WriteRunState(state, path); err != nil {
+		logging.Logger.Warnf("Failed to write state file %s: %s", path, err)
+	}
+}
+
+// RecordOutcome updates the resource of the given region and type with the given outcome of nuking it, and signals the
+// checkpoint writer if the state is being checkpointed. It matches NukeOptions.OnOutcome.
+func (state *RunState) RecordOutcome(region string, resourceType string, entry report.Entry) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if !state.recordOutcome(plannedResourceKey(region, resourceType, entry.Identifier), entry.Error) {
+		return
+	}
+	if state.changed != nil {
+		select {
+		case state.changed <- struct{}{}:
+		default:
+			// The writer was already signalled and has yet to write
+		}
+	}
+}
+
+// recordOutcome updates the given resource, unless it was deleted already, with the outcome of nuking it. A resource
+// whose nuking was cancelled is pending again. It returns true if the resource was updated. The caller must hold the
+// mutex of the state.
+func (state *RunState) recordOutcome(key PlannedResource, err error) bool {
+	if state.index == nil {
+		state.index = make(map[PlannedResource]int, len(state.Resources))
+		for i, resource := range state.Resources {
+			state.index[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] = i
+		}
+	}
+	i, ok := state.index[key]
+	if !ok || state.Resources[i].Status == ResourceStateDeleted {
+		return false
+	}
+
+	resource := &state.Resources[i]
+	if report.IsCancelled(err) {
+		resource.Status = ResourceStatePending
+		resource.Error = ""
+	} else if err != nil {
+		resource.Status = ResourceStateFailed
+		resource.Error = err.Error()
+	} else {
+		resource.Status = ResourceStateDeleted
+		resource.Error = ""
+	}
+	state.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// MarkDeleted records the given resources as deleted. It is used for resources that no longer exist when the run is
+// resumed.
+func (state *RunState) MarkDeleted(resources []PlannedResource) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	gone := map[PlannedResource]bool{}
+	for _, resource := range resources {
+		gone[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] = true
+	}
+	for i := range state.Resources {
+		resource := &state.Resources[i]
+		if gone[plannedResourceKey(resource.Region, resource.ResourceType, resource.Identifier)] {
+			resource.Status = ResourceStateDeleted
+			resource.Error = ""
+		}
+	}
+	state.UpdatedAt = time.Now().UTC()
+}
+
+// Remaining returns the resources that are still pending or failed as a plan, narrowed down to the regions and resource
+// types that have such resources
+func (state *RunState) Remaining() *Plan {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	plan := &Plan{
+		Version:                  PlanVersion,
+		CreatedAt:                state.CreatedAt,
+		AllowDeleteUnaliasedKeys: state.AllowDeleteUnaliasedKeys,
+		Resources:                []PlannedResource{},
+		Mode:                     state.Mode,
+	}
+	regions := map[string]bool{}
+	resourceTypes := map[string]bool{}
+	for _, resource := range state.Resources {
+		if resource.Status == ResourceStateDeleted {
+			continue
+		}
+		reason := "pending in the state of an interrupted run"
+		if resource.Status == ResourceStateFailed {
+			reason = fmt.Sprintf("failed to nuke in a previous run: %s", resource.Error)
+		}
+		plan.Resources = append(plan.Resources, PlannedResource{
+			Region:       resource.Region,
+			ResourceType: resource.ResourceType,
+			Identifier:   resource.Identifier,
+			Reason:       reason,
+		})
+		regions[resource.Region] = true
+		resourceTypes[resource.ResourceType] = true
+	}
+
+	// Keep the original order, as it determines the order in which resources are nuked
+	for _, region := range state.Regions {
+		if regions[region] {
+			plan.Regions = append(plan.Regions, region)
+		}
+	}
+	for _, resourceType := range state.ResourceTypes {
+		if resourceTypes[resourceType] {
+			plan.ResourceTypes = append(plan.ResourceTypes, resourceType)
+		}
+	}
+	return plan
+}
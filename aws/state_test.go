@@ -0,0 +1,140 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestNewRunState(t *testing.T) {
+	t.Parallel()
+
+	state := NewRunState(newTestPlanAccount([]string{"i-1", "i-2"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1", GlobalRegion}, []string{"ec2", "ebs"}, true)
+
+	assert.Equal(t, RunStateVersion, state.Version)
+	assert.True(t, state.AllowDeleteUnaliasedKeys)
+	require.Len(t, state.Resources, 3)
+	assert.Equal(t, ResourceState{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-1", Status: ResourceStatePending}, state.Resources[0])
+	assert.Equal(t, "vol-1", state.Resources[2].Identifier)
+}
+
+func TestWriteAndReadRunState(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewRunState(newTestPlanAccount([]string{"i-1"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1"}, []string{"ec2", "ebs"}, false)
+	state.AccountID = "123456789012"
+	state.recordOutcome(plannedResourceKey("eu-west-1", "ebs", "vol-1"), fmt.Errorf("VolumeInUse"))
+	require.NoError(t, WriteRunState(state, path))
+
+	readState, err := ReadRunState(path)
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", readState.AccountID)
+	assert.Equal(t, state.Regions, readState.Regions)
+	assert.Equal(t, state.ResourceTypes, readState.ResourceTypes)
+	assert.Equal(t, state.Resources, readState.Resources)
+
+	// No temporary files are left behind
+	files, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestReadRunStateErrors(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	_, err := ReadRunState(filepath.Join(dir, "missing.json"))
+	assert.IsType(t, InvalidStateFileError{}, errors.Unwrap(err))
+
+	futurePath := filepath.Join(dir, "future.json")
+	require.NoError(t, os.WriteFile(futurePath, []byte(`{"version": 2, "regions": ["us-east-1"]}`), 0644))
+	_, err = ReadRunState(futurePath)
+	assert.Equal(t, UnsupportedStateVersionError{Path: futurePath, Version: 2}, errors.Unwrap(err))
+}
+
+func TestRunStateRecordOutcome(t *testing.T) {
+	t.Parallel()
+
+	state := NewRunState(newTestPlanAccount([]string{"i-1", "i-2"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1"}, []string{"ec2", "ebs"}, false)
+
+	assert.True(t, state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "i-1"), nil))
+	assert.True(t, state.recordOutcome(plannedResourceKey("eu-west-1", "ebs", "vol-1"), fmt.Errorf("VolumeInUse")))
+	assert.False(t, state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "unknown"), nil))
+	// Resources are matched by region and resource type as well as identifier
+	assert.False(t, state.recordOutcome(plannedResourceKey("us-east-1", "ebs", "vol-1"), nil))
+	// A resource that was deleted stays deleted
+	assert.False(t, state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "i-1"), fmt.Errorf("InvalidInstanceID.NotFound")))
+
+	assert.Equal(t, ResourceStateDeleted, state.Resources[0].Status)
+	assert.Equal(t, ResourceStatePending, state.Resources[1].Status)
+	assert.Equal(t, ResourceStateFailed, state.Resources[2].Status)
+	assert.Equal(t, "VolumeInUse", state.Resources[2].Error)
+
+	// Failed resources are deleted when retried successfully
+	assert.True(t, state.recordOutcome(plannedResourceKey("eu-west-1", "ebs", "vol-1"), nil))
+	assert.Equal(t, ResourceStateDeleted, state.Resources[2].Status)
+	assert.Empty(t, state.Resources[2].Error)
+
+	// Resources whose nuking was cancelled are pending again
+	assert.True(t, state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "i-2"), fmt.Errorf("OperationNotPermitted")))
+	assert.True(t, state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "i-2"), context.Canceled))
+	assert.Equal(t, ResourceStatePending, state.Resources[1].Status)
+	assert.Empty(t, state.Resources[1].Error)
+}
+
+func TestRunStateRemaining(t *testing.T) {
+	t.Parallel()
+
+	state := NewRunState(newTestPlanAccount([]string{"i-1", "i-2"}, []string{"vol-1"}), []string{"us-east-1", "eu-west-1", GlobalRegion}, []string{"ec2", "ebs"}, true)
+	state.Mode = NukeModeQuarantine
+	state.recordOutcome(plannedResourceKey("us-east-1", "ec2", "i-1"), nil)
+	state.recordOutcome(plannedResourceKey("eu-west-1", "ebs", "vol-1"), nil)
+
+	plan := state.Remaining()
+	assert.Equal(t, NukeModeQuarantine, plan.Mode)
+	assert.Equal(t, []string{"us-east-1"}, plan.Regions)
+	assert.Equal(t, []string{"ec2"}, plan.ResourceTypes)
+	assert.True(t, plan.AllowDeleteUnaliasedKeys)
+	require.Len(t, plan.Resources, 1)
+	assert.Equal(t, "i-2", plan.Resources[0].Identifier)
+
+	state.MarkDeleted(plan.Resources)
+	assert.Empty(t, state.Remaining().Resources)
+}
+
+func TestRunStateCheckpoint(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := NewRunState(newTestPlanAccount([]string{"i-1", "i-2"}, nil), []string{"us-east-1"}, []string{"ec2"}, false)
+
+	stop, err := state.Checkpoint(path)
+	require.NoError(t, err)
+	state.RecordOutcome("us-east-1", "ec2", report.Entry{Identifier: "i-1"})
+
+	// The update is written by the checkpoint writer while the run goes on
+	assert.Eventually(t, func() bool {
+		readState, err := ReadRunState(path)
+		return err == nil && readState.Resources[0].Status == ResourceStateDeleted
+	}, 10*checkpointInterval, checkpointInterval/10)
+
+	// The updates not written yet are written when checkpointing stops, and the later ones are not written
+	state.RecordOutcome("us-east-1", "ec2", report.Entry{Identifier: "i-2", Error: fmt.Errorf("OperationNotPermitted")})
+	stop()
+	state.RecordOutcome("us-east-1", "ec2", report.Entry{Identifier: "i-2"})
+
+	readState, err := ReadRunState(path)
+	require.NoError(t, err)
+	assert.Equal(t, ResourceStateDeleted, readState.Resources[0].Status)
+	assert.Equal(t, ResourceStateFailed, readState.Resources[1].Status)
+	assert.Equal(t, "OperationNotPermitted", readState.Resources[1].Error)
+}
@@ -0,0 +1,41 @@
+package aws
+
+import (
+	"strings"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ec2TagsToMap converts the tags of an EC2 resource into a map of tag keys to values, as used by config rules
+func ec2TagsToMap(tags []*ec2.Tag) map[string]string {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		if tag == nil {
+			continue
+		}
+		tagMap[awsgo.StringValue(tag.Key)] = awsgo.StringValue(tag.Value)
+	}
+	return tagMap
+}
+
+// hasExclusionTag returns true if the given tags include the exclusion tag (cloud-nuke-excluded=true). Like for S3
+// buckets, both the key and the value are compared case-insensitively.
+func hasExclusionTag(tags map[string]string) bool {
+	for key, value := range tags {
+		if strings.ToLower(key) == AwsResourceExclusionTagKey && strings.ToLower(value) == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedByTag returns true if the given tags include the exclusion tag, in which case the resource is recorded as
+// excluded so that it shows up as such when inspecting resources. Listers must skip resources for which it returns true.
+func excludedByTag(resourceType string, region string, identifier string, tags map[string]string) bool {
+	if !hasExclusionTag(tags) {
+		return false
+	}
+	recordExclusion(resourceType, region, identifier, ExcludedByTagReason)
+	return true
+}
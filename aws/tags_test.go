@@ -0,0 +1,47 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+)
+
+func TestHasExclusionTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		tags     map[string]string
+		expected bool
+	}{
+		{"NoTags", nil, false},
+		{"OtherTags", map[string]string{"Name": "shared-key"}, false},
+		{"ExclusionTag", map[string]string{AwsResourceExclusionTagKey: "true"}, true},
+		{"ExclusionTagOtherCase", map[string]string{"Cloud-Nuke-Excluded": "TRUE"}, true},
+		{"ExclusionTagFalse", map[string]string{AwsResourceExclusionTagKey: "false"}, false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, hasExclusionTag(tt.tags))
+		})
+	}
+}
+
+func TestExcludedByTagRecordsExclusion(t *testing.T) {
+	report.ResetExclusions()
+	defer report.ResetExclusions()
+
+	assert.False(t, excludedByTag("rds", "us-east-1", "db-1", map[string]string{"Name": "db-1"}))
+	assert.True(t, excludedByTag("rds", "us-east-1", "db-2", map[string]string{AwsResourceExclusionTagKey: "true"}))
+
+	exclusions := report.GetExclusions()
+	if assert.Len(t, exclusions, 1) {
+		assert.Equal(t, "db-2", exclusions[0].Identifier)
+		assert.Equal(t, "rds", exclusions[0].ResourceType)
+		assert.Equal(t, "us-east-1", exclusions[0].Region)
+		assert.Equal(t, ExcludedByTagReason, exclusions[0].Reason)
+	}
+}
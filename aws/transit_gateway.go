@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
 	"time"
@@ -15,22 +17,31 @@ import (
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
-func sleepWithMessage(duration time.Duration, whySleepMessage string) {
+func sleepWithMessage(ctx context.Context, duration time.Duration, whySleepMessage string) error {
 	logging.Logger.Debugf("Sleeping %v: %s", duration, whySleepMessage)
-	time.Sleep(duration)
+	return sleepWithContext(ctx, duration)
 }
 
 // Returns a formatted string of TransitGateway IDs
-func getAllTransitGatewayInstances(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := ec2.New(session)
-	result, err := svc.DescribeTransitGateways(&ec2.DescribeTransitGatewaysInput{})
+func getAllTransitGatewayInstances(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
+	var allTransitGateways []*ec2.TransitGateway
+	err := svc.DescribeTransitGatewaysPages(&ec2.DescribeTransitGatewaysInput{}, func(page *ec2.DescribeTransitGatewaysOutput, lastPage bool) bool {
+		allTransitGateways = append(allTransitGateways, page.TransitGateways...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var ids []*string
-	for _, transitGateway := range result.TransitGateways {
-		if excludeAfter.After(*transitGateway.CreationTime) && awsgo.StringValue(transitGateway.State) != "deleted" && awsgo.StringValue(transitGateway.State) != "deleting" {
+	for _, transitGateway := range allTransitGateways {
+		tags := ec2TagsToMap(transitGateway.Tags)
+		if excludedByTag(TransitGateways{}.ResourceName(), region, awsgo.StringValue(transitGateway.TransitGatewayId), tags) {
+			continue
+		}
+		if excludeAfter.After(*transitGateway.CreationTime) && awsgo.StringValue(transitGateway.State) != "deleted" && awsgo.StringValue(transitGateway.State) != "deleting" &&
+			configObj.TransitGateway.ShouldInclude(config.ResourceValue{Name: tags["Name"], Tags: tags, ID: awsgo.StringValue(transitGateway.TransitGatewayId), Time: awsgo.TimeValue(transitGateway.CreationTime)}) {
 			ids = append(ids, transitGateway.TransitGatewayId)
 		}
 	}
@@ -39,8 +50,8 @@ func getAllTransitGatewayInstances(session *session.Session, region string, excl
 }
 
 // Delete all TransitGateways
-func nukeAllTransitGatewayInstances(session *session.Session, ids []*string) error {
-	svc := ec2.New(session)
+func nukeAllTransitGatewayInstances(ctx context.Context, session *session.Session, ids []*string) error {
+	svc := newEC2Client(session)
 
 	if len(ids) == 0 {
 		logging.Logger.Debugf("No Transit Gateways to nuke in region %s", *session.Config.Region)
@@ -55,7 +66,7 @@ func nukeAllTransitGatewayInstances(session *session.Session, ids []*string) err
 			TransitGatewayId: id,
 		}
 
-		_, err := svc.DeleteTransitGateway(params)
+		_, err := svc.DeleteTransitGatewayWithContext(ctx, params)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -63,7 +74,7 @@ func nukeAllTransitGatewayInstances(session *session.Session, ids []*string) err
 			ResourceType: "Transit Gateway",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -83,8 +94,8 @@ func nukeAllTransitGatewayInstances(session *session.Session, ids []*string) err
 }
 
 // Returns a formatted string of TranstGatewayRouteTable IDs
-func getAllTransitGatewayRouteTables(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := ec2.New(session)
+func getAllTransitGatewayRouteTables(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
 
 	// Remove defalt route table, that will be deleted along with its TransitGateway
 	param := &ec2.DescribeTransitGatewayRouteTablesInput{
@@ -98,14 +109,23 @@ func getAllTransitGatewayRouteTables(session *session.Session, region string, ex
 		},
 	}
 
-	result, err := svc.DescribeTransitGatewayRouteTables(param)
+	var allTransitGatewayRouteTables []*ec2.TransitGatewayRouteTable
+	err := svc.DescribeTransitGatewayRouteTablesPages(param, func(page *ec2.DescribeTransitGatewayRouteTablesOutput, lastPage bool) bool {
+		allTransitGatewayRouteTables = append(allTransitGatewayRouteTables, page.TransitGatewayRouteTables...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var ids []*string
-	for _, transitGatewayRouteTable := range result.TransitGatewayRouteTables {
-		if excludeAfter.After(*transitGatewayRouteTable.CreationTime) && awsgo.StringValue(transitGatewayRouteTable.State) != "deleted" && awsgo.StringValue(transitGatewayRouteTable.State) != "deleting" {
+	for _, transitGatewayRouteTable := range allTransitGatewayRouteTables {
+		tags := ec2TagsToMap(transitGatewayRouteTable.Tags)
+		if excludedByTag(TransitGatewaysRouteTables{}.ResourceName(), region, awsgo.StringValue(transitGatewayRouteTable.TransitGatewayRouteTableId), tags) {
+			continue
+		}
+		if excludeAfter.After(*transitGatewayRouteTable.CreationTime) && awsgo.StringValue(transitGatewayRouteTable.State) != "deleted" && awsgo.StringValue(transitGatewayRouteTable.State) != "deleting" &&
+			configObj.TransitGatewayRouteTable.ShouldInclude(config.ResourceValue{Name: tags["Name"], Tags: tags, ID: awsgo.StringValue(transitGatewayRouteTable.TransitGatewayRouteTableId), Time: awsgo.TimeValue(transitGatewayRouteTable.CreationTime)}) {
 			ids = append(ids, transitGatewayRouteTable.TransitGatewayRouteTableId)
 		}
 	}
@@ -114,8 +134,8 @@ func getAllTransitGatewayRouteTables(session *session.Session, region string, ex
 }
 
 // Delete all TransitGatewayRouteTables
-func nukeAllTransitGatewayRouteTables(session *session.Session, ids []*string) error {
-	svc := ec2.New(session)
+func nukeAllTransitGatewayRouteTables(ctx context.Context, session *session.Session, ids []*string) error {
+	svc := newEC2Client(session)
 
 	if len(ids) == 0 {
 		logging.Logger.Debugf("No Transit Gateway Route Tables to nuke in region %s", *session.Config.Region)
@@ -130,7 +150,7 @@ func nukeAllTransitGatewayRouteTables(session *session.Session, ids []*string) e
 			TransitGatewayRouteTableId: id,
 		}
 
-		_, err := svc.DeleteTransitGatewayRouteTable(param)
+		_, err := svc.DeleteTransitGatewayRouteTableWithContext(ctx, param)
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
 		} else {
@@ -144,16 +164,25 @@ func nukeAllTransitGatewayRouteTables(session *session.Session, ids []*string) e
 }
 
 // Returns a formated string of TransitGatewayVpcAttachment IDs
-func getAllTransitGatewayVpcAttachments(session *session.Session, region string, excludeAfter time.Time) ([]*string, error) {
-	svc := ec2.New(session)
-	result, err := svc.DescribeTransitGatewayVpcAttachments(&ec2.DescribeTransitGatewayVpcAttachmentsInput{})
+func getAllTransitGatewayVpcAttachments(session *session.Session, region string, excludeAfter time.Time, configObj config.Config) ([]*string, error) {
+	svc := newEC2Client(session)
+	var allTransitGatewayVpcAttachments []*ec2.TransitGatewayVpcAttachment
+	err := svc.DescribeTransitGatewayVpcAttachmentsPages(&ec2.DescribeTransitGatewayVpcAttachmentsInput{}, func(page *ec2.DescribeTransitGatewayVpcAttachmentsOutput, lastPage bool) bool {
+		allTransitGatewayVpcAttachments = append(allTransitGatewayVpcAttachments, page.TransitGatewayVpcAttachments...)
+		return !lastPage
+	})
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
 	var ids []*string
-	for _, tgwVpcAttachment := range result.TransitGatewayVpcAttachments {
-		if excludeAfter.After(*tgwVpcAttachment.CreationTime) && awsgo.StringValue(tgwVpcAttachment.State) != "deleted" && awsgo.StringValue(tgwVpcAttachment.State) != "deleting" {
+	for _, tgwVpcAttachment := range allTransitGatewayVpcAttachments {
+		tags := ec2TagsToMap(tgwVpcAttachment.Tags)
+		if excludedByTag(TransitGatewaysVpcAttachment{}.ResourceName(), region, awsgo.StringValue(tgwVpcAttachment.TransitGatewayAttachmentId), tags) {
+			continue
+		}
+		if excludeAfter.After(*tgwVpcAttachment.CreationTime) && awsgo.StringValue(tgwVpcAttachment.State) != "deleted" && awsgo.StringValue(tgwVpcAttachment.State) != "deleting" &&
+			configObj.TransitGatewayVpcAttachment.ShouldInclude(config.ResourceValue{Name: tags["Name"], Tags: tags, ID: awsgo.StringValue(tgwVpcAttachment.TransitGatewayAttachmentId), Time: awsgo.TimeValue(tgwVpcAttachment.CreationTime)}) {
 			ids = append(ids, tgwVpcAttachment.TransitGatewayAttachmentId)
 		}
 	}
@@ -162,8 +191,8 @@ func getAllTransitGatewayVpcAttachments(session *session.Session, region string,
 }
 
 // Delete all TransitGatewayVpcAttachments
-func nukeAllTransitGatewayVpcAttachments(session *session.Session, ids []*string) error {
-	svc := ec2.New(session)
+func nukeAllTransitGatewayVpcAttachments(ctx context.Context, session *session.Session, ids []*string) error {
+	svc := newEC2Client(session)
 
 	if len(ids) == 0 {
 		logging.Logger.Debugf("No Transit Gateway Vpc Attachments to nuke in region %s", *session.Config.Region)
@@ -178,7 +207,7 @@ func nukeAllTransitGatewayVpcAttachments(session *session.Session, ids []*string
 			TransitGatewayAttachmentId: id,
 		}
 
-		_, err := svc.DeleteTransitGatewayVpcAttachment(param)
+		_, err := svc.DeleteTransitGatewayVpcAttachmentWithContext(ctx, param)
 
 		// Record status of this resource
 		e := report.Entry{
@@ -186,7 +215,7 @@ func nukeAllTransitGatewayVpcAttachments(session *session.Session, ids []*string
 			ResourceType: "Transit Gateway",
 			Error:        err,
 		}
-		report.Record(e)
+		record(ctx, e)
 
 		if err != nil {
 			logging.Logger.Debugf("[Failed] %s", err)
@@ -198,14 +227,16 @@ func nukeAllTransitGatewayVpcAttachments(session *session.Session, ids []*string
 
 	sleepMessage := "TransitGateway Vpc Attachments takes some time to create, and since there is no waiter available, we sleep instead."
 	sleepFor := 180 * time.Second
-	sleepWithMessage(sleepFor, sleepMessage)
+	if err := sleepWithMessage(ctx, sleepFor, sleepMessage); err != nil {
+		return errors.WithStackTrace(err)
+	}
 
 	logging.Logger.Debugf(("[OK] %d Transit Gateway Vpc Attachment(s) deleted in %s"), len(deletedIds), *session.Config.Region)
 	return nil
 }
 
 func tgIsAvailableInRegion(session *session.Session, region string) (bool, error) {
-	svc := ec2.New(session)
+	svc := newEC2Client(session)
 	_, err := svc.DescribeTransitGateways(&ec2.DescribeTransitGatewaysInput{})
 	if err != nil {
 		if awsErr, isAwsErr := err.(awserr.Error); isAwsErr && awsErr.Code() == "InvalidAction" {
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
 	"testing"
 	"time"
@@ -39,7 +41,7 @@ func createTestTransitGateway(t *testing.T, session *session.Session, name strin
 
 	sleepMessage := "TransitGateway takes some time to create, and since there is no waiter available, we sleep instead."
 	sleepFor := 180 * time.Second
-	sleepWithMessage(sleepFor, sleepMessage)
+	require.NoError(t, sleepWithMessage(context.Background(), sleepFor, sleepMessage))
 
 	return *result.TransitGateway
 }
@@ -59,13 +61,13 @@ func TestGetAllTransitGatewayInstances(t *testing.T) {
 	tgwName := "cloud-nuke-test-" + util.UniqueID()
 	tgw := createTestTransitGateway(t, session, tgwName)
 
-	defer nukeAllTransitGatewayInstances(session, []*string{tgw.TransitGatewayId})
+	defer nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgw.TransitGatewayId})
 
-	ids, err := getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour*-1))
+	ids, err := getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgw.TransitGatewayId))
 
-	ids, err = getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour))
+	ids, err = getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 	assert.Contains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgw.TransitGatewayId))
 }
@@ -97,10 +99,10 @@ func TestNukeTransitGateway(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	err = nukeAllTransitGatewayInstances(session, []*string{tgw.TransitGatewayId})
+	err = nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgw.TransitGatewayId})
 	require.NoError(t, err)
 
-	ids, err := getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour))
+	ids, err := getAllTransitGatewayInstances(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 
 	assert.NotContains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgw.TransitGatewayId))
@@ -132,7 +134,7 @@ func createTestTransitGatewayRouteTable(t *testing.T, session *session.Session,
 
 	sleepMessage := "TransitGateway Route Tables takes some time to create, and since there is no waiter available, we sleep instead."
 	sleepFor := 180 * time.Second
-	sleepWithMessage(sleepFor, sleepMessage)
+	require.NoError(t, sleepWithMessage(context.Background(), sleepFor, sleepMessage))
 
 	return *result.TransitGatewayRouteTable
 }
@@ -152,14 +154,14 @@ func TestGetAllTransitGatewayRouteTableInstances(t *testing.T) {
 	tgwRouteTableName := "cloud-nuke-test-" + util.UniqueID()
 	tgwRouteTable := createTestTransitGatewayRouteTable(t, session, tgwRouteTableName)
 
-	defer nukeAllTransitGatewayRouteTables(session, []*string{tgwRouteTable.TransitGatewayRouteTableId})
-	defer nukeAllTransitGatewayInstances(session, []*string{tgwRouteTable.TransitGatewayId})
+	defer nukeAllTransitGatewayRouteTables(context.Background(), session, []*string{tgwRouteTable.TransitGatewayRouteTableId})
+	defer nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgwRouteTable.TransitGatewayId})
 
-	ids, err := getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour*-1))
+	ids, err := getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgwRouteTable.TransitGatewayRouteTableId))
 
-	ids, err = getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour))
+	ids, err = getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 	assert.Contains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgwRouteTable.TransitGatewayRouteTableId))
 }
@@ -180,7 +182,7 @@ func TestNukeTransitGatewayRouteTable(t *testing.T) {
 
 	tgwRouteTableName := "cloud-nuke-test-" + util.UniqueID()
 	tgwRouteTable := createTestTransitGatewayRouteTable(t, session, tgwRouteTableName)
-	defer nukeAllTransitGatewayInstances(session, []*string{tgwRouteTable.TransitGatewayId})
+	defer nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgwRouteTable.TransitGatewayId})
 
 	_, err = svc.DescribeTransitGatewayRouteTables(&ec2.DescribeTransitGatewayRouteTablesInput{
 		TransitGatewayRouteTableIds: []*string{
@@ -189,10 +191,10 @@ func TestNukeTransitGatewayRouteTable(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	err = nukeAllTransitGatewayRouteTables(session, []*string{tgwRouteTable.TransitGatewayRouteTableId})
+	err = nukeAllTransitGatewayRouteTables(context.Background(), session, []*string{tgwRouteTable.TransitGatewayRouteTableId})
 	require.NoError(t, err)
 
-	ids, err := getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour))
+	ids, err := getAllTransitGatewayRouteTables(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgwRouteTable.TransitGatewayRouteTableId))
 }
@@ -243,7 +245,7 @@ func createTestTransitGatewayVpcAttachment(t *testing.T, session *session.Sessio
 
 	sleepMessage := "TransitGateway Vpc Attachment takes some time to create, and since there is no waiter available, we sleep instead."
 	sleepFor := 180 * time.Second
-	sleepWithMessage(sleepFor, sleepMessage)
+	require.NoError(t, sleepWithMessage(context.Background(), sleepFor, sleepMessage))
 
 	return *result.TransitGatewayVpcAttachment
 }
@@ -263,14 +265,14 @@ func TestGetAllTransitGatewayVpcAttachment(t *testing.T) {
 	tgwName := "cloud-nuke-test-" + util.UniqueID()
 	tgwAttachment := createTestTransitGatewayVpcAttachment(t, session, tgwName)
 
-	defer nukeAllTransitGatewayVpcAttachments(session, []*string{tgwAttachment.TransitGatewayAttachmentId})
-	defer nukeAllTransitGatewayInstances(session, []*string{tgwAttachment.TransitGatewayId})
+	defer nukeAllTransitGatewayVpcAttachments(context.Background(), session, []*string{tgwAttachment.TransitGatewayAttachmentId})
+	defer nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgwAttachment.TransitGatewayId})
 
-	ids, err := getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour*-1))
+	ids, err := getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour*-1), config.Config{})
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgwAttachment.TransitGatewayAttachmentId))
 
-	ids, err = getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour))
+	ids, err = getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 	assert.Contains(t, awsgo.StringValueSlice(ids), awsgo.StringValue(tgwAttachment.TransitGatewayAttachmentId))
 }
@@ -300,12 +302,12 @@ func TestNukeTransitGatewayVpcAttachment(t *testing.T) {
 		},
 	})
 	require.NoError(t, err)
-	defer nukeAllTransitGatewayInstances(session, []*string{tgwVpcAttachment.TransitGatewayId})
+	defer nukeAllTransitGatewayInstances(context.Background(), session, []*string{tgwVpcAttachment.TransitGatewayId})
 
-	err = nukeAllTransitGatewayVpcAttachments(session, []*string{tgwVpcAttachment.TransitGatewayAttachmentId})
+	err = nukeAllTransitGatewayVpcAttachments(context.Background(), session, []*string{tgwVpcAttachment.TransitGatewayAttachmentId})
 	require.NoError(t, err)
 
-	ids, err := getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour))
+	ids, err := getAllTransitGatewayVpcAttachments(session, region, time.Now().Add(1*time.Hour), config.Config{})
 	require.NoError(t, err)
 	assert.NotContains(t, awsgo.StringValueSlice(ids), aws.StringValue(tgwVpcAttachment.TransitGatewayAttachmentId))
 }
@@ -1,6 +1,8 @@
 package aws
 
 import (
+	"context"
+
 	awsgo "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
@@ -27,8 +29,8 @@ func (tgw TransitGatewaysVpcAttachment) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (tgw TransitGatewaysVpcAttachment) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllTransitGatewayVpcAttachments(session, awsgo.StringSlice(identifiers)); err != nil {
+func (tgw TransitGatewaysVpcAttachment) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllTransitGatewayVpcAttachments(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -56,8 +58,8 @@ func (tgw TransitGatewaysRouteTables) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (tgw TransitGatewaysRouteTables) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllTransitGatewayRouteTables(session, awsgo.StringSlice(identifiers)); err != nil {
+func (tgw TransitGatewaysRouteTables) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllTransitGatewayRouteTables(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
@@ -85,10 +87,57 @@ func (tgw TransitGateways) ResourceIdentifiers() []string {
 }
 
 // Nuke - nuke 'em all!!!
-func (tgw TransitGateways) Nuke(session *session.Session, identifiers []string) error {
-	if err := nukeAllTransitGatewayInstances(session, awsgo.StringSlice(identifiers)); err != nil {
+func (tgw TransitGateways) Nuke(ctx context.Context, session *session.Session, identifiers []string) error {
+	if err := nukeAllTransitGatewayInstances(ctx, session, awsgo.StringSlice(identifiers)); err != nil {
 		return errors.WithStackTrace(err)
 	}
 
 	return nil
 }
+
+func init() {
+	RegisterResourceType(ResourceRegistration{
+		Name:            TransitGatewaysVpcAttachment{}.ResourceName(),
+		Description:     "Transit Gateway VPC Attachments",
+		ConfigKey:       "TransitGatewayVpcAttachment",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			if available, err := tgIsAvailableInRegion(session, params.Region); !available {
+				return nil, err
+			}
+			ids, err := getAllTransitGatewayVpcAttachments(session, params.Region, params.ExcludeAfter, params.Config)
+			return TransitGatewaysVpcAttachment{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+	RegisterResourceType(ResourceRegistration{
+		Name:            TransitGatewaysRouteTables{}.ResourceName(),
+		Description:     "Transit Gateway Route Tables",
+		ConfigKey:       "TransitGatewayRouteTable",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		DependsOn:       []string{"transit-gateway-attachment"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			if available, err := tgIsAvailableInRegion(session, params.Region); !available {
+				return nil, err
+			}
+			ids, err := getAllTransitGatewayRouteTables(session, params.Region, params.ExcludeAfter, params.Config)
+			return TransitGatewaysRouteTables{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+	RegisterResourceType(ResourceRegistration{
+		Name:            TransitGateways{}.ResourceName(),
+		Description:     "Transit Gateways",
+		ConfigKey:       "TransitGateway",
+		SupportsTags:    true,
+		SupportsRuleAge: true,
+		DependsOn:       []string{"transit-gateway-attachment", "transit-gateway-route-table"},
+		List: func(session *session.Session, params ListParams) (AwsResources, error) {
+			if available, err := tgIsAvailableInRegion(session, params.Region); !available {
+				return nil, err
+			}
+			ids, err := getAllTransitGatewayInstances(session, params.Region, params.ExcludeAfter, params.Config)
+			return TransitGateways{Ids: awsgo.StringValueSlice(ids)}, err
+		},
+	})
+}
@@ -1,11 +1,13 @@
 package aws
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
 )
 
 const AwsResourceExclusionTagKey = "cloud-nuke-excluded"
@@ -78,7 +80,9 @@ type AwsResources interface {
 	ResourceName() string
 	ResourceIdentifiers() []string
 	MaxBatchSize() int
-	Nuke(session *session.Session, identifiers []string) error
+	// Nuke deletes the resources with the given identifiers. Waiting for deletions to complete stops once the context
+	// is done.
+	Nuke(ctx context.Context, session *session.Session, identifiers []string) error
 }
 
 type AwsRegionResource struct {
@@ -93,6 +97,12 @@ type Query struct {
 	ExcludeResourceTypes []string
 	ExcludeAfter         time.Time
 	ListUnaliasedKMSKeys bool
+	// Parallelism is the maximum number of resource listings in flight at the same time. NewQuery sets it to
+	// DefaultParallelism.
+	Parallelism int
+	// Config holds the include/exclude rules to inspect resources with, exactly as they are applied when nuking. The
+	// zero value includes all resources.
+	Config config.Config
 }
 
 // NewQuery configures and returns a Query struct that can be passed into the InspectResources method
@@ -104,6 +114,7 @@ func NewQuery(regions, excludeRegions, resourceTypes, excludeResourceTypes []str
 		ExcludeResourceTypes: excludeResourceTypes,
 		ExcludeAfter:         excludeAfter,
 		ListUnaliasedKMSKeys: listUnaliasedKMSKeys,
+		Parallelism:          DefaultParallelism,
 	}
 
 	validationErr := q.Validate()
@@ -199,3 +210,261 @@ type CouldNotDetermineEnabledRegionsError struct {
 func (err CouldNotDetermineEnabledRegionsError) Error() string {
 	return fmt.Sprintf("Unable to determine enabled regions in target account. Original error: %v", err.Underlying)
 }
+
+type UnknownResourceDependencyError struct {
+	ResourceType string
+	Dependency   string
+}
+
+func (err UnknownResourceDependencyError) Error() string {
+	return fmt.Sprintf("Resource type %s depends on %s, which is not a registered resource type", err.ResourceType, err.Dependency)
+}
+
+type ResourceDependencyCycleError struct {
+	Cycle []string
+}
+
+func (err ResourceDependencyCycleError) Error() string {
+	return fmt.Sprintf("Resource type dependencies form a cycle: %s", strings.Join(err.Cycle, " -> "))
+}
+
+type InvalidPlanFileError struct {
+	Path       string
+	Underlying error
+}
+
+func (err InvalidPlanFileError) Error() string {
+	return fmt.Sprintf("Could not read plan file %s: %s", err.Path, err.Underlying)
+}
+
+type UnsupportedPlanVersionError struct {
+	Path    string
+	Version int
+}
+
+func (err UnsupportedPlanVersionError) Error() string {
+	return fmt.Sprintf("Plan file %s has version %d, but this version of cloud-nuke only supports version %d", err.Path, err.Version, PlanVersion)
+}
+
+type MissingNukerQueryError struct{}
+
+func (err MissingNukerQueryError) Error() string {
+	return "The Nuker has no query. Set its Query to select the resources to inspect or nuke."
+}
+
+type InvalidStateFileError struct {
+	Path       string
+	Underlying error
+}
+
+func (err InvalidStateFileError) Error() string {
+	return fmt.Sprintf("Could not read state file %s: %s", err.Path, err.Underlying)
+}
+
+type UnsupportedStateVersionError struct {
+	Path    string
+	Version int
+}
+
+func (err UnsupportedStateVersionError) Error() string {
+	return fmt.Sprintf("State file %s has version %d, but this version of cloud-nuke only supports version %d", err.Path, err.Version, RunStateVersion)
+}
+
+type TagRulesNotSupportedError struct {
+	ConfigKey string
+}
+
+func (err TagRulesNotSupportedError) Error() string {
+	return fmt.Sprintf("The config file defines tag rules for %s, which does not support matching on tags", err.ConfigKey)
+}
+
+type AccountBlockedError struct {
+	AccountId string
+	Entry     string
+}
+
+func (err AccountBlockedError) Error() string {
+	if err.Entry == err.AccountId {
+		return fmt.Sprintf("Refusing to run against account %s, which is blocked", err.AccountId)
+	}
+	return fmt.Sprintf("Refusing to run against account %s, which is blocked as %s", err.AccountId, err.Entry)
+}
+
+type AccountNotAllowedError struct {
+	AccountId string
+	Aliases   []string
+}
+
+func (err AccountNotAllowedError) Error() string {
+	account := err.AccountId
+	if len(err.Aliases) > 0 {
+		account = fmt.Sprintf("%s (%s)", err.AccountId, strings.Join(err.Aliases, ", "))
+	}
+	return fmt.Sprintf("Refusing to run against account %s, which is not in the list of allowed accounts", account)
+}
+
+type RecordedAccountMismatchError struct {
+	RecordedAccountId string
+	AccountId         string
+}
+
+func (err RecordedAccountMismatchError) Error() string {
+	return fmt.Sprintf("Refusing to run against account %s, the file was written for account %s. Use credentials of account %s, e.g. with --profile or --role-arn.", err.AccountId, err.RecordedAccountId, err.RecordedAccountId)
+}
+
+type ResourceLimitExceededError struct {
+	// ResourceType is the resource type whose limit is exceeded, or empty if the total limit is exceeded
+	ResourceType string
+	Count        int
+	Limit        int
+}
+
+func (err ResourceLimitExceededError) Error() string {
+	if err.ResourceType == "" {
+		return fmt.Sprintf("Refusing to nuke %d resources, which exceeds the limit of %d resources per run", err.Count, err.Limit)
+	}
+	return fmt.Sprintf("Refusing to nuke %d %s resources, which exceeds the limit of %d %s resources per run", err.Count, err.ResourceType, err.Limit, err.ResourceType)
+}
+
+type UnknownResourceTypeLimitError struct {
+	ResourceType string
+}
+
+func (err UnknownResourceTypeLimitError) Error() string {
+	return fmt.Sprintf("The config file sets a limit for the unknown resource type %s", err.ResourceType)
+}
+
+type RuleAgeNotSupportedError struct {
+	ConfigKey string
+}
+
+func (err RuleAgeNotSupportedError) Error() string {
+	return fmt.Sprintf("The config file sets older_than in the include or exclude rule of %s, which only supports older_than at the top level", err.ConfigKey)
+}
+
+type UnknownNukeModeError struct {
+	Mode string
+}
+
+func (err UnknownNukeModeError) Error() string {
+	return fmt.Sprintf("Unknown mode %s, the supported modes are %v", err.Mode, NukeModes)
+}
+
+type QuarantineNotSupportedError struct {
+	ResourceType string
+}
+
+func (err QuarantineNotSupportedError) Error() string {
+	return fmt.Sprintf("Resource type %s cannot be quarantined, the resource types that can be quarantined are %v", err.ResourceType, QuarantinableResourceTypes())
+}
+
+type DataPreservationError struct {
+	Identifier string
+	Underlying error
+}
+
+func (err DataPreservationError) Error() string {
+	return fmt.Sprintf("Could not back up the data of %s, so it was not deleted: %s", err.Identifier, err.Underlying)
+}
+
+func (err DataPreservationError) Unwrap() error {
+	return err.Underlying
+}
+
+type DataPreservationNotSupportedError struct {
+	ConfigKey string
+}
+
+func (err DataPreservationNotSupportedError) Error() string {
+	return fmt.Sprintf("The config file sets preserve_data for %s, which does not support preserving data", err.ConfigKey)
+}
+
+type InvalidRecoveryWindowError struct {
+	ResourceType string
+	Days         int
+}
+
+func (err InvalidRecoveryWindowError) Error() string {
+	if err.ResourceType == (SecretsManagerSecrets{}).ResourceName() {
+		return fmt.Sprintf("Invalid recovery window of %d days for %s, it must be 0 or between %d and %d days", err.Days, err.ResourceType, MinRecoveryWindowDays, MaxRecoveryWindowDays)
+	}
+	return fmt.Sprintf("Invalid recovery window of %d days for %s, it must be between %d and %d days", err.Days, err.ResourceType, MinRecoveryWindowDays, MaxRecoveryWindowDays)
+}
+
+type RecoveryWindowNotSupportedError struct {
+	ConfigKey string
+}
+
+func (err RecoveryWindowNotSupportedError) Error() string {
+	return fmt.Sprintf("The config file sets recovery_window_days for %s, which cannot be restored after being deleted", err.ConfigKey)
+}
+
+type InvalidRunIDError struct {
+	RunID string
+}
+
+func (err InvalidRunIDError) Error() string {
+	return fmt.Sprintf("%q is not a valid run ID", err.RunID)
+}
+
+type RunReportNotFoundError struct {
+	RunID string
+	Dir   string
+}
+
+func (err RunReportNotFoundError) Error() string {
+	return fmt.Sprintf("No report of run %s was found in %s. Pass the directory it was saved in with --report-dir.", err.RunID, err.Dir)
+}
+
+type InvalidRunReportError struct {
+	Path       string
+	Underlying error
+}
+
+func (err InvalidRunReportError) Error() string {
+	return fmt.Sprintf("Could not read run report %s: %s", err.Path, err.Underlying)
+}
+
+type UnsupportedRunReportVersionError struct {
+	Path    string
+	Version int
+}
+
+func (err UnsupportedRunReportVersionError) Error() string {
+	return fmt.Sprintf("Run report %s has version %d, but this version of cloud-nuke only supports version %d", err.Path, err.Version, RunReportVersion)
+}
+
+type MissingUndoMetadataError struct {
+	Identifier string
+	Key        string
+}
+
+func (err MissingUndoMetadataError) Error() string {
+	return fmt.Sprintf("The run report does not record the %s of %s, which is needed to restore it", err.Key, err.Identifier)
+}
+
+type UnknownOrganizationAccountError struct {
+	AccountID string
+}
+
+func (err UnknownOrganizationAccountError) Error() string {
+	return fmt.Sprintf("Account %s is not a member of the organization", err.AccountID)
+}
+
+type AssumeOrganizationRoleError struct {
+	RoleArn    string
+	Underlying error
+}
+
+func (err AssumeOrganizationRoleError) Error() string {
+	return fmt.Sprintf("Could not assume role %s: %s", err.RoleArn, err.Underlying)
+}
+
+type UnexpectedOrganizationAccountError struct {
+	RoleArn   string
+	AccountID string
+}
+
+func (err UnexpectedOrganizationAccountError) Error() string {
+	return fmt.Sprintf("Assuming role %s resulted in credentials for account %s, refusing to run against it", err.RoleArn, err.AccountID)
+}
@@ -0,0 +1,143 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// Keys of the metadata recorded with the outcome of nuking a resource, which UndoRun uses to reverse it
+const (
+	// metadataRecoveryWindowDays is the number of days during which a deleted resource can be restored
+	metadataRecoveryWindowDays = "recoveryWindowDays"
+	// metadataPreviousState is the state of a resource before it was quarantined, e.g. whether an instance was running
+	metadataPreviousState = "previousState"
+	metadataArn           = "arn"
+	// metadataCluster is the ARN of the cluster of a quarantined ECS service
+	metadataCluster = "cluster"
+	// metadataMinSize, metadataMaxSize and metadataDesiredCount are the capacity of a resource before it was
+	// quarantined
+	metadataMinSize      = "minSize"
+	metadataMaxSize      = "maxSize"
+	metadataDesiredCount = "desiredCount"
+	// metadataReservedConcurrency is the reserved concurrency of a Lambda function before it was quarantined, missing if
+	// it had none
+	metadataReservedConcurrency = "reservedConcurrency"
+	// metadataPublicAccessBlock is the public access block configuration of an S3 bucket before it was quarantined, as
+	// JSON, missing if it had none
+	metadataPublicAccessBlock = "publicAccessBlock"
+)
+
+// UndoFunc reverses what a run did to a single resource of its run report, using the metadata recorded with its outcome
+type UndoFunc func(session *session.Session, resource RunReportResource) error
+
+// UndoRun reverses what the run of the given report did to its resources, as far as it can: the resources it
+// quarantined are brought back into service, and the resources it deleted are restored if they were kept recoverable
+// and their recovery window has not ended. It returns the outcome for every resource that the run deleted or
+// quarantined, explaining why the ones that cannot be restored are irreversible. Once the context is done, the
+// remaining resources are skipped.
+func UndoRun(ctx context.Context, runReport *RunReport) ([]ui.ResourceRow, error) {
+	rows := []ui.ResourceRow{}
+	sessions := map[string]*session.Session{}
+	for _, resource := range runReport.Resources {
+		if resource.Status != ui.ResourceStatusDeleted && resource.Status != ui.ResourceStatusQuarantined {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return rows, errors.WithStackTrace(err)
+		}
+
+		undo, reason := undoFuncFor(runReport, resource, time.Now())
+		if undo == nil {
+			rows = append(rows, irreversibleResourceRow(resource, reason))
+			continue
+		}
+
+		awsSession, ok := sessions[resource.Region]
+		if !ok {
+			var err error
+			awsSession, err = newAWSSession(resource.Region)
+			if err != nil {
+				return rows, err
+			}
+			sessions[resource.Region] = awsSession
+		}
+		err := undo(awsSession, resource)
+		if err != nil {
+			logging.Logger.Debugf("[Failed] %s", err)
+		} else {
+			logging.Logger.Debugf("Restored %s %s in region %s", resource.ResourceType, resource.Identifier, resource.Region)
+		}
+		rows = append(rows, restoredResourceRow(resource.ResourceType, resource.Identifier, resource.Region, errors.WithStackTrace(err)))
+	}
+	return rows, nil
+}
+
+// undoFuncFor returns the function that reverses what the run of the given report did to the given resource, or the
+// reason why it cannot be reversed at the given time
+func undoFuncFor(runReport *RunReport, resource RunReportResource, now time.Time) (UndoFunc, string) {
+	var undoQuarantine, undoDeletion UndoFunc
+	supportsDataPreservation := false
+	for _, registration := range registrations {
+		if registration.Name != resource.ResourceType {
+			continue
+		}
+		if registration.UndoQuarantine != nil {
+			undoQuarantine = registration.UndoQuarantine
+		}
+		if registration.UndoDeletion != nil {
+			undoDeletion = registration.UndoDeletion
+		}
+		supportsDataPreservation = supportsDataPreservation || registration.SupportsDataPreservation
+	}
+
+	if resource.Status == ui.ResourceStatusQuarantined {
+		if undoQuarantine == nil {
+			return nil, fmt.Sprintf("cloud-nuke cannot undo quarantining %s", resource.ResourceType)
+		}
+		return undoQuarantine, ""
+	}
+
+	days, _ := strconv.Atoi(resource.Metadata[metadataRecoveryWindowDays])
+	if undoDeletion == nil || days <= 0 || resource.Region == "" {
+		if supportsDataPreservation && runReport.PreserveData.IsEnabled(resource.ResourceType) {
+			return nil, fmt.Sprintf("deleted permanently, but its data was backed up by run %s", runReport.RunID)
+		}
+		return nil, "deleted permanently"
+	}
+	if windowEnd := resource.Timestamp.AddDate(0, 0, days); !now.Before(windowEnd) {
+		return nil, fmt.Sprintf("its recovery window ended on %s", windowEnd.UTC().Format(time.RFC3339))
+	}
+	return undoDeletion, ""
+}
+
+// irreversibleResourceRow returns the row reporting that the given resource cannot be restored, for the given reason
+func irreversibleResourceRow(resource RunReportResource, reason string) ui.ResourceRow {
+	return ui.ResourceRow{
+		Identifier:   resource.Identifier,
+		ResourceType: resource.ResourceType,
+		Region:       resource.Region,
+		Status:       ui.ResourceStatusIrreversible,
+		Reason:       reason,
+		Timestamp:    time.Now().UTC(),
+	}
+}
+
+// metadataInt64 returns the integer recorded under the given key in the metadata of the given resource
+func metadataInt64(resource RunReportResource, key string) (int64, error) {
+	value, ok := resource.Metadata[key]
+	if !ok {
+		return 0, MissingUndoMetadataError{Identifier: resource.Identifier, Key: key}
+	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	return number, nil
+}
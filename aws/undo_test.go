@@ -0,0 +1,92 @@
+package aws
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/aws/aws-sdk-go/service/secretsmanager/secretsmanageriface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+)
+
+func TestUndoFuncFor(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	runReport := &RunReport{RunID: "run-1", PreserveData: DataPreservation{Enabled: true}}
+	recoverable := func(deletedAt time.Time) RunReportResource {
+		return RunReportResource{
+			Region:       "us-east-1",
+			ResourceType: "secretsmanager",
+			Status:       ui.ResourceStatusDeleted,
+			Timestamp:    deletedAt,
+			Metadata:     map[string]string{metadataRecoveryWindowDays: strconv.Itoa(7)},
+		}
+	}
+
+	undo, _ := undoFuncFor(runReport, RunReportResource{Region: "us-east-1", ResourceType: "ec2", Status: ui.ResourceStatusQuarantined}, now)
+	assert.NotNil(t, undo)
+	undo, _ = undoFuncFor(runReport, recoverable(now.Add(-6*24*time.Hour)), now)
+	assert.NotNil(t, undo)
+
+	testCases := []struct {
+		name     string
+		resource RunReportResource
+		reason   string
+	}{
+		{"deleted", RunReportResource{Region: "us-east-1", ResourceType: "sqs", Status: ui.ResourceStatusDeleted}, "deleted permanently"},
+		{"deleted without recovery window", RunReportResource{Region: "us-east-1", ResourceType: "secretsmanager", Status: ui.ResourceStatusDeleted}, "deleted permanently"},
+		{"backed up", RunReportResource{Region: "us-east-1", ResourceType: "ebs", Status: ui.ResourceStatusDeleted}, "deleted permanently, but its data was backed up by run run-1"},
+		{"recovery window ended", recoverable(now.Add(-8 * 24 * time.Hour)), "its recovery window ended on " + now.Add(-24*time.Hour).UTC().Format(time.RFC3339)},
+		{"quarantine not supported", RunReportResource{Region: "us-east-1", ResourceType: "sqs", Status: ui.ResourceStatusQuarantined}, "cloud-nuke cannot undo quarantining sqs"},
+	}
+	for _, testCase := range testCases {
+		undo, reason := undoFuncFor(runReport, testCase.resource, now)
+		assert.Nil(t, undo, testCase.name)
+		assert.Equal(t, testCase.reason, reason, testCase.name)
+	}
+}
+
+func TestUndoRunOffline(t *testing.T) {
+	secrets := &fakeSecretsManager{replicas: map[string][]string{}}
+	secret := secrets.addSecret("secret", time.Now(), nil, nil)
+	useFakeClient(t, &newSecretsManagerClient, secretsmanageriface.SecretsManagerAPI(secrets))
+	require.NoError(t, SecretsManagerSecrets{}.NukeRecoverably(context.Background(), newFakeSession(t, "us-east-1"), []string{secret}, 7, "run-1"))
+
+	instances := &fakeEC2Instances{instances: map[string]*ec2.Instance{
+		"i-1": {InstanceId: awsgo.String("i-1"), State: &ec2.InstanceState{Name: awsgo.String(ec2.InstanceStateNameStopped)}},
+	}}
+	useFakeClient(t, &newEC2Client, ec2iface.EC2API(instances))
+
+	deletedAt := time.Now().UTC()
+	runReport := &RunReport{RunID: "run-1", Resources: []RunReportResource{
+		{Region: "us-east-1", ResourceType: "secretsmanager", Identifier: secret, Status: ui.ResourceStatusDeleted, Timestamp: deletedAt, Metadata: map[string]string{metadataRecoveryWindowDays: "7"}},
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-1", Status: ui.ResourceStatusQuarantined, Timestamp: deletedAt, Metadata: map[string]string{metadataPreviousState: ec2.InstanceStateNameRunning}},
+		{Region: "us-east-1", ResourceType: "sqs", Identifier: "queue", Status: ui.ResourceStatusDeleted, Timestamp: deletedAt},
+		// Resources that the run failed to nuke were left as they were, so there is nothing to undo
+		{Region: "us-east-1", ResourceType: "ec2", Identifier: "i-2", Status: ui.ResourceStatusFailed, Timestamp: deletedAt},
+	}}
+
+	rows, err := UndoRun(context.Background(), runReport)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	assert.Equal(t, ui.ResourceStatusRestored, rows[0].Status)
+	assert.Nil(t, secrets.secrets[0].DeletedDate)
+	assert.Equal(t, ui.ResourceStatusRestored, rows[1].Status)
+	assert.Equal(t, ec2.InstanceStateNameRunning, awsgo.StringValue(instances.instances["i-1"].State.Name))
+	assert.Equal(t, ui.ResourceStatusIrreversible, rows[2].Status)
+	assert.Equal(t, "deleted permanently", rows[2].Reason)
+
+	// Once the context is done, the remaining resources are skipped
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rows, err = UndoRun(ctx, runReport)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, rows)
+}
@@ -1,8 +1,13 @@
 package commands
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
@@ -10,7 +15,9 @@ import (
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/aws"
 	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/externalcreds"
 	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
 	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 	"github.com/pterm/pterm"
@@ -38,7 +45,7 @@ func CreateCli(version string, mixPanelClientId string) *cli.App {
 			Name:   "aws",
 			Usage:  "BEWARE: DESTRUCTIVE OPERATION! Nukes AWS resources (ASG, ELB, ELBv2, EBS, EC2, AMI, Snapshots, Elastic IP, RDS, Lambda Function).",
 			Action: errors.WithPanicHandling(awsNuke),
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringSliceFlag{
 					Name:  "region",
 					Usage: "Regions to include. Include multiple times if more than one.",
@@ -59,6 +66,10 @@ func CreateCli(version string, mixPanelClientId string) *cli.App {
 					Name:  "list-resource-types",
 					Usage: "List available resource types",
 				},
+				&cli.BoolFlag{
+					Name:  "show-order",
+					Usage: "Show the order in which the selected resource types will be nuked, based on their dependencies",
+				},
 				&cli.StringFlag{
 					Name:  "older-than",
 					Usage: "Only delete resources older than this specified value. Can be any valid Go duration, such as 10m or 8h.",
@@ -86,12 +97,111 @@ func CreateCli(version string, mixPanelClientId string) *cli.App {
 					Name:  "config",
 					Usage: "YAML file specifying matching rules.",
 				},
+				&cli.StringFlag{
+					Name:  "out-plan",
+					Usage: "Write the resources that would be nuked to this plan file instead of nuking them. Use 'cloud-nuke aws apply' to nuke exactly the resources in the plan.",
+				},
+				&cli.StringFlag{
+					Name:  "resume",
+					Usage: "Continue an interrupted run from the state file written with --state-file. Only the resources that are still pending or failed are nuked.",
+				},
+				&cli.BoolFlag{
+					Name:  "org",
+					Usage: "Nuke the accounts of the AWS Organization of the current credentials, by assuming a role in each of them. The management account and the current account are never nuked. All other accounts are nuked, unless they are narrowed down with --org-account-id, --org-unit-id or the organization section of the config file.",
+				},
+				&cli.StringFlag{
+					Name:  "org-role-name",
+					Usage: "Name of the role assumed in every account of the organization with --org.",
+					Value: aws.DefaultOrganizationRoleName,
+				},
+				&cli.StringSliceFlag{
+					Name:  "org-account-id",
+					Usage: "ID of an account of the organization to nuke with --org. Include multiple times if more than one.",
+				},
+				&cli.StringSliceFlag{
+					Name:  "org-unit-id",
+					Usage: "ID of an organizational unit whose accounts, including those of nested units, to nuke with --org. Include multiple times if more than one.",
+				},
+			}, append(nukeOptionFlags(), append(outputFlags(), append(accountFlags(), credentialFlags()...)...)...)...),
+			Subcommands: []*cli.Command{
+				{
+					Name:      "apply",
+					Usage:     "BEWARE: DESTRUCTIVE OPERATION! Nukes exactly the resources listed in a plan file written with --out-plan.",
+					ArgsUsage: "PLAN_FILE",
+					Action:    errors.WithPanicHandling(awsApply),
+					Flags: append([]cli.Flag{
+						&cli.BoolFlag{
+							Name:  "force",
+							Usage: "Skip nuke confirmation prompt. WARNING: this will automatically delete all resources in the plan without any confirmation.",
+						},
+						&cli.StringFlag{
+							Name:    "log-level",
+							Value:   "info",
+							Usage:   "Set log level",
+							EnvVars: []string{"LOG_LEVEL"},
+						},
+					}, append(nukeOptionFlags(), append(outputFlags(), append(accountFlags(), credentialFlags()...)...)...)...),
+				},
+				{
+					Name:   "restore",
+					Usage:  "Cancels the deletion of the KMS keys, and restores the Secrets Manager secrets, that a run deleted, as long as their recovery window has not ended.",
+					Action: errors.WithPanicHandling(awsRestore),
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:  "run-id",
+							Usage: "ID of the run whose resources to restore, as logged when the run started.",
+						},
+						reportDirFlag(),
+						&cli.StringSliceFlag{
+							Name:  "region",
+							Usage: "Regions to include. Include multiple times if more than one.",
+						},
+						&cli.StringSliceFlag{
+							Name:  "exclude-region",
+							Usage: "Regions to exclude. Include multiple times if more than one.",
+						},
+						&cli.StringFlag{
+							Name:  "timeout",
+							Usage: "Stop restoring once this much time has passed since the command started. Can be any valid Go duration, such as 30m or 2h. 0 means no timeout.",
+							Value: "0s",
+						},
+						&cli.StringFlag{
+							Name:    "log-level",
+							Value:   "info",
+							Usage:   "Set log level",
+							EnvVars: []string{"LOG_LEVEL"},
+						},
+					}, append(outputFlags(), append(accountFlags(), credentialFlags()...)...)...),
+				},
+				{
+					Name:   "undo",
+					Usage:  "Reverses what a run did to the resources that can still be restored, using the run report saved when it ended, and lists the resources that cannot be restored.",
+					Action: errors.WithPanicHandling(awsUndo),
+					Flags: append([]cli.Flag{
+						&cli.StringFlag{
+							Name:  "run-id",
+							Usage: "ID of the run to undo, as logged when the run started.",
+						},
+						reportDirFlag(),
+						&cli.StringFlag{
+							Name:  "timeout",
+							Usage: "Stop undoing once this much time has passed since the command started. Can be any valid Go duration, such as 30m or 2h. 0 means no timeout.",
+							Value: "0s",
+						},
+						&cli.StringFlag{
+							Name:    "log-level",
+							Value:   "info",
+							Usage:   "Set log level",
+							EnvVars: []string{"LOG_LEVEL"},
+						},
+					}, append(outputFlags(), append(accountFlags(), credentialFlags()...)...)...),
+				},
 			},
 		}, {
 			Name:   "defaults-aws",
 			Usage:  "Nukes AWS default VPCs and permissive default security group rules. Optionally include/exclude specified regions, or just nuke security group rules (not default VPCs).",
 			Action: errors.WithPanicHandling(awsDefaults),
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringSliceFlag{
 					Name:  "region",
 					Usage: "regions to include",
@@ -114,12 +224,12 @@ func CreateCli(version string, mixPanelClientId string) *cli.App {
 					Usage:   "Set log level",
 					EnvVars: []string{"LOG_LEVEL"},
 				},
-			},
+			}, append(accountFlags(), credentialFlags()...)...),
 		}, {
 			Name:   "inspect-aws",
 			Usage:  "Non-destructive inspection of target resources only",
 			Action: errors.WithPanicHandling(awsInspect),
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				&cli.StringSliceFlag{
 					Name:  "region",
 					Usage: "regions to include",
@@ -149,241 +259,1148 @@ func CreateCli(version string, mixPanelClientId string) *cli.App {
 					Name:  "list-unaliased-kms-keys",
 					Usage: "List KMS keys that do not have aliases associated with them.",
 				},
+				&cli.StringFlag{
+					Name:  "config",
+					Usage: "YAML file specifying matching rules. Resources excluded by the rules are reported along with the rule that excluded them.",
+				},
+				&cli.IntFlag{
+					Name:  "parallelism",
+					Usage: "Maximum number of regions and resource types scanned at the same time.",
+					Value: aws.DefaultParallelism,
+				},
+				&cli.StringFlag{
+					Name:  "timeout",
+					Usage: "Stop inspecting once this much time has passed since the command started. Can be any valid Go duration, such as 30m or 2h. 0 means no timeout.",
+					Value: "0s",
+				},
 				&cli.StringFlag{
 					Name:    "log-level",
 					Value:   "info",
 					Usage:   "Set log level",
 					EnvVars: []string{"LOG_LEVEL"},
 				},
-			},
+			}, append(outputFlags(), credentialFlags()...)...),
+		},
+	}
+
+	return app
+}
+
+// nukeOptionFlags returns the flags that tune how resources are nuked, shared by the commands that nuke resources
+func nukeOptionFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:  "max-passes",
+			Usage: "Maximum number of deletion passes. Resources that fail to delete because of dependencies or transient errors are retried in the next pass, until no further progress is made.",
+			Value: aws.DefaultNukeOptions().MaxPasses,
 		},
+		&cli.StringFlag{
+			Name:  "pass-backoff",
+			Usage: "Time to wait before the first retry pass, doubled for every subsequent pass. Can be any valid Go duration, such as 30s or 2m.",
+			Value: aws.DefaultNukeOptions().PassBackoff.String(),
+		},
+		&cli.IntFlag{
+			Name:  "parallelism",
+			Usage: "Maximum number of resource types scanned at the same time. When nuking, maximum number of regions nuked at the same time, and of resource types nuked at the same time within each region.",
+			Value: aws.DefaultParallelism,
+		},
+		&cli.IntFlag{
+			Name:  "max-resources",
+			Usage: "Refuse to nuke anything if more than this number of resources are targeted, even with --force. 0 means no limit.",
+		},
+		&cli.StringFlag{
+			Name:  "timeout",
+			Usage: "Cancel the run once this much time has passed since the command started. Resources that were not nuked by then are reported as cancelled. Can be any valid Go duration, such as 30m or 2h. 0 means no timeout.",
+			Value: "0s",
+		},
+		&cli.StringFlag{
+			Name:  "mode",
+			Usage: fmt.Sprintf("What to do with the targeted resources. One of %s. In quarantine mode, resources are stopped and tagged instead of deleted, and only the resource types that support it are targeted.", aws.NukeModes),
+			Value: string(aws.NukeModeDelete),
+		},
+		&cli.IntFlag{
+			Name:  "kms-pending-window",
+			Usage: fmt.Sprintf("Number of days, between %d and %d, after which KMS keys scheduled for deletion are deleted. Until then, 'cloud-nuke aws restore' can cancel their deletion.", aws.MinRecoveryWindowDays, aws.MaxRecoveryWindowDays),
+			Value: aws.DefaultRecoveryWindows().Days(aws.KmsCustomerKeys{}.ResourceName()),
+		},
+		&cli.IntFlag{
+			Name:  "secrets-recovery-window",
+			Usage: fmt.Sprintf("Number of days, between %d and %d, during which deleted Secrets Manager secrets can be restored with 'cloud-nuke aws restore'. 0 deletes them without recovery.", aws.MinRecoveryWindowDays, aws.MaxRecoveryWindowDays),
+			Value: aws.DefaultRecoveryWindows().Days(aws.SecretsManagerSecrets{}.ResourceName()),
+		},
+		&cli.BoolFlag{
+			Name:  "preserve-data",
+			Usage: "Back up the data of RDS instances, Aurora clusters, DynamoDB tables, Elasticache clusters and EBS volumes before deleting them. The backups are tagged with the run ID, and deleted by a later run through the preserved-data resource type once they are old enough.",
+		},
+		&cli.StringFlag{
+			Name:  "state-file",
+			Usage: "Record which resources were deleted, failed or are still pending in this file while nuking, so that an interrupted run can be continued with 'cloud-nuke aws --resume'.",
+		},
+		reportDirFlag(),
+	}
+}
+
+// reportDirFlag returns the flag setting the directory of the run reports, shared by the commands that nuke resources
+// and 'cloud-nuke aws undo'
+func reportDirFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "report-dir",
+		Usage: "Directory in which the report of every run is saved, named after the run ID, so that 'cloud-nuke aws undo' can reverse what the run did.",
+		Value: aws.DefaultRunReportDir(),
+	}
+}
+
+// outputFlags returns the flags that control how results are written, shared by the commands that produce results
+func outputFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "output-format",
+			Usage: fmt.Sprintf("Format of the results. One of %s. The text format is meant for humans, the others for other tools.", ui.OutputFormats),
+			Value: string(ui.OutputFormatText),
+		},
+		&cli.StringFlag{
+			Name:  "output-file",
+			Usage: "Write the results to this file instead of stdout.",
+		},
+	}
+}
+
+// accountFlags returns the flags that restrict the accounts cloud-nuke may run against, shared by the commands that
+// nuke resources
+func accountFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "allowed-account-id",
+			Usage: "ID or alias of an account cloud-nuke may run against. If set, cloud-nuke refuses to run against any other account. Include multiple times if more than one.",
+		},
+		&cli.StringSliceFlag{
+			Name:  "blocked-account-id",
+			Usage: "ID or alias of an account cloud-nuke must never run against. Include multiple times if more than one.",
+		},
+	}
+}
+
+// credentialFlags returns the flags that select the credentials cloud-nuke uses, shared by the commands that call AWS
+func credentialFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "profile",
+			Usage: "Named profile of the AWS config and credentials files to use. Takes precedence over the AWS_PROFILE environment variable.",
+		},
+		&cli.StringFlag{
+			Name:  "role-arn",
+			Usage: "ARN of a role to assume with the credentials of the profile, e.g. in the account to nuke.",
+		},
+		&cli.StringFlag{
+			Name:  "external-id",
+			Usage: "External ID to pass when assuming the role set with --role-arn.",
+		},
+		&cli.StringFlag{
+			Name:  "mfa-serial",
+			Usage: "Serial number or ARN of an MFA device, whose current code is prompted for. Used to assume the role set with --role-arn, or else to get temporary credentials for the profile.",
+		},
+		&cli.StringFlag{
+			Name:  "session-duration",
+			Usage: fmt.Sprintf("Duration of the temporary credentials obtained with --role-arn or --mfa-serial, at most %s for a role and %s otherwise. They are refreshed automatically before they expire, which prompts for a new MFA code with --mfa-serial.", externalcreds.MaxRoleSessionDuration, externalcreds.MaxMFASessionDuration),
+			Value: externalcreds.DefaultSessionDuration.String(),
+		},
+	}
+}
+
+// configureCredentials makes cloud-nuke use the credentials selected with the credential flags, if any
+func configureCredentials(c *cli.Context) error {
+	options := externalcreds.Options{
+		Profile:    c.String("profile"),
+		RoleArn:    c.String("role-arn"),
+		ExternalID: c.String("external-id"),
+		MFASerial:  c.String("mfa-serial"),
+	}
+	if c.IsSet("session-duration") {
+		duration, err := time.ParseDuration(c.String("session-duration"))
+		if err != nil {
+			return InvalidFlagError{Name: "session-duration", Value: c.String("session-duration")}
+		}
+		options.SessionDuration = duration
+	}
+
+	if err := options.Validate(); err != nil {
+		switch errors.Unwrap(err).(type) {
+		case externalcreds.ExternalIDWithoutRoleError:
+			return MissingRequiredFlagError{Name: "external-id", Required: "--role-arn"}
+		case externalcreds.SessionDurationWithoutSessionError:
+			return MissingRequiredFlagError{Name: "session-duration", Required: "--role-arn or --mfa-serial"}
+		}
+		return InvalidFlagError{Name: "session-duration", Value: c.String("session-duration")}
+	}
+	if err := externalcreds.Configure(options); err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error configuring credentials",
+		}, map[string]interface{}{})
+		return err
+	}
+	return nil
+}
+
+// accountRules returns the given rules from the config file, plan or state file, extended with the accounts passed with
+// --allowed-account-id and --blocked-account-id
+func accountRules(c *cli.Context, rules config.AccountRules) config.AccountRules {
+	return config.AccountRules{
+		Allowed: append(append([]string{}, rules.Allowed...), c.StringSlice("allowed-account-id")...),
+		Blocked: append(append([]string{}, rules.Blocked...), c.StringSlice("blocked-account-id")...),
+	}
+}
+
+// checkAccount returns an error if the account of the current credentials is not allowed by the given rules from the
+// config file, plan or state file, extended with the accounts passed with --allowed-account-id and --blocked-account-id
+func checkAccount(c *cli.Context, rules config.AccountRules, region string) error {
+	if err := aws.CheckAccount(region, accountRules(c, rules)); err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Account not allowed",
+		}, map[string]interface{}{})
+		return err
+	}
+	return nil
+}
+
+// parseOutputFormat parses --output-format. When the results are written to stdout in a format meant for other tools,
+// the output meant for humans is sent to stderr, so that it does not get mixed with the results.
+func parseOutputFormat(c *cli.Context) (ui.OutputFormat, error) {
+	format, err := ui.ParseOutputFormat(c.String("output-format"))
+	if err != nil {
+		return "", InvalidFlagError{Name: "output-format", Value: c.String("output-format")}
+	}
+	if writesResultsToStdout(c, format) {
+		ui.SendHumanOutputToStderr()
+	}
+	return format, nil
+}
+
+// writesResultsToStdout returns true if the results are written to stdout in the given format meant for other tools
+func writesResultsToStdout(c *cli.Context, format ui.OutputFormat) bool {
+	return format != ui.OutputFormatText && c.String("output-file") == ""
+}
+
+// writeOutput calls write with the file set with --output-file, or with stdout if no output file is set
+func writeOutput(c *cli.Context, write func(w io.Writer) error) error {
+	path := c.String("output-file")
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close()
+	return write(file)
+}
+
+func parseDurationParam(paramValue string) (*time.Time, error) {
+	duration, err := time.ParseDuration(paramValue)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	// make it negative so it goes back in time
+	duration = -1 * duration
+
+	excludeAfter := time.Now().Add(duration)
+	return &excludeAfter, nil
+}
+
+func parseNukeOptions(c *cli.Context) (*aws.NukeOptions, error) {
+	passBackoff, err := time.ParseDuration(c.String("pass-backoff"))
+	if err != nil {
+		return nil, InvalidFlagError{Name: "pass-backoff", Value: c.String("pass-backoff")}
+	}
+	if c.Int("max-passes") < 1 {
+		return nil, InvalidFlagError{Name: "max-passes", Value: c.String("max-passes")}
+	}
+	if c.Int("parallelism") < 1 {
+		return nil, InvalidFlagError{Name: "parallelism", Value: c.String("parallelism")}
+	}
+	if c.Int("max-resources") < 0 {
+		return nil, InvalidFlagError{Name: "max-resources", Value: c.String("max-resources")}
+	}
+	mode, err := aws.ParseNukeMode(c.String("mode"))
+	if err != nil {
+		return nil, InvalidFlagError{Name: "mode", Value: c.String("mode")}
+	}
+	recoveryWindows, err := parseRecoveryWindows(c, aws.DefaultRecoveryWindows())
+	if err != nil {
+		return nil, err
+	}
+
+	return &aws.NukeOptions{
+		MaxPasses:   c.Int("max-passes"),
+		PassBackoff: passBackoff,
+		Parallelism: c.Int("parallelism"),
+		Limits:      config.ResourceLimits{MaxResources: c.Int("max-resources")},
+		Mode:        mode,
+		// The config file can override --preserve-data per resource type, see NewDataPreservation
+		PreserveData:    aws.DataPreservation{Enabled: c.Bool("preserve-data")},
+		RecoveryWindows: recoveryWindows,
+		RunID:           aws.NewRunID(),
+	}, nil
+}
+
+// savedNukeMode returns the mode recorded in the plan or state file at the given path, in which its resources are to be
+// nuked, or the given mode set with --mode if the file predates recording the mode. Setting --mode to another mode than
+// the recorded one is an error, so that resources meant to be quarantined are never deleted.
+func savedNukeMode(c *cli.Context, saved aws.NukeMode, path string, mode aws.NukeMode) (aws.NukeMode, error) {
+	if saved == "" {
+		return mode, nil
+	}
+	if c.IsSet("mode") && mode != saved {
+		return "", NukeModeMismatchError{Path: path, Saved: string(saved), Mode: string(mode)}
+	}
+	return saved, nil
+}
+
+// recoveryWindowFlags maps the flags setting recovery windows to the resource types they apply to
+var recoveryWindowFlags = []struct {
+	name         string
+	resourceType string
+}{
+	{name: "kms-pending-window", resourceType: aws.KmsCustomerKeys{}.ResourceName()},
+	{name: "secrets-recovery-window", resourceType: aws.SecretsManagerSecrets{}.ResourceName()},
+}
+
+// parseRecoveryWindows returns a copy of the given recovery windows, overridden by the recovery window flags that are
+// set
+func parseRecoveryWindows(c *cli.Context, windows aws.RecoveryWindows) (aws.RecoveryWindows, error) {
+	parsed := aws.RecoveryWindows{}
+	for resourceType, days := range windows {
+		parsed[resourceType] = days
+	}
+	for _, flag := range recoveryWindowFlags {
+		if !c.IsSet(flag.name) {
+			continue
+		}
+		if err := aws.ValidateRecoveryWindow(flag.resourceType, c.Int(flag.name)); err != nil {
+			return nil, InvalidFlagError{Name: flag.name, Value: c.String(flag.name)}
+		}
+		parsed[flag.resourceType] = c.Int(flag.name)
+	}
+	return parsed, nil
+}
+
+// commandContext returns the context of a command, which is cancelled once the duration set with --timeout has passed
+func commandContext(c *cli.Context) (context.Context, context.CancelFunc, error) {
+	timeout, err := time.ParseDuration(c.String("timeout"))
+	if err != nil || timeout < 0 {
+		return nil, nil, InvalidFlagError{Name: "timeout", Value: c.String("timeout")}
+	}
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	return ctx, cancel, nil
+}
+
+// cancelOnInterrupt returns a context that is also cancelled on Ctrl+C or SIGTERM, so that the resources being nuked
+// are reported as cancelled instead of the process being killed mid-deletion. After the first signal, the default
+// behavior is restored, so that a second Ctrl+C kills the process right away.
+func cancelOnInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			logging.Logger.Warnln("Cancelling the run, resources that are not nuked yet will be reported as cancelled. Press Ctrl+C again to exit immediately.")
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+	return ctx, cancel
+}
+
+func parseLogLevel(c *cli.Context) error {
+	logLevel := c.String("log-level")
+
+	parsedLogLevel, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		return fmt.Errorf("Invalid log level - %s - %s", logLevel, err)
+	}
+	logging.Logger.Level = parsedLogLevel
+	return nil
+}
+
+func awsNuke(c *cli.Context) error {
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Start aws",
+	}, map[string]interface{}{})
+	defer telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "End aws",
+	}, map[string]interface{}{})
+
+	parseErr := parseLogLevel(c)
+	if parseErr != nil {
+		return errors.WithStackTrace(parseErr)
+	}
+	if err := configureCredentials(c); err != nil {
+		return err
+	}
+
+	ctx, cancel, err := commandContext(c)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	if err := validateOrgFlags(c); err != nil {
+		return err
+	}
+	if statePath := c.String("resume"); statePath != "" {
+		return awsResume(ctx, c, statePath)
+	}
+
+	configFilePath := c.String("config")
+	configObj, err := readConfigFile(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("list-resource-types") {
+		for _, resourceType := range aws.ListResourceTypes() {
+			fmt.Println(resourceType)
+		}
+		return nil
+	}
+
+	// Ensure that the resourceTypes and excludeResourceTypes arguments are valid, and then filter
+	// resourceTypes
+	resourceTypes, err := aws.HandleResourceTypeSelections(c.StringSlice("resource-type"), c.StringSlice("exclude-resource-type"))
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Bad Resource Types",
+		}, map[string]interface{}{})
+		return err
+	}
+
+	nukeOptions, err := parseNukeOptions(c)
+	if err != nil {
+		return err
+	}
+	// Only some resource types can be quarantined. Asking for others explicitly is an error, rather than silently
+	// deleting them or leaving them alone.
+	if nukeOptions.Mode == aws.NukeModeQuarantine {
+		resourceTypes, err = aws.SelectQuarantinableResourceTypes(resourceTypes, len(c.StringSlice("resource-type")) > 0)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("show-order") {
+		nukeOrder, err := aws.GetNukeOrder(resourceTypes)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		for _, line := range nukeOrder {
+			fmt.Println(line)
+		}
+		return nil
+	}
+
+	targetedResourceList := []pterm.BulletListItem{}
+
+	for _, resource := range resourceTypes {
+		targetedResourceList = append(targetedResourceList, pterm.BulletListItem{Level: 0, Text: resource})
+	}
+
+	ui.WarningMessage("The following resource types are targeted for destruction")
+
+	// Log which resource types will be nuked
+	list := pterm.DefaultBulletList.
+		WithItems(targetedResourceList).
+		WithBullet(ui.TargetEmoji)
+
+	renderErr := list.Render()
+	if renderErr != nil {
+		return errors.WithStackTrace(renderErr)
+	}
+
+	excludeAfter, err := parseDurationParam(c.String("older-than"))
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error parsing duration",
+		}, map[string]interface{}{})
+		return errors.WithStackTrace(err)
+	}
+
+	// When both --max-resources and the config file set a limit, the lowest one applies
+	nukeOptions.Limits = nukeOptions.Limits.Stricter(configObj.Limits)
+	nukeOptions.PreserveData = aws.NewDataPreservation(c.Bool("preserve-data"), configObj)
+	// The recovery windows set with flags take precedence over the ones set in the config file
+	nukeOptions.RecoveryWindows, err = parseRecoveryWindows(c, aws.NewRecoveryWindows(configObj))
+	if err != nil {
+		return err
+	}
+	if _, err := parseOutputFormat(c); err != nil {
+		return err
+	}
+
+	if c.Bool("org") {
+		return awsNukeOrganization(ctx, c, configObj, resourceTypes, *nukeOptions, *excludeAfter)
+	}
+
+	targetRegions, err := selectTargetRegions(c)
+	if err != nil {
+		return err
+	}
+
+	// Abort before discovering anything if the account must not be nuked
+	if err := checkAccount(c, configObj.Accounts, targetRegions[0]); err != nil {
+		return err
+	}
+
+	spinnerMsg := fmt.Sprintf("Retrieving active AWS resources in [%s]", strings.Join(targetRegions[:], ", "))
+
+	// Start a simple spinner to track progress reading all relevant AWS resources
+	spinnerSuccess, spinnerErr := pterm.DefaultSpinner.
+		WithRemoveWhenDone(true).
+		Start(spinnerMsg)
+
+	if spinnerErr != nil {
+		return errors.WithStackTrace(spinnerErr)
+	}
+
+	account, err := aws.GetAllResources(ctx, targetRegions, *excludeAfter, resourceTypes, configObj, c.Bool("delete-unaliased-kms-keys"), nukeOptions.Parallelism)
+	// Stop the spinner
+	spinnerSuccess.Stop()
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error getting resources",
+		}, map[string]interface{}{})
+		return errors.WithStackTrace(err)
+	}
+
+	if len(account.Resources) == 0 {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "No resources to nuke",
+		}, map[string]interface{}{})
+		pterm.Info.Println("Nothing to nuke, you're all good!")
+		return nil
+	}
+
+	if err := renderResourcesToNuke(account, nukeOptions.Mode); err != nil {
+		return err
+	}
+
+	if planPath := c.String("out-plan"); planPath != "" {
+		plan := aws.NewPlan(account, targetRegions, resourceTypes, *excludeAfter, configFilePath, c.Bool("delete-unaliased-kms-keys"))
+		plan.AccountID, err = aws.GetCallerAccountID(targetRegions[0])
+		if err != nil {
+			return err
+		}
+		plan.Mode = nukeOptions.Mode
+		plan.Accounts = accountRules(c, configObj.Accounts)
+		plan.Limits = nukeOptions.Limits
+		if err := aws.WritePlan(plan, planPath); err != nil {
+			return err
+		}
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Wrote nuke plan",
+		}, map[string]interface{}{
+			"totalResourceCount": len(plan.Resources),
+		})
+		logging.Logger.Infof("Wrote %d resources to plan %s. Run 'cloud-nuke aws apply %s' to nuke them.", len(plan.Resources), planPath, planPath)
+		return nil
+	}
+
+	if c.Bool("dry-run") {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Skipping nuke, dryrun set",
+		}, map[string]interface{}{})
+		logging.Logger.Infoln("Not taking any action as dry-run set to true.")
+		return nil
+	}
+
+	state := aws.NewRunState(account, targetRegions, resourceTypes, c.Bool("delete-unaliased-kms-keys"))
+	state.AccountID, err = aws.GetCallerAccountID(targetRegions[0])
+	if err != nil {
+		return err
+	}
+	state.Accounts = accountRules(c, configObj.Accounts)
+	return confirmAndNuke(ctx, c, account, targetRegions, *nukeOptions, state, c.String("state-file"))
+}
+
+// selectTargetRegions returns the regions enabled for the current credentials, including the global region, narrowed
+// down with --region and --exclude-region
+func selectTargetRegions(c *cli.Context) ([]string, error) {
+	regions, err := aws.GetEnabledRegions()
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error getting regions",
+		}, map[string]interface{}{})
+		return nil, errors.WithStackTrace(err)
+	}
+
+	// global is a fake region, used to represent global resources
+	regions = append(regions, aws.GlobalRegion)
+
+	selectedRegions := c.StringSlice("region")
+	excludedRegions := c.StringSlice("exclude-region")
+
+	// targetRegions uses selectedRegions and excludedRegions to create a final
+	// target region slice.
+	targetRegions, err := aws.GetTargetRegions(regions, selectedRegions, excludedRegions)
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error targeting regions",
+		}, map[string]interface{}{})
+		return nil, fmt.Errorf("Failed to select regions: %s", err)
+	}
+	return targetRegions, nil
+}
+
+// renderResourcesToNuke prints the given resources, as a warning that they are about to be nuked, or quarantined in
+// quarantine mode
+func renderResourcesToNuke(account *aws.AwsAccountResources, mode aws.NukeMode) error {
+	nukableResources := aws.ExtractResourcesForPrinting(account)
+
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Found resources to nuke",
+	}, map[string]interface{}{
+		"totalResourceCount": len(nukableResources),
+	})
+
+	action := "nuked"
+	if mode == aws.NukeModeQuarantine {
+		action = "quarantined"
+	}
+	ui.WarningMessage(fmt.Sprintf("The following %d AWS resources will be %s:\n", len(nukableResources), action))
+
+	items := []pterm.BulletListItem{}
+
+	for _, resource := range nukableResources {
+		items = append(items, pterm.BulletListItem{Level: 0, Text: resource})
+	}
+
+	targetList := pterm.DefaultBulletList.
+		WithItems(items).
+		WithBullet(ui.FireEmoji)
+
+	targetRenderErr := targetList.Render()
+	if targetRenderErr != nil {
+		return errors.WithStackTrace(targetRenderErr)
+	}
+	return nil
+}
+
+// readConfigFile reads the include/exclude rules from the given config file. An empty path results in an empty config,
+// which includes all resources.
+func readConfigFile(configFilePath string) (config.Config, error) {
+	if configFilePath == "" {
+		return config.Config{}, nil
+	}
+
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Reading config file",
+	}, map[string]interface{}{})
+	configObj, err := config.GetConfig(configFilePath)
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error reading config file",
+		}, map[string]interface{}{})
+		return config.Config{}, fmt.Errorf("Error reading config - %s - %s", configFilePath, err)
+	}
+	return *configObj, nil
+}
+
+// confirmAndNuke asks the user for confirmation (or waits for 10 seconds if --force is set), nukes the given resources
+// and renders the run report. If statePath is set, the progress of the run is recorded in the given state and written
+// to statePath while nuking. If the run is cancelled, the report is rendered before returning a RunCancelledError.
+func confirmAndNuke(ctx context.Context, c *cli.Context, account *aws.AwsAccountResources, targetRegions []string, nukeOptions aws.NukeOptions, state *aws.RunState, statePath string) error {
+	// The limits are a safeguard against filters that target far more resources than intended, so they apply even with
+	// --force
+	if err := aws.CheckResourceLimits(account, nukeOptions.Limits); err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Resource limits exceeded",
+		}, map[string]interface{}{
+			"totalResourceCount": account.TotalResourceCount(),
+		})
+		return err
+	}
+
+	proceed, err := confirmNuke(c, nukeOptions.Mode)
+	if err != nil {
+		return err
+	}
+	var nukeErr error
+	if proceed {
+		nukeErr = nukeWithCheckpoints(ctx, account, targetRegions, nukeOptions, state, statePath)
+	}
+	saveRunReport(state.AccountID, nukeOptions, c.String("report-dir"))
+	if nukeErr != nil && !report.IsCancelled(nukeErr) {
+		return nukeErr
+	}
+
+	if err := renderRunReport(c); err != nil {
+		return err
+	}
+	if nukeErr != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Nuke cancelled",
+		}, map[string]interface{}{})
+		return RunCancelledError{Underlying: errors.Unwrap(nukeErr)}
+	}
+	return nil
+}
+
+// confirmNuke asks the user to confirm that the listed resources are to be nuked, or waits for 10 seconds if --force is
+// set. It returns false if the user did not confirm.
+func confirmNuke(c *cli.Context, mode aws.NukeMode) (bool, error) {
+	if c.Bool("force") {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Forcing nuke in 10 seconds",
+		}, map[string]interface{}{})
+		logging.Logger.Infoln("The --force flag is set, so waiting for 10 seconds before proceeding to nuke everything in your account. If you don't want to proceed, hit CTRL+C now!!")
+		for i := 10; i > 0; i-- {
+			fmt.Fprintf(ui.HumanOutput(), "%d...", i)
+			time.Sleep(1 * time.Second)
+		}
+		return true, nil
+	}
+	// The confirmation prompt can only be drawn on stdout, where it would get mixed with the results
+	if format, err := ui.ParseOutputFormat(c.String("output-format")); err == nil && writesResultsToStdout(c, format) {
+		return false, PromptWithResultsOnStdoutError{Format: string(format)}
+	}
+
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Awaiting nuke confirmation",
+	}, map[string]interface{}{})
+	prompt := "\nAre you sure you want to nuke all listed resources? Enter 'nuke' to confirm (or exit with ^C) "
+	if mode == aws.NukeModeQuarantine {
+		prompt = "\nAre you sure you want to stop and quarantine all listed resources? Enter 'nuke' to confirm (or exit with ^C) "
+	}
+	proceed, err := confirmationPrompt(prompt, 2)
+	if err != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Error confirming nuke",
+		}, map[string]interface{}{})
+		return false, err
+	}
+	if !proceed {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "User aborted nuke",
+		}, map[string]interface{}{})
+	}
+	return proceed, nil
+}
+
+// nukeWithCheckpoints nukes the given resources until they are all done or the context is cancelled, which also happens
+// on Ctrl+C. Their progress is written to statePath if it is set.
+func nukeWithCheckpoints(ctx context.Context, account *aws.AwsAccountResources, targetRegions []string, nukeOptions aws.NukeOptions, state *aws.RunState, statePath string) error {
+	ctx, cancel := cancelOnInterrupt(ctx)
+	defer cancel()
+
+	logging.Logger.Infof("Starting run %s", nukeOptions.RunID)
+	state.RunID = nukeOptions.RunID
+	state.Mode = nukeOptions.Mode
+	state.Limits = nukeOptions.Limits
+	state.PreserveData = &nukeOptions.PreserveData
+	state.RecoveryWindows = nukeOptions.RecoveryWindows
+
+	if statePath == "" {
+		return aws.NukeAllResources(ctx, account, targetRegions, nukeOptions)
+	}
+
+	stopCheckpoints, err := state.Checkpoint(statePath)
+	if err != nil {
+		return err
+	}
+	onOutcome := nukeOptions.OnOutcome
+	nukeOptions.OnOutcome = func(region string, resourceType string, entry report.Entry) {
+		if onOutcome != nil {
+			onOutcome(region, resourceType, entry)
+		}
+		state.RecordOutcome(region, resourceType, entry)
+	}
+
+	nukeErr := aws.NukeAllResources(ctx, account, targetRegions, nukeOptions)
+	// Write the outcomes that were recorded since the last checkpoint
+	stopCheckpoints()
+	if remaining := len(state.Remaining().Resources); remaining > 0 {
+		logging.Logger.Infof("%d resources are still pending or failed. Run 'cloud-nuke aws --resume %s' to continue.", remaining, statePath)
+	}
+	return nukeErr
+}
+
+// saveRunReport saves the report of the run in the account with the given ID to the given directory, normally the one
+// set with --report-dir, so that it can be undone with 'cloud-nuke aws undo'. Nothing is saved if nothing was nuked.
+// Failing to save the report does not fail the run, which is over by then.
+func saveRunReport(accountID string, nukeOptions aws.NukeOptions, dir string) {
+	runReport := aws.NewRunReport(nukeOptions)
+	if len(runReport.Resources) == 0 {
+		return
+	}
+	runReport.AccountID = accountID
+	if err := aws.SaveRunReport(runReport, dir); err != nil {
+		logging.Logger.Warnf("Failed to save the report of run %s to %s: %s", nukeOptions.RunID, dir, err)
+		return
+	}
+	logging.Logger.Infof("Saved the report of run %s to %s. Run 'cloud-nuke aws undo --run-id %s --report-dir %s' to reverse what can still be reversed.", nukeOptions.RunID, dir, nukeOptions.RunID, dir)
+}
+
+// renderRunReport displays the outcome of the run, as tables unless another output format or an output file is set
+func renderRunReport(c *cli.Context) error {
+	format, err := parseOutputFormat(c)
+	if err != nil {
+		return err
+	}
+	if format == ui.OutputFormatText && c.String("output-file") == "" {
+		ui.RenderRunReport()
+		return nil
+	}
+
+	ui.StopProgressBar()
+	return writeOutput(c, func(w io.Writer) error {
+		return ui.WriteResourceRows(w, format, aws.ExtractRunReportForOutput())
+	})
+}
+
+// awsApply nukes the resources listed in a plan file written by `cloud-nuke aws --out-plan`. The regions and resource
+// types of the plan are scanned again, and only the discovered resources that are in the plan are nuked, so that
+// resources created since the plan was written are left alone.
+func awsApply(c *cli.Context) error {
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Start aws apply",
+	}, map[string]interface{}{})
+	defer telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "End aws apply",
+	}, map[string]interface{}{})
+
+	parseErr := parseLogLevel(c)
+	if parseErr != nil {
+		return errors.WithStackTrace(parseErr)
+	}
+	if err := configureCredentials(c); err != nil {
+		return err
+	}
+
+	if c.NArg() != 1 {
+		return MissingPlanFileError{}
+	}
+	ctx, cancel, err := commandContext(c)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	plan, err := aws.ReadPlan(c.Args().First())
+	if err != nil {
+		return err
+	}
+
+	nukeOptions, err := parseNukeOptions(c)
+	if err != nil {
+		return err
+	}
+	nukeOptions.Mode, err = savedNukeMode(c, plan.Mode, c.Args().First(), nukeOptions.Mode)
+	if err != nil {
+		return err
+	}
+	if _, err := parseOutputFormat(c); err != nil {
+		return err
+	}
+
+	// The account rules and limits of the plan are enforced again, along with the ones passed to apply
+	nukeOptions.Limits = nukeOptions.Limits.Stricter(plan.Limits)
+	if err := checkAccount(c, plan.Accounts, plan.Regions[0]); err != nil {
+		return err
+	}
+	accountID, err := aws.CheckRecordedAccountID(plan.Regions[0], plan.AccountID)
+	if err != nil {
+		return err
+	}
+
+	account, missing, err := discoverPlannedResources(ctx, plan, nukeOptions.Parallelism)
+	if err != nil {
+		return err
+	}
+	for _, resource := range missing {
+		logging.Logger.Warnf("Planned %s %s in %s no longer exists, skipping it", resource.ResourceType, resource.Identifier, resource.Region)
+	}
+
+	if len(account.Resources) == 0 {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "No resources to nuke",
+		}, map[string]interface{}{})
+		pterm.Info.Println("None of the planned resources exist anymore, you're all good!")
+		return nil
+	}
+
+	if err := renderResourcesToNuke(account, nukeOptions.Mode); err != nil {
+		return err
+	}
+
+	state := aws.NewRunState(account, plan.Regions, plan.ResourceTypes, plan.AllowDeleteUnaliasedKeys)
+	state.AccountID = accountID
+	state.Accounts = accountRules(c, plan.Accounts)
+	return confirmAndNuke(ctx, c, account, plan.Regions, *nukeOptions, state, c.String("state-file"))
+}
+
+// awsResume continues a run that was interrupted, from the state file written with --state-file. Like applying a plan,
+// the regions and resource types that still have resources left to nuke are scanned again, and only the discovered
+// resources that are pending or failed in the state file are nuked. The state file keeps being updated, unless
+// --state-file points to another file.
+func awsResume(ctx context.Context, c *cli.Context, statePath string) error {
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Resuming aws run",
+	}, map[string]interface{}{})
+
+	// The rules of the config file were applied when the run started, and its account rules and limits are recorded in
+	// the state file, so a config file passed now would be silently ignored
+	if c.IsSet("config") {
+		return IncompatibleFlagsError{Name: "config", Other: "resume"}
+	}
+	state, err := aws.ReadRunState(statePath)
+	if err != nil {
+		return err
+	}
+
+	nukeOptions, err := parseNukeOptions(c)
+	if err != nil {
+		return err
+	}
+	nukeOptions.Mode, err = savedNukeMode(c, state.Mode, statePath, nukeOptions.Mode)
+	if err != nil {
+		return err
+	}
+	if _, err := parseOutputFormat(c); err != nil {
+		return err
+	}
+	if c.String("state-file") != "" {
+		statePath = c.String("state-file")
+	}
+	// Keep backing up data as the interrupted run did, under the same run ID
+	if state.RunID != "" {
+		nukeOptions.RunID = state.RunID
+	}
+	if state.PreserveData != nil && !c.IsSet("preserve-data") {
+		nukeOptions.PreserveData = *state.PreserveData
+	}
+	if state.RecoveryWindows != nil {
+		nukeOptions.RecoveryWindows, err = parseRecoveryWindows(c, state.RecoveryWindows)
+		if err != nil {
+			return err
+		}
 	}
 
-	return app
-}
+	plan := state.Remaining()
+	if len(plan.Resources) == 0 {
+		pterm.Info.Println("All resources of the interrupted run were already nuked, you're all good!")
+		return nil
+	}
 
-func parseDurationParam(paramValue string) (*time.Time, error) {
-	duration, err := time.ParseDuration(paramValue)
+	// The account rules and limits of the interrupted run are enforced again, along with the ones passed to resume it
+	nukeOptions.Limits = nukeOptions.Limits.Stricter(state.Limits)
+	if err := checkAccount(c, state.Accounts, plan.Regions[0]); err != nil {
+		return err
+	}
+	state.AccountID, err = aws.CheckRecordedAccountID(plan.Regions[0], state.AccountID)
 	if err != nil {
-		return nil, errors.WithStackTrace(err)
+		return err
 	}
+	state.Accounts = accountRules(c, state.Accounts)
 
-	// make it negative so it goes back in time
-	duration = -1 * duration
+	account, missing, err := discoverPlannedResources(ctx, plan, nukeOptions.Parallelism)
+	if err != nil {
+		return err
+	}
+	// Resources that no longer exist were deleted when the run was interrupted, or by someone else since then
+	for _, resource := range missing {
+		logging.Logger.Infof("%s %s in %s no longer exists, marking it as deleted", resource.ResourceType, resource.Identifier, resource.Region)
+	}
+	state.MarkDeleted(missing)
 
-	excludeAfter := time.Now().Add(duration)
-	return &excludeAfter, nil
-}
+	if len(account.Resources) == 0 {
+		if err := aws.WriteRunState(state, statePath); err != nil {
+			return err
+		}
+		pterm.Info.Println("None of the remaining resources exist anymore, you're all good!")
+		return nil
+	}
 
-func parseLogLevel(c *cli.Context) error {
-	logLevel := c.String("log-level")
+	if err := renderResourcesToNuke(account, nukeOptions.Mode); err != nil {
+		return err
+	}
 
-	parsedLogLevel, err := logrus.ParseLevel(logLevel)
-	if err != nil {
-		return fmt.Errorf("Invalid log level - %s - %s", logLevel, err)
+	if c.Bool("dry-run") {
+		logging.Logger.Infoln("Not taking any action as dry-run set to true.")
+		return nil
 	}
-	logging.Logger.Level = parsedLogLevel
-	return nil
+
+	return confirmAndNuke(ctx, c, account, plan.Regions, *nukeOptions, state, statePath)
 }
 
-func awsNuke(c *cli.Context) error {
+// awsRestore restores the resources that the run passed with --run-id deleted, as long as their recovery window has not
+// ended: it cancels the deletion of KMS keys and restores Secrets Manager secrets
+func awsRestore(c *cli.Context) error {
 	telemetry.TrackEvent(commonTelemetry.EventContext{
-		EventName: "Start aws",
+		EventName: "Start aws restore",
 	}, map[string]interface{}{})
 	defer telemetry.TrackEvent(commonTelemetry.EventContext{
-		EventName: "End aws",
+		EventName: "End aws restore",
 	}, map[string]interface{}{})
 
 	parseErr := parseLogLevel(c)
 	if parseErr != nil {
 		return errors.WithStackTrace(parseErr)
 	}
-
-	configObj := config.Config{}
-	configFilePath := c.String("config")
-
-	if configFilePath != "" {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Reading config file",
-		}, map[string]interface{}{})
-		configObjPtr, err := config.GetConfig(configFilePath)
-		if err != nil {
-			telemetry.TrackEvent(commonTelemetry.EventContext{
-				EventName: "Error reading config file",
-			}, map[string]interface{}{})
-			return fmt.Errorf("Error reading config - %s - %s", configFilePath, err)
-		}
-		configObj = *configObjPtr
+	if err := configureCredentials(c); err != nil {
+		return err
 	}
 
-	if c.Bool("list-resource-types") {
-		for _, resourceType := range aws.ListResourceTypes() {
-			fmt.Println(resourceType)
-		}
-		return nil
+	runID := c.String("run-id")
+	if runID == "" {
+		return MissingRunIDError{}
 	}
-
-	// Ensure that the resourceTypes and excludeResourceTypes arguments are valid, and then filter
-	// resourceTypes
-	resourceTypes, err := aws.HandleResourceTypeSelections(c.StringSlice("resource-type"), c.StringSlice("exclude-resource-type"))
+	format, err := parseOutputFormat(c)
 	if err != nil {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Bad Resource Types",
-		}, map[string]interface{}{})
 		return err
 	}
-
-	targetedResourceList := []pterm.BulletListItem{}
-
-	for _, resource := range resourceTypes {
-		targetedResourceList = append(targetedResourceList, pterm.BulletListItem{Level: 0, Text: resource})
-	}
-
-	ui.WarningMessage("The following resource types are targeted for destruction")
-
-	// Log which resource types will be nuked
-	list := pterm.DefaultBulletList.
-		WithItems(targetedResourceList).
-		WithBullet(ui.TargetEmoji)
-
-	renderErr := list.Render()
-	if renderErr != nil {
-		return errors.WithStackTrace(renderErr)
+	ctx, cancel, err := commandContext(c)
+	if err != nil {
+		return err
 	}
+	defer cancel()
 
 	regions, err := aws.GetEnabledRegions()
 	if err != nil {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Error getting regions",
-		}, map[string]interface{}{})
 		return errors.WithStackTrace(err)
 	}
-
-	// global is a fake region, used to represent global resources
-	regions = append(regions, aws.GlobalRegion)
-
-	selectedRegions := c.StringSlice("region")
-	excludedRegions := c.StringSlice("exclude-region")
-
-	// targetRegions uses selectedRegions and excludedRegions to create a final
-	// target region slice.
-	targetRegions, err := aws.GetTargetRegions(regions, selectedRegions, excludedRegions)
+	targetRegions, err := aws.GetTargetRegions(regions, c.StringSlice("region"), c.StringSlice("exclude-region"))
 	if err != nil {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Error targeting regions",
-		}, map[string]interface{}{})
 		return fmt.Errorf("Failed to select regions: %s", err)
 	}
 
-	excludeAfter, err := parseDurationParam(c.String("older-than"))
+	if err := checkAccount(c, config.AccountRules{}, targetRegions[0]); err != nil {
+		return err
+	}
+	// The resources of the run are found through their tags, so its report is only needed for the account it ran in
+	var recordedAccountID string
+	runReport, err := aws.ReadRunReport(c.String("report-dir"), runID)
+	if err == nil {
+		recordedAccountID = runReport.AccountID
+	} else if _, notFound := errors.Unwrap(err).(aws.RunReportNotFoundError); !notFound {
+		return err
+	}
+	if _, err := aws.CheckRecordedAccountID(targetRegions[0], recordedAccountID); err != nil {
+		return err
+	}
+
+	rows, err := aws.RestoreRun(ctx, targetRegions, runID)
 	if err != nil {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Error parsing duration",
-		}, map[string]interface{}{})
-		return errors.WithStackTrace(err)
+		return err
+	}
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Restored resources",
+	}, map[string]interface{}{
+		"resourceCount": len(rows),
+	})
+	if len(rows) == 0 {
+		pterm.Info.Printf("No resources deleted by run %s are left to restore.\n", runID)
+		return nil
 	}
 
-	spinnerMsg := fmt.Sprintf("Retrieving active AWS resources in [%s]", strings.Join(targetRegions[:], ", "))
+	return writeOutput(c, func(w io.Writer) error {
+		return ui.WriteResourceRows(w, format, rows)
+	})
+}
 
-	// Start a simple spinner to track progress reading all relevant AWS resources
-	spinnerSuccess, spinnerErr := pterm.DefaultSpinner.
-		WithRemoveWhenDone(true).
-		Start(spinnerMsg)
+// awsUndo reverses what a past run did to the resources that can still be restored, using the report of the run saved
+// in --report-dir, and reports the resources that cannot be restored along with the reason why
+func awsUndo(c *cli.Context) error {
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Start aws undo",
+	}, map[string]interface{}{})
+	defer telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "End aws undo",
+	}, map[string]interface{}{})
 
-	if spinnerErr != nil {
-		return errors.WithStackTrace(spinnerErr)
+	parseErr := parseLogLevel(c)
+	if parseErr != nil {
+		return errors.WithStackTrace(parseErr)
+	}
+	if err := configureCredentials(c); err != nil {
+		return err
 	}
 
-	account, err := aws.GetAllResources(targetRegions, *excludeAfter, resourceTypes, configObj, c.Bool("delete-unaliased-kms-keys"))
-	// Stop the spinner
-	spinnerSuccess.Stop()
+	runID := c.String("run-id")
+	if runID == "" {
+		return MissingRunIDError{}
+	}
+	format, err := parseOutputFormat(c)
 	if err != nil {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Error getting resources",
-		}, map[string]interface{}{})
-		return errors.WithStackTrace(err)
+		return err
 	}
-
-	if len(account.Resources) == 0 {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "No resources to nuke",
-		}, map[string]interface{}{})
-		pterm.Info.Println("Nothing to nuke, you're all good!")
-		return nil
+	runReport, err := aws.ReadRunReport(c.String("report-dir"), runID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel, err := commandContext(c)
+	if err != nil {
+		return err
 	}
+	defer cancel()
 
-	nukableResources := aws.ExtractResourcesForPrinting(account)
+	if regions := runReport.Regions(); len(regions) > 0 {
+		if err := checkAccount(c, config.AccountRules{}, regions[0]); err != nil {
+			return err
+		}
+		if _, err := aws.CheckRecordedAccountID(regions[0], runReport.AccountID); err != nil {
+			return err
+		}
+	}
 
+	rows, err := aws.UndoRun(ctx, runReport)
+	if err != nil {
+		return err
+	}
 	telemetry.TrackEvent(commonTelemetry.EventContext{
-		EventName: "Found resources to nuke",
+		EventName: "Undid run",
 	}, map[string]interface{}{
-		"totalResourceCount": len(nukableResources),
+		"resourceCount": len(rows),
 	})
-
-	ui.WarningMessage(fmt.Sprintf("The following %d AWS resources will be nuked:\n", len(nukableResources)))
-
-	items := []pterm.BulletListItem{}
-
-	for _, resource := range nukableResources {
-		items = append(items, pterm.BulletListItem{Level: 0, Text: resource})
+	if len(rows) == 0 {
+		pterm.Info.Printf("Run %s did not delete or quarantine any resource, there is nothing to undo.\n", runID)
+		return nil
 	}
 
-	targetList := pterm.DefaultBulletList.
-		WithItems(items).
-		WithBullet(ui.FireEmoji)
-
-	targetRenderErr := targetList.Render()
-	if targetRenderErr != nil {
-		return errors.WithStackTrace(targetRenderErr)
-	}
+	return writeOutput(c, func(w io.Writer) error {
+		return ui.WriteResourceRows(w, format, rows)
+	})
+}
 
-	if c.Bool("dry-run") {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Skipping nuke, dryrun set",
-		}, map[string]interface{}{})
-		logging.Logger.Infoln("Not taking any action as dry-run set to true.")
-		return nil
+// discoverPlannedResources scans the regions and resource types of the given plan, and returns the discovered
+// resources that are in the plan, along with the planned resources that no longer exist
+func discoverPlannedResources(ctx context.Context, plan *aws.Plan, parallelism int) (*aws.AwsAccountResources, []aws.PlannedResource, error) {
+	spinnerMsg := fmt.Sprintf("Retrieving planned AWS resources in [%s]", strings.Join(plan.Regions, ", "))
+	spinnerSuccess, spinnerErr := pterm.DefaultSpinner.
+		WithRemoveWhenDone(true).
+		Start(spinnerMsg)
+	if spinnerErr != nil {
+		return nil, nil, errors.WithStackTrace(spinnerErr)
 	}
 
-	if !c.Bool("force") {
-		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Awaiting nuke confirmation",
-		}, map[string]interface{}{})
-		prompt := "\nAre you sure you want to nuke all listed resources? Enter 'nuke' to confirm (or exit with ^C) "
-		proceed, err := confirmationPrompt(prompt, 2)
-		if err != nil {
-			telemetry.TrackEvent(commonTelemetry.EventContext{
-				EventName: "Error confirming nuke",
-			}, map[string]interface{}{})
-			return err
-		}
-		if proceed {
-			if err := aws.NukeAllResources(account, targetRegions); err != nil {
-				return err
-			}
-		} else {
-			telemetry.TrackEvent(commonTelemetry.EventContext{
-				EventName: "User aborted nuke",
-			}, map[string]interface{}{})
-		}
-	} else {
+	// The filters that selected the resources were already applied when the plan was written, so we only need to
+	// find out which of the planned resources still exist
+	discovered, err := aws.GetAllResources(ctx, plan.Regions, time.Now(), plan.ResourceTypes, config.Config{}, plan.AllowDeleteUnaliasedKeys, parallelism)
+	spinnerSuccess.Stop()
+	if err != nil {
 		telemetry.TrackEvent(commonTelemetry.EventContext{
-			EventName: "Forcing nuke in 10 seconds",
+			EventName: "Error getting resources",
 		}, map[string]interface{}{})
-		logging.Logger.Infoln("The --force flag is set, so waiting for 10 seconds before proceeding to nuke everything in your account. If you don't want to proceed, hit CTRL+C now!!")
-		for i := 10; i > 0; i-- {
-			fmt.Printf("%d...", i)
-			time.Sleep(1 * time.Second)
-		}
-
-		if err := aws.NukeAllResources(account, targetRegions); err != nil {
-			return err
-		}
+		return nil, nil, errors.WithStackTrace(err)
 	}
 
-	ui.RenderRunReport()
-
-	return nil
+	account, missing := plan.SelectResources(discovered)
+	return account, missing, nil
 }
 
 func awsDefaults(c *cli.Context) error {
@@ -397,6 +1414,9 @@ func awsDefaults(c *cli.Context) error {
 	if parseErr != nil {
 		return errors.WithStackTrace(parseErr)
 	}
+	if err := configureCredentials(c); err != nil {
+		return err
+	}
 
 	logging.Logger.Infoln("Identifying enabled regions")
 	regions, err := aws.GetEnabledRegions()
@@ -420,6 +1440,10 @@ func awsDefaults(c *cli.Context) error {
 		return fmt.Errorf("Failed to select regions: %s", err)
 	}
 
+	if err := checkAccount(c, config.AccountRules{}, targetRegions[0]); err != nil {
+		return err
+	}
+
 	if c.Bool("sg-only") {
 		logging.Logger.Info("Not removing default VPCs.")
 	} else {
@@ -606,6 +1630,9 @@ func awsInspect(c *cli.Context) error {
 	defer telemetry.TrackEvent(commonTelemetry.EventContext{
 		EventName: "End aws-inspect",
 	}, map[string]interface{}{})
+	if err := configureCredentials(c); err != nil {
+		return err
+	}
 	logging.Logger.Infoln("Identifying enabled regions")
 	regions, err := aws.GetEnabledRegions()
 	if err != nil {
@@ -630,6 +1657,14 @@ func awsInspect(c *cli.Context) error {
 		return errors.WithStackTrace(err)
 	}
 
+	if c.Int("parallelism") < 1 {
+		return InvalidFlagError{Name: "parallelism", Value: c.String("parallelism")}
+	}
+	outputFormat, err := parseOutputFormat(c)
+	if err != nil {
+		return err
+	}
+
 	query, err := aws.NewQuery(
 		c.StringSlice("region"),
 		c.StringSlice("exclude-region"),
@@ -641,8 +1676,20 @@ func awsInspect(c *cli.Context) error {
 	if err != nil {
 		return aws.QueryCreationError{Underlying: err}
 	}
+	query.Parallelism = c.Int("parallelism")
+
+	query.Config, err = readConfigFile(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel, err := commandContext(c)
+	if err != nil {
+		return err
+	}
+	defer cancel()
 
-	accountResources, err := aws.InspectResources(query)
+	accountResources, err := aws.InspectResourcesWithContext(ctx, query)
 	if err != nil {
 		telemetry.TrackEvent(commonTelemetry.EventContext{
 			EventName: "Error inspecting resources",
@@ -650,16 +1697,26 @@ func awsInspect(c *cli.Context) error {
 		return errors.WithStackTrace(aws.ResourceInspectionError{Underlying: err})
 	}
 
-	foundResources := aws.ExtractResourcesForPrinting(accountResources)
-
 	telemetry.TrackEvent(commonTelemetry.EventContext{
 		EventName: "Found resources with aws-inspect",
 	}, map[string]interface{}{
-		"resourceCount": len(foundResources),
+		"resourceCount": accountResources.TotalResourceCount(),
 	})
-	for _, resource := range foundResources {
-		logging.Logger.Infoln(resource)
+
+	// Resources that were left alone, e.g. because they carry the exclusion tag, are listed after the ones found
+	exclusions := report.GetExclusions()
+	if outputFormat == ui.OutputFormatText && c.String("output-file") == "" {
+		for _, resource := range aws.ExtractResourcesForPrinting(accountResources) {
+			logging.Logger.Infoln(resource)
+		}
+		for _, exclusion := range aws.ExtractExclusionsForPrinting(exclusions) {
+			logging.Logger.Infoln(exclusion)
+		}
+		return nil
 	}
 
-	return nil
+	rows := append(aws.ExtractResourcesForOutput(accountResources, time.Now().UTC()), aws.ExtractExclusionsForOutput(exclusions)...)
+	return writeOutput(c, func(w io.Writer) error {
+		return ui.WriteResourceRows(w, outputFormat, rows)
+	})
 }
@@ -1,12 +1,18 @@
 package commands
 
 import (
+	goerrors "errors"
+	"flag"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/aws"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
 	"github.com/tnn-gruntwork-io/go-commons/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
 )
 
 func TestParseDuration(t *testing.T) {
@@ -57,3 +63,138 @@ func TestIsNukeable(t *testing.T) {
 	assert.Equal(t, aws.IsNukeable(ec2ResourceName, []string{}), true)
 	assert.Equal(t, aws.IsNukeable(ec2ResourceName, []string{amiResourceName}), false)
 }
+
+func TestAwsCommandStillRunsWithApplySubcommand(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--list-resource-types"})
+	assert.NoError(t, err)
+}
+
+func TestAwsApplyRequiresPlanFile(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "apply"})
+	assert.Equal(t, MissingPlanFileError{}, err)
+}
+
+func TestAwsApplyRejectsInvalidPlanFile(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "apply", "does-not-exist.json"})
+	assert.IsType(t, aws.InvalidPlanFileError{}, errors.Unwrap(err))
+}
+
+func TestAwsRejectsInvalidTimeout(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--timeout", "-1m"})
+	assert.Equal(t, InvalidFlagError{Name: "timeout", Value: "-1m"}, err)
+}
+
+func TestAwsRejectsInvalidMode(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--mode", "stop"})
+	assert.Equal(t, InvalidFlagError{Name: "mode", Value: "stop"}, err)
+}
+
+func TestAwsRejectsInvalidRecoveryWindows(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--kms-pending-window", "0"})
+	assert.Equal(t, InvalidFlagError{Name: "kms-pending-window", Value: "0"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "aws", "--secrets-recovery-window", "31"})
+	assert.Equal(t, InvalidFlagError{Name: "secrets-recovery-window", Value: "31"}, err)
+}
+
+func TestAwsApplyAndResumeKeepTheSavedMode(t *testing.T) {
+	dir := t.TempDir()
+	planPath := filepath.Join(dir, "plan.json")
+	plan := &aws.Plan{Version: aws.PlanVersion, Regions: []string{"us-east-1"}, Mode: aws.NukeModeQuarantine}
+	require.NoError(t, aws.WritePlan(plan, planPath))
+	statePath := filepath.Join(dir, "state.json")
+	state := &aws.RunState{Version: aws.RunStateVersion, Regions: []string{"us-east-1"}, Mode: aws.NukeModeQuarantine}
+	require.NoError(t, aws.WriteRunState(state, statePath))
+
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "apply", "--mode", "delete", planPath})
+	assert.Equal(t, NukeModeMismatchError{Path: planPath, Saved: "quarantine", Mode: "delete"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "aws", "--resume", statePath, "--mode", "delete"})
+	assert.Equal(t, NukeModeMismatchError{Path: statePath, Saved: "quarantine", Mode: "delete"}, err)
+}
+
+func TestAwsResumeRejectsConfigFile(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--resume", "state.json", "--config", "config.yaml"})
+	assert.Equal(t, IncompatibleFlagsError{Name: "config", Other: "resume"}, err)
+}
+
+func TestConfirmNukeRejectsPromptWithResultsOnStdout(t *testing.T) {
+	set := flag.NewFlagSet("test", flag.ContinueOnError)
+	set.Bool("force", false, "")
+	set.String("output-format", "json", "")
+	set.String("output-file", "", "")
+	_, err := confirmNuke(cli.NewContext(nil, set, nil), aws.NukeModeDelete)
+	assert.Equal(t, PromptWithResultsOnStdoutError{Format: "json"}, err)
+}
+
+func TestAwsRestoreRequiresRunID(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "restore"})
+	assert.Equal(t, MissingRunIDError{}, err)
+}
+
+func TestAwsUndoRequiresRunReport(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "undo"})
+	assert.Equal(t, MissingRunIDError{}, err)
+
+	dir := t.TempDir()
+	err = app.Run([]string{"cloud-nuke", "aws", "undo", "--run-id", "run-1", "--report-dir", dir})
+	assert.Equal(t, aws.RunReportNotFoundError{RunID: "run-1", Dir: dir}, errors.Unwrap(err))
+}
+
+func TestAwsQuarantineRejectsResourceTypesThatCannotBeQuarantined(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--mode", "quarantine", "--resource-type", "sqs"})
+	assert.Equal(t, aws.QuarantineNotSupportedError{ResourceType: "sqs"}, err)
+}
+
+func TestAwsCredentialFlags(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--external-id", "secret"})
+	assert.Equal(t, MissingRequiredFlagError{Name: "external-id", Required: "--role-arn"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "aws", "--session-duration", "2h"})
+	assert.Equal(t, MissingRequiredFlagError{Name: "session-duration", Required: "--role-arn or --mfa-serial"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "inspect-aws", "--role-arn", "arn:aws:iam::123456789012:role/nuke", "--session-duration", "13h"})
+	assert.Equal(t, InvalidFlagError{Name: "session-duration", Value: "13h"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "defaults-aws", "--mfa-serial", "arn:aws:iam::123456789012:mfa/operator", "--session-duration", "soon"})
+	assert.Equal(t, InvalidFlagError{Name: "session-duration", Value: "soon"}, err)
+}
+
+func TestAwsOrgFlags(t *testing.T) {
+	app := CreateCli("test", "")
+	err := app.Run([]string{"cloud-nuke", "aws", "--org-account-id", "123456789012"})
+	assert.Equal(t, OrgFlagWithoutOrgError{Name: "org-account-id"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "aws", "--org", "--state-file", "state.json"})
+	assert.Equal(t, IncompatibleFlagsError{Name: "state-file", Other: "org"}, err)
+
+	err = app.Run([]string{"cloud-nuke", "aws", "--org", "--resume", "state.json"})
+	assert.Equal(t, IncompatibleFlagsError{Name: "resume", Other: "org"}, err)
+}
+
+func TestAccountErrorRow(t *testing.T) {
+	account := aws.OrganizationAccount{ID: "200000000000", Name: "sandbox"}
+
+	row := accountErrorRow(account, errors.WithStackTrace(aws.AssumeOrganizationRoleError{RoleArn: "arn:aws:iam::200000000000:role/OrganizationAccountAccessRole", Underlying: goerrors.New("AccessDenied")}))
+	assert.Equal(t, "200000000000", row.AccountID)
+	assert.Equal(t, ui.ResourceStatusError, row.Status)
+	assert.Equal(t, "Could not assume role arn:aws:iam::200000000000:role/OrganizationAccountAccessRole: AccessDenied", row.Error)
+
+	// Accounts that the account rules do not allow are left alone on purpose
+	row = accountErrorRow(account, errors.WithStackTrace(aws.AccountBlockedError{AccountId: "200000000000", Entry: "200000000000"}))
+	assert.Equal(t, ui.ResourceStatusExcluded, row.Status)
+	assert.Equal(t, "Refusing to run against account 200000000000, which is blocked", row.Reason)
+	assert.Empty(t, row.Error)
+}
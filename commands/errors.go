@@ -2,6 +2,7 @@ package commands
 
 import (
 	"fmt"
+	"strings"
 )
 
 type InvalidFlagError struct {
@@ -12,3 +13,75 @@ type InvalidFlagError struct {
 func (e InvalidFlagError) Error() string {
 	return fmt.Sprintf("Invalid value %s for flag %s", e.Value, e.Name)
 }
+
+type RunCancelledError struct {
+	Underlying error
+}
+
+func (e RunCancelledError) Error() string {
+	return fmt.Sprintf("The run was cancelled before all resources were nuked (%s). The resources that were not nuked are reported as cancelled.", e.Underlying)
+}
+
+type MissingRunIDError struct{}
+
+func (e MissingRunIDError) Error() string {
+	return "The ID of the run must be passed with --run-id"
+}
+
+type MissingPlanFileError struct{}
+
+func (e MissingPlanFileError) Error() string {
+	return "Exactly one plan file must be passed, e.g. cloud-nuke aws apply plan.json"
+}
+
+type OrgFlagWithoutOrgError struct {
+	Name string
+}
+
+func (e OrgFlagWithoutOrgError) Error() string {
+	return fmt.Sprintf("The flag --%s only applies to runs against the accounts of an organization, with --org", e.Name)
+}
+
+type IncompatibleFlagsError struct {
+	Name  string
+	Other string
+}
+
+func (e IncompatibleFlagsError) Error() string {
+	return fmt.Sprintf("The flag --%s cannot be used with --%s", e.Name, e.Other)
+}
+
+type PromptWithResultsOnStdoutError struct {
+	Format string
+}
+
+func (e PromptWithResultsOnStdoutError) Error() string {
+	return fmt.Sprintf("Cannot ask for confirmation while writing the results to stdout in the %s format. Set --force, or write the results to a file with --output-file.", e.Format)
+}
+
+type OrganizationAccountsFailedError struct {
+	AccountIDs []string
+}
+
+func (e OrganizationAccountsFailedError) Error() string {
+	return fmt.Sprintf("Could not nuke %d accounts of the organization: %s. See the errors reported for them.", len(e.AccountIDs), strings.Join(e.AccountIDs, ", "))
+}
+
+type NukeModeMismatchError struct {
+	Path  string
+	Saved string
+	Mode  string
+}
+
+func (e NukeModeMismatchError) Error() string {
+	return fmt.Sprintf("%s was written in %s mode, so its resources cannot be nuked in %s mode. Omit --mode to nuke them in %s mode.", e.Path, e.Saved, e.Mode, e.Saved)
+}
+
+type MissingRequiredFlagError struct {
+	Name     string
+	Required string
+}
+
+func (e MissingRequiredFlagError) Error() string {
+	return fmt.Sprintf("The flag --%s can only be used with %s", e.Name, e.Required)
+}
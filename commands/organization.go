@@ -0,0 +1,288 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+	"github.com/tnn-gruntwork-io/cloud-nuke/aws"
+	"github.com/tnn-gruntwork-io/cloud-nuke/config"
+	"github.com/tnn-gruntwork-io/cloud-nuke/logging"
+	"github.com/tnn-gruntwork-io/cloud-nuke/report"
+	"github.com/tnn-gruntwork-io/cloud-nuke/telemetry"
+	"github.com/tnn-gruntwork-io/cloud-nuke/ui"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+	commonTelemetry "github.com/tnn-gruntwork-io/go-commons/telemetry"
+	"github.com/urfave/cli/v2"
+)
+
+// organizationAccountTarget holds the resources discovered in an account of the organization
+type organizationAccountTarget struct {
+	account       aws.OrganizationAccount
+	targetRegions []string
+	resources     *aws.AwsAccountResources
+	// generalErrors are the errors recorded while discovering the resources, which are reported with the outcome of
+	// nuking them
+	generalErrors map[string]report.GeneralError
+}
+
+// validateOrgFlags returns an error if flags that only apply to --org are set without it, or if flags that cannot
+// apply to several accounts are set with it
+func validateOrgFlags(c *cli.Context) error {
+	if !c.Bool("org") {
+		for _, name := range []string{"org-role-name", "org-account-id", "org-unit-id"} {
+			if c.IsSet(name) {
+				return OrgFlagWithoutOrgError{Name: name}
+			}
+		}
+		return nil
+	}
+	for _, name := range []string{"resume", "state-file", "out-plan"} {
+		if c.IsSet(name) {
+			return IncompatibleFlagsError{Name: name, Other: "org"}
+		}
+	}
+	return nil
+}
+
+// orgRoleName returns the role to assume in every account of the organization: the one set with --org-role-name, or
+// else the one set in the config file, or else the default one
+func orgRoleName(c *cli.Context, configObj config.Config) string {
+	if !c.IsSet("org-role-name") && configObj.Organization.RoleName != "" {
+		return configObj.Organization.RoleName
+	}
+	return c.String("org-role-name")
+}
+
+// awsNukeOrganization nukes the selected resources in the selected accounts of the AWS Organization of the current
+// credentials, by assuming a role in each of them. The resources of all accounts are discovered first, so that a single
+// confirmation covers them all, and so that nothing is nuked if the resources of an account exceed the limits. Accounts
+// are then nuked one after the other, each in a run of its own whose report is saved to a directory named after the
+// account. An account that cannot be nuked, e.g. because its role cannot be assumed, is skipped and reported as an
+// error once the other accounts are nuked.
+func awsNukeOrganization(ctx context.Context, c *cli.Context, configObj config.Config, resourceTypes []string, nukeOptions aws.NukeOptions, excludeAfter time.Time) error {
+	regions, err := aws.GetEnabledRegions()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	// Organizations is a global service, any region reaches it
+	orgRegion := regions[0]
+	accounts, err := aws.ListOrganizationAccounts(
+		orgRegion,
+		append(configObj.Organization.Accounts, c.StringSlice("org-account-id")...),
+		append(configObj.Organization.OrganizationalUnits, c.StringSlice("org-unit-id")...),
+	)
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		pterm.Info.Println("No account of the organization is selected, nothing to nuke.")
+		return nil
+	}
+	roleName := orgRoleName(c, configObj)
+
+	telemetry.TrackEvent(commonTelemetry.EventContext{
+		EventName: "Start aws organization",
+	}, map[string]interface{}{
+		"accountCount": len(accounts),
+	})
+
+	var accountIDs, failedAccountIDs []string
+	var targets []*organizationAccountTarget
+	rows := []ui.ResourceRow{}
+	reportAccountError := func(account aws.OrganizationAccount, err error) {
+		row := accountErrorRow(account, err)
+		if row.Status == ui.ResourceStatusError {
+			failedAccountIDs = append(failedAccountIDs, account.ID)
+		}
+		rows = append(rows, row)
+	}
+	for _, account := range accounts {
+		accountIDs = append(accountIDs, account.ID)
+		target := &organizationAccountTarget{account: account}
+		err := inOrganizationAccount(orgRegion, account, roleName, func() error {
+			return discoverOrganizationAccount(ctx, c, configObj, resourceTypes, nukeOptions, excludeAfter, target)
+		})
+		if ctx.Err() != nil {
+			return errors.WithStackTrace(ctx.Err())
+		}
+		if err != nil {
+			reportAccountError(account, err)
+			continue
+		}
+		targets = append(targets, target)
+	}
+
+	// The limits are a safeguard against filters that target far more resources than intended, so no account is nuked
+	// if any of them exceeds the limits
+	totalResourceCount := 0
+	for _, target := range targets {
+		if err := aws.CheckResourceLimits(target.resources, nukeOptions.Limits); err != nil {
+			logging.Logger.Errorf("The resources of account %s exceed the limits", target.account)
+			return err
+		}
+		totalResourceCount += target.resources.TotalResourceCount()
+	}
+
+	if totalResourceCount == 0 {
+		pterm.Info.Println("Nothing to nuke in the accounts of the organization, you're all good!")
+		return failedAccountsError(failedAccountIDs)
+	}
+	for _, target := range targets {
+		if target.resources.TotalResourceCount() == 0 {
+			continue
+		}
+		pterm.DefaultSection.Println(fmt.Sprintf("Account %s", target.account))
+		if err := renderResourcesToNuke(target.resources, nukeOptions.Mode); err != nil {
+			return err
+		}
+	}
+
+	if c.Bool("dry-run") {
+		logging.Logger.Infoln("Not taking any action as dry-run set to true.")
+		return failedAccountsError(failedAccountIDs)
+	}
+	proceed, err := confirmNuke(c, nukeOptions.Mode)
+	if err != nil || !proceed {
+		return err
+	}
+
+	format, err := parseOutputFormat(c)
+	if err != nil {
+		return err
+	}
+	renderTables := format == ui.OutputFormatText && c.String("output-file") == ""
+
+	var cancelErr error
+	for _, target := range targets {
+		if target.resources.TotalResourceCount() == 0 {
+			continue
+		}
+		var nukeErr error
+		err := inOrganizationAccount(orgRegion, target.account, roleName, func() error {
+			nukeErr = nukeOrganizationAccount(ctx, c, resourceTypes, nukeOptions, target)
+			return nil
+		})
+		if err != nil {
+			reportAccountError(target.account, err)
+			continue
+		}
+
+		accountRows := aws.ExtractRunReportForOutput()
+		for idx := range accountRows {
+			accountRows[idx].AccountID = target.account.ID
+		}
+		rows = append(rows, accountRows...)
+		if renderTables {
+			pterm.DefaultSection.Println(fmt.Sprintf("Account %s", target.account))
+			ui.RenderRunReport()
+		}
+		if report.IsCancelled(nukeErr) {
+			cancelErr = nukeErr
+			break
+		}
+		if nukeErr != nil {
+			reportAccountError(target.account, nukeErr)
+		}
+	}
+
+	ui.StopProgressBar()
+	if renderTables {
+		ui.PrintAccountReport(os.Stdout, accountIDs, rows)
+	} else if err := writeOutput(c, func(w io.Writer) error {
+		return ui.WriteResourceRows(w, format, rows)
+	}); err != nil {
+		return err
+	}
+
+	if cancelErr != nil {
+		telemetry.TrackEvent(commonTelemetry.EventContext{
+			EventName: "Nuke cancelled",
+		}, map[string]interface{}{})
+		return RunCancelledError{Underlying: errors.Unwrap(cancelErr)}
+	}
+	return failedAccountsError(failedAccountIDs)
+}
+
+// inOrganizationAccount calls fn while cloud-nuke acts on the given account of the organization
+func inOrganizationAccount(region string, account aws.OrganizationAccount, roleName string, fn func() error) error {
+	restore, err := aws.AssumeOrganizationRole(region, account, roleName)
+	if err != nil {
+		return err
+	}
+	defer restore()
+	return fn()
+}
+
+// discoverOrganizationAccount discovers the selected resources of the account of the target, in the regions enabled
+// for that account, unless the account is not allowed by the account rules
+func discoverOrganizationAccount(ctx context.Context, c *cli.Context, configObj config.Config, resourceTypes []string, nukeOptions aws.NukeOptions, excludeAfter time.Time, target *organizationAccountTarget) error {
+	targetRegions, err := selectTargetRegions(c)
+	if err != nil {
+		return err
+	}
+	if err := checkAccount(c, configObj.Accounts, targetRegions[0]); err != nil {
+		return err
+	}
+
+	spinnerSuccess, spinnerErr := pterm.DefaultSpinner.
+		WithRemoveWhenDone(true).
+		Start(fmt.Sprintf("Retrieving active AWS resources of account %s in [%s]", target.account, strings.Join(targetRegions, ", ")))
+	if spinnerErr != nil {
+		return errors.WithStackTrace(spinnerErr)
+	}
+	report.ResetErrors()
+	resources, err := aws.GetAllResources(ctx, targetRegions, excludeAfter, resourceTypes, configObj, c.Bool("delete-unaliased-kms-keys"), nukeOptions.Parallelism)
+	spinnerSuccess.Stop()
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	target.targetRegions = targetRegions
+	target.resources = resources
+	target.generalErrors = report.GetErrors()
+	return nil
+}
+
+// nukeOrganizationAccount nukes the resources discovered in the account of the target, in a run whose report is saved
+// to the subdirectory of --report-dir named after the account
+func nukeOrganizationAccount(ctx context.Context, c *cli.Context, resourceTypes []string, nukeOptions aws.NukeOptions, target *organizationAccountTarget) error {
+	report.ResetRecords()
+	report.ResetErrors()
+	for _, generalError := range target.generalErrors {
+		report.RecordError(generalError)
+	}
+
+	logging.Logger.Infof("Nuking account %s", target.account)
+	state := aws.NewRunState(target.resources, target.targetRegions, resourceTypes, c.Bool("delete-unaliased-kms-keys"))
+	state.AccountID = target.account.ID
+	nukeErr := nukeWithCheckpoints(ctx, target.resources, target.targetRegions, nukeOptions, state, "")
+	saveRunReport(target.account.ID, nukeOptions, filepath.Join(c.String("report-dir"), target.account.ID))
+	return nukeErr
+}
+
+// accountErrorRow returns the row reporting the given error, which kept cloud-nuke from nuking the given account.
+// Accounts that the account rules do not allow are reported as excluded rather than as errors.
+func accountErrorRow(account aws.OrganizationAccount, err error) ui.ResourceRow {
+	message := errors.Unwrap(err).Error()
+	switch errors.Unwrap(err).(type) {
+	case aws.AccountBlockedError, aws.AccountNotAllowedError:
+		logging.Logger.Infof("Skipping account %s: %s", account, message)
+		return ui.ResourceRow{AccountID: account.ID, Status: ui.ResourceStatusExcluded, Reason: message, Timestamp: time.Now().UTC()}
+	}
+	logging.Logger.Errorf("Could not nuke account %s: %s", account, message)
+	return ui.ResourceRow{AccountID: account.ID, Status: ui.ResourceStatusError, Error: message, Timestamp: time.Now().UTC()}
+}
+
+// failedAccountsError returns an error listing the given accounts, which could not be nuked, or nil if there are none
+func failedAccountsError(accountIDs []string) error {
+	if len(accountIDs) == 0 {
+		return nil
+	}
+	return OrganizationAccountsFailedError{AccountIDs: accountIDs}
+}
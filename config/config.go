@@ -1,65 +1,219 @@
 package config
 
 import (
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 // Config - the config object we pass around
 type Config struct {
-	S3                    ResourceType `yaml:"s3"`
-	IAMUsers              ResourceType `yaml:"IAMUsers"`
-	IAMGroups             ResourceType `yaml:"IAMGroups"`
-	IAMPolicies           ResourceType `yaml:"IAMPolicies"`
-	IAMServiceLinkedRoles ResourceType `yaml:"IAMServiceLinkedRoles"`
-	IAMRoles              ResourceType `yaml:"IAMRoles"`
-	SecretsManagerSecrets ResourceType `yaml:"SecretsManager"`
-	NatGateway            ResourceType `yaml:"NatGateway"`
-	AccessAnalyzer        ResourceType `yaml:"AccessAnalyzer"`
-	CloudWatchDashboard   ResourceType `yaml:"CloudWatchDashboard"`
-	OpenSearchDomain      ResourceType `yaml:"OpenSearchDomain"`
-	DynamoDB              ResourceType `yaml:"DynamoDB"`
-	EBSVolume             ResourceType `yaml:"EBSVolume"`
-	LambdaFunction        ResourceType `yaml:"LambdaFunction"`
-	ELBv2                 ResourceType `yaml:"ELBv2"`
-	ECSService            ResourceType `yaml:"ECSService"`
-	ECSCluster            ResourceType `yaml:"ECSCluster"`
-	Elasticache           ResourceType `yaml:"Elasticache"`
-	VPC                   ResourceType `yaml:"VPC"`
-	OIDCProvider          ResourceType `yaml:"OIDCProvider"`
-	AutoScalingGroup      ResourceType `yaml:"AutoScalingGroup"`
-	LaunchConfiguration   ResourceType `yaml:"LaunchConfiguration"`
-	ElasticIP             ResourceType `yaml:"ElasticIP"`
-	EC2                   ResourceType `yaml:"EC2"`
-	EC2KeyPairs           ResourceType `yaml:"EC2KeyPairs"`
-	EC2DedicatedHosts     ResourceType `yaml:"EC2DedicatedHosts"`
-	CloudWatchLogGroup    ResourceType `yaml:"CloudWatchLogGroup"`
-	KMSCustomerKeys       ResourceType `yaml:"KMSCustomerKeys"`
-	EKSCluster            ResourceType `yaml:"EKSCluster"`
-	SageMakerNotebook     ResourceType `yaml:"SageMakerNotebook"`
-	KinesisStream         ResourceType `yaml:"KinesisStream"`
-	APIGateway            ResourceType `yaml:"APIGateway"`
-	APIGatewayV2          ResourceType `yaml:"APIGatewayV2"`
-	ElasticFileSystem     ResourceType `yaml:"ElasticFileSystem"`
-	CloudtrailTrail       ResourceType `yaml:"CloudtrailTrail"`
-	ECRRepository         ResourceType `yaml:"ECRRepository"`
-	DBInstances           ResourceType `yaml:"DBInstances"`
-	LaunchTemplate        ResourceType `yaml:"LaunchTemplate"`
-	ConfigServiceRule     ResourceType `yaml:"ConfigServiceRule"`
-	ConfigServiceRecorder ResourceType `yaml:"ConfigServiceRecorder"`
-	CloudWatchAlarm       ResourceType `yaml:"CloudWatchAlarm"`
+	S3                          ResourceType `yaml:"s3"`
+	IAMUsers                    ResourceType `yaml:"IAMUsers"`
+	IAMGroups                   ResourceType `yaml:"IAMGroups"`
+	IAMPolicies                 ResourceType `yaml:"IAMPolicies"`
+	IAMServiceLinkedRoles       ResourceType `yaml:"IAMServiceLinkedRoles"`
+	IAMRoles                    ResourceType `yaml:"IAMRoles"`
+	SecretsManagerSecrets       ResourceType `yaml:"SecretsManager"`
+	NatGateway                  ResourceType `yaml:"NatGateway"`
+	AccessAnalyzer              ResourceType `yaml:"AccessAnalyzer"`
+	CloudWatchDashboard         ResourceType `yaml:"CloudWatchDashboard"`
+	OpenSearchDomain            ResourceType `yaml:"OpenSearchDomain"`
+	DynamoDB                    ResourceType `yaml:"DynamoDB"`
+	EBSVolume                   ResourceType `yaml:"EBSVolume"`
+	LambdaFunction              ResourceType `yaml:"LambdaFunction"`
+	ELBv2                       ResourceType `yaml:"ELBv2"`
+	ECSService                  ResourceType `yaml:"ECSService"`
+	ECSCluster                  ResourceType `yaml:"ECSCluster"`
+	Elasticache                 ResourceType `yaml:"Elasticache"`
+	VPC                         ResourceType `yaml:"VPC"`
+	OIDCProvider                ResourceType `yaml:"OIDCProvider"`
+	AutoScalingGroup            ResourceType `yaml:"AutoScalingGroup"`
+	LaunchConfiguration         ResourceType `yaml:"LaunchConfiguration"`
+	ElasticIP                   ResourceType `yaml:"ElasticIP"`
+	EC2                         ResourceType `yaml:"EC2"`
+	EC2KeyPairs                 ResourceType `yaml:"EC2KeyPairs"`
+	EC2DedicatedHosts           ResourceType `yaml:"EC2DedicatedHosts"`
+	CloudWatchLogGroup          ResourceType `yaml:"CloudWatchLogGroup"`
+	KMSCustomerKeys             ResourceType `yaml:"KMSCustomerKeys"`
+	EKSCluster                  ResourceType `yaml:"EKSCluster"`
+	SageMakerNotebook           ResourceType `yaml:"SageMakerNotebook"`
+	KinesisStream               ResourceType `yaml:"KinesisStream"`
+	APIGateway                  ResourceType `yaml:"APIGateway"`
+	APIGatewayV2                ResourceType `yaml:"APIGatewayV2"`
+	ElasticFileSystem           ResourceType `yaml:"ElasticFileSystem"`
+	CloudtrailTrail             ResourceType `yaml:"CloudtrailTrail"`
+	ECRRepository               ResourceType `yaml:"ECRRepository"`
+	DBInstances                 ResourceType `yaml:"DBInstances"`
+	LaunchTemplate              ResourceType `yaml:"LaunchTemplate"`
+	ConfigServiceRule           ResourceType `yaml:"ConfigServiceRule"`
+	ConfigServiceRecorder       ResourceType `yaml:"ConfigServiceRecorder"`
+	CloudWatchAlarm             ResourceType `yaml:"CloudWatchAlarm"`
+	ACMPCA                      ResourceType `yaml:"acmpca"`
+	AMI                         ResourceType `yaml:"AMI"`
+	EBSSnapshot                 ResourceType `yaml:"EBSSnapshot"`
+	ELB                         ResourceType `yaml:"ELB"`
+	GuardDuty                   ResourceType `yaml:"GuardDuty"`
+	MacieMember                 ResourceType `yaml:"MacieMember"`
+	DBClusters                  ResourceType `yaml:"DBClusters"`
+	SNS                         ResourceType `yaml:"SNS"`
+	SQS                         ResourceType `yaml:"SQS"`
+	TransitGateway              ResourceType `yaml:"TransitGateway"`
+	TransitGatewayRouteTable    ResourceType `yaml:"TransitGatewayRouteTable"`
+	TransitGatewayVpcAttachment ResourceType `yaml:"TransitGatewayVpcAttachment"`
+	PreservedData               ResourceType `yaml:"PreservedData"`
+
+	// Accounts restricts the AWS accounts cloud-nuke may run against
+	Accounts AccountRules `yaml:"accounts"`
+	// Limits caps the number of resources a single run may nuke
+	Limits ResourceLimits `yaml:"limits"`
+	// Organization selects the accounts nuked with --org
+	Organization OrganizationConfig `yaml:"organization"`
+}
+
+// OrganizationConfig selects the member accounts of an AWS Organization that cloud-nuke nukes with --org, by account ID
+// or by organizational unit, including the units nested in it. All the accounts of the organization are selected if
+// neither is set. RoleName is the role that cloud-nuke assumes in every account.
+type OrganizationConfig struct {
+	Accounts            []string `yaml:"accounts"`
+	OrganizationalUnits []string `yaml:"organizational_units"`
+	RoleName            string   `yaml:"role_name"`
+}
+
+// AccountRules lists AWS accounts by ID or alias. cloud-nuke refuses to run against a blocked account, or against any
+// account that is not allowed if allowed accounts are listed.
+type AccountRules struct {
+	Allowed []string `yaml:"allowed" json:"allowed,omitempty"`
+	Blocked []string `yaml:"blocked" json:"blocked,omitempty"`
+}
+
+// ResourceLimits caps the number of resources cloud-nuke may nuke in a single run, in total and per resource type (e.g.
+// rds). Runs that target more resources are refused. A limit of zero means no limit.
+type ResourceLimits struct {
+	MaxResources    int            `yaml:"max_resources" json:"maxResources,omitempty"`
+	PerResourceType map[string]int `yaml:"resource_types" json:"resourceTypes,omitempty"`
+}
+
+// Stricter returns the limits that satisfy both the receiver and other, that is the lowest of both limits wherever
+// both set one
+func (limits ResourceLimits) Stricter(other ResourceLimits) ResourceLimits {
+	stricter := ResourceLimits{
+		MaxResources:    stricterLimit(limits.MaxResources, other.MaxResources),
+		PerResourceType: map[string]int{},
+	}
+	for resourceType, limit := range limits.PerResourceType {
+		stricter.PerResourceType[resourceType] = limit
+	}
+	for resourceType, limit := range other.PerResourceType {
+		stricter.PerResourceType[resourceType] = stricterLimit(stricter.PerResourceType[resourceType], limit)
+	}
+	return stricter
+}
+
+func stricterLimit(limit int, other int) int {
+	if limit <= 0 || (other > 0 && other < limit) {
+		return other
+	}
+	return limit
+}
+
+// IsEmpty returns true if no accounts are allowed or blocked
+func (rules AccountRules) IsEmpty() bool {
+	return len(rules.Allowed) == 0 && len(rules.Blocked) == 0
+}
+
+// GetResourceType returns the rules defined under the given config key (e.g. "EC2" or "s3"), as declared in the yaml
+// tags of Config. An empty ResourceType is returned if the key is unknown.
+func (c Config) GetResourceType(configKey string) ResourceType {
+	value := reflect.ValueOf(c)
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("yaml") != configKey {
+			continue
+		}
+		if resourceType, ok := value.Field(i).Interface().(ResourceType); ok {
+			return resourceType
+		}
+	}
+	return ResourceType{}
+}
+
+// WithExclusionHandler returns a copy of the config in which handler is called for every resource excluded by the
+// rules of the resource type with the given config key
+func (c Config) WithExclusionHandler(configKey string, handler ExclusionHandler) Config {
+	value := reflect.ValueOf(&c).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		if value.Type().Field(i).Tag.Get("yaml") != configKey {
+			continue
+		}
+		if resourceType, ok := value.Field(i).Addr().Interface().(*ResourceType); ok {
+			resourceType.onExclude = handler
+		}
+	}
+	return c
 }
 
 type ResourceType struct {
 	IncludeRule FilterRule `yaml:"include"`
 	ExcludeRule FilterRule `yaml:"exclude"`
+	// OlderThan overrides the age passed with --older-than for the resources of this type
+	OlderThan *Duration `yaml:"older_than"`
+	// PreserveData overrides --preserve-data for the resources of this type
+	PreserveData *bool `yaml:"preserve_data"`
+	// RecoveryWindowDays overrides the number of days during which the deleted resources of this type can be restored
+	RecoveryWindowDays *int `yaml:"recovery_window_days"`
+
+	// onExclude is called whenever ShouldInclude excludes a resource. It is set with Config.WithExclusionHandler.
+	onExclude ExclusionHandler
 }
 
+// ExclusionHandler is called with a resource that was excluded by the rules of its type, and a description of the rule
+// that excluded it
+type ExclusionHandler func(value ResourceValue, reason string)
+
 type FilterRule struct {
 	NamesRegExp []Expression `yaml:"names_regex"`
+	Tags        []TagMatcher `yaml:"tags"`
+	// OlderThan sets the age the resources matched by the rule must reach to be nuked. An exclude rule with an age only
+	// protects the resources it matches until they reach that age.
+	OlderThan *Duration `yaml:"older_than"`
+}
+
+// TagMatcher matches resources by their tags. Key or KeyRegExp select the tags to look at, or all tags if neither is
+// set. The matcher matches a resource when one of the selected tags has a value matching ValueRegExp (or any value if
+// ValueRegExp is not set), or, when Absent is true, when none of them does.
+type TagMatcher struct {
+	Key         string      `yaml:"key"`
+	KeyRegExp   *Expression `yaml:"key_regex"`
+	ValueRegExp *Expression `yaml:"value_regex"`
+	Absent      bool        `yaml:"absent"`
+}
+
+// ResourceValue holds the attributes of a resource that include and exclude rules are evaluated against. Tags only
+// needs to be set for resource types that support tag rules.
+type ResourceValue struct {
+	Name string
+	Tags map[string]string
+	// ID identifies the resource when reporting that it was excluded, for resource types whose identifier is not their
+	// name. It defaults to Name.
+	ID string
+	// Time is the creation time of the resource, which rules with an age are evaluated against. Resources without a
+	// known creation time are never old enough for such rules.
+	Time time.Time
+}
+
+// Identifier returns the identifier of the resource
+func (value ResourceValue) Identifier() string {
+	if value.ID != "" {
+		return value.ID
+	}
+	return value.Name
 }
 
 type Expression struct {
@@ -84,6 +238,59 @@ func (expression *Expression) UnmarshalText(data []byte) error {
 	return nil
 }
 
+// Duration is an age in the config file, written either as a Go duration (e.g. 4h) or as a number of days (e.g. 30d)
+type Duration struct {
+	time.Duration
+	text string
+}
+
+// UnmarshalText - Internally used by yaml.Unmarshal to unmarshall a Duration field
+func (duration *Duration) UnmarshalText(data []byte) error {
+	parsed, err := ParseDuration(string(data))
+	if err != nil {
+		return err
+	}
+
+	duration.Duration = parsed
+	duration.text = string(data)
+
+	return nil
+}
+
+// String returns the duration as written in the config file
+func (duration Duration) String() string {
+	if duration.text == "" {
+		return duration.Duration.String()
+	}
+	return duration.text
+}
+
+// ParseDuration parses a Go duration (e.g. 4h), or a number of days (e.g. 30d)
+func ParseDuration(text string) (time.Duration, error) {
+	if days := strings.TrimSuffix(text, "d"); days != text {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, InvalidDurationError{Value: text}
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, InvalidDurationError{Value: text}
+	}
+	return duration, nil
+}
+
+// InvalidDurationError is returned for ages that are neither a Go duration nor a number of days
+type InvalidDurationError struct {
+	Value string
+}
+
+func (err InvalidDurationError) Error() string {
+	return fmt.Sprintf("Invalid duration %q: expected a Go duration such as 8h or a number of days such as 30d", err.Value)
+}
+
 // GetConfig - Unmarshall the config file and parse it into a config object.
 func GetConfig(filePath string) (*Config, error) {
 	var configObj Config
@@ -115,6 +322,119 @@ func matches(name string, regexps []Expression) bool {
 	return false
 }
 
+func (matcher TagMatcher) matches(tags map[string]string) bool {
+	found := false
+	for key, value := range tags {
+		if matcher.Key != "" && key != matcher.Key {
+			continue
+		}
+		if matcher.KeyRegExp != nil && !matcher.KeyRegExp.RE.MatchString(key) {
+			continue
+		}
+		if matcher.ValueRegExp != nil && !matcher.ValueRegExp.RE.MatchString(value) {
+			continue
+		}
+		found = true
+		break
+	}
+	return found != matcher.Absent
+}
+
+// IsEmpty returns true if the rule does not define any matchers
+func (r FilterRule) IsEmpty() bool {
+	return len(r.NamesRegExp) == 0 && len(r.Tags) == 0
+}
+
+// Matches returns true if any of the name or tag matchers of the rule matches the given resource
+func (r FilterRule) Matches(value ResourceValue) bool {
+	return r.match(value) != ""
+}
+
+// match returns a description of the first name or tag matcher of the rule that matches the given resource, or an empty
+// string if none does
+func (r FilterRule) match(value ResourceValue) string {
+	for _, re := range r.NamesRegExp {
+		if re.RE.MatchString(value.Name) {
+			return fmt.Sprintf("names_regex %q", re.RE.String())
+		}
+	}
+	for _, matcher := range r.Tags {
+		if matcher.matches(value.Tags) {
+			return fmt.Sprintf("tags %s", matcher)
+		}
+	}
+	return ""
+}
+
+// String describes the matcher with the same keys as in the config file, e.g. {key: owner, absent: true}
+func (matcher TagMatcher) String() string {
+	var fields []string
+	if matcher.Key != "" {
+		fields = append(fields, fmt.Sprintf("key: %s", matcher.Key))
+	}
+	if matcher.KeyRegExp != nil {
+		fields = append(fields, fmt.Sprintf("key_regex: %s", matcher.KeyRegExp.RE.String()))
+	}
+	if matcher.ValueRegExp != nil {
+		fields = append(fields, fmt.Sprintf("value_regex: %s", matcher.ValueRegExp.RE.String()))
+	}
+	if matcher.Absent {
+		fields = append(fields, "absent: true")
+	}
+	return fmt.Sprintf("{%s}", strings.Join(fields, ", "))
+}
+
+// HasRuleAge returns true if the include or exclude rule of the resource type sets its own age
+func (r ResourceType) HasRuleAge() bool {
+	return r.IncludeRule.OlderThan != nil || r.ExcludeRule.OlderThan != nil
+}
+
+// isYoungerThan returns true if the rule sets an age that the given resource has not reached yet
+func (r FilterRule) isYoungerThan(value ResourceValue) bool {
+	if r.OlderThan == nil {
+		return false
+	}
+	return value.Time.IsZero() || value.Time.After(time.Now().Add(-r.OlderThan.Duration))
+}
+
+// HasTagRules returns true if the include or exclude rule of the resource type matches on tags
+func (r ResourceType) HasTagRules() bool {
+	return len(r.IncludeRule.Tags) > 0 || len(r.ExcludeRule.Tags) > 0
+}
+
+// ShouldInclude checks if a resource should be included according to the inclusion and exclusion rules of its type.
+// A resource is excluded if any matcher of the exclusion rule matches it, unless the exclusion rule sets an age that the
+// resource has reached. Otherwise, it is included if there is no inclusion rule, or if any matcher of the inclusion rule
+// matches it, provided it has reached the age set by the inclusion rule, if any.
+// If an exclusion handler is set, it is called when the resource is excluded.
+func (r ResourceType) ShouldInclude(value ResourceValue) bool {
+	include, reason := r.Evaluate(value)
+	if !include && r.onExclude != nil {
+		r.onExclude(value, reason)
+	}
+	return include
+}
+
+// Evaluate checks if a resource should be included like ShouldInclude, without calling the exclusion handler. If the
+// resource is excluded, it also returns a description of the rule that excluded it.
+func (r ResourceType) Evaluate(value ResourceValue) (bool, string) {
+	if match := r.ExcludeRule.match(value); match != "" {
+		if r.ExcludeRule.OlderThan == nil {
+			return false, fmt.Sprintf("matched exclude rule %s", match)
+		}
+		if r.ExcludeRule.isYoungerThan(value) {
+			return false, fmt.Sprintf("matched exclude rule %s and is not older than %s", match, r.ExcludeRule.OlderThan)
+		}
+	}
+	if !r.IncludeRule.IsEmpty() && !r.IncludeRule.Matches(value) {
+		return false, "did not match any include rule"
+	}
+	if r.IncludeRule.isYoungerThan(value) {
+		return false, fmt.Sprintf("is not older than %s as required by the include rule", r.IncludeRule.OlderThan)
+	}
+	return true, ""
+}
+
 // ShouldInclude - Checks if a resource's name should be included according to the inclusion and exclusion rules
 func ShouldInclude(name string, includeREs []Expression, excludeREs []Expression) bool {
 	if len(includeREs) == 0 && len(excludeREs) == 0 {
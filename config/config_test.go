@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -11,47 +13,63 @@ import (
 
 func emptyConfig() *Config {
 	return &Config{
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
-		ResourceType{FilterRule{}, FilterRule{}},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		ResourceType{},
+		AccountRules{},
+		ResourceLimits{},
+		OrganizationConfig{},
 	}
 }
 
@@ -183,6 +201,17 @@ func TestConfigS3_FilterNames(t *testing.T) {
 	return
 }
 
+func TestConfig_GetResourceType(t *testing.T) {
+	configFilePath := "./mocks/s3_filter_names.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	assert.Equal(t, configObj.S3, configObj.GetResourceType("s3"))
+	assert.Equal(t, ResourceType{}, configObj.GetResourceType("IAMUsers"))
+	assert.Equal(t, ResourceType{}, configObj.GetResourceType("unknown"))
+}
+
 // IAM Users Tests
 
 func TestConfigIAM_Users_Empty(t *testing.T) {
@@ -931,6 +960,101 @@ func TestConfigElasticFileSystem_FilterNames(t *testing.T) {
 
 // end ElasticFileSystem tests
 
+// ACMPCA Tests
+
+func TestConfigACMPCA_Empty(t *testing.T) {
+	configFilePath := "./mocks/acmpca_empty.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if !reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should be empty, %+v\n", configObj.APIGateway)
+	}
+
+	return
+}
+
+func TestConfigACMPCA_EmptyFilters(t *testing.T) {
+	configFilePath := "./mocks/acmpca_empty_filters.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if !reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should be empty, %+v\n", configObj)
+	}
+
+	return
+}
+
+func TestConfigACMPCA_EmptyRules(t *testing.T) {
+	configFilePath := "./mocks/acmpca_empty_rules.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if !reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should be empty, %+v\n", configObj)
+	}
+
+	return
+}
+
+func TestConfigACMPCA_IncludeNames(t *testing.T) {
+	configFilePath := "./mocks/acmpca_include_names.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should not be empty, %+v\n", configObj)
+	}
+
+	if len(configObj.ACMPCA.IncludeRule.NamesRegExp) == 0 {
+		assert.Fail(t, "ConfigObj should contain ACMPCA regexes, %+v\n", configObj)
+	}
+
+	return
+}
+
+func TestConfigACMPCA_ExcludeNames(t *testing.T) {
+	configFilePath := "./mocks/acmpca_exclude_names.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should not be empty, %+v\n", configObj)
+	}
+
+	if len(configObj.ACMPCA.ExcludeRule.NamesRegExp) == 0 {
+		assert.Fail(t, "ConfigObj should contain ACMPCA regexes, %+v\n", configObj)
+	}
+
+	return
+}
+
+func TestConfigACMPCA_FilterNames(t *testing.T) {
+	configFilePath := "./mocks/acmpca_filter_names.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	if reflect.DeepEqual(configObj, emptyConfig()) {
+		assert.Fail(t, "Config should not be empty, %+v\n", configObj)
+	}
+
+	if len(configObj.ACMPCA.IncludeRule.NamesRegExp) == 0 ||
+		len(configObj.ACMPCA.ExcludeRule.NamesRegExp) == 0 {
+		assert.Fail(t, "ConfigObj should contain ACMPCA regexes, %+v\n", configObj)
+	}
+
+	return
+}
+
+// end ACMPCA tests
+
 func TestShouldInclude_AllowWhenEmpty(t *testing.T) {
 	var includeREs []Expression
 	var excludeREs []Expression
@@ -981,3 +1105,227 @@ func TestShouldInclude_WhenMatchesIncludeAndExclude(t *testing.T) {
 	assert.False(t, ShouldInclude("terraform-tf-state", includeREs, excludeREs),
 		"Should not include when doesn't matches 'include' list")
 }
+
+func TestConfigEC2_Tags(t *testing.T) {
+	configFilePath := "./mocks/ec2_tags.yaml"
+	configObj, err := GetConfig(configFilePath)
+
+	require.NoError(t, err)
+
+	require.Len(t, configObj.EC2.IncludeRule.Tags, 2)
+	require.Len(t, configObj.EC2.ExcludeRule.Tags, 2)
+	assert.Equal(t, "env", configObj.EC2.IncludeRule.Tags[0].Key)
+	require.NotNil(t, configObj.EC2.IncludeRule.Tags[0].ValueRegExp)
+	assert.Equal(t, "^sandbox$", configObj.EC2.IncludeRule.Tags[0].ValueRegExp.RE.String())
+	require.NotNil(t, configObj.EC2.IncludeRule.Tags[1].KeyRegExp)
+	assert.Nil(t, configObj.EC2.IncludeRule.Tags[1].ValueRegExp)
+	assert.True(t, configObj.EC2.ExcludeRule.Tags[0].Absent)
+	assert.True(t, configObj.EC2.HasTagRules())
+	assert.False(t, configObj.EBSVolume.HasTagRules())
+}
+
+func TestResourceTypeShouldInclude_Tags(t *testing.T) {
+	configObj, err := GetConfig("./mocks/ec2_tags.yaml")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		tags     map[string]string
+		expected bool
+	}{
+		{"included by key and value", map[string]string{"env": "sandbox", "owner": "alice"}, true},
+		{"included by key regex", map[string]string{"team-data": "", "owner": "alice"}, true},
+		{"value does not match", map[string]string{"env": "production", "owner": "alice"}, false},
+		{"excluded when tag is absent", map[string]string{"env": "sandbox"}, false},
+		{"excluded by key", map[string]string{"env": "sandbox", "owner": "alice", "cloud-nuke-keep": "yes"}, false},
+		{"no tags", nil, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, configObj.EC2.ShouldInclude(ResourceValue{Name: "test", Tags: tc.tags}))
+		})
+	}
+}
+
+func TestResourceTypeShouldInclude_NamesAndTags(t *testing.T) {
+	include, err := regexp.Compile(`^test-`)
+	require.NoError(t, err)
+	value, err := regexp.Compile(`^prod`)
+	require.NoError(t, err)
+
+	resourceType := ResourceType{
+		IncludeRule: FilterRule{NamesRegExp: []Expression{{RE: *include}}},
+		ExcludeRule: FilterRule{Tags: []TagMatcher{{Key: "env", ValueRegExp: &Expression{RE: *value}}}},
+	}
+
+	assert.True(t, resourceType.ShouldInclude(ResourceValue{Name: "test-1", Tags: map[string]string{"env": "dev"}}))
+	assert.False(t, resourceType.ShouldInclude(ResourceValue{Name: "test-2", Tags: map[string]string{"env": "production"}}))
+	assert.False(t, resourceType.ShouldInclude(ResourceValue{Name: "other", Tags: map[string]string{"env": "dev"}}))
+	assert.True(t, ResourceType{}.ShouldInclude(ResourceValue{Name: "anything"}))
+}
+
+func TestResourceTypeEvaluate_Reasons(t *testing.T) {
+	include, err := regexp.Compile(`^test-`)
+	require.NoError(t, err)
+	exclude, err := regexp.Compile(`-keep$`)
+	require.NoError(t, err)
+
+	resourceType := ResourceType{
+		IncludeRule: FilterRule{NamesRegExp: []Expression{{RE: *include}}},
+		ExcludeRule: FilterRule{
+			NamesRegExp: []Expression{{RE: *exclude}},
+			Tags:        []TagMatcher{{Key: "owner", Absent: true}},
+		},
+	}
+
+	included, reason := resourceType.Evaluate(ResourceValue{Name: "test-1", Tags: map[string]string{"owner": "ops"}})
+	assert.True(t, included)
+	assert.Empty(t, reason)
+
+	included, reason = resourceType.Evaluate(ResourceValue{Name: "test-keep", Tags: map[string]string{"owner": "ops"}})
+	assert.False(t, included)
+	assert.Equal(t, `matched exclude rule names_regex "-keep$"`, reason)
+
+	included, reason = resourceType.Evaluate(ResourceValue{Name: "test-2"})
+	assert.False(t, included)
+	assert.Equal(t, "matched exclude rule tags {key: owner, absent: true}", reason)
+
+	included, reason = resourceType.Evaluate(ResourceValue{Name: "other", Tags: map[string]string{"owner": "ops"}})
+	assert.False(t, included)
+	assert.Equal(t, "did not match any include rule", reason)
+}
+
+func TestConfig_OlderThan(t *testing.T) {
+	configObj, err := GetConfig("./mocks/older_than.yaml")
+	require.NoError(t, err)
+
+	require.NotNil(t, configObj.EC2.OlderThan)
+	assert.Equal(t, 4*time.Hour, configObj.EC2.OlderThan.Duration)
+	require.NotNil(t, configObj.KMSCustomerKeys.OlderThan)
+	assert.Equal(t, 7*24*time.Hour, configObj.KMSCustomerKeys.OlderThan.Duration)
+	require.NotNil(t, configObj.S3.ExcludeRule.OlderThan)
+	assert.Equal(t, "90d", configObj.S3.ExcludeRule.OlderThan.String())
+	assert.True(t, configObj.S3.HasRuleAge())
+	assert.False(t, configObj.EC2.HasRuleAge())
+	assert.Nil(t, configObj.IAMUsers.OlderThan)
+}
+
+func TestConfig_OlderThanInvalid(t *testing.T) {
+	_, err := GetConfig("./mocks/older_than_invalid.yaml")
+	assert.Error(t, err)
+}
+
+func TestParseDuration(t *testing.T) {
+	testCases := map[string]time.Duration{
+		"0s":  0,
+		"90m": 90 * time.Minute,
+		"4h":  4 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+	}
+	for text, expected := range testCases {
+		duration, err := ParseDuration(text)
+		require.NoError(t, err)
+		assert.Equal(t, expected, duration, text)
+	}
+
+	for _, text := range []string{"", "d", "-1d", "1.5d", "2 weeks"} {
+		_, err := ParseDuration(text)
+		assert.Equal(t, InvalidDurationError{Value: text}, err)
+	}
+}
+
+func TestResourceTypeEvaluate_RuleAge(t *testing.T) {
+	exclude, err := regexp.Compile(`^logs-`)
+	require.NoError(t, err)
+
+	resourceType := ResourceType{
+		IncludeRule: FilterRule{OlderThan: &Duration{Duration: time.Hour, text: "1h"}},
+		ExcludeRule: FilterRule{
+			NamesRegExp: []Expression{{RE: *exclude}},
+			OlderThan:   &Duration{Duration: 30 * 24 * time.Hour, text: "30d"},
+		},
+	}
+
+	included, _ := resourceType.Evaluate(ResourceValue{Name: "app", Time: time.Now().Add(-2 * time.Hour)})
+	assert.True(t, included)
+
+	included, reason := resourceType.Evaluate(ResourceValue{Name: "app", Time: time.Now()})
+	assert.False(t, included)
+	assert.Equal(t, "is not older than 1h as required by the include rule", reason)
+
+	included, reason = resourceType.Evaluate(ResourceValue{Name: "logs-app", Time: time.Now().Add(-2 * time.Hour)})
+	assert.False(t, included)
+	assert.Equal(t, `matched exclude rule names_regex "^logs-" and is not older than 30d`, reason)
+
+	included, _ = resourceType.Evaluate(ResourceValue{Name: "logs-app", Time: time.Now().Add(-31 * 24 * time.Hour)})
+	assert.True(t, included)
+
+	// Resources without a known creation time never reach the age of a rule
+	included, _ = resourceType.Evaluate(ResourceValue{Name: "app"})
+	assert.False(t, included)
+}
+
+func TestConfig_Accounts(t *testing.T) {
+	configObj, err := GetConfig("./mocks/accounts.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"123456789012", "sandbox"}, configObj.Accounts.Allowed)
+	assert.Equal(t, []string{"210987654321"}, configObj.Accounts.Blocked)
+	assert.False(t, configObj.Accounts.IsEmpty())
+	assert.True(t, emptyConfig().Accounts.IsEmpty())
+}
+
+func TestConfig_Limits(t *testing.T) {
+	configObj, err := GetConfig("./mocks/limits.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, ResourceLimits{MaxResources: 100, PerResourceType: map[string]int{"rds": 5, "s3": 10}}, configObj.Limits)
+}
+
+func TestConfig_Organization(t *testing.T) {
+	configObj, err := GetConfig("./mocks/organization.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, OrganizationConfig{
+		Accounts:            []string{"123456789012"},
+		OrganizationalUnits: []string{"ou-ab12-sandbox1", "ou-ab12-sandbox2"},
+		RoleName:            "CloudNukeRole",
+	}, configObj.Organization)
+}
+
+func TestResourceLimitsStricter(t *testing.T) {
+	limits := ResourceLimits{MaxResources: 100, PerResourceType: map[string]int{"rds": 5, "s3": 10}}
+
+	assert.Equal(
+		t,
+		ResourceLimits{MaxResources: 50, PerResourceType: map[string]int{"rds": 2, "s3": 10, "ec2": 20}},
+		limits.Stricter(ResourceLimits{MaxResources: 50, PerResourceType: map[string]int{"rds": 2, "ec2": 20}}),
+	)
+	assert.Equal(
+		t,
+		ResourceLimits{MaxResources: 100, PerResourceType: map[string]int{"rds": 5, "s3": 10}},
+		limits.Stricter(ResourceLimits{MaxResources: 500}),
+	)
+	assert.Equal(t, 30, ResourceLimits{}.Stricter(ResourceLimits{MaxResources: 30}).MaxResources)
+}
+
+func TestConfigWithExclusionHandler(t *testing.T) {
+	exclude, err := regexp.Compile(`^prod-`)
+	require.NoError(t, err)
+
+	configObj := Config{}
+	configObj.EC2.ExcludeRule.NamesRegExp = []Expression{{RE: *exclude}}
+
+	var excluded []string
+	withHandler := configObj.WithExclusionHandler("EC2", func(value ResourceValue, reason string) {
+		excluded = append(excluded, fmt.Sprintf("%s: %s", value.Identifier(), reason))
+	})
+
+	assert.True(t, withHandler.EC2.ShouldInclude(ResourceValue{Name: "dev-1", ID: "i-1"}))
+	assert.False(t, withHandler.EC2.ShouldInclude(ResourceValue{Name: "prod-1", ID: "i-2"}))
+	// The original config is left untouched
+	assert.False(t, configObj.EC2.ShouldInclude(ResourceValue{Name: "prod-2", ID: "i-3"}))
+
+	assert.Equal(t, []string{`i-2: matched exclude rule names_regex "^prod-"`}, excluded)
+}
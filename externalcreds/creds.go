@@ -3,15 +3,32 @@ package externalcreds
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/cloud-nuke/throttling"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
 )
 
 var externalConfig *aws.Config
 
+// profile is the named profile passed to Configure, if any
+var profile string
+
 func Set(opts *aws.Config) {
 	externalConfig = opts
 }
 
+// Config returns the config passed to Set, or nil if none was set
+func Config() *aws.Config {
+	return externalConfig
+}
+
+// Get returns a session for the given region, like NewSession, and panics if it cannot be created
 func Get(region string) *session.Session {
+	return session.Must(NewSession(region))
+}
+
+// NewSession returns a session for the given region, which retries throttled requests. It uses the credentials passed
+// to Set or Configure, if any, and the default credential chain otherwise.
+func NewSession(region string) (*session.Session, error) {
 	config := aws.Config{
 		Region: aws.String(region),
 	}
@@ -19,12 +36,15 @@ func Get(region string) *session.Session {
 	if externalConfig != nil {
 		config.Credentials = externalConfig.Credentials
 	}
-	return session.Must(
-		session.NewSessionWithOptions(
-			session.Options{
-				SharedConfigState: session.SharedConfigEnable,
-				Config:            config,
-			},
-		),
+	awsSession, err := session.NewSessionWithOptions(
+		session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+			Profile:           profile,
+			Config:            config,
+		},
 	)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return throttling.Configure(awsSession), nil
 }
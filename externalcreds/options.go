@@ -0,0 +1,202 @@
+package externalcreds
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+const (
+	// DefaultSessionDuration is the duration of the temporary credentials obtained by assuming a role or with an MFA
+	// device, unless Options say otherwise
+	DefaultSessionDuration = time.Hour
+	MinSessionDuration     = 15 * time.Minute
+	// MaxRoleSessionDuration is the longest duration of the credentials of an assumed role. The role may allow less.
+	MaxRoleSessionDuration = 12 * time.Hour
+	// MaxMFASessionDuration is the longest duration of the temporary credentials obtained with an MFA device
+	MaxMFASessionDuration = 36 * time.Hour
+
+	// refreshWindow is how long before they expire temporary credentials are refreshed, so that requests made by a long
+	// run never fail because the credentials expired while they were being sent
+	refreshWindow = 5 * time.Minute
+	// defaultSTSRegion is the region in which roles are assumed if the profile does not set one
+	defaultSTSRegion = "us-east-1"
+	// roleSessionName is the session name of the roles assumed by cloud-nuke, which shows in CloudTrail
+	roleSessionName = "cloud-nuke"
+)
+
+// Options select the credentials that cloud-nuke uses, as set with the --profile, --role-arn, --external-id,
+// --mfa-serial and --session-duration flags. They are all optional.
+type Options struct {
+	// Profile is the named profile of the shared config and credentials files to use, instead of the AWS_PROFILE
+	// environment variable
+	Profile string
+	// RoleArn is the ARN of a role to assume with the credentials of the profile
+	RoleArn string
+	// ExternalID is passed when assuming RoleArn
+	ExternalID string
+	// MFASerial is the serial number or ARN of the MFA device of the user, whose current code is prompted for on stdin.
+	// It is used to assume RoleArn, or to get temporary credentials for the profile if no role is set.
+	MFASerial string
+	// SessionDuration is the duration of the temporary credentials obtained by assuming RoleArn or with MFASerial. Zero
+	// means DefaultSessionDuration.
+	SessionDuration time.Duration
+}
+
+// Validate returns an error if the options cannot be used together
+func (options Options) Validate() error {
+	if options.ExternalID != "" && options.RoleArn == "" {
+		return errors.WithStackTrace(ExternalIDWithoutRoleError{})
+	}
+	if options.SessionDuration == 0 {
+		return nil
+	}
+	if options.RoleArn == "" && options.MFASerial == "" {
+		return errors.WithStackTrace(SessionDurationWithoutSessionError{})
+	}
+	maxDuration := MaxRoleSessionDuration
+	if options.RoleArn == "" {
+		maxDuration = MaxMFASessionDuration
+	}
+	if options.SessionDuration < MinSessionDuration || options.SessionDuration > maxDuration {
+		return errors.WithStackTrace(InvalidSessionDurationError{Duration: options.SessionDuration, Min: MinSessionDuration, Max: maxDuration})
+	}
+	return nil
+}
+
+// Configure makes cloud-nuke use the credentials selected by the given options, as Set does. Nothing changes if no
+// option is set. The temporary credentials obtained by assuming a role or with an MFA device are refreshed
+// automatically shortly before they expire, so that long runs are not interrupted. With an MFA device, refreshing them
+// prompts for a new code. The credentials are retrieved right away, so that a wrong profile, role or MFA code fails
+// before anything is done with them.
+func Configure(options Options) error {
+	if err := options.Validate(); err != nil {
+		return err
+	}
+	if options == (Options{}) {
+		return nil
+	}
+
+	baseSession, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState:       session.SharedConfigEnable,
+		Profile:                 options.Profile,
+		AssumeRoleTokenProvider: stscreds.StdinTokenProvider,
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if aws.StringValue(baseSession.Config.Region) == "" {
+		baseSession.Config.Region = aws.String(defaultSTSRegion)
+	}
+
+	duration := options.SessionDuration
+	if duration == 0 {
+		duration = DefaultSessionDuration
+	}
+	creds := baseSession.Config.Credentials
+	if options.RoleArn != "" {
+		creds = stscreds.NewCredentials(baseSession, options.RoleArn, func(provider *stscreds.AssumeRoleProvider) {
+			provider.RoleSessionName = roleSessionName
+			provider.Duration = duration
+			provider.ExpiryWindow = refreshWindow
+			if options.ExternalID != "" {
+				provider.ExternalID = aws.String(options.ExternalID)
+			}
+			if options.MFASerial != "" {
+				provider.SerialNumber = aws.String(options.MFASerial)
+				provider.TokenProvider = mfaCodePrompt(options.MFASerial)
+			}
+		})
+	} else if options.MFASerial != "" {
+		creds = credentials.NewCredentials(&mfaSessionProvider{
+			client:        sts.New(baseSession),
+			serialNumber:  options.MFASerial,
+			duration:      duration,
+			tokenProvider: mfaCodePrompt(options.MFASerial),
+		})
+	}
+
+	if _, err := creds.Get(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	// Every session shares the same credentials, so that MFA codes are only prompted for once, and temporary
+	// credentials are only refreshed when they are about to expire
+	profile = options.Profile
+	Set(aws.NewConfig().WithCredentials(creds))
+	return nil
+}
+
+// mfaCodePrompt returns a function that prompts for the current code of the MFA device with the given serial number
+func mfaCodePrompt(serialNumber string) func() (string, error) {
+	return func() (string, error) {
+		var code string
+		fmt.Fprintf(os.Stderr, "Enter the current code of MFA device %s: ", serialNumber)
+		if _, err := fmt.Scanln(&code); err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		return code, nil
+	}
+}
+
+// mfaSessionProvider retrieves temporary credentials for the MFA device with the given serial number, from the
+// credentials of the client, prompting for the current code of the device whenever they are retrieved
+type mfaSessionProvider struct {
+	credentials.Expiry
+	client        stsiface.STSAPI
+	serialNumber  string
+	duration      time.Duration
+	tokenProvider func() (string, error)
+}
+
+func (provider *mfaSessionProvider) Retrieve() (credentials.Value, error) {
+	code, err := provider.tokenProvider()
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	output, err := provider.client.GetSessionToken(&sts.GetSessionTokenInput{
+		SerialNumber:    aws.String(provider.serialNumber),
+		TokenCode:       aws.String(code),
+		DurationSeconds: aws.Int64(int64(provider.duration / time.Second)),
+	})
+	if err != nil {
+		return credentials.Value{}, errors.WithStackTrace(err)
+	}
+
+	provider.SetExpiration(aws.TimeValue(output.Credentials.Expiration), refreshWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(output.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(output.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(output.Credentials.SessionToken),
+		ProviderName:    "MFASessionProvider",
+	}, nil
+}
+
+type ExternalIDWithoutRoleError struct{}
+
+func (err ExternalIDWithoutRoleError) Error() string {
+	return "An external ID only applies to assuming a role, but no role ARN is set"
+}
+
+type SessionDurationWithoutSessionError struct{}
+
+func (err SessionDurationWithoutSessionError) Error() string {
+	return "A session duration only applies to the temporary credentials of an assumed role or an MFA device"
+}
+
+type InvalidSessionDurationError struct {
+	Duration time.Duration
+	Min      time.Duration
+	Max      time.Duration
+}
+
+func (err InvalidSessionDurationError) Error() string {
+	return fmt.Sprintf("Invalid session duration %s, it must be between %s and %s", err.Duration, err.Min, err.Max)
+}
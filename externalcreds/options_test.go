@@ -0,0 +1,114 @@
+package externalcreds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{Profile: "sandbox", RoleArn: "arn:aws:iam::123456789012:role/nuke", ExternalID: "id", SessionDuration: 12 * time.Hour}.Validate())
+	assert.NoError(t, Options{MFASerial: "arn:aws:iam::123456789012:mfa/user", SessionDuration: 36 * time.Hour}.Validate())
+
+	assert.Equal(t, ExternalIDWithoutRoleError{}, errors.Unwrap(Options{ExternalID: "id"}.Validate()))
+	assert.Equal(t, SessionDurationWithoutSessionError{}, errors.Unwrap(Options{Profile: "sandbox", SessionDuration: time.Hour}.Validate()))
+	assert.Equal(
+		t,
+		InvalidSessionDurationError{Duration: 13 * time.Hour, Min: MinSessionDuration, Max: MaxRoleSessionDuration},
+		errors.Unwrap(Options{RoleArn: "arn:aws:iam::123456789012:role/nuke", SessionDuration: 13 * time.Hour}.Validate()),
+	)
+	assert.Equal(
+		t,
+		InvalidSessionDurationError{Duration: 5 * time.Minute, Min: MinSessionDuration, Max: MaxMFASessionDuration},
+		errors.Unwrap(Options{MFASerial: "arn:aws:iam::123456789012:mfa/user", SessionDuration: 5 * time.Minute}.Validate()),
+	)
+}
+
+func TestConfigureWithProfile(t *testing.T) {
+	previousConfig, previousProfile := externalConfig, profile
+	defer func() { externalConfig, profile = previousConfig, previousProfile }()
+
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials")
+	require.NoError(t, os.WriteFile(credentialsFile, []byte("[sandbox]\naws_access_key_id = AKIASANDBOX\naws_secret_access_key = secret\n"), 0600))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_PROFILE", "production")
+
+	// Without options, the credentials are left alone
+	Set(nil)
+	require.NoError(t, Configure(Options{}))
+	assert.Nil(t, Config())
+
+	// The profile takes precedence over AWS_PROFILE, for every session
+	require.NoError(t, Configure(Options{Profile: "sandbox"}))
+	for _, region := range []string{"us-east-1", "eu-west-1"} {
+		awsSession, err := NewSession(region)
+		require.NoError(t, err)
+		value, err := awsSession.Config.Credentials.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "AKIASANDBOX", value.AccessKeyID)
+		assert.Equal(t, region, aws.StringValue(awsSession.Config.Region))
+	}
+
+	assert.Error(t, Configure(Options{Profile: "missing"}))
+}
+
+type fakeSTS struct {
+	stsiface.STSAPI
+	inputs []*sts.GetSessionTokenInput
+}
+
+func (fake *fakeSTS) GetSessionToken(input *sts.GetSessionTokenInput) (*sts.GetSessionTokenOutput, error) {
+	fake.inputs = append(fake.inputs, input)
+	return &sts.GetSessionTokenOutput{Credentials: &sts.Credentials{
+		AccessKeyId:     aws.String("ASIATEMPORARY"),
+		SecretAccessKey: aws.String("secret"),
+		SessionToken:    aws.String("token"),
+		Expiration:      aws.Time(time.Now().Add(time.Duration(aws.Int64Value(input.DurationSeconds)) * time.Second)),
+	}}, nil
+}
+
+func TestMFASessionProvider(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSTS{}
+	prompts := 0
+	creds := credentials.NewCredentials(&mfaSessionProvider{
+		client:       client,
+		serialNumber: "arn:aws:iam::123456789012:mfa/user",
+		duration:     8 * time.Hour,
+		tokenProvider: func() (string, error) {
+			prompts++
+			return "123456", nil
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		value, err := creds.Get()
+		require.NoError(t, err)
+		assert.Equal(t, "ASIATEMPORARY", value.AccessKeyID)
+		assert.Equal(t, "token", value.SessionToken)
+	}
+	// The code is only prompted for again once the credentials are about to expire
+	assert.Equal(t, 1, prompts)
+	require.Len(t, client.inputs, 1)
+	assert.Equal(t, "123456", aws.StringValue(client.inputs[0].TokenCode))
+	assert.Equal(t, int64(8*60*60), aws.Int64Value(client.inputs[0].DurationSeconds))
+
+	expiresAt, err := creds.ExpiresAt()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(8*time.Hour-refreshWindow), expiresAt, time.Minute)
+}
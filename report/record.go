@@ -1,7 +1,11 @@
 package report
 
 import (
+	"context"
+	"errors"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/tnn-gruntwork-io/cloud-nuke/progressbar"
 )
@@ -10,28 +14,97 @@ var m = &sync.Mutex{}
 
 var generalErrors = make(map[string]GeneralError)
 
-var records = make(map[string]Entry)
+var records = make(map[RecordKey]Entry)
 
-func GetRecords() map[string]Entry {
-	return records
+var exclusions = make(map[Exclusion]Exclusion)
+
+// GetRecords returns a copy of the recorded entries, keyed by the resource they were recorded for. A copy is returned
+// so that callers can safely iterate over it while resources are still being nuked concurrently.
+func GetRecords() map[RecordKey]Entry {
+	defer m.Unlock()
+	m.Lock()
+	recordsCopy := make(map[RecordKey]Entry, len(records))
+	for key, entry := range records {
+		recordsCopy[key] = entry
+	}
+	return recordsCopy
+}
+
+// GetRecord returns the recorded outcome of nuking the resource of the given type with the given identifier in the
+// given region, if any
+func GetRecord(region string, resourceType string, identifier string) (Entry, bool) {
+	defer m.Unlock()
+	m.Lock()
+	entry, ok := records[RecordKey{Region: region, ResourceType: resourceType, Identifier: identifier}]
+	return entry, ok
 }
 
+// GetErrors returns a copy of the recorded general errors, keyed by description
 func GetErrors() map[string]GeneralError {
-	return generalErrors
+	defer m.Unlock()
+	m.Lock()
+	errorsCopy := make(map[string]GeneralError, len(generalErrors))
+	for description, generalError := range generalErrors {
+		errorsCopy[description] = generalError
+	}
+	return errorsCopy
 }
 
 func ResetRecords() {
-	records = make(map[string]Entry)
+	defer m.Unlock()
+	m.Lock()
+	records = make(map[RecordKey]Entry)
 }
 
 func ResetErrors() {
+	defer m.Unlock()
+	m.Lock()
 	generalErrors = make(map[string]GeneralError)
 }
 
+// GetExclusions returns the recorded exclusions, sorted by region, resource type and identifier
+func GetExclusions() []Exclusion {
+	defer m.Unlock()
+	m.Lock()
+	exclusionsCopy := make([]Exclusion, 0, len(exclusions))
+	for _, exclusion := range exclusions {
+		exclusionsCopy = append(exclusionsCopy, exclusion)
+	}
+	sort.Slice(exclusionsCopy, func(i, j int) bool {
+		a, b := exclusionsCopy[i], exclusionsCopy[j]
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		if a.ResourceType != b.ResourceType {
+			return a.ResourceType < b.ResourceType
+		}
+		return a.Identifier < b.Identifier
+	})
+	return exclusionsCopy
+}
+
+func ResetExclusions() {
+	defer m.Unlock()
+	m.Lock()
+	exclusions = make(map[Exclusion]Exclusion)
+}
+
+// Record stores the outcome of an attempt to nuke a resource, stamping it with the current time if it has no timestamp.
+// The outcome is recorded for the region, resource type and identifier of the entry, replacing any previous outcome of
+// the same resource.
 func Record(e Entry) {
+	RecordAs(e.ResourceType, e)
+}
+
+// RecordAs is like Record, but records the outcome for the given resource type rather than the one of the entry, which
+// may only describe the resource for display
+func RecordAs(resourceType string, e Entry) {
 	defer m.Unlock()
 	m.Lock()
-	records[e.Identifier] = e
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	records[RecordKey{Region: e.Region, ResourceType: resourceType, Identifier: e.Identifier}] = e
 	// Increment the progressbar so the user feels measurable progress on long-running nuke jobs
 	p := progressbar.GetProgressbar()
 	p.Increment()
@@ -40,37 +113,109 @@ func Record(e Entry) {
 // RecordBatch accepts a BatchEntry that contains a slice of identifiers, loops through them and converts each identifier to
 // a standard Entry. This is useful for supporting batch delete workflows in cloud-nuke (such as cloudwatch_dashboards)
 func RecordBatch(e BatchEntry) {
-	for _, identifier := range e.Identifiers {
-		entry := Entry{
-			Identifier:   identifier,
-			ResourceType: e.ResourceType,
-			Error:        e.Error,
-		}
+	for _, entry := range e.Entries() {
 		Record(entry)
 	}
 }
 
+// RecordError stores an error that is not specific to a single resource, stamping it with the current time if it has
+// no timestamp
 func RecordError(e GeneralError) {
 	defer m.Unlock()
 	m.Lock()
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
 	generalErrors[e.Description] = e
 }
 
+// RecordExclusion stores a resource that was discovered, but left out of the resources to nuke, stamping it with the
+// current time if it has no timestamp. Recording the same resource again replaces the previous exclusion.
+func RecordExclusion(e Exclusion) {
+	defer m.Unlock()
+	m.Lock()
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+	exclusions[Exclusion{Identifier: e.Identifier, ResourceType: e.ResourceType, Region: e.Region}] = e
+}
+
+// IsCancelled returns true if the given error means that nuking a resource was cancelled, e.g. with Ctrl+C or because
+// of a timeout, rather than that it failed
+func IsCancelled(err error) bool {
+	for err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return true
+		}
+		// The errors of the AWS SDK do not support unwrapping, but keep the error that caused them
+		origErr, ok := err.(interface{ OrigErr() error })
+		if !ok {
+			return false
+		}
+		err = origErr.OrigErr()
+	}
+	return false
+}
+
 // Custom types
 type Entry struct {
 	Identifier   string
 	ResourceType string
-	Error        error
+	// Region is the region of the resource, as the same identifier may be used by resources in different regions
+	Region string
+	Error  error
+	// Timestamp is the time at which the outcome was recorded
+	Timestamp time.Time
+	// Quarantined is true if the resource was quarantined rather than deleted
+	Quarantined bool
+	// Metadata holds what is needed to undo the outcome, e.g. the recovery window of a deleted KMS key or the capacity
+	// of a quarantined Auto Scaling group
+	Metadata map[string]string
 }
 
 type BatchEntry struct {
 	Identifiers  []string
 	ResourceType string
+	Region       string
 	Error        error
 }
 
+// Entries converts the batch into an Entry for each of its identifiers
+func (e BatchEntry) Entries() []Entry {
+	entries := make([]Entry, 0, len(e.Identifiers))
+	for _, identifier := range e.Identifiers {
+		entries = append(entries, Entry{
+			Identifier:   identifier,
+			ResourceType: e.ResourceType,
+			Region:       e.Region,
+			Error:        e.Error,
+		})
+	}
+	return entries
+}
+
+// RecordKey identifies the resource an outcome was recorded for
+type RecordKey struct {
+	Region       string
+	ResourceType string
+	Identifier   string
+}
+
 type GeneralError struct {
 	Error        error
 	ResourceType string
 	Description  string
+	// Timestamp is the time at which the error was recorded
+	Timestamp time.Time
+}
+
+// Exclusion is a resource that was discovered, but deliberately left out of the resources to nuke
+type Exclusion struct {
+	Identifier   string
+	ResourceType string
+	Region       string
+	// Reason explains why the resource was excluded, e.g. "excluded by tag"
+	Reason string
+	// Timestamp is the time at which the exclusion was recorded
+	Timestamp time.Time
 }
@@ -1,8 +1,12 @@
 package report
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -86,13 +90,101 @@ func TestRecordErrorSingle(t *testing.T) {
 	ensureGeneralErrorsContainError(t, ge.Error)
 }
 
+func TestRecordSetsTimestamp(t *testing.T) {
+	ResetRecords()
+
+	Record(Entry{Identifier: "arn:aws:sns:us-east-1:999999999999:TestTopic", ResourceType: "SNS Topic"})
+	entry := getTestRecord("arn:aws:sns:us-east-1:999999999999:TestTopic")
+	require.NotNil(t, entry)
+	require.WithinDuration(t, time.Now(), entry.Timestamp, time.Minute)
+
+	recordedAt := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	Record(Entry{Identifier: "arn:aws:sns:us-east-1:999999999999:OtherTopic", ResourceType: "SNS Topic", Timestamp: recordedAt})
+	entry = getTestRecord("arn:aws:sns:us-east-1:999999999999:OtherTopic")
+	require.NotNil(t, entry)
+	require.Equal(t, recordedAt, entry.Timestamp)
+}
+
+func TestRecordConcurrently(t *testing.T) {
+	ResetRecords()
+
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			Record(Entry{
+				Identifier:   fmt.Sprintf("arn:aws:sns:us-east-1:999999999999:TestTopic%d", i),
+				ResourceType: "SNS Topic",
+			})
+			// Reading the records while others are written must be safe
+			GetRecords()
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, GetRecords(), 50)
+}
+
+func TestRecordExclusion(t *testing.T) {
+	ResetExclusions()
+
+	RecordExclusion(Exclusion{Identifier: "vol-2", ResourceType: "ebs", Region: "us-east-1", Reason: "excluded by tag"})
+	RecordExclusion(Exclusion{Identifier: "i-1", ResourceType: "ec2", Region: "us-east-1", Reason: "excluded by tag"})
+	RecordExclusion(Exclusion{Identifier: "vol-1", ResourceType: "ebs", Region: "us-east-1", Reason: "excluded by tag"})
+	// Recording the same resource again must not duplicate it
+	RecordExclusion(Exclusion{Identifier: "i-1", ResourceType: "ec2", Region: "us-east-1", Reason: "excluded by tag"})
+
+	exclusions := GetExclusions()
+	require.Len(t, exclusions, 3)
+	require.Equal(t, "vol-1", exclusions[0].Identifier)
+	require.Equal(t, "vol-2", exclusions[1].Identifier)
+	require.Equal(t, "i-1", exclusions[2].Identifier)
+	require.Equal(t, "excluded by tag", exclusions[0].Reason)
+	require.WithinDuration(t, time.Now(), exclusions[0].Timestamp, time.Minute)
+
+	ResetExclusions()
+	require.Empty(t, GetExclusions())
+}
+
 // Test helpers
 
+func TestGetRecord(t *testing.T) {
+	ResetRecords()
+
+	Record(Entry{Identifier: "queue-1", ResourceType: "SQS Queue", Region: "us-east-1"})
+	// The same identifier in another region does not overwrite the outcome recorded in the first one
+	Record(Entry{Identifier: "queue-1", ResourceType: "SQS Queue", Region: "eu-west-1", Error: errors.New("AccessDenied")})
+	// Recording for a resource type other than the one of the entry
+	RecordAs("sqs", Entry{Identifier: "queue-1", ResourceType: "SQS Queue", Region: "us-east-1"})
+
+	entry, ok := GetRecord("us-east-1", "SQS Queue", "queue-1")
+	require.True(t, ok)
+	require.Equal(t, "SQS Queue", entry.ResourceType)
+	require.NoError(t, entry.Error)
+	require.False(t, entry.Timestamp.IsZero())
+	entry, ok = GetRecord("eu-west-1", "SQS Queue", "queue-1")
+	require.True(t, ok)
+	require.Error(t, entry.Error)
+	_, ok = GetRecord("us-east-1", "sqs", "queue-1")
+	require.True(t, ok)
+	_, ok = GetRecord("us-east-1", "SQS Queue", "queue-2")
+	require.False(t, ok)
+	require.Len(t, GetRecords(), 3)
+}
+
+func TestIsCancelled(t *testing.T) {
+	require.True(t, IsCancelled(context.Canceled))
+	require.True(t, IsCancelled(fmt.Errorf("listing buckets: %w", context.DeadlineExceeded)))
+	require.False(t, IsCancelled(errors.New("AccessDenied")))
+	require.False(t, IsCancelled(nil))
+}
+
 func ensureRecordsContainIdentifier(t *testing.T, key string) {
 	records := GetRecords()
 	found := false
 	for k := range records {
-		if k == key {
+		if k.Identifier == key {
 			found = true
 		}
 	}
@@ -129,7 +221,7 @@ func ensureGeneralErrorsContainError(t *testing.T, err error) {
 func getTestRecord(key string) *Entry {
 	records := GetRecords()
 	for k, entry := range records {
-		if k == key {
+		if k.Identifier == key {
 			return &entry
 		}
 	}
@@ -23,6 +23,17 @@ func InitTelemetry(name string, version string, clientId string) {
 	}
 }
 
+// IsEnabled returns true if events are sent, as decided by InitTelemetry
+func IsEnabled() bool {
+	return sendTelemetry
+}
+
+// SetEnabled turns sending events on or off, e.g. to restore the value returned by IsEnabled. Events may only be turned
+// on once InitTelemetry has set up the client that sends them.
+func SetEnabled(enabled bool) {
+	sendTelemetry = enabled
+}
+
 func SetAccountId(accountId string) {
 	account = accountId
 }
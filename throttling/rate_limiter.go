@@ -0,0 +1,140 @@
+package throttling
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// DefaultRequestsPerSecond is the number of requests per second each service of each region may receive from
+	// cloud-nuke, until AWS starts throttling them
+	DefaultRequestsPerSecond = 20.0
+	// MinRequestsPerSecond is the rate below which a throttled rate limiter does not slow down any further
+	MinRequestsPerSecond = 1.0
+)
+
+// RateLimiter is a token bucket that paces the requests made to an AWS service. Its rate adapts to the throttling of
+// AWS: it is halved every time a request is throttled, and grows back by a small step after every successful request,
+// up to the rate it started with.
+type RateLimiter struct {
+	mutex   sync.Mutex
+	rate    float64
+	maxRate float64
+	tokens  float64
+	last    time.Time
+}
+
+// NewRateLimiter returns a rate limiter allowing the given number of requests per second, in bursts of up to as many
+// requests
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	return &RateLimiter{rate: requestsPerSecond, maxRate: requestsPerSecond, tokens: requestsPerSecond, last: time.Now()}
+}
+
+// Wait blocks until a request may be made, or returns the error of the context if it is done first
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		limiter.mutex.Lock()
+		limiter.refill()
+		if limiter.tokens >= 1 {
+			limiter.tokens--
+			limiter.mutex.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - limiter.tokens) / limiter.rate * float64(time.Second))
+		limiter.mutex.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// Rate returns the number of requests per second currently allowed
+func (limiter *RateLimiter) Rate() float64 {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	return limiter.rate
+}
+
+// Throttled halves the rate of the limiter, as AWS throttled a request
+func (limiter *RateLimiter) Throttled() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.refill()
+	limiter.rate = limiter.rate / 2
+	if limiter.rate < MinRequestsPerSecond {
+		limiter.rate = MinRequestsPerSecond
+	}
+	// Also drop the burst built up at the previous rate, so that the next requests are actually slowed down
+	limiter.tokens = 0
+}
+
+// Succeeded grows the rate of the limiter back towards its initial rate, as a request was not throttled
+func (limiter *RateLimiter) Succeeded() {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+	limiter.rate += limiter.maxRate / 100
+	if limiter.rate > limiter.maxRate {
+		limiter.rate = limiter.maxRate
+	}
+}
+
+// refill adds the tokens earned since the last refill. The mutex must be held.
+func (limiter *RateLimiter) refill() {
+	now := time.Now()
+	limiter.tokens += now.Sub(limiter.last).Seconds() * limiter.rate
+	if limiter.tokens > limiter.maxRate {
+		limiter.tokens = limiter.maxRate
+	}
+	limiter.last = now
+}
+
+var (
+	limitersMutex sync.Mutex
+	// limiters holds the rate limiter of each service of each region, shared by all the sessions and clients of a run
+	limiters = map[string]*RateLimiter{}
+)
+
+// RateLimiterFor returns the rate limiter shared by every request made to the given service in the given region
+func RateLimiterFor(serviceName string, region string) *RateLimiter {
+	limitersMutex.Lock()
+	defer limitersMutex.Unlock()
+	key := serviceName + "/" + region
+	limiter, ok := limiters[key]
+	if !ok {
+		limiter = NewRateLimiter(DefaultRequestsPerSecond)
+		limiters[key] = limiter
+	}
+	return limiter
+}
+
+func rateLimiterForRequest(r *request.Request) *RateLimiter {
+	return RateLimiterFor(r.ClientInfo.ServiceName, aws.StringValue(r.Config.Region))
+}
+
+func waitForRateLimiter(r *request.Request) {
+	if err := rateLimiterForRequest(r).Wait(r.Context()); err != nil {
+		r.Error = awserr.New(request.CanceledErrorCode, "request context canceled", err)
+	}
+}
+
+func slowDownRateLimiter(r *request.Request) {
+	if IsThrottlingError(r.Error) {
+		rateLimiterForRequest(r).Throttled()
+	}
+}
+
+func speedUpRateLimiter(r *request.Request) {
+	if r.Error == nil {
+		rateLimiterForRequest(r).Succeeded()
+	}
+}
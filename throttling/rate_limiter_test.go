@@ -0,0 +1,68 @@
+package throttling
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterAllowsBurst(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(5)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimiterWaitsForTokens(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(20)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+	start := time.Now()
+	require.NoError(t, limiter.Wait(context.Background()))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
+
+func TestRateLimiterWaitIsCancelled(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(1)
+	require.NoError(t, limiter.Wait(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestRateLimiterAdaptsToThrottling(t *testing.T) {
+	t.Parallel()
+
+	limiter := NewRateLimiter(8)
+	limiter.Throttled()
+	assert.Equal(t, 4.0, limiter.Rate())
+	for i := 0; i < 10; i++ {
+		limiter.Throttled()
+	}
+	assert.Equal(t, MinRequestsPerSecond, limiter.Rate())
+
+	for i := 0; i < 1000; i++ {
+		limiter.Succeeded()
+	}
+	assert.Equal(t, 8.0, limiter.Rate())
+}
+
+func TestRateLimiterForIsSharedPerServiceAndRegion(t *testing.T) {
+	t.Parallel()
+
+	assert.Same(t, RateLimiterFor("sqs", "us-east-1"), RateLimiterFor("sqs", "us-east-1"))
+	assert.NotSame(t, RateLimiterFor("sqs", "us-east-1"), RateLimiterFor("sqs", "us-west-2"))
+	assert.NotSame(t, RateLimiterFor("sqs", "us-east-1"), RateLimiterFor("ec2", "us-east-1"))
+}
@@ -0,0 +1,129 @@
+// Package throttling keeps cloud-nuke within the request rates of the AWS APIs. Every session used by cloud-nuke is
+// configured with Configure, which retries throttled requests with exponential backoff and jitter, and paces the
+// requests to each service with a rate limiter that slows down whenever AWS starts throttling.
+package throttling
+
+import (
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+// MaxRetries is the number of times a failed request is retried by the SDK before its error is returned
+const MaxRetries = 8
+
+// throttlingErrorCodes are the AWS error codes that mean a request was rejected because too many requests were made,
+// rather than because it was invalid
+var throttlingErrorCodes = []string{
+	"Throttling",
+	"ThrottlingException",
+	"ThrottledException",
+	"RequestThrottled",
+	"RequestThrottledException",
+	"RequestLimitExceeded",
+	"TooManyRequestsException",
+	"ProvisionedThroughputExceededException",
+	"TransactionInProgressException",
+	"PriorRequestNotComplete",
+	"EC2ThrottledException",
+	"SlowDown",
+	"BandwidthLimitExceeded",
+}
+
+// IsThrottlingError returns true if the given error means that AWS throttled the request, so that it can succeed if
+// made again later
+func IsThrottlingError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if awsErr, isAwsErr := errors.Unwrap(err).(awserr.Error); isAwsErr {
+		for _, code := range throttlingErrorCodes {
+			if awsErr.Code() == code {
+				return true
+			}
+		}
+		if requestErr, isRequestErr := awsErr.(awserr.RequestFailure); isRequestErr && requestErr.StatusCode() == http.StatusTooManyRequests {
+			return true
+		}
+	}
+	// Many resource types wrap the AWS error in their own error, so we also look for the error code in the message
+	for _, code := range throttlingErrorCodes {
+		if strings.Contains(err.Error(), code) {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff computes exponentially growing delays with full jitter: the delay before retry number attempt (starting at
+// 0) is a random duration between 0 and Base * 2^attempt, capped at Max. The jitter keeps the concurrent regions of a
+// run from retrying at the same time and being throttled again.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns how long to wait before retry number attempt, starting at 0
+func (backoff Backoff) Delay(attempt int) time.Duration {
+	ceiling := backoff.Max
+	if attempt < 32 && backoff.Base<<uint(attempt) < backoff.Max && backoff.Base<<uint(attempt) > 0 {
+		ceiling = backoff.Base << uint(attempt)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// Retryer is the request.Retryer used by every session of cloud-nuke. On top of the errors retried by the default
+// retryer of the SDK, it retries every throttling error returned by the AWS APIs, and waits according to Backoff before
+// retrying them.
+type Retryer struct {
+	client.DefaultRetryer
+	// Backoff computes the delay before retrying a throttled request
+	Backoff Backoff
+}
+
+// NewRetryer returns the retryer used by every session of cloud-nuke
+func NewRetryer() Retryer {
+	return Retryer{
+		DefaultRetryer: client.DefaultRetryer{NumMaxRetries: MaxRetries},
+		Backoff:        Backoff{Base: 1 * time.Second, Max: 30 * time.Second},
+	}
+}
+
+// ShouldRetry returns true if the request failed with a throttling error, or with an error the default retryer of the
+// SDK retries
+func (retryer Retryer) ShouldRetry(r *request.Request) bool {
+	if r.Retryable == nil && IsThrottlingError(r.Error) {
+		return true
+	}
+	return retryer.DefaultRetryer.ShouldRetry(r)
+}
+
+// RetryRules returns how long to wait before retrying the request
+func (retryer Retryer) RetryRules(r *request.Request) time.Duration {
+	if IsThrottlingError(r.Error) {
+		return retryer.Backoff.Delay(r.RetryCount)
+	}
+	return retryer.DefaultRetryer.RetryRules(r)
+}
+
+// Configure sets up the given session to retry throttled requests with NewRetryer, and to pace the requests made to
+// each service of each region with a shared rate limiter. It returns the session, so that it can wrap the creation of
+// the session.
+func Configure(sess *session.Session) *session.Session {
+	sess.Config.Retryer = NewRetryer()
+	// Every attempt, including retries, is signed right before it is sent, so this is where we wait for the limiter
+	sess.Handlers.Sign.SetFrontNamed(request.NamedHandler{Name: "cloudnuke.throttling.Wait", Fn: waitForRateLimiter})
+	sess.Handlers.Retry.SetFrontNamed(request.NamedHandler{Name: "cloudnuke.throttling.Throttled", Fn: slowDownRateLimiter})
+	sess.Handlers.Complete.SetBackNamed(request.NamedHandler{Name: "cloudnuke.throttling.Succeeded", Fn: speedUpRateLimiter})
+	return sess
+}
@@ -0,0 +1,77 @@
+package throttling
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tnn-gruntwork-io/go-commons/errors"
+)
+
+func TestIsThrottlingError(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []string{"Throttling", "ThrottlingException", "RequestLimitExceeded", "TooManyRequestsException", "SlowDown"} {
+		throttlingErr := awserr.New(code, "Rate exceeded", nil)
+		assert.True(t, IsThrottlingError(throttlingErr), code)
+		assert.True(t, IsThrottlingError(errors.WithStackTrace(throttlingErr)), code)
+		assert.True(t, IsThrottlingError(fmt.Errorf("failed to delete: %s", throttlingErr)), code)
+	}
+	assert.True(t, IsThrottlingError(awserr.NewRequestFailure(awserr.New("Unknown", "", nil), http.StatusTooManyRequests, "")))
+	assert.False(t, IsThrottlingError(awserr.New("DependencyViolation", "has dependencies", nil)))
+	assert.False(t, IsThrottlingError(nil))
+}
+
+func TestBackoffDelay(t *testing.T) {
+	t.Parallel()
+
+	backoff := Backoff{Base: time.Second, Max: 10 * time.Second}
+	for attempt := 0; attempt < 100; attempt++ {
+		delay := backoff.Delay(attempt)
+		assert.True(t, delay >= 0, "attempt %d", attempt)
+		ceiling := backoff.Max
+		if attempt < 4 {
+			ceiling = backoff.Base << uint(attempt)
+		}
+		assert.True(t, delay <= ceiling, "attempt %d: %s is longer than %s", attempt, delay, ceiling)
+	}
+}
+
+func TestConfigureRetriesThrottledRequests(t *testing.T) {
+	t.Parallel()
+
+	sess := Configure(session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+	})))
+	retryer := NewRetryer()
+	retryer.Backoff = Backoff{Base: time.Millisecond, Max: time.Millisecond}
+	sess.Config.Retryer = retryer
+
+	// Replace the HTTP call with a backend that throttles the first two attempts
+	attempts := 0
+	sess.Handlers.Send.Clear()
+	sess.Handlers.Send.PushBack(func(r *request.Request) {
+		attempts++
+		r.HTTPResponse = &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody}
+		if attempts <= 2 {
+			r.HTTPResponse.StatusCode = http.StatusBadRequest
+			r.Error = awserr.NewRequestFailure(awserr.New("Throttling", "Rate exceeded", nil), http.StatusBadRequest, "")
+		}
+	})
+	sess.Handlers.UnmarshalError.Clear()
+	sess.Handlers.Unmarshal.Clear()
+
+	_, err := sqs.New(sess).ListQueues(&sqs.ListQueuesInput{})
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// OutputFormat is the format in which resources are written for consumption by other tools
+type OutputFormat string
+
+const (
+	// OutputFormatText is the human readable format, which is the default
+	OutputFormatText   OutputFormat = "text"
+	OutputFormatJSON   OutputFormat = "json"
+	OutputFormatCSV    OutputFormat = "csv"
+	OutputFormatNDJSON OutputFormat = "ndjson"
+)
+
+// OutputFormats lists the supported output formats
+var OutputFormats = []OutputFormat{OutputFormatText, OutputFormatJSON, OutputFormatCSV, OutputFormatNDJSON}
+
+const (
+	// ResourceStatusFound is the status of a resource that was discovered, but not nuked
+	ResourceStatusFound = "found"
+	// ResourceStatusDeleted is the status of a resource that was nuked successfully
+	ResourceStatusDeleted = "deleted"
+	// ResourceStatusQuarantined is the status of a resource that was quarantined successfully
+	ResourceStatusQuarantined = "quarantined"
+	// ResourceStatusFailed is the status of a resource that could not be nuked, or restored with 'cloud-nuke aws restore'
+	// or 'cloud-nuke aws undo'
+	ResourceStatusFailed = "failed"
+	// ResourceStatusRestored is the status of a resource that was restored successfully with 'cloud-nuke aws restore' or
+	// 'cloud-nuke aws undo'
+	ResourceStatusRestored = "restored"
+	// ResourceStatusIrreversible is the status of a resource that 'cloud-nuke aws undo' cannot restore. The row's reason
+	// explains why.
+	ResourceStatusIrreversible = "irreversible"
+	// ResourceStatusCancelled is the status of a resource that was not nuked, or not completely, because the run was
+	// cancelled
+	ResourceStatusCancelled = "cancelled"
+	// ResourceStatusError is the status of an error that is not specific to a single resource
+	ResourceStatusError = "error"
+	// ResourceStatusExcluded is the status of a resource that was discovered, but deliberately left alone. The row's
+	// reason explains why.
+	ResourceStatusExcluded = "excluded"
+)
+
+// ResourceRow is a single resource, or a general error, in machine-readable output
+type ResourceRow struct {
+	// AccountID is the account of the resource when several accounts of an AWS Organization are nuked with --org
+	AccountID    string `json:"accountId,omitempty"`
+	Identifier   string `json:"identifier"`
+	ResourceType string `json:"resourceType"`
+	Region       string `json:"region"`
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	// Reason explains why an excluded resource was left alone, e.g. "excluded by tag"
+	Reason string `json:"reason"`
+	// Timestamp is the time at which the resource was discovered (for inspection results) or at which the outcome of
+	// nuking it was recorded (for run reports)
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ParseOutputFormat returns the output format with the given name, or an error if it is not supported
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	for _, format := range OutputFormats {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", UnsupportedOutputFormatError{Name: name}
+}
+
+// WriteResourceRows writes the given rows to w in the given format
+func WriteResourceRows(w io.Writer, format OutputFormat, rows []ResourceRow) error {
+	switch format {
+	case OutputFormatText:
+		return writeTextRows(w, rows)
+	case OutputFormatJSON:
+		if rows == nil {
+			rows = []ResourceRow{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+	case OutputFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OutputFormatCSV:
+		return writeCsvRows(w, rows)
+	}
+	return UnsupportedOutputFormatError{Name: string(format)}
+}
+
+func writeTextRows(w io.Writer, rows []ResourceRow) error {
+	for _, row := range rows {
+		line := fmt.Sprintf("%s %s %s %s", row.ResourceType, row.Identifier, row.Region, row.Status)
+		if row.AccountID != "" {
+			line = fmt.Sprintf("%s %s", row.AccountID, line)
+		}
+		if row.Error != "" {
+			line = fmt.Sprintf("%s: %s", line, removeNewlines(row.Error))
+		}
+		if row.Reason != "" {
+			line = fmt.Sprintf("%s (%s)", line, row.Reason)
+		}
+		if _, err := fmt.Fprintln(w, strings.TrimSpace(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeCsvRows writes the given rows as CSV. The account_id column is only added to the rows of --org runs, so that the
+// columns of the other runs do not change.
+func writeCsvRows(w io.Writer, rows []ResourceRow) error {
+	withAccounts := false
+	for _, row := range rows {
+		withAccounts = withAccounts || row.AccountID != ""
+	}
+
+	writer := csv.NewWriter(w)
+	header := []string{"identifier", "resource_type", "region", "status", "error", "reason", "timestamp"}
+	if withAccounts {
+		header = append(header, "account_id")
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{row.Identifier, row.ResourceType, row.Region, row.Status, row.Error, row.Reason, row.Timestamp.Format(time.RFC3339)}
+		if withAccounts {
+			record = append(record, row.AccountID)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+type UnsupportedOutputFormatError struct {
+	Name string
+}
+
+func (err UnsupportedOutputFormatError) Error() string {
+	return fmt.Sprintf("Unsupported output format %q. Supported formats are: %s", err.Name, OutputFormats)
+}
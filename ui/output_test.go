@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRows = []ResourceRow{
+	{
+		Identifier:   "i-0123456789",
+		ResourceType: "ec2",
+		Region:       "us-east-1",
+		Status:       ResourceStatusDeleted,
+		Timestamp:    time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+	},
+	{
+		Identifier:   "vol-0123456789",
+		ResourceType: "ebs",
+		Region:       "eu-west-1",
+		Status:       ResourceStatusFailed,
+		Error:        "VolumeInUse: volume is attached,\nretry later",
+		Timestamp:    time.Date(2022, 1, 2, 3, 4, 6, 0, time.UTC),
+	},
+	{
+		Identifier:   "key-0123456789",
+		ResourceType: "kmscustomerkeys",
+		Region:       "eu-west-1",
+		Status:       ResourceStatusExcluded,
+		Reason:       "excluded by tag",
+		Timestamp:    time.Date(2022, 1, 2, 3, 4, 7, 0, time.UTC),
+	},
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	for _, format := range OutputFormats {
+		parsed, err := ParseOutputFormat(string(format))
+		require.NoError(t, err)
+		assert.Equal(t, format, parsed)
+	}
+
+	_, err := ParseOutputFormat("yaml")
+	assert.Equal(t, UnsupportedOutputFormatError{Name: "yaml"}, err)
+}
+
+func TestWriteResourceRowsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatJSON, testRows))
+
+	var rows []ResourceRow
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	assert.Equal(t, testRows, rows)
+	assert.Contains(t, buf.String(), `"resourceType": "ec2"`)
+}
+
+func TestWriteResourceRowsJSONWithoutRows(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatJSON, nil))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestWriteResourceRowsNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatNDJSON, testRows))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, len(testRows))
+	for idx, line := range lines {
+		var row ResourceRow
+		require.NoError(t, json.Unmarshal([]byte(line), &row))
+		assert.Equal(t, testRows[idx], row)
+	}
+}
+
+func TestWriteResourceRowsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatCSV, testRows))
+
+	expected := "identifier,resource_type,region,status,error,reason,timestamp\n" +
+		"i-0123456789,ec2,us-east-1,deleted,,,2022-01-02T03:04:05Z\n" +
+		"vol-0123456789,ebs,eu-west-1,failed,\"VolumeInUse: volume is attached,\nretry later\",,2022-01-02T03:04:06Z\n" +
+		"key-0123456789,kmscustomerkeys,eu-west-1,excluded,,excluded by tag,2022-01-02T03:04:07Z\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteResourceRowsText(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatText, testRows))
+
+	expected := "ec2 i-0123456789 us-east-1 deleted\n" +
+		"ebs vol-0123456789 eu-west-1 failed: VolumeInUse: volume is attached,retry later\n" +
+		"kmscustomerkeys key-0123456789 eu-west-1 excluded (excluded by tag)\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteResourceRowsWithAccounts(t *testing.T) {
+	rows := []ResourceRow{
+		{AccountID: "200000000000", Identifier: "i-0123456789", ResourceType: "ec2", Region: "us-east-1", Status: ResourceStatusDeleted, Timestamp: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{AccountID: "300000000000", Status: ResourceStatusError, Error: "Could not assume role", Timestamp: time.Date(2022, 1, 2, 3, 4, 6, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatCSV, rows))
+	expected := "identifier,resource_type,region,status,error,reason,timestamp,account_id\n" +
+		"i-0123456789,ec2,us-east-1,deleted,,,2022-01-02T03:04:05Z,200000000000\n" +
+		",,,error,Could not assume role,,2022-01-02T03:04:06Z,300000000000\n"
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatText, rows))
+	expected = "200000000000 ec2 i-0123456789 us-east-1 deleted\n" +
+		"300000000000    error: Could not assume role\n"
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatJSON, rows[:1]))
+	assert.Contains(t, buf.String(), `"accountId": "200000000000"`)
+
+	// The output of runs against a single account does not change
+	buf.Reset()
+	require.NoError(t, WriteResourceRows(&buf, OutputFormatJSON, testRows))
+	assert.NotContains(t, buf.String(), "accountId")
+}
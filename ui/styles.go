@@ -10,6 +10,7 @@ import (
 var (
 	FailureEmoji           = "❌"
 	SuccessEmoji           = "✅"
+	CancelledEmoji         = "🛑"
 	FireEmoji              = "🔥"
 	TargetEmoji            = "🎯"
 	ResourceHighlightStyle = lipgloss.NewStyle().
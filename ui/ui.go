@@ -12,18 +12,27 @@ import (
 	"github.com/pterm/pterm"
 )
 
+// humanOutput is where the output meant for humans, such as progress bars, spinners and messages, is written
+var humanOutput io.Writer = os.Stdout
+
+// SendHumanOutputToStderr writes the output meant for humans to stderr, so that stdout only gets the results written
+// for other tools
+func SendHumanOutputToStderr() {
+	humanOutput = os.Stderr
+	pterm.SetDefaultOutput(os.Stderr)
+}
+
+// HumanOutput returns the writer the output meant for humans is written to
+func HumanOutput() io.Writer {
+	return humanOutput
+}
+
 // RenderRunReport should be called at the end of a support cloud-nuke function
 // It will print a table showing resources were deleted, and what errors occurred
 // Note that certain functions don't support the report table, such as aws-inspect,
 // which prints its own findings out directly to os.Stdout
 func RenderRunReport() {
-	// Remove the progressbar, now that we're ready to display the table report
-	p := progressbar.GetProgressbar()
-	// This next entry is necessary to workaround an issue where the spinner is not reliably cleaned up before the
-	// final run report table is printed
-	fmt.Print("\r")
-	p.Stop()
-	pterm.Println()
+	StopProgressBar()
 
 	// Conditionally print the general error report, if in fact there were errors
 	PrintGeneralErrorReport(os.Stdout)
@@ -32,6 +41,16 @@ func RenderRunReport() {
 	PrintRunReport(os.Stdout)
 }
 
+// StopProgressBar removes the progressbar, so that a report can be displayed
+func StopProgressBar() {
+	p := progressbar.GetProgressbar()
+	// This next entry is necessary to workaround an issue where the spinner is not reliably cleaned up before the
+	// final run report table is printed
+	fmt.Fprint(humanOutput, "\r")
+	p.Stop()
+	pterm.Println()
+}
+
 func PrintGeneralErrorReport(w io.Writer) {
 	// generalErrors is a map[string]GeneralError from the report package. This map contains
 	// an entry for every general error (that is, not a resource-specific erorr) that occurred
@@ -86,7 +105,9 @@ func PrintRunReport(w io.Writer) {
 
 	for idx, entry := range entriesToDisplay {
 		var errSymbol string
-		if entry.Error != nil {
+		if report.IsCancelled(entry.Error) {
+			errSymbol = fmt.Sprintf("%s %s", CancelledEmoji, ResourceStatusCancelled)
+		} else if entry.Error != nil {
 			// If we encountered an error when deleting the resource, display it in-line within the table for the operator
 			//
 			// We intentionally truncate the error message to its first 40 characters, because pterm tables are not fully
@@ -106,6 +127,34 @@ func PrintRunReport(w io.Writer) {
 	w.Write([]byte("\r"))
 }
 
+// PrintAccountReport prints a table counting the outcomes of the given rows per account, in the order of the given
+// account IDs, for runs against several accounts of an AWS Organization
+func PrintAccountReport(w io.Writer, accountIDs []string, rows []ResourceRow) {
+	counts := map[string]map[string]int{}
+	for _, accountID := range accountIDs {
+		counts[accountID] = map[string]int{}
+	}
+	for _, row := range rows {
+		if accountCounts, ok := counts[row.AccountID]; ok {
+			accountCounts[row.Status]++
+		}
+	}
+
+	statuses := []string{ResourceStatusDeleted, ResourceStatusQuarantined, ResourceStatusFailed, ResourceStatusCancelled, ResourceStatusError}
+	data := make([][]string, len(accountIDs))
+	for idx, accountID := range accountIDs {
+		data[idx] = []string{accountID}
+		for _, status := range statuses {
+			data[idx] = append(data[idx], fmt.Sprint(counts[accountID][status]))
+		}
+	}
+
+	// Workaround an issue where the pterm progressbar might not be cleaned up correctly
+	w.Write([]byte("\r"))
+	renderTableWithHeader([]string{"Account", "Deleted", "Quarantined", "Failed", "Cancelled", "Errors"}, data, w)
+	w.Write([]byte("\r"))
+}
+
 func renderTableWithHeader(headers []string, data [][]string, w io.Writer) {
 	tableData := pterm.TableData{
 		headers,
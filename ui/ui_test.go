@@ -43,6 +43,23 @@ func TestRenderEntriesWithErrors(t *testing.T) {
 	ensureRenderedReportDoesNotContain(t, SuccessEmoji)
 }
 
+func TestPrintAccountReport(t *testing.T) {
+	rows := []ResourceRow{
+		{AccountID: "200000000000", Identifier: "i-1", Status: ResourceStatusDeleted},
+		{AccountID: "200000000000", Identifier: "i-2", Status: ResourceStatusDeleted},
+		{AccountID: "200000000000", Identifier: "vol-1", Status: ResourceStatusFailed},
+		{AccountID: "300000000000", Status: ResourceStatusError, Error: "Could not assume role"},
+	}
+	output := pterm.RemoveColorFromString(captureStdout(func(w io.Writer) {
+		PrintAccountReport(w, []string{"200000000000", "300000000000", "400000000000"}, rows)
+	}))
+
+	require.Regexp(t, `Account +\| Deleted +\| Quarantined +\| Failed +\| Cancelled +\| Errors`, output)
+	require.Regexp(t, `200000000000 +\| 2 +\| 0 +\| 1 +\| 0 +\| 0`, output)
+	require.Regexp(t, `300000000000 +\| 0 +\| 0 +\| 0 +\| 0 +\| 1`, output)
+	require.Regexp(t, `400000000000 +\| 0 +\| 0 +\| 0 +\| 0 +\| 0`, output)
+}
+
 // testPrintContains can be used to test Print methods.
 func ensureRenderedReportContains(t *testing.T, match string) {
 	output := captureStdout(PrintRunReport)